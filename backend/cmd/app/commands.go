@@ -0,0 +1,439 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dntjd1097/allora-monitor/internal/app"
+)
+
+// runCommand는 서버 대신 실행할 하위 명령을 처리합니다
+func runCommand(config *app.Config, dbPath string, args []string) error {
+	switch args[0] {
+	case "backup":
+		return runBackupCommand(config, dbPath, args[1:])
+	case "restore":
+		return runRestoreCommand(config, dbPath, args[1:])
+	case "check":
+		return runCheckCommand(config, dbPath, args[1:])
+	case "export":
+		return runExportCommand(config, dbPath, args[1:])
+	case "import":
+		return runImportCommand(config, dbPath, args[1:])
+	case "shard":
+		return runShardCommand(config, dbPath, args[1:])
+	case "openapi":
+		return runOpenAPICommand(args[1:])
+	default:
+		return fmt.Errorf("알 수 없는 명령: %s (사용 가능: backup, restore, check, export, import, shard, openapi)", args[0])
+	}
+}
+
+// openDatabase는 하위 명령용으로 데이터베이스를 열고 토픽 샤드 설정을 적용합니다
+func openDatabase(config *app.Config, dbPath string) (*app.Database, error) {
+	db, err := app.NewDatabase(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("데이터베이스 연결 실패: %w", err)
+	}
+	db.SetDebug(false)
+
+	if config.TopicShardingEnabled {
+		if err := db.SetTopicSharding(config.TopicShardDir); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("토픽 샤드 설정 실패: %w", err)
+		}
+	}
+	return db, nil
+}
+
+// runBackupCommand는 백업을 생성(create), 조회(list), 검증(verify)합니다
+func runBackupCommand(config *app.Config, dbPath string, args []string) error {
+	action := "create"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "create":
+		db, err := openDatabase(config, dbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		backups := app.NewBackupManager(db, config.BackupDir, config.BackupKeep, 0)
+		backup, err := backups.CreateBackup()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\t%d\t%s\n", backup.Name, backup.SizeBytes, backup.SHA256)
+		return nil
+
+	case "list":
+		backups, err := app.ListBackups(config.BackupDir)
+		if err != nil {
+			return err
+		}
+		for _, backup := range backups {
+			fmt.Printf("%s\t%s\t%d\t%s\n", backup.Name, backup.CreatedAt, backup.SizeBytes, backup.SHA256)
+		}
+		return nil
+
+	case "verify":
+		if len(args) < 2 {
+			return fmt.Errorf("검증할 백업 파일을 지정하세요")
+		}
+		path := resolveBackupPath(config.BackupDir, args[1])
+		if err := app.VerifyBackup(path); err != nil {
+			return err
+		}
+		fmt.Printf("%s: ok\n", path)
+		return nil
+
+	default:
+		return fmt.Errorf("알 수 없는 backup 명령: %s (사용 가능: create, list, verify)", action)
+	}
+}
+
+// runRestoreCommand는 백업 파일로 데이터베이스를 복원합니다
+// 백업을 지정하지 않으면 -at 시각 이전의 가장 최근 백업(기본값: 현재 시각)을 사용합니다
+// 서버가 데이터베이스를 열고 있으면 배타적 잠금을 얻지 못해 복원하지 않습니다
+func runRestoreCommand(config *app.Config, dbPath string, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	at := flags.String("at", "", "이 시각(RFC3339) 이전의 가장 최근 백업으로 복원")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var backupPath string
+	if flags.NArg() > 0 {
+		backupPath = resolveBackupPath(config.BackupDir, flags.Arg(0))
+	} else {
+		target := time.Now()
+		if *at != "" {
+			parsed, err := time.Parse(time.RFC3339, *at)
+			if err != nil {
+				return fmt.Errorf("잘못된 -at 시각 형식: %w", err)
+			}
+			target = parsed
+		}
+
+		backup, err := app.FindBackupAt(config.BackupDir, target)
+		if err != nil {
+			return err
+		}
+		backupPath = backup.Path
+	}
+
+	log.Printf("백업으로 복원합니다: %s -> %s", backupPath, dbPath)
+	if err := app.RestoreBackup(backupPath, dbPath); err != nil {
+		return err
+	}
+	log.Println("복원이 완료되었습니다")
+	return nil
+}
+
+// runCheckCommand는 데이터베이스 무결성을 검사하고 보고서를 출력합니다
+// 조치되지 않은 문제가 남아 있으면 오류를 반환합니다
+func runCheckCommand(config *app.Config, dbPath string, args []string) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	quarantine := flags.Bool("quarantine", false, "읽을 수 없거나 형식이 잘못된 행을 quarantined_rows로 격리")
+	repair := flags.Bool("repair", false, "중복 토픽/블록 높이 행과 스냅샷이 없는 리더보드 행 정리")
+	asJSON := flags.Bool("json", false, "보고서를 JSON으로 출력")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := openDatabase(config, dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	report, err := db.CheckIntegrity(app.IntegrityOptions{Quarantine: *quarantine, Repair: *repair})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("sqlite integrity: %s\n", strings.Join(report.SQLiteIntegrity, "; "))
+		for _, table := range []string{"competitions", "topic_inferences", "leaderboard_entries"} {
+			fmt.Printf("%s: %d rows checked\n", table, report.RowsChecked[table])
+		}
+		for _, issue := range report.Issues {
+			action := issue.Action
+			if action == "" {
+				action = "-"
+			}
+			table := issue.Table
+			if issue.Shard != "" {
+				table += "@topic-" + issue.Shard
+			}
+			fmt.Printf("%s\t%d\t%s\t%s\t%s\n", table, issue.RowID, issue.Kind, action, issue.Detail)
+		}
+		fmt.Printf("issues: %d, quarantined: %d, repaired: %d, unresolved: %d (%dms)\n",
+			len(report.Issues), report.Quarantined, report.Repaired, report.Unresolved(), report.DurationMs)
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("해결되지 않은 무결성 문제 %d개가 있습니다", report.Unresolved())
+	}
+	return nil
+}
+
+// runOpenAPICommand는 OpenAPI 문서를 표준 출력이나 파일로 출력합니다
+func runOpenAPICommand(args []string) error {
+	flags := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	output := flags.String("o", "", "문서를 저장할 파일 (비어 있으면 표준 출력)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(app.BuildOpenAPIDocument(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("OpenAPI 문서 생성 실패: %w", err)
+	}
+	data = append(data, '\n')
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0644)
+}
+
+// resolveBackupPath는 파일 이름만 주어지면 백업 디렉토리 기준 경로로 변환합니다
+func resolveBackupPath(backupDir, name string) string {
+	if _, err := os.Stat(name); err == nil {
+		return name
+	}
+	return filepath.Join(backupDir, name)
+}
+
+// runExportCommand는 토픽 데이터를 CSV/NDJSON/Parquet으로 내보냅니다 (기본값: 표준 출력)
+func runExportCommand(config *app.Config, dbPath string, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	dataset := flags.String("dataset", app.ExportDatasetSnapshots, "데이터셋 (snapshots, workers, leaderboard, competitions)")
+	format := flags.String("format", app.ExportFormatNDJSON, "출력 형식 (csv, ndjson, parquet)")
+	topicID := flags.String("topic", "", "토픽 ID (비어 있으면 전체 토픽)")
+	start := flags.String("start", "", "시작 시각 (RFC3339)")
+	end := flags.String("end", "", "종료 시각 (RFC3339)")
+	minHeight := flags.Int64("min-height", 0, "최소 추론 블록 높이")
+	maxHeight := flags.Int64("max-height", 0, "최대 추론 블록 높이")
+	columns := flags.String("columns", "", "출력할 컬럼 (쉼표 구분, 비어 있으면 전체)")
+	compress := flags.Bool("gzip", false, "gzip으로 압축")
+	output := flags.String("o", "", "출력 파일 경로 (비어 있으면 표준 출력)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	opts := app.ExportOptions{
+		Dataset:   *dataset,
+		Format:    *format,
+		TopicID:   *topicID,
+		MinHeight: *minHeight,
+		MaxHeight: *maxHeight,
+		Gzip:      *compress,
+	}
+	for _, value := range []struct {
+		flag   string
+		raw    string
+		target *time.Time
+	}{{"-start", *start, &opts.Start}, {"-end", *end, &opts.End}} {
+		if value.raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value.raw)
+		if err != nil {
+			return fmt.Errorf("잘못된 %s 시각 형식: %w", value.flag, err)
+		}
+		*value.target = parsed
+	}
+	if *columns != "" {
+		for _, column := range strings.Split(*columns, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				opts.Columns = append(opts.Columns, column)
+			}
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	db, err := openDatabase(config, dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("출력 파일 생성 실패: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	count, err := db.Export(out, opts)
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		if err := out.Sync(); err != nil {
+			return fmt.Errorf("출력 파일 기록 실패: %w", err)
+		}
+		log.Printf("%s 데이터셋 %d행을 %s로 내보냈습니다", opts.Dataset, count, *output)
+	}
+	return nil
+}
+
+// runImportCommand는 내보낸 NDJSON/Parquet 파일이나 다른 데이터베이스의 토픽 스냅샷을 병합하고 결과를 출력합니다
+func runImportCommand(config *app.Config, dbPath string, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", app.ImportFormatAuto, "원본 형식 (auto, ndjson, parquet, sqlite)")
+	topicID := flags.String("topic", "", "가져올 토픽 ID (비어 있으면 전체 토픽)")
+	batchSize := flags.Int("batch", 500, "트랜잭션 하나에 병합하는 스냅샷 수")
+	dryRun := flags.Bool("dry-run", false, "저장하지 않고 병합 결과만 출력")
+	asJSON := flags.Bool("json", false, "결과를 JSON으로 출력")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("가져올 파일을 지정하세요")
+	}
+
+	db, err := openDatabase(config, dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, source := range flags.Args() {
+		report, err := db.ImportTopicData(source, app.ImportOptions{
+			Format:    *format,
+			TopicID:   *topicID,
+			BatchSize: *batchSize,
+			DryRun:    *dryRun,
+		})
+		if err != nil {
+			return fmt.Errorf("%s 가져오기 실패: %w", source, err)
+		}
+
+		if *asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fmt.Printf("%s (%s)\n", report.Source, report.Format)
+		for _, topic := range report.SortedTopicIDs() {
+			result := report.Topics[topic]
+			fmt.Printf("topic %s\tadded %d\tupdated %d\tskipped %d\theights %d-%d\n",
+				topic, result.Added, result.Updated, result.Skipped, result.MinHeight, result.MaxHeight)
+		}
+		for _, message := range report.Errors {
+			fmt.Printf("error\t%s\n", message)
+		}
+		dryRunNote := ""
+		if report.DryRun {
+			dryRunNote = " (dry run, nothing saved)"
+		}
+		fmt.Printf("read: %d, added: %d, updated: %d, skipped: %d, invalid: %d (%dms)%s\n",
+			report.Read, report.Added, report.Updated, report.Skipped, report.Invalid, report.DurationMs, dryRunNote)
+	}
+	return nil
+}
+
+// runShardCommand는 토픽 샤드를 조회(list), 이전(migrate), 보관(archive), 정리(vacuum)하고 보관 기록(archives)을 출력합니다
+func runShardCommand(config *app.Config, dbPath string, args []string) error {
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+		args = args[1:]
+	}
+
+	flags := flag.NewFlagSet("shard "+action, flag.ContinueOnError)
+	topicID := flags.String("topic", "", "대상 토픽 ID (archive, vacuum)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if (action == "archive" || action == "vacuum") && *topicID == "" {
+		return fmt.Errorf("shard %s 명령에는 -topic이 필요합니다", action)
+	}
+	if action != "list" && action != "archives" && !config.TopicShardingEnabled {
+		return fmt.Errorf("토픽 샤딩이 활성화되어 있지 않습니다 (TOPIC_SHARDING_ENABLED)")
+	}
+
+	db, err := openDatabase(config, dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch action {
+	case "list":
+		shards, err := db.ListTopicShards()
+		if err != nil {
+			return err
+		}
+		for _, shard := range shards {
+			fmt.Printf("%s\t%s\t%s\t%d\n", shard.TopicID, shard.FileName, shard.CreatedAt, shard.SizeBytes)
+		}
+		return nil
+
+	case "migrate":
+		topicIDs, err := db.MigrateTopicShards()
+		if err != nil {
+			return err
+		}
+		for _, id := range topicIDs {
+			fmt.Printf("topic %s\tmigrated\n", id)
+		}
+		fmt.Printf("migrated: %d\n", len(topicIDs))
+		return nil
+
+	case "archive":
+		archive, err := db.ArchiveTopicShard(*topicID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\t%s\t%d\t%d\n", archive.TopicID, archive.Path, archive.RowCount, archive.SizeBytes)
+		return nil
+
+	case "vacuum":
+		if err := db.VacuumTopicShard(*topicID); err != nil {
+			return err
+		}
+		fmt.Printf("topic %s: vacuumed\n", *topicID)
+		return nil
+
+	case "archives":
+		archives, err := db.ListTopicShardArchives()
+		if err != nil {
+			return err
+		}
+		for _, archive := range archives {
+			fmt.Printf("%s\t%s\t%s\t%d\t%d\n", archive.TopicID, archive.ArchivedAt, archive.Path, archive.RowCount, archive.SizeBytes)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("알 수 없는 shard 명령: %s (사용 가능: list, migrate, archive, vacuum, archives)", action)
+	}
+}
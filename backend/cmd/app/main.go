@@ -20,7 +20,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 		// Set CORS headers
 		w.Header().Set("Access-Control-Allow-Origin", "*") // Allow any origin
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Last-Event-ID")
 
 		// Handle preflight requests
 		if r.Method == "OPTIONS" {
@@ -52,6 +52,14 @@ func main() {
 	// 데이터 디렉토리 권한 확인
 	dbPath := filepath.Join(config.DataDir, "allora-monitor.db")
 
+	// 하위 명령 실행 (예: backup, restore)
+	if flag.NArg() > 0 {
+		if err := runCommand(config, dbPath, flag.Args()); err != nil {
+			log.Fatalf("명령 실행 실패: %v", err)
+		}
+		return
+	}
+
 	// 데이터베이스 파일이 이미 존재하는 경우 권한 확인
 	if _, err := os.Stat(dbPath); err == nil {
 		// 파일이 존재하면 쓰기 권한 확인
@@ -88,19 +96,112 @@ func main() {
 	}
 	defer db.Close()
 
+	// 토픽별 샤드 파일 사용 설정
+	if config.TopicShardingEnabled {
+		if err := db.SetTopicSharding(config.TopicShardDir); err != nil {
+			log.Fatalf("토픽 샤드 설정 실패: %v", err)
+		}
+	}
+
+	// 스냅샷 저장 방식 설정 (키프레임 + 델타)
+	db.SetSnapshotDelta(config.SnapshotDeltaEnabled, config.SnapshotKeyframeInterval)
+	db.SetRetentionPolicy(app.RetentionPolicy{
+		RawDays:    config.DataRetentionDays,
+		HourlyDays: config.HourlyRollupRetentionDays,
+	}, config.TopicRetention)
+	db.SetSnapshotCache(int64(config.SnapshotCacheMB)<<20, time.Duration(config.SnapshotCacheTTLSeconds)*time.Second)
+
+	// 정리 전 데이터를 보관할 Parquet 아카이브 설정
+	archiver := app.NewArchiver(config.ArchiveDir)
+	db.SetArchiver(archiver)
+
 	// API 클라이언트 생성
 	apiClient := app.NewAlloraAPIClient(config.AlloraBaseURL, time.Duration(config.APITimeoutSeconds)*time.Second)
 
 	// 모니터링 서비스 생성
 	monitor := app.NewMonitor(apiClient, db, config)
 
+	// 수집 이벤트 브로커 (저장된 변경과 수집 실패를 스트림 API 구독자에게 전달)
+	events := app.NewEventBroker(config.EventBufferSize)
+	monitor.SetEventBroker(events)
+
+	// Prometheus 지표 (수집 결과, 상위 API 요청, HTTP 요청, 저장소/캐시 통계를 /metrics로 노출)
+	metrics := app.NewMetrics(db)
+	monitor.SetMetrics(metrics)
+
 	// 서비스 생성
 	service := app.NewService(monitor, db)
+	service.SetEventBroker(events)
+	service.SetMetrics(metrics)
+
+	// WebSocket 허브 생성 (이벤트 브로커를 구독해 채널별 스냅샷/델타를 전달)
+	hub := app.NewWebSocketHub(db, events)
+	if err := hub.Start(); err != nil {
+		log.Fatalf("WebSocket 허브 시작 실패: %v", err)
+	}
+	service.SetWebSocketHub(hub)
+	service.SetAdminToken(config.AdminToken)
+	if config.AdminToken == "" {
+		log.Println("관리자 토큰(ADMIN_TOKEN)이 설정되지 않아 관리자 API를 비활성화합니다")
+	}
+	service.SetArchiver(archiver)
+
+	// 백업 관리자 생성 (관리자 API에서는 토큰 설정 시 사용 가능, 정기 백업은 설정 시에만)
+	backups := app.NewBackupManager(db, config.BackupDir, config.BackupKeep, time.Duration(config.BackupIntervalHours)*time.Hour)
+	service.SetBackupManager(backups)
+
+	// 무결성 검사기 생성 (관리자 API에서는 토큰 설정 시 사용 가능, 정기 검사는 설정 시에만)
+	integrity := app.NewIntegrityChecker(db, time.Duration(config.IntegrityCheckIntervalHours)*time.Hour, app.IntegrityOptions{
+		Quarantine: config.IntegrityAutoQuarantine,
+	})
+	service.SetIntegrityChecker(integrity)
+
+	// 수집과 정기 작업 (리더 선출을 사용하면 리더인 동안에만 실행)
+	startCollectors := func() {
+		if err := monitor.Start(); err != nil {
+			log.Printf("모니터링 서비스 시작 실패: %v", err)
+		}
+		if config.BackupEnabled {
+			if err := backups.Start(); err != nil {
+				log.Printf("백업 관리자 시작 실패: %v", err)
+			}
+		}
+		if config.IntegrityCheckEnabled {
+			if err := integrity.Start(); err != nil {
+				log.Printf("무결성 검사기 시작 실패: %v", err)
+			}
+		}
+	}
+	stopCollectors := func() {
+		if monitor.IsRunning() {
+			if err := monitor.Stop(); err != nil {
+				log.Printf("모니터링 서비스 중지 실패: %v", err)
+			}
+		}
+		if config.BackupEnabled {
+			if err := backups.Stop(); err != nil {
+				log.Printf("백업 관리자 중지 실패: %v", err)
+			}
+		}
+		if config.IntegrityCheckEnabled {
+			if err := integrity.Stop(); err != nil {
+				log.Printf("무결성 검사기 중지 실패: %v", err)
+			}
+		}
+	}
 
-	// 모니터링 서비스 시작
-	if err := monitor.Start(); err != nil {
-		log.Fatalf("모니터링 서비스 시작 실패: %v", err)
+	// 리더 선출 (임대를 보유한 레플리카만 수집, 모든 레플리카는 조회 API 제공)
+	var elector *app.LeaderElector
+	if config.LeaderElectionEnabled {
+		elector = app.NewLeaderElector(db, config.InstanceID, time.Duration(config.LeaderLeaseSeconds)*time.Second)
+		elector.SetCallbacks(startCollectors, stopCollectors)
+		if err := elector.Start(); err != nil {
+			log.Fatalf("리더 선출기 시작 실패: %v", err)
+		}
+	} else {
+		startCollectors()
 	}
+	service.SetLeaderElector(elector)
 
 	// HTTP 서버 설정
 	mux := http.NewServeMux()
@@ -110,30 +211,13 @@ func main() {
 		w.Header().Set("Content-Type", "application/json")
 		w.Write([]byte(`{"message": "Welcome to Allora Monitor API", "status": "running"}`))
 	})
-	mux.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		monitorStatus := "stopped"
-		if monitor.IsRunning() {
-			monitorStatus = "running"
-		}
-		w.Write([]byte(`{"status": "healthy", "monitor_status": "` + monitorStatus + `", "timestamp": "` + time.Now().Format(time.RFC3339) + `"}`))
-	})
-	mux.HandleFunc("/api/competitions", service.HandleGetCompetitions)
-	mux.HandleFunc("/api/competitions/range", service.HandleGetCompetitionsByTimeRange)
-	mux.HandleFunc("/api/competitions/v2", service.HandleGetCompetitionsV2)
-	mux.HandleFunc("/api/stats", service.HandleGetDatabaseStats)
+	// /api/v1 리소스 경로와 프론트엔드가 사용하는 이전 경로(별칭), API 문서 등록
+	service.RegisterRoutes(mux)
 	// mux.HandleFunc("/api/set-direct-url", service.HandleSetDirectURL)
 	// mux.HandleFunc("/api/fetch-now", service.HandleFetchNow)
-
-	// 토픽 추론 데이터 API 엔드포인트 등록
-	mux.HandleFunc("/api/topics/active", service.HandleGetActiveTopics)
-	mux.HandleFunc("/api/topics/inference", service.HandleGetTopicInference)
-	mux.HandleFunc("/api/topics/inferences", service.HandleGetAllTopicInferences)
 	// mux.HandleFunc("/api/topics/collect", service.HandleForceCollectTopicData)
 	// mux.HandleFunc("/api/topics/add", service.HandleAddActiveTopic)
 	// mux.HandleFunc("/api/topics/remove", service.HandleRemoveActiveTopic)
-	mux.HandleFunc("/api/topics/stats", service.HandleGetTopicStats)
-	mux.HandleFunc("/api/topics/heights", service.HandleGetTopicBlockHeights)
 
 	// Apply CORS middleware
 	handler := corsMiddleware(mux)
@@ -143,6 +227,10 @@ func main() {
 		Addr:    ":" + config.Port,
 		Handler: handler,
 	}
+	// 종료 시 이벤트 스트림 연결이 끝나지 않아 Shutdown이 시간 초과되지 않도록 구독을 모두 끊음
+	server.RegisterOnShutdown(events.Close)
+	// 업그레이드된 WebSocket 연결은 Shutdown이 추적하지 않으므로 허브를 직접 중지
+	server.RegisterOnShutdown(hub.Stop)
 
 	// 서버를 고루틴에서 시작
 	go func() {
@@ -159,9 +247,13 @@ func main() {
 
 	log.Println("종료 신호를 받았습니다. 서버를 종료합니다...")
 
-	// 모니터링 서비스 중지
-	if err := monitor.Stop(); err != nil {
-		log.Printf("모니터링 서비스 중지 실패: %v", err)
+	// 수집 중지 (리더였다면 임대를 반납하여 다른 레플리카가 바로 이어받음)
+	if elector != nil {
+		if err := elector.Stop(); err != nil {
+			log.Printf("리더 선출기 중지 실패: %v", err)
+		}
+	} else {
+		stopCollectors()
 	}
 
 	// HTTP 서버 종료
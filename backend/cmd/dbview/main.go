@@ -11,6 +11,9 @@ import (
 	"github.com/golang/snappy"
 )
 
+// competitionDataColumn은 본문 저장소(snapshot_blobs)를 참조하는 레코드의 본문을 함께 읽는 식입니다
+const competitionDataColumn = "CASE WHEN length(data) = 0 THEN (SELECT b.data FROM snapshot_blobs b WHERE b.hash = content_hash) ELSE data END"
+
 func main() {
 	// 데이터베이스 파일 경로
 	dbPath := "data/allora-monitor.db"
@@ -40,7 +43,7 @@ func main() {
 	fmt.Println()
 
 	// competitions 테이블 데이터 조회
-	rows, err = db.Query("SELECT id, timestamp, data FROM competitions ORDER BY timestamp DESC LIMIT 1")
+	rows, err = db.Query("SELECT id, timestamp, " + competitionDataColumn + " FROM competitions ORDER BY timestamp DESC LIMIT 1")
 	if err != nil {
 		log.Fatalf("데이터 조회 실패: %v", err)
 	}
@@ -89,7 +92,7 @@ func main() {
 	var count int
 	var totalSize int64
 	db.QueryRow("SELECT COUNT(*) FROM competitions").Scan(&count)
-	db.QueryRow("SELECT SUM(LENGTH(data)) FROM competitions").Scan(&totalSize)
+	db.QueryRow("SELECT SUM(LENGTH(" + competitionDataColumn + ")) FROM competitions").Scan(&totalSize)
 
 	fmt.Printf("\n총 레코드 수: %d, 총 데이터 크기: %.2f MB\n",
 		count, float64(totalSize)/(1024*1024))
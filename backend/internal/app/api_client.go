@@ -82,6 +82,11 @@ func (c *AlloraAPIClient) SetDebug(debug bool) {
 	c.debug = debug
 }
 
+// SetMetrics는 요청 응답 시간과 상태 코드를 기록할 지표를 설정합니다
+func (c *AlloraAPIClient) SetMetrics(metrics *Metrics) {
+	c.httpClient.Transport = metrics.Transport(c.httpClient.Transport)
+}
+
 // FetchCompetitions는 경쟁 데이터를 가져옵니다
 func (c *AlloraAPIClient) FetchCompetitions() (*CompetitionsResponse, error) {
 	// 먼저 메인 페이지를 요청하여 최신 빌드 ID를 추출
@@ -320,3 +325,32 @@ func (c *AlloraAPIClient) FetchLeaderboard(competitionID string, continuationTok
 
 	return &leaderboardResp, nil
 }
+
+// FetchTopicIsActive는 체인에서 토픽의 활성 여부를 조회합니다
+func (c *AlloraAPIClient) FetchTopicIsActive(topicID int) (bool, error) {
+	url := fmt.Sprintf("https://%s/emissions/%s/is_topic_active/%d", apiaddress, version, topicID)
+
+	if c.debug {
+		log.Printf("토픽 활성 여부 요청 URL: %s", url)
+	}
+
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return false, fmt.Errorf("토픽 활성 여부 API 요청 실패: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 응답 상태 코드 확인
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("토픽 활성 여부 API 응답 오류: %d %s", resp.StatusCode, resp.Status)
+	}
+
+	var activeResp struct {
+		IsActive bool `json:"is_active"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&activeResp); err != nil {
+		return false, fmt.Errorf("토픽 활성 여부 JSON 디코딩 실패: %w", err)
+	}
+
+	return activeResp.IsActive, nil
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// API 오류 응답
+// 모든 오류는 {"status": "error", "error": {"code": ..., "message": ...}} 형태의 JSON으로 응답합니다
+// 클라이언트는 메시지 대신 code로 분기합니다
+
+// ErrorCode는 오류 종류를 나타내는 기계 판독용 코드입니다
+type ErrorCode string
+
+// 오류 코드
+const (
+	ErrCodeInvalidParameter    ErrorCode = "INVALID_PARAMETER"     // 400: 파라미터 형식이 잘못됨
+	ErrCodeMissingParameter    ErrorCode = "MISSING_PARAMETER"     // 400: 필수 파라미터 누락
+	ErrCodeInvalidRequestBody  ErrorCode = "INVALID_REQUEST_BODY"  // 400: 요청 본문을 읽거나 파싱할 수 없음
+	ErrCodeUnauthorized        ErrorCode = "UNAUTHORIZED"          // 401: 관리자 토큰 불일치
+	ErrCodeAdminDisabled       ErrorCode = "ADMIN_DISABLED"        // 403: 관리자 토큰이 설정되지 않아 관리자 API를 사용할 수 없음
+	ErrCodeNotFound            ErrorCode = "NOT_FOUND"             // 404: 경로 또는 리소스 없음
+	ErrCodeTopicNotFound       ErrorCode = "TOPIC_NOT_FOUND"       // 404: 토픽의 스냅샷이 없음
+	ErrCodeHeightNotFound      ErrorCode = "HEIGHT_NOT_FOUND"      // 404: 토픽은 있지만 해당 블록 높이의 스냅샷이 없음
+	ErrCodeCompetitionNotFound ErrorCode = "COMPETITION_NOT_FOUND" // 404: 경쟁이 없거나 토픽이 연결되지 않음
+	ErrCodeWorkerNotFound      ErrorCode = "WORKER_NOT_FOUND"      // 404: 워커 주소의 기록이 없음
+	ErrCodeMethodNotAllowed    ErrorCode = "METHOD_NOT_ALLOWED"    // 405
+	ErrCodeUpgradeRequired     ErrorCode = "UPGRADE_REQUIRED"      // 400/426: WebSocket 경로에 업그레이드 요청이 아님
+	ErrCodeInvalidQuery        ErrorCode = "INVALID_QUERY"         // GraphQL: 쿼리 구문 또는 스키마 검증 오류
+	ErrCodeQueryTooComplex     ErrorCode = "QUERY_TOO_COMPLEX"     // GraphQL: 쿼리 깊이 또는 복잡도 한도 초과
+	ErrCodeInternal            ErrorCode = "INTERNAL_ERROR"        // 500
+	ErrCodeUpstreamUnavailable ErrorCode = "UPSTREAM_UNAVAILABLE"  // 502/503: Allora API에서 데이터를 가져오지 못함
+	ErrCodeNotConfigured       ErrorCode = "NOT_CONFIGURED"        // 503: 기능이 설정되지 않음
+	ErrCodeMonitorNotRunning   ErrorCode = "MONITOR_NOT_RUNNING"   // 503: 수집기가 실행 중이 아님
+	ErrCodeNotLeader           ErrorCode = "NOT_LEADER"            // 503: 리더가 아닌 레플리카는 이벤트를 발행하지 않음 (X-Leader-ID 헤더로 리더 안내)
+)
+
+// errorCodes는 문서화할 오류 코드 목록입니다
+var errorCodes = []ErrorCode{
+	ErrCodeInvalidParameter, ErrCodeMissingParameter, ErrCodeInvalidRequestBody, ErrCodeUnauthorized, ErrCodeAdminDisabled,
+	ErrCodeNotFound, ErrCodeTopicNotFound, ErrCodeHeightNotFound, ErrCodeCompetitionNotFound, ErrCodeWorkerNotFound,
+	ErrCodeMethodNotAllowed, ErrCodeUpgradeRequired, ErrCodeInvalidQuery, ErrCodeQueryTooComplex, ErrCodeInternal, ErrCodeUpstreamUnavailable, ErrCodeNotConfigured, ErrCodeMonitorNotRunning, ErrCodeNotLeader,
+}
+
+// openAPISchema는 오류 코드의 스키마를 반환합니다
+func (ErrorCode) openAPISchema() *jsonSchema {
+	enum := make([]string, 0, len(errorCodes))
+	for _, code := range errorCodes {
+		enum = append(enum, string(code))
+	}
+	return &jsonSchema{Type: "string", Enum: enum}
+}
+
+// APIError는 오류 코드와 사람이 읽는 메시지입니다
+type APIError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// ErrorResponse는 오류 응답 본문입니다
+type ErrorResponse struct {
+	Status string   `json:"status"` // 항상 "error"
+	Error  APIError `json:"error"`
+}
+
+// writeJSON은 상태 코드와 함께 JSON 응답을 보냅니다
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("응답 인코딩 실패: %v", err)
+	}
+}
+
+// writeError는 오류 응답을 보냅니다
+func writeError(w http.ResponseWriter, status int, code ErrorCode, message string) {
+	writeJSON(w, status, ErrorResponse{Status: "error", Error: APIError{Code: code, Message: message}})
+}
+
+// methodNotAllowed는 경로는 있지만 메서드가 허용되지 않은 요청에 405로 응답하는 핸들러입니다
+func methodNotAllowed(allowed []string) http.HandlerFunc {
+	var methods []string
+	for _, method := range allowed {
+		methods = append(methods, method)
+		if method == http.MethodGet {
+			methods = append(methods, http.MethodHead)
+		}
+	}
+	allow := strings.Join(methods, ", ")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		writeError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "Method not allowed. Allowed: "+allow)
+	}
+}
+
+// notFound는 등록되지 않은 API 경로에 404로 응답하는 핸들러입니다
+func notFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, ErrCodeNotFound, "Unknown API path: "+r.URL.Path)
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// decodeErrorResponse는 오류 응답의 봉투 형식과 콘텐츠 타입을 확인하고 본문을 반환합니다
+func decodeErrorResponse(t *testing.T, recorder *httptest.ResponseRecorder) ErrorResponse {
+	t.Helper()
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		t.Errorf("오류 응답 콘텐츠 타입 = %q, want application/json", contentType)
+	}
+
+	// 최상위 필드는 status와 error뿐
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("오류 응답 파싱 실패: %v (%s)", err, recorder.Body.String())
+	}
+	if len(envelope) != 2 || envelope["status"] == nil || envelope["error"] == nil {
+		t.Errorf("오류 응답 필드 = %s, want status와 error", recorder.Body.String())
+	}
+
+	var response ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("오류 응답 파싱 실패: %v", err)
+	}
+	if response.Status != "error" || response.Error.Message == "" {
+		t.Errorf("오류 응답 = %+v, want status \"error\"와 메시지", response)
+	}
+	return response
+}
+
+func TestWriteErrorEnvelope(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writeError(recorder, http.StatusBadRequest, ErrCodeInvalidParameter, "bad topic_id")
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("상태 코드 = %d, want 400", recorder.Code)
+	}
+	response := decodeErrorResponse(t, recorder)
+	if response.Error != (APIError{Code: ErrCodeInvalidParameter, Message: "bad topic_id"}) {
+		t.Errorf("오류 = %+v", response.Error)
+	}
+}
+
+func TestMethodNotAllowedAllowHeader(t *testing.T) {
+	tests := []struct {
+		allowed []string
+		want    string
+	}{
+		{[]string{http.MethodGet}, "GET, HEAD"},
+		{[]string{http.MethodGet, http.MethodPost}, "GET, HEAD, POST"},
+		{[]string{http.MethodPost}, "POST"},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		methodNotAllowed(tt.allowed)(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/topics", nil))
+		if recorder.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%v: 상태 코드 = %d, want 405", tt.allowed, recorder.Code)
+		}
+		if allow := recorder.Header().Get("Allow"); allow != tt.want {
+			t.Errorf("%v: Allow = %q, want %q", tt.allowed, allow, tt.want)
+		}
+		if response := decodeErrorResponse(t, recorder); response.Error.Code != ErrCodeMethodNotAllowed {
+			t.Errorf("%v: 오류 코드 = %s, want %s", tt.allowed, response.Error.Code, ErrCodeMethodNotAllowed)
+		}
+	}
+}
+
+func TestAPIErrorResponses(t *testing.T) {
+	env := newConformanceEnv(t)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+		code   ErrorCode
+	}{
+		{"없는 토픽의 최신 스냅샷", http.MethodGet, "/api/v1/topics/999/inferences/latest", http.StatusNotFound, ErrCodeTopicNotFound},
+		{"없는 블록 높이", http.MethodGet, "/api/v1/topics/1/inferences/1", http.StatusNotFound, ErrCodeHeightNotFound},
+		{"없는 토픽의 통계", http.MethodGet, "/api/v1/topics/999/stats", http.StatusNotFound, ErrCodeTopicNotFound},
+		{"없는 토픽의 블록 높이", http.MethodGet, "/api/v1/topics/999/heights", http.StatusNotFound, ErrCodeTopicNotFound},
+		{"숫자가 아닌 토픽 ID", http.MethodGet, "/api/v1/topics/abc/stats", http.StatusBadRequest, ErrCodeInvalidParameter},
+		{"이전 경로의 필수 파라미터 누락", http.MethodGet, "/api/topics/stats", http.StatusBadRequest, ErrCodeMissingParameter},
+		{"잘못된 계층", http.MethodGet, "/api/v1/topics/1/series?tier=weekly", http.StatusBadRequest, ErrCodeInvalidParameter},
+		{"없는 경쟁", http.MethodGet, "/api/v1/competitions/999", http.StatusNotFound, ErrCodeCompetitionNotFound},
+		{"없는 경쟁의 순위 변동", http.MethodGet, "/api/v1/competitions/999/leaderboard/movers", http.StatusNotFound, ErrCodeCompetitionNotFound},
+		{"경쟁이 없는 토픽", http.MethodGet, "/api/v1/topics/999/competition", http.StatusNotFound, ErrCodeCompetitionNotFound},
+		{"없는 워커", http.MethodGet, "/api/v1/workers/allo1nobody", http.StatusNotFound, ErrCodeWorkerNotFound},
+		{"허용되지 않은 메서드", http.MethodDelete, "/api/v1/topics", http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed},
+		{"이전 경로의 허용되지 않은 메서드", http.MethodPost, "/api/competitions", http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed},
+		{"등록되지 않은 경로", http.MethodGet, "/api/v1/unknown", http.StatusNotFound, ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serveTest(env.mux, tt.method, tt.target, "")
+			if recorder.Code != tt.status {
+				t.Fatalf("%s %s 상태 코드 = %d, want %d: %s", tt.method, tt.target, recorder.Code, tt.status, recorder.Body.String())
+			}
+			if response := decodeErrorResponse(t, recorder); response.Error.Code != tt.code {
+				t.Errorf("오류 코드 = %s, want %s", response.Error.Code, tt.code)
+			}
+
+			// 405는 허용된 메서드를 Allow 헤더로 알림 (GET이면 HEAD 포함)
+			allow := recorder.Header().Get("Allow")
+			if tt.status == http.StatusMethodNotAllowed {
+				if !strings.Contains(allow, http.MethodGet) || !strings.Contains(allow, http.MethodHead) || strings.Contains(allow, tt.method) {
+					t.Errorf("Allow = %q, want GET과 HEAD 포함, %s 제외", allow, tt.method)
+				}
+			} else if allow != "" {
+				t.Errorf("%d 응답에 Allow 헤더 %q", tt.status, allow)
+			}
+		})
+	}
+}
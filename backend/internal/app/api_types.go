@@ -0,0 +1,239 @@
+package app
+
+// API 응답 타입
+// 핸들러가 반환하는 JSON 본문의 형태이며, OpenAPI 문서(/api/openapi.json)의 응답 스키마는 이 타입에서 생성됩니다
+// 핸들러의 실제 응답이 스키마와 다르면 API 응답 테스트(openapi_test.go)가 실패합니다
+
+// HealthResponse는 서버와 수집기 상태입니다
+type HealthResponse struct {
+	Status        string       `json:"status"`
+	MonitorStatus string       `json:"monitor_status"` // running 또는 stopped
+	Leader        LeaderStatus `json:"leader"`
+	Timestamp     string       `json:"timestamp"`
+}
+
+// MessageResponse는 요청 처리 결과 메시지 응답입니다
+type MessageResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// PageInfo는 목록 응답의 페이지 메타데이터입니다
+// 다음 페이지가 있으면 next_cursor를 cursor 파라미터로 전달하며, 같은 URL이 Link 헤더(rel="next")에도 포함됩니다
+type PageInfo struct {
+	Limit      int     `json:"limit"` // 0이면 전체 조회
+	HasMore    bool    `json:"has_more"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// InferenceSnapshot은 복원한 토픽 스냅샷입니다
+// 수집기가 저장한 필드를 그대로 포함하므로 스키마에는 공통 필드만 정의하고 나머지 필드는 허용합니다
+type InferenceSnapshot map[string]interface{}
+
+// openAPISchema는 InferenceSnapshot의 스키마를 반환합니다
+func (InferenceSnapshot) openAPISchema() *jsonSchema {
+	str := func() *jsonSchema { return &jsonSchema{Type: "string"} }
+	nullableStr := func() *jsonSchema { return &jsonSchema{Type: "string", Nullable: true} }
+	return &jsonSchema{
+		Type:        "object",
+		Description: "복원한 토픽 스냅샷 (수집한 필드를 그대로 포함)",
+		Properties: map[string]*jsonSchema{
+			"topic_id":               str(),
+			"timestamp":              str(),
+			"inference_block_height": str(),
+			"loss_block_height":      str(),
+			"snapshot_hash":          str(),
+			"prev_height":            nullableStr(),
+			"next_height":            nullableStr(),
+			"network_inferences": {
+				Type:     "object",
+				Nullable: true,
+				Properties: map[string]*jsonSchema{
+					"combined_value":  nullableStr(),
+					"naive_value":     nullableStr(),
+					"synthesis_value": {Type: "array", Nullable: true, Items: &jsonSchema{Type: "object"}},
+				},
+			},
+		},
+		Required: []string{"topic_id", "timestamp", "inference_block_height"},
+	}
+}
+
+// HeightPagination은 스냅샷 조회 응답의 이전/다음 블록 높이입니다
+type HeightPagination struct {
+	PrevHeight    *string `json:"prev_height"`
+	NextHeight    *string `json:"next_height"`
+	CurrentHeight string  `json:"current_height"`
+}
+
+// TopicInferenceResponse는 토픽 스냅샷 하나의 응답입니다
+type TopicInferenceResponse struct {
+	Status     string            `json:"status"`
+	Data       InferenceSnapshot `json:"data"`
+	Pagination HeightPagination  `json:"pagination"`
+}
+
+// AllTopicInferencesResponse는 활성 토픽별 최신 스냅샷 응답입니다
+type AllTopicInferencesResponse struct {
+	Status     string                       `json:"status"`
+	Count      int                          `json:"count"`
+	Inferences map[string]InferenceSnapshot `json:"inferences"` // 토픽 ID별 최신 스냅샷
+}
+
+// TopicInferencesResponse는 기간 내 토픽 스냅샷 응답입니다
+// 원본 계층(raw)은 inferences에 스냅샷을, 롤업 계층(hourly/daily)은 series에 구간별 지표를 담습니다
+type TopicInferencesResponse struct {
+	Status     string              `json:"status"`
+	TopicID    string              `json:"topic_id"`
+	Tier       string              `json:"tier"`
+	Count      int                 `json:"count"`
+	Inferences []InferenceSnapshot `json:"inferences"`
+	Series     []TopicSeriesPoint  `json:"series,omitempty"`
+	Page       PageInfo            `json:"page"`
+}
+
+// ActiveTopicsResponse는 활성 토픽 목록 응답입니다
+type ActiveTopicsResponse struct {
+	Status       string   `json:"status"`
+	ActiveTopics []string `json:"active_topics"`
+	Count        int      `json:"count"`
+}
+
+// TopicBlockHeights는 토픽의 블록 높이 목록 한 페이지입니다
+type TopicBlockHeights struct {
+	TopicID      string   `json:"topic_id"`
+	TotalCount   int      `json:"total_count"`
+	Limit        int      `json:"limit"`
+	Offset       int      `json:"offset"` // 커서로 조회하면 0
+	Heights      []string `json:"heights"`
+	HeightsCount int      `json:"heights_count"`
+}
+
+// TopicBlockHeightsResponse는 블록 높이 목록 응답입니다
+type TopicBlockHeightsResponse struct {
+	Status string            `json:"status"`
+	Data   TopicBlockHeights `json:"data"`
+	Page   PageInfo          `json:"page"`
+}
+
+// TopicStats는 토픽 하나의 저장 통계입니다
+type TopicStats struct {
+	TopicID         string  `json:"topic_id"`
+	RecordCount     int     `json:"record_count"`
+	OldestTimestamp string  `json:"oldest_timestamp"`
+	NewestTimestamp string  `json:"newest_timestamp"`
+	TotalSizeBytes  int64   `json:"total_size_bytes"`
+	TotalSizeMB     float64 `json:"total_size_mb"`
+}
+
+// TopicSeriesResponse는 토픽 지표 시계열 응답입니다
+type TopicSeriesResponse struct {
+	Status string       `json:"status"`
+	Count  int          `json:"count"`
+	Series *TopicSeries `json:"series"`
+}
+
+// CompetitionStoreStats는 경쟁 원본 이력의 저장 통계입니다
+type CompetitionStoreStats struct {
+	RecordCount     int     `json:"record_count"`
+	OldestTimestamp string  `json:"oldest_timestamp"`
+	NewestTimestamp string  `json:"newest_timestamp"`
+	TotalSizeBytes  int64   `json:"total_size_bytes"`
+	TotalSizeMB     float64 `json:"total_size_mb"`
+}
+
+// TopicStoreStats는 모든 토픽 스냅샷의 저장 통계입니다
+type TopicStoreStats struct {
+	RecordCount      int     `json:"record_count"`
+	UniqueTopicCount int     `json:"unique_topic_count"`
+	OldestTimestamp  string  `json:"oldest_timestamp"`
+	NewestTimestamp  string  `json:"newest_timestamp"`
+	TotalSizeBytes   int64   `json:"total_size_bytes"`
+	TotalSizeMB      float64 `json:"total_size_mb"`
+	ShardCount       int     `json:"shard_count,omitempty"` // 토픽 샤드를 사용할 때만 포함
+}
+
+// DatabaseStats는 데이터베이스 전체 통계입니다
+type DatabaseStats struct {
+	Competitions    CompetitionStoreStats `json:"competitions"`
+	TopicInferences TopicStoreStats       `json:"topic_inferences"`
+	SnapshotBlobs   SnapshotBlobStats     `json:"snapshot_blobs"`
+	SnapshotCache   SnapshotCacheStats    `json:"snapshot_cache"`
+}
+
+// CompetitionResponse는 경쟁 하나의 응답입니다
+type CompetitionResponse struct {
+	Status      string         `json:"status"`
+	Competition *CompetitionV2 `json:"competition"`
+}
+
+// CompetitionChangesResponse는 경쟁별 필드 변경 이력 응답입니다
+type CompetitionChangesResponse struct {
+	Status    string                         `json:"status"`
+	Count     int                            `json:"count"`
+	Timelines map[string][]CompetitionChange `json:"timelines"` // 경쟁 ID별 변경 이력
+}
+
+// CompetitionLifecycleResponse는 경쟁별 라이프사이클 전이 기록 응답입니다
+type CompetitionLifecycleResponse struct {
+	Status    string                           `json:"status"`
+	Count     int                              `json:"count"`
+	Timelines map[string][]LifecycleTransition `json:"timelines"` // 경쟁 ID별 전이 기록
+}
+
+// LeaderboardHistoryResponse는 참가자 리더보드 시계열 응답입니다
+type LeaderboardHistoryResponse struct {
+	Status  string              `json:"status"`
+	Count   int                 `json:"count"`
+	History *LeaderboardHistory `json:"history"`
+	Page    PageInfo            `json:"page"`
+}
+
+// LeaderboardMoversResponse는 리더보드 순위 변동 응답입니다
+type LeaderboardMoversResponse struct {
+	Status string             `json:"status"`
+	Window string             `json:"window"`
+	Movers *LeaderboardMovers `json:"movers"`
+}
+
+// WorkerResponse는 워커 프로필 응답입니다
+type WorkerResponse struct {
+	Status string         `json:"status"`
+	Worker *WorkerProfile `json:"worker"`
+}
+
+// SearchResponse는 전문 검색 응답입니다
+type SearchResponse struct {
+	Status  string         `json:"status"`
+	Query   string         `json:"query"`
+	Count   int            `json:"count"`
+	Results []SearchResult `json:"results"`
+}
+
+// BackupListResponse는 백업 목록 응답입니다
+type BackupListResponse struct {
+	Status         string       `json:"status"`
+	Count          int          `json:"count"`
+	TotalSizeBytes int64        `json:"total_size_bytes"`
+	Backups        []BackupInfo `json:"backups"`
+}
+
+// BackupCreateResponse는 백업 생성 응답입니다
+type BackupCreateResponse struct {
+	Status string      `json:"status"`
+	Backup *BackupInfo `json:"backup"`
+}
+
+// IntegrityResponse는 무결성 검사 보고서 응답입니다
+type IntegrityResponse struct {
+	Status     string           `json:"status"`
+	OK         bool             `json:"ok"`
+	Unresolved int              `json:"unresolved"`
+	Report     *IntegrityReport `json:"report"`
+}
+
+// ArchiveManifestResponse는 아카이브 매니페스트 응답입니다
+type ArchiveManifestResponse struct {
+	Status   string           `json:"status"`
+	Manifest *ArchiveManifest `json:"manifest"`
+}
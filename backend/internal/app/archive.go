@@ -0,0 +1,602 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// 아카이브 대상 테이블 이름 (디렉토리 이름으로도 사용)
+const (
+	archiveTableTopicInferences = "topic_inferences"
+	archiveTableWorkerValues    = "worker_values"
+	archiveTableLeaderboard     = "leaderboard_entries"
+
+	archiveManifestName    = "manifest.json"
+	archiveManifestVersion = 1
+)
+
+// archivedSnapshot은 아카이브된 토픽 스냅샷 행입니다 (델타가 아닌 전체 JSON)
+type archivedSnapshot struct {
+	TopicID              string `parquet:"topic_id,dict"`
+	Timestamp            string `parquet:"timestamp"`
+	InferenceBlockHeight string `parquet:"inference_block_height"`
+	LossBlockHeight      string `parquet:"loss_block_height"`
+	Data                 string `parquet:"data"`
+}
+
+// archivedWorkerValue는 스냅샷에서 펼친 워커별 값입니다
+type archivedWorkerValue struct {
+	TopicID              string `parquet:"topic_id,dict"`
+	Timestamp            string `parquet:"timestamp"`
+	InferenceBlockHeight string `parquet:"inference_block_height"`
+	Worker               string `parquet:"worker,dict"`
+	InfererValue         string `parquet:"inferer_value,optional"`
+	OneOutInfererValue   string `parquet:"one_out_inferer_value,optional"`
+	Weight               string `parquet:"weight,optional"`
+}
+
+// archivedLeaderboardEntry는 아카이브된 리더보드 행입니다
+type archivedLeaderboardEntry struct {
+	TopicID              string  `parquet:"topic_id,dict"`
+	InferenceBlockHeight string  `parquet:"inference_block_height"`
+	Timestamp            string  `parquet:"timestamp"`
+	CosmosAddress        string  `parquet:"cosmos_address,dict"`
+	Username             string  `parquet:"username,optional"`
+	FirstName            string  `parquet:"first_name,optional"`
+	LastName             string  `parquet:"last_name,optional"`
+	Rank                 string  `parquet:"rank,optional"`
+	Points               float64 `parquet:"points"`
+	Score                float64 `parquet:"score"`
+	Loss                 float64 `parquet:"loss"`
+	IsActive             bool    `parquet:"is_active"`
+}
+
+// ArchiveFile은 매니페스트에 기록되는 Parquet 파일 하나의 정보입니다
+type ArchiveFile struct {
+	Table        string   `json:"table"`
+	Date         string   `json:"date"` // 파티션 날짜 (UTC, YYYY-MM-DD)
+	Path         string   `json:"path"` // 아카이브 디렉토리 기준 상대 경로
+	Rows         int      `json:"rows"`
+	MinTimestamp string   `json:"min_timestamp"`
+	MaxTimestamp string   `json:"max_timestamp"`
+	TopicIDs     []string `json:"topic_ids"`
+	SizeBytes    int64    `json:"size_bytes"`
+	SHA256       string   `json:"sha256"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+// ArchiveManifest는 아카이브 디렉토리의 파일 목록입니다
+type ArchiveManifest struct {
+	Version   int           `json:"version"`
+	UpdatedAt string        `json:"updated_at"`
+	Files     []ArchiveFile `json:"files"`
+}
+
+// Archiver는 정리 대상 데이터를 날짜별로 나눈 Parquet 파일로 보관합니다
+// 파일 구조: <dir>/<table>/date=YYYY-MM-DD/part-<나노초>.parquet
+type Archiver struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewArchiver는 새로운 아카이브 저장소를 생성합니다
+func NewArchiver(dir string) *Archiver {
+	return &Archiver{dir: dir}
+}
+
+// Dir은 아카이브 디렉토리 경로를 반환합니다
+func (a *Archiver) Dir() string {
+	return a.dir
+}
+
+// LoadManifest는 매니페스트를 읽습니다 (없으면 빈 매니페스트)
+func (a *Archiver) LoadManifest() (*ArchiveManifest, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.loadManifest()
+}
+
+func (a *Archiver) loadManifest() (*ArchiveManifest, error) {
+	data, err := os.ReadFile(filepath.Join(a.dir, archiveManifestName))
+	if os.IsNotExist(err) {
+		return &ArchiveManifest{Version: archiveManifestVersion, Files: []ArchiveFile{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("아카이브 매니페스트 읽기 실패: %w", err)
+	}
+
+	var manifest ArchiveManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("아카이브 매니페스트 파싱 실패: %w", err)
+	}
+	return &manifest, nil
+}
+
+// saveManifest는 임시 파일에 쓴 뒤 이름을 바꿔 매니페스트를 원자적으로 갱신합니다
+func (a *Archiver) saveManifest(manifest *ArchiveManifest) error {
+	manifest.Version = archiveManifestVersion
+	manifest.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("아카이브 매니페스트 마샬링 실패: %w", err)
+	}
+
+	path := filepath.Join(a.dir, archiveManifestName)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("아카이브 매니페스트 쓰기 실패: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("아카이브 매니페스트 교체 실패: %w", err)
+	}
+	return nil
+}
+
+// archivePartition은 같은 날짜 파티션에 쓸 행 묶음입니다
+type archivePartition struct {
+	date     string
+	rows     interface{} // []archivedSnapshot, []archivedWorkerValue 또는 []archivedLeaderboardEntry
+	count    int
+	minTime  string
+	maxTime  string
+	topicIDs map[string]bool
+}
+
+// partitionByDate는 타임스탬프의 UTC 날짜별로 행 위치를 묶습니다
+func partitionByDate(count int, timestampOf func(int) string) map[string][]int {
+	partitions := make(map[string][]int)
+	for i := 0; i < count; i++ {
+		date := "unknown"
+		if t, err := time.Parse(time.RFC3339, timestampOf(i)); err == nil {
+			date = t.UTC().Format("2006-01-02")
+		}
+		partitions[date] = append(partitions[date], i)
+	}
+	return partitions
+}
+
+// writeParquet은 행 묶음의 타입에 맞게 Parquet 파일을 씁니다
+func writeParquet(path string, rows interface{}) error {
+	options := []parquet.WriterOption{parquet.Compression(&parquet.Zstd)}
+
+	switch typed := rows.(type) {
+	case []archivedSnapshot:
+		return parquet.WriteFile(path, typed, options...)
+	case []archivedWorkerValue:
+		return parquet.WriteFile(path, typed, options...)
+	case []archivedLeaderboardEntry:
+		return parquet.WriteFile(path, typed, options...)
+	}
+	return fmt.Errorf("지원하지 않는 아카이브 행 타입: %T", rows)
+}
+
+// writePartitions는 파티션별 Parquet 파일을 쓰고 매니페스트 항목을 반환합니다
+func (a *Archiver) writePartitions(table string, partitions []archivePartition) ([]ArchiveFile, error) {
+	var files []ArchiveFile
+
+	for _, partition := range partitions {
+		if partition.count == 0 {
+			continue
+		}
+
+		relDir := filepath.Join(table, "date="+partition.date)
+		if err := os.MkdirAll(filepath.Join(a.dir, relDir), 0755); err != nil {
+			return files, fmt.Errorf("아카이브 디렉토리 생성 실패: %w", err)
+		}
+
+		relPath := filepath.Join(relDir, "part-"+strconv.FormatInt(time.Now().UnixNano(), 10)+".parquet")
+		path := filepath.Join(a.dir, relPath)
+
+		if err := writeParquet(path+".tmp", partition.rows); err != nil {
+			os.Remove(path + ".tmp")
+			return files, fmt.Errorf("Parquet 파일 쓰기 실패 (%s): %w", relPath, err)
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			os.Remove(path + ".tmp")
+			return files, fmt.Errorf("Parquet 파일 이름 변경 실패: %w", err)
+		}
+
+		checksum, size, err := fileSHA256(path)
+		if err != nil {
+			return files, err
+		}
+
+		topicIDs := make([]string, 0, len(partition.topicIDs))
+		for topicID := range partition.topicIDs {
+			topicIDs = append(topicIDs, topicID)
+		}
+		sort.Strings(topicIDs)
+
+		files = append(files, ArchiveFile{
+			Table:        table,
+			Date:         partition.date,
+			Path:         filepath.ToSlash(relPath),
+			Rows:         partition.count,
+			MinTimestamp: partition.minTime,
+			MaxTimestamp: partition.maxTime,
+			TopicIDs:     topicIDs,
+			SizeBytes:    size,
+			SHA256:       checksum,
+			CreatedAt:    time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+
+	return files, nil
+}
+
+// archiveBatch는 한 번의 정리 작업에서 아카이브할 데이터입니다
+type archiveBatch struct {
+	snapshots   []archivedSnapshot
+	workers     []archivedWorkerValue
+	leaderboard []archivedLeaderboardEntry
+}
+
+// isEmpty는 아카이브할 데이터가 없는지 확인합니다
+func (b *archiveBatch) isEmpty() bool {
+	return len(b.snapshots) == 0 && len(b.workers) == 0 && len(b.leaderboard) == 0
+}
+
+// write는 배치를 날짜별 Parquet 파일로 쓰고 매니페스트에 추가합니다
+// 파일 쓰기에 실패하면 이미 쓴 파일을 지우고 오류를 반환하므로, 호출자는 데이터를 삭제하지 않아야 합니다
+func (a *Archiver) write(batch *archiveBatch) (int, error) {
+	if batch.isEmpty() {
+		return 0, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	manifest, err := a.loadManifest()
+	if err != nil {
+		return 0, err
+	}
+
+	var written []ArchiveFile
+	cleanup := func() {
+		for _, file := range written {
+			os.Remove(filepath.Join(a.dir, filepath.FromSlash(file.Path)))
+		}
+	}
+
+	tables := []struct {
+		name       string
+		partitions []archivePartition
+	}{
+		{archiveTableTopicInferences, snapshotPartitions(batch.snapshots)},
+		{archiveTableWorkerValues, workerPartitions(batch.workers)},
+		{archiveTableLeaderboard, leaderboardPartitions(batch.leaderboard)},
+	}
+
+	for _, table := range tables {
+		files, err := a.writePartitions(table.name, table.partitions)
+		written = append(written, files...)
+		if err != nil {
+			cleanup()
+			return 0, err
+		}
+	}
+
+	manifest.Files = append(manifest.Files, written...)
+	if err := a.saveManifest(manifest); err != nil {
+		cleanup()
+		return 0, err
+	}
+
+	return len(written), nil
+}
+
+// newArchivePartition은 행 위치 목록으로 파티션 정보를 계산합니다
+func newArchivePartition(date string, indexes []int, timestampOf, topicOf func(int) string) archivePartition {
+	partition := archivePartition{date: date, count: len(indexes), topicIDs: make(map[string]bool)}
+	for _, i := range indexes {
+		timestamp := timestampOf(i)
+		if partition.minTime == "" || timestamp < partition.minTime {
+			partition.minTime = timestamp
+		}
+		if timestamp > partition.maxTime {
+			partition.maxTime = timestamp
+		}
+		partition.topicIDs[topicOf(i)] = true
+	}
+	return partition
+}
+
+func snapshotPartitions(rows []archivedSnapshot) []archivePartition {
+	timestampOf := func(i int) string { return rows[i].Timestamp }
+	topicOf := func(i int) string { return rows[i].TopicID }
+
+	var partitions []archivePartition
+	for date, indexes := range partitionByDate(len(rows), timestampOf) {
+		partition := newArchivePartition(date, indexes, timestampOf, topicOf)
+		selected := make([]archivedSnapshot, 0, len(indexes))
+		for _, i := range indexes {
+			selected = append(selected, rows[i])
+		}
+		partition.rows = selected
+		partitions = append(partitions, partition)
+	}
+	return partitions
+}
+
+func workerPartitions(rows []archivedWorkerValue) []archivePartition {
+	timestampOf := func(i int) string { return rows[i].Timestamp }
+	topicOf := func(i int) string { return rows[i].TopicID }
+
+	var partitions []archivePartition
+	for date, indexes := range partitionByDate(len(rows), timestampOf) {
+		partition := newArchivePartition(date, indexes, timestampOf, topicOf)
+		selected := make([]archivedWorkerValue, 0, len(indexes))
+		for _, i := range indexes {
+			selected = append(selected, rows[i])
+		}
+		partition.rows = selected
+		partitions = append(partitions, partition)
+	}
+	return partitions
+}
+
+func leaderboardPartitions(rows []archivedLeaderboardEntry) []archivePartition {
+	timestampOf := func(i int) string { return rows[i].Timestamp }
+	topicOf := func(i int) string { return rows[i].TopicID }
+
+	var partitions []archivePartition
+	for date, indexes := range partitionByDate(len(rows), timestampOf) {
+		partition := newArchivePartition(date, indexes, timestampOf, topicOf)
+		selected := make([]archivedLeaderboardEntry, 0, len(indexes))
+		for _, i := range indexes {
+			selected = append(selected, rows[i])
+		}
+		partition.rows = selected
+		partitions = append(partitions, partition)
+	}
+	return partitions
+}
+
+// ReadTopicSnapshots는 아카이브에서 토픽 스냅샷을 시간 범위로 읽어 타임스탬프 순으로 반환합니다
+func (a *Archiver) ReadTopicSnapshots(topicID string, start, end time.Time) ([]archivedSnapshot, error) {
+	manifest, err := a.LoadManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []archivedSnapshot
+	for _, file := range manifest.Files {
+		if file.Table != archiveTableTopicInferences || !containsString(file.TopicIDs, topicID) {
+			continue
+		}
+		if !archiveFileOverlaps(file, start, end) {
+			continue
+		}
+
+		rows, err := parquet.ReadFile[archivedSnapshot](filepath.Join(a.dir, filepath.FromSlash(file.Path)))
+		if err != nil {
+			return nil, fmt.Errorf("Parquet 파일 읽기 실패 (%s): %w", file.Path, err)
+		}
+
+		for _, row := range rows {
+			if row.TopicID != topicID {
+				continue
+			}
+			timestamp, err := time.Parse(time.RFC3339, row.Timestamp)
+			if err != nil || timestamp.Before(start) || timestamp.After(end) {
+				continue
+			}
+			results = append(results, row)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp < results[j].Timestamp
+	})
+
+	return results, nil
+}
+
+// archiveFileOverlaps는 파일의 타임스탬프 범위가 조회 범위와 겹치는지 확인합니다
+func archiveFileOverlaps(file ArchiveFile, start, end time.Time) bool {
+	minTime, err1 := time.Parse(time.RFC3339, file.MinTimestamp)
+	maxTime, err2 := time.Parse(time.RFC3339, file.MaxTimestamp)
+	if err1 != nil || err2 != nil {
+		return true
+	}
+	return !maxTime.Before(start) && !minTime.After(end)
+}
+
+// containsString은 슬라이스에 값이 있는지 확인합니다
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// SetArchiver는 정리 전에 데이터를 보관할 아카이브 저장소를 설정합니다
+func (d *Database) SetArchiver(archiver *Archiver) {
+	d.archiver = archiver
+}
+
+// collectArchiveBatch는 삭제 조건에 해당하는 토픽 스냅샷(각 저장소)과 리더보드 행을 아카이브용으로 읽습니다
+// 조건은 topic_id와 timestamp 컬럼만 사용해야 합니다 (두 테이블에 같은 조건을 적용)
+func (d *Database) collectArchiveBatch(stores []*topicStore, deleteCondition string, args ...interface{}) (*archiveBatch, error) {
+	batch := &archiveBatch{}
+
+	for _, store := range stores {
+		if err := collectArchiveSnapshots(batch, store.reader, deleteCondition, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	leaderboardRows, err := d.reader.Query(
+		`SELECT topic_id, inference_block_height, timestamp, cosmos_address,
+			COALESCE(username, ''), COALESCE(first_name, ''), COALESCE(last_name, ''), COALESCE(rank, ''),
+			COALESCE(points, 0), COALESCE(score, 0), COALESCE(loss, 0), COALESCE(is_active, 0)
+		FROM leaderboard_entries WHERE `+deleteCondition,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("아카이브 대상 리더보드 조회 실패: %w", err)
+	}
+	defer leaderboardRows.Close()
+
+	for leaderboardRows.Next() {
+		var entry archivedLeaderboardEntry
+		if err := leaderboardRows.Scan(&entry.TopicID, &entry.InferenceBlockHeight, &entry.Timestamp, &entry.CosmosAddress,
+			&entry.Username, &entry.FirstName, &entry.LastName, &entry.Rank,
+			&entry.Points, &entry.Score, &entry.Loss, &entry.IsActive); err != nil {
+			return nil, fmt.Errorf("리더보드 스캔 실패: %w", err)
+		}
+		batch.leaderboard = append(batch.leaderboard, entry)
+	}
+	if err := leaderboardRows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	return batch, nil
+}
+
+// collectArchiveSnapshots는 저장소 하나에서 삭제 조건에 해당하는 스냅샷을 복원하여 batch에 추가합니다
+func collectArchiveSnapshots(batch *archiveBatch, q sqlQueryer, deleteCondition string, args ...interface{}) error {
+	rows, err := q.Query(
+		"SELECT "+snapshotRowColumns+", topic_id, inference_block_height, loss_block_height FROM topic_inferences WHERE "+deleteCondition+" ORDER BY id",
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("아카이브 대상 스냅샷 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	decoder := newSnapshotDecoder(q)
+	for rows.Next() {
+		var row snapshotRow
+		var snapshot archivedSnapshot
+		if err := rows.Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data,
+			&snapshot.TopicID, &snapshot.InferenceBlockHeight, &snapshot.LossBlockHeight); err != nil {
+			return fmt.Errorf("데이터 스캔 실패: %w", err)
+		}
+
+		state, err := decoder.decode(row)
+		if err != nil {
+			return fmt.Errorf("스냅샷 복원 실패 (ID=%d): %w", row.ID, err)
+		}
+		restoreSnapshotMetadata(state, row.Timestamp, snapshot.LossBlockHeight)
+		data, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("JSON 마샬링 실패: %w", err)
+		}
+
+		snapshot.Timestamp = row.Timestamp
+		snapshot.Data = string(data)
+		batch.snapshots = append(batch.snapshots, snapshot)
+		batch.workers = append(batch.workers, archivedWorkerValues(snapshot, state)...)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+	return nil
+}
+
+// archivedWorkerValues는 스냅샷의 synthesis_value에서 워커별 값을 펼칩니다
+func archivedWorkerValues(snapshot archivedSnapshot, state map[string]interface{}) []archivedWorkerValue {
+	networkInferences, ok := state["network_inferences"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	synthesisValue, _ := networkInferences["synthesis_value"].([]interface{})
+
+	values := make([]archivedWorkerValue, 0, len(synthesisValue))
+	for _, item := range synthesisValue {
+		workerData, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		worker := getStringValue(workerData, "worker", "")
+		if worker == "" {
+			continue
+		}
+		values = append(values, archivedWorkerValue{
+			TopicID:              snapshot.TopicID,
+			Timestamp:            snapshot.Timestamp,
+			InferenceBlockHeight: snapshot.InferenceBlockHeight,
+			Worker:               worker,
+			InfererValue:         getStringValue(workerData, "inferer_values", ""),
+			OneOutInfererValue:   getStringValue(workerData, "one_out_inferer_values", ""),
+			Weight:               getStringValue(workerData, "weight", ""),
+		})
+	}
+	return values
+}
+
+// getArchivedTopicInferences는 라이브 데이터베이스보다 오래된 구간을 아카이브에서 읽어 가공합니다
+// 라이브 데이터가 시작되는 시각 이후의 아카이브 행은 라이브 데이터와 겹치므로 제외합니다
+func (d *Database) getArchivedTopicInferences(topicID string, start, end time.Time) ([]map[string]interface{}, error) {
+	if d.archiver == nil {
+		return nil, nil
+	}
+
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return nil, err
+	}
+	defer store.release()
+
+	var earliestLive *string
+	if err := store.reader.QueryRow("SELECT MIN(timestamp) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&earliestLive); err != nil {
+		return nil, fmt.Errorf("라이브 데이터 시작 시각 조회 실패: %w", err)
+	}
+
+	if earliestLive != nil {
+		liveStart, err := time.Parse(time.RFC3339, *earliestLive)
+		if err == nil {
+			if !start.Before(liveStart) {
+				return nil, nil
+			}
+			if end.After(liveStart) {
+				end = liveStart.Add(-time.Nanosecond)
+			}
+		}
+	}
+
+	archived, err := d.archiver.ReadTopicSnapshots(topicID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0, len(archived))
+	for _, row := range archived {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(row.Data), &data); err != nil {
+			log.Printf("아카이브 스냅샷 파싱 실패 (토픽=%s, 높이=%s): %v", row.TopicID, row.InferenceBlockHeight, err)
+			continue
+		}
+		// 아카이브에는 해시가 없으므로 라이브 응답과 같도록 본문으로 계산
+		snapshotHash, err := snapshotContentHash(data)
+		if err != nil {
+			return nil, fmt.Errorf("아카이브 스냅샷 해시 계산 실패 (토픽=%s, 높이=%s): %w", row.TopicID, row.InferenceBlockHeight, err)
+		}
+
+		result := d.processTopicInferenceData(data)
+		result["snapshot_hash"] = snapshotHash
+		if networkInferences, ok := result["network_inferences"].(map[string]interface{}); ok {
+			delete(networkInferences, "topic_id")
+			result["network_inferences"] = networkInferences
+		}
+		results = append(results, result)
+	}
+
+	if d.debug && len(results) > 0 {
+		log.Printf("아카이브에서 토픽 %s 데이터 %d개 조회", topicID, len(results))
+	}
+
+	return results, nil
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// getTopicRange는 토픽 기간 조회 API(raw 계층)를 다음 페이지 기준을 따라 끝까지 읽어 스냅샷 목록을 반환합니다
+func getTopicRange(t *testing.T, mux http.Handler, topicID string, start, end time.Time, limit int) []InferenceSnapshot {
+	t.Helper()
+	query := url.Values{
+		"start": {start.Format(time.RFC3339)},
+		"end":   {end.Format(time.RFC3339)},
+		"tier":  {SeriesTierRaw},
+	}
+	if limit > 0 {
+		query.Set("limit", fmt.Sprint(limit))
+	}
+
+	var snapshots []InferenceSnapshot
+	for {
+		var response TopicInferencesResponse
+		getJSON(t, mux, "/api/v1/topics/"+topicID+"/inferences?"+query.Encode(), http.StatusOK, &response)
+		if limit > 0 && len(response.Inferences) > limit {
+			t.Fatalf("페이지 크기 %d의 스냅샷 %d개", limit, len(response.Inferences))
+		}
+		snapshots = append(snapshots, response.Inferences...)
+		if response.Page.NextCursor == nil {
+			return snapshots
+		}
+		query.Set("cursor", *response.Page.NextCursor)
+	}
+}
+
+func TestArchivedRangeReadBack(t *testing.T) {
+	db := newTestDatabase(t)
+	archiver := NewArchiver(filepath.Join(t.TempDir(), "archive"))
+	db.SetArchiver(archiver)
+	_, mux := newTestService(t, db)
+
+	// 날짜가 다른 오래된 스냅샷 4개와 보존 기간 안의 스냅샷 2개
+	now := time.Now().UTC().Truncate(time.Second)
+	workers := testWorkers(3)
+	var timestamps []time.Time
+	for day := 5; day >= 2; day-- {
+		timestamps = append(timestamps, now.AddDate(0, 0, -day))
+	}
+	timestamps = append(timestamps, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	for i, timestamp := range timestamps {
+		if err := db.SaveTopicInference(testSnapshot("1", 100+i*10, timestamp, workers, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start, end := now.AddDate(0, 0, -6), now
+
+	// 보관 전 전체 기간 응답
+	before := getTopicRange(t, mux, "1", start, end, 0)
+	if len(before) != len(timestamps) {
+		t.Fatalf("보관 전 스냅샷 %d개, want %d", len(before), len(timestamps))
+	}
+
+	if deleted, err := db.PruneOldTopicData("1", 24*time.Hour); err != nil || deleted != 4 {
+		t.Fatalf("정리된 스냅샷 = %d (%v), want 4", deleted, err)
+	}
+	if count := countRows(t, db, "SELECT COUNT(*) FROM topic_inferences WHERE topic_id = '1'"); count != 2 {
+		t.Fatalf("라이브 스냅샷 %d개, want 2", count)
+	}
+	manifest, err := archiver.LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshotFiles := 0
+	for _, file := range manifest.Files {
+		if file.Table == archiveTableTopicInferences {
+			snapshotFiles++
+		}
+	}
+	if snapshotFiles != 4 {
+		t.Errorf("스냅샷 아카이브 파일 %d개, want 날짜별 4개", snapshotFiles)
+	}
+
+	// 정리된 구간은 아카이브에서 읽어 라이브 구간 앞에 이어 붙이고, snapshot_hash도 보관 전과 같음
+	// (페이지 경계가 아카이브와 라이브에 걸쳐도 같음)
+	for _, limit := range []int{0, 1, 3, 4} {
+		after := getTopicRange(t, mux, "1", start, end, limit)
+		if !reflect.DeepEqual(after, before) {
+			t.Errorf("페이지 크기 %d의 보관 후 응답이 보관 전과 다릅니다\n got: %v\nwant: %v", limit, after, before)
+		}
+	}
+
+	// 아카이브 구간 일부만 조회
+	archived := getTopicRange(t, mux, "1", now.AddDate(0, 0, -4).Add(-time.Minute), now.AddDate(0, 0, -3).Add(time.Minute), 0)
+	var heights []interface{}
+	for _, snapshot := range archived {
+		heights = append(heights, snapshot["inference_block_height"])
+	}
+	if want := []interface{}{"110", "120"}; !reflect.DeepEqual(heights, want) {
+		t.Errorf("아카이브 구간 높이 = %v, want %v", heights, want)
+	}
+}
@@ -0,0 +1,448 @@
+package app
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 백업 파일 이름 규칙: allora-monitor-20060102T150405Z.db
+const (
+	backupFilePrefix     = "allora-monitor-"
+	backupFileSuffix     = ".db"
+	backupTimeLayout     = "20060102T150405Z"
+	backupChecksumSuffix = ".sha256"
+)
+
+// BackupInfo는 백업 파일 하나의 정보입니다
+type BackupInfo struct {
+	Name      string `json:"name"`
+	Path      string `json:"-"`
+	CreatedAt string `json:"created_at"`
+	SizeBytes int64  `json:"size_bytes"`
+	SHA256    string `json:"sha256"`
+}
+
+// BackupManager는 데이터베이스 온라인 백업을 주기적으로 생성하고 보관 개수를 관리합니다
+type BackupManager struct {
+	db           *Database
+	dir          string        // 백업 파일 디렉토리
+	keep         int           // 보관할 백업 개수
+	interval     time.Duration // 백업 주기
+	stopChan     chan struct{}
+	isRunning    bool
+	runningMutex sync.Mutex
+	backupMutex  sync.Mutex // 백업 생성과 정리가 동시에 실행되지 않도록 보호
+	debug        bool
+}
+
+// NewBackupManager는 새로운 백업 관리자를 생성합니다
+func NewBackupManager(db *Database, dir string, keep int, interval time.Duration) *BackupManager {
+	if keep <= 0 {
+		keep = 1
+	}
+	return &BackupManager{
+		db:       db,
+		dir:      dir,
+		keep:     keep,
+		interval: interval,
+		debug:    true, // 디버깅 모드 활성화
+	}
+}
+
+// SetDebug는 디버깅 모드를 설정합니다
+func (b *BackupManager) SetDebug(debug bool) {
+	b.debug = debug
+}
+
+// Start는 주기적인 백업을 시작합니다
+func (b *BackupManager) Start() error {
+	b.runningMutex.Lock()
+	defer b.runningMutex.Unlock()
+
+	if b.isRunning {
+		return fmt.Errorf("백업 관리자가 이미 실행 중입니다")
+	}
+	if b.interval <= 0 {
+		return fmt.Errorf("백업 주기가 설정되지 않았습니다")
+	}
+
+	b.stopChan = make(chan struct{})
+	b.isRunning = true
+
+	go func(stopChan chan struct{}) {
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := b.CreateBackup(); err != nil {
+					log.Printf("정기 백업 실패: %v", err)
+				}
+			case <-stopChan:
+				return
+			}
+		}
+	}(b.stopChan)
+
+	log.Printf("백업 관리자가 시작되었습니다. 간격: %v, 보관 개수: %d", b.interval, b.keep)
+	return nil
+}
+
+// Stop은 주기적인 백업을 중지합니다
+func (b *BackupManager) Stop() error {
+	b.runningMutex.Lock()
+	defer b.runningMutex.Unlock()
+
+	if !b.isRunning {
+		return fmt.Errorf("백업 관리자가 실행 중이 아닙니다")
+	}
+
+	close(b.stopChan)
+	b.isRunning = false
+	log.Println("백업 관리자가 중지되었습니다")
+	return nil
+}
+
+// CreateBackup은 VACUUM INTO로 일관된 온라인 백업을 생성하고 검증한 뒤 오래된 백업을 정리합니다
+func (b *BackupManager) CreateBackup() (*BackupInfo, error) {
+	b.backupMutex.Lock()
+	defer b.backupMutex.Unlock()
+
+	startTime := time.Now()
+
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return nil, fmt.Errorf("백업 디렉토리 생성 실패: %w", err)
+	}
+
+	createdAt := startTime.UTC()
+	name := backupFilePrefix + createdAt.Format(backupTimeLayout) + backupFileSuffix
+	path := filepath.Join(b.dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("같은 시각의 백업이 이미 존재합니다: %s", name)
+	}
+
+	// 임시 파일에 백업한 뒤 검증이 끝나면 최종 이름으로 변경
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath)
+	if err := b.db.BackupInto(tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	if err := checkSQLiteIntegrity(tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("백업 검증 실패: %w", err)
+	}
+
+	checksum, size, err := fileSHA256(tmpPath)
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("백업 파일 이름 변경 실패: %w", err)
+	}
+	if err := os.WriteFile(path+backupChecksumSuffix, []byte(checksum+"  "+name+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("체크섬 파일 저장 실패: %w", err)
+	}
+
+	info := &BackupInfo{
+		Name:      name,
+		Path:      path,
+		CreatedAt: createdAt.Format(time.RFC3339),
+		SizeBytes: size,
+		SHA256:    checksum,
+	}
+
+	log.Printf("백업 생성 완료: %s (%.2fMB, 소요 시간=%v)", name, float64(size)/(1024*1024), time.Since(startTime))
+
+	if removed, err := b.pruneBackups(); err != nil {
+		log.Printf("오래된 백업 정리 실패: %v", err)
+	} else if removed > 0 && b.debug {
+		log.Printf("오래된 백업 %d개 삭제", removed)
+	}
+
+	return info, nil
+}
+
+// pruneBackups는 보관 개수를 넘는 오래된 백업을 삭제합니다
+func (b *BackupManager) pruneBackups() (int, error) {
+	backups, err := ListBackups(b.dir)
+	if err != nil {
+		return 0, err
+	}
+	if len(backups) <= b.keep {
+		return 0, nil
+	}
+
+	// ListBackups는 최신순으로 정렬되어 있음
+	removed := 0
+	for _, backup := range backups[b.keep:] {
+		if err := os.Remove(backup.Path); err != nil {
+			return removed, fmt.Errorf("백업 삭제 실패 (%s): %w", backup.Name, err)
+		}
+		os.Remove(backup.Path + backupChecksumSuffix)
+		removed++
+	}
+	return removed, nil
+}
+
+// ListBackups는 백업 관리자의 백업 목록을 최신순으로 반환합니다
+func (b *BackupManager) ListBackups() ([]BackupInfo, error) {
+	return ListBackups(b.dir)
+}
+
+// ListBackups는 디렉토리의 백업 파일 목록을 최신순으로 반환합니다
+func ListBackups(dir string) ([]BackupInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []BackupInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("백업 디렉토리 읽기 실패: %w", err)
+	}
+
+	backups := []BackupInfo{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupFilePrefix) || !strings.HasSuffix(name, backupFileSuffix) {
+			continue
+		}
+
+		createdAt, err := time.Parse(backupTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, backupFilePrefix), backupFileSuffix))
+		if err != nil {
+			continue
+		}
+
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("백업 파일 정보 조회 실패 (%s): %w", name, err)
+		}
+
+		path := filepath.Join(dir, name)
+		checksum, err := readBackupChecksum(path)
+		if err != nil {
+			// 체크섬 파일이 없으면 직접 계산
+			checksum, _, err = fileSHA256(path)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		backups = append(backups, BackupInfo{
+			Name:      name,
+			Path:      path,
+			CreatedAt: createdAt.Format(time.RFC3339),
+			SizeBytes: fileInfo.Size(),
+			SHA256:    checksum,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt > backups[j].CreatedAt
+	})
+
+	return backups, nil
+}
+
+// FindBackupAt은 지정 시각 이전에 생성된 가장 최근 백업을 찾습니다
+func FindBackupAt(dir string, at time.Time) (*BackupInfo, error) {
+	backups, err := ListBackups(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	target := at.UTC().Format(time.RFC3339)
+	for _, backup := range backups {
+		if backup.CreatedAt <= target {
+			return &backup, nil
+		}
+	}
+	return nil, fmt.Errorf("%s 이전에 생성된 백업이 없습니다", at.Format(time.RFC3339))
+}
+
+// VerifyBackup은 백업 파일의 체크섬과 SQLite 무결성을 검증합니다
+func VerifyBackup(path string) error {
+	expected, err := readBackupChecksum(path)
+	if err == nil {
+		actual, _, err := fileSHA256(path)
+		if err != nil {
+			return err
+		}
+		if actual != expected {
+			return fmt.Errorf("체크섬 불일치: 기대값=%s, 실제값=%s", expected, actual)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	return checkSQLiteIntegrity(path)
+}
+
+// RestoreBackup은 백업 파일을 검증한 뒤 데이터베이스 파일을 교체합니다
+// 다른 프로세스가 데이터베이스를 열고 있으면 복원하지 않으며, 기존 데이터베이스는 WAL을 반영한 뒤 .pre-restore 파일로 보존합니다
+func RestoreBackup(backupPath, dbPath string) error {
+	if err := VerifyBackup(backupPath); err != nil {
+		return fmt.Errorf("백업 검증 실패: %w", err)
+	}
+
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := checkpointForRestore(dbPath); err != nil {
+			return err
+		}
+	}
+
+	// 교체 전에 같은 디렉토리에 복사하여 rename이 원자적으로 처리되도록 함
+	tmpPath := dbPath + ".restore.tmp"
+	if err := copyFile(backupPath, tmpPath); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(dbPath); err == nil {
+		// WAL/공유 메모리 파일도 함께 옮겨 보존한 데이터베이스가 커밋된 페이지를 모두 포함하고 복원된 파일과 섞이지 않도록 함
+		preRestorePath := dbPath + ".pre-restore." + time.Now().Format("20060102150405")
+		if err := moveSQLiteFile(dbPath, preRestorePath); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("기존 데이터베이스 보존 실패: %w", err)
+		}
+		log.Printf("기존 데이터베이스를 보존했습니다: %s", preRestorePath)
+	}
+
+	if err := os.Rename(tmpPath, dbPath); err != nil {
+		return fmt.Errorf("데이터베이스 파일 교체 실패: %w", err)
+	}
+
+	return nil
+}
+
+// checkpointForRestore는 데이터베이스에 배타적 잠금을 걸어 다른 프로세스가 사용 중이 아닌지 확인하고
+// WAL에 남은 커밋된 페이지를 데이터베이스 파일에 기록합니다
+func checkpointForRestore(dbPath string) error {
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(0)&_pragma=locking_mode(EXCLUSIVE)")
+	if err != nil {
+		return fmt.Errorf("데이터베이스 열기 실패: %w", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("BEGIN EXCLUSIVE"); err != nil {
+		return fmt.Errorf("데이터베이스를 사용 중인 프로세스가 있습니다 (서버를 중지한 뒤 복원하세요): %w", err)
+	}
+	if _, err := db.Exec("COMMIT"); err != nil {
+		return fmt.Errorf("데이터베이스 잠금 해제 실패: %w", err)
+	}
+
+	var busy, logFrames, checkpointed int
+	if err := db.QueryRow("PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logFrames, &checkpointed); err != nil {
+		return fmt.Errorf("WAL 체크포인트 실패: %w", err)
+	}
+	if busy != 0 {
+		return fmt.Errorf("WAL 체크포인트를 완료하지 못했습니다 (기록된 프레임 %d/%d)", checkpointed, logFrames)
+	}
+	return nil
+}
+
+// BackupInto는 VACUUM INTO로 현재 데이터베이스의 일관된 사본을 path에 생성합니다
+func (d *Database) BackupInto(path string) error {
+	if d.debug {
+		log.Printf("BackupInto 시작: %s", path)
+	}
+
+	if _, err := d.db.Exec("VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("온라인 백업 실패: %w", err)
+	}
+
+	// 토픽 샤드를 사용하면 샤드 스냅샷을 백업 파일에 합침
+	if d.shards != nil {
+		if err := d.mergeTopicShardsInto(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSQLiteIntegrity는 SQLite 파일을 읽기 전용으로 열어 PRAGMA integrity_check를 실행합니다
+func checkSQLiteIntegrity(path string) error {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("백업 파일 열기 실패: %w", err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow("PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("무결성 검사 실행 실패: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("무결성 검사 실패: %s", result)
+	}
+	return nil
+}
+
+// readBackupChecksum은 백업 파일 옆의 .sha256 파일에서 체크섬을 읽습니다
+func readBackupChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path + backupChecksumSuffix)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("체크섬 파일이 비어 있습니다: %s", path+backupChecksumSuffix)
+	}
+	return fields[0], nil
+}
+
+// fileSHA256은 파일의 SHA-256 체크섬과 크기를 계산합니다
+func fileSHA256(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("파일 열기 실패: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("체크섬 계산 실패: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// copyFile은 파일을 복사하고 디스크에 기록합니다
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("원본 파일 열기 실패: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("대상 파일 생성 실패: %w", err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return fmt.Errorf("파일 복사 실패: %w", err)
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return fmt.Errorf("파일 동기화 실패: %w", err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,181 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// copyBackupAs는 백업 파일과 체크섬 파일을 createdAt 시각의 백업 이름으로 복사합니다
+func copyBackupAs(t *testing.T, backup *BackupInfo, createdAt time.Time) string {
+	t.Helper()
+	name := backupFilePrefix + createdAt.UTC().Format(backupTimeLayout) + backupFileSuffix
+	path := filepath.Join(filepath.Dir(backup.Path), name)
+	if err := copyFile(backup.Path, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+backupChecksumSuffix, []byte(backup.SHA256+"  "+name+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCreateBackupListsAndPrunes(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.SaveTopicInference(testSnapshot("1", 100, time.Now().Add(-time.Hour), testWorkers(3), 0)); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "backups")
+	manager := NewBackupManager(db, dir, 2, 0)
+	manager.SetDebug(false)
+
+	backup, err := manager.CreateBackup()
+	if err != nil {
+		t.Fatalf("백업 생성 실패: %v", err)
+	}
+	if checksum, size, err := fileSHA256(backup.Path); err != nil || checksum != backup.SHA256 || size != backup.SizeBytes {
+		t.Errorf("백업 체크섬 = %s (%d bytes), want %s (%d bytes)", checksum, size, backup.SHA256, backup.SizeBytes)
+	}
+	if _, err := os.Stat(backup.Path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("임시 백업 파일이 남아 있습니다: %v", err)
+	}
+
+	// 백업은 독립된 데이터베이스로 열림
+	restored := openTestDatabase(t, backup.Path)
+	if state, err := restored.GetTopicInferenceByHeight("1", "100"); err != nil || state == nil {
+		t.Errorf("백업의 높이 100 스냅샷 = %v (%v)", state, err)
+	}
+	restored.Close()
+
+	// 보관 개수를 넘는 오래된 백업은 체크섬 파일과 함께 삭제
+	oldest := copyBackupAs(t, backup, time.Now().Add(-48*time.Hour))
+	older := copyBackupAs(t, backup, time.Now().Add(-24*time.Hour))
+	if removed, err := manager.pruneBackups(); err != nil || removed != 1 {
+		t.Fatalf("정리된 백업 = %d (%v), want 1", removed, err)
+	}
+	if _, err := os.Stat(oldest); !os.IsNotExist(err) {
+		t.Errorf("가장 오래된 백업이 남아 있습니다: %v", err)
+	}
+	if _, err := os.Stat(oldest + backupChecksumSuffix); !os.IsNotExist(err) {
+		t.Errorf("가장 오래된 백업의 체크섬 파일이 남아 있습니다: %v", err)
+	}
+
+	backups, err := manager.ListBackups()
+	if err != nil || len(backups) != 2 || backups[0].Name != backup.Name || backups[1].Path != older {
+		t.Fatalf("백업 목록 = %+v (%v), want 최신순 2개", backups, err)
+	}
+	found, err := FindBackupAt(dir, time.Now().Add(-time.Hour))
+	if err != nil || found.Path != older {
+		t.Errorf("1시간 전 시점의 백업 = %+v (%v), want %s", found, err, older)
+	}
+	if _, err := FindBackupAt(dir, time.Now().Add(-72*time.Hour)); err == nil {
+		t.Error("가장 오래된 백업보다 이전 시점에 백업을 찾았습니다")
+	}
+}
+
+func TestVerifyBackup(t *testing.T) {
+	db := newTestDatabase(t)
+	manager := NewBackupManager(db, t.TempDir(), 1, 0)
+	manager.SetDebug(false)
+	backup, err := manager.CreateBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBackup(backup.Path); err != nil {
+		t.Errorf("정상 백업 검증 실패: %v", err)
+	}
+
+	// 체크섬 파일이 없으면 SQLite 무결성만 검사
+	unchecked := filepath.Join(t.TempDir(), "unchecked.db")
+	if err := copyFile(backup.Path, unchecked); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBackup(unchecked); err != nil {
+		t.Errorf("체크섬 없는 백업 검증 실패: %v", err)
+	}
+
+	// 내용이 바뀐 백업은 체크섬 불일치
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(backup.Path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBackup(backup.Path); err == nil || !strings.Contains(err.Error(), "체크섬 불일치") {
+		t.Errorf("변조된 백업 검증 = %v, want 체크섬 불일치", err)
+	}
+
+	// SQLite 파일이 아니면 무결성 검사 실패
+	garbage := filepath.Join(t.TempDir(), "garbage.db")
+	if err := os.WriteFile(garbage, []byte(strings.Repeat("not a database", 512)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBackup(garbage); err == nil {
+		t.Error("SQLite 파일이 아닌 백업을 검증했습니다")
+	}
+}
+
+func TestRestoreBackupPreservesWAL(t *testing.T) {
+	dir := t.TempDir()
+	livePath := filepath.Join(dir, "live.db")
+	live := openTestDatabase(t, livePath)
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(3)
+	if err := live.SaveTopicInference(testSnapshot("1", 100, base, workers, 0)); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewBackupManager(live, filepath.Join(dir, "backups"), 1, 0)
+	manager.SetDebug(false)
+	backup, err := manager.CreateBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := live.SaveTopicInference(testSnapshot("1", 110, base.Add(time.Minute), workers, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 데이터베이스를 연 프로세스가 있으면 복원하지 않음
+	if err := RestoreBackup(backup.Path, livePath); err == nil || !strings.Contains(err.Error(), "사용 중") {
+		t.Fatalf("사용 중인 데이터베이스 복원 = %v, want 거부", err)
+	}
+	if latest := latestHeight(t, live, "1"); latest != "110" {
+		t.Errorf("복원 거부 후 최신 높이 = %v, want 110", latest)
+	}
+
+	// 서버가 비정상 종료되어 커밋된 높이 110이 아직 WAL에만 있는 데이터베이스 파일
+	dbPath := filepath.Join(dir, "crashed.db")
+	for _, suffix := range []string{"", "-wal"} {
+		if err := copyFile(livePath+suffix, dbPath+suffix); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if info, err := os.Stat(dbPath + "-wal"); err != nil || info.Size() == 0 {
+		t.Fatalf("WAL 파일이 비어 있습니다: %v", err)
+	}
+
+	if err := RestoreBackup(backup.Path, dbPath); err != nil {
+		t.Fatalf("복원 실패: %v", err)
+	}
+	if _, err := os.Stat(dbPath + "-wal"); !os.IsNotExist(err) {
+		t.Errorf("복원한 데이터베이스 옆에 이전 WAL 파일이 남아 있습니다: %v", err)
+	}
+
+	restored := openTestDatabase(t, dbPath)
+	if latest := latestHeight(t, restored, "1"); latest != "100" {
+		t.Errorf("복원한 데이터베이스 최신 높이 = %v, want 100", latest)
+	}
+
+	// 보존한 이전 데이터베이스는 WAL에만 있던 높이 110을 포함
+	preserved, err := filepath.Glob(dbPath + ".pre-restore.*")
+	if err != nil || len(preserved) != 1 || strings.HasSuffix(preserved[0], "-wal") {
+		t.Fatalf("보존된 데이터베이스 파일 = %v (%v), want 1개", preserved, err)
+	}
+	previous := openTestDatabase(t, preserved[0])
+	if latest := latestHeight(t, previous, "1"); latest != "110" {
+		t.Errorf("보존된 데이터베이스 최신 높이 = %v, want 110", latest)
+	}
+}
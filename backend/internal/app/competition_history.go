@@ -0,0 +1,282 @@
+package app
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// competition_changes 테이블에 기록되는 필드 이름
+const (
+	competitionFieldCreated   = "created"    // 새 경쟁 등장
+	competitionFieldRemoved   = "removed"    // 경쟁 목록에서 사라짐
+	competitionFieldName      = "name"       // 경쟁 이름
+	competitionFieldPrizePool = "prize_pool" // 상금 규모
+	competitionFieldStartDate = "start_date" // 시작 일시
+	competitionFieldEndDate   = "end_date"   // 종료 일시
+	competitionFieldSeasonID  = "season_id"  // 시즌 ID
+	competitionFieldTags      = "tags"       // 태그 목록
+	competitionFieldIsActive  = "is_active"  // 활성 여부
+)
+
+// CompetitionChange는 경쟁 필드 하나의 변경 이력입니다
+type CompetitionChange struct {
+	ID            int64   `json:"id"`
+	CompetitionID int     `json:"competition_id"`
+	Field         string  `json:"field"`
+	OldValue      *string `json:"old_value"`
+	NewValue      *string `json:"new_value"`
+	ChangedAt     string  `json:"changed_at"`
+}
+
+// competitionState는 변경 감지에 사용하는 competitions_v2 행의 필드 값입니다
+type competitionState struct {
+	Name      string
+	PrizePool int
+	StartDate string
+	EndDate   string
+	SeasonID  int
+	Tags      string // JSON 배열 문자열
+	IsActive  bool
+	TopicID   int // 변경 이벤트의 토픽 (비교하지 않음)
+}
+
+// newCompetitionState는 API 응답의 경쟁 정보로부터 비교용 상태를 만듭니다
+func newCompetitionState(comp Competition, isActive bool) (competitionState, error) {
+	tagsJSON, err := json.Marshal(comp.Tags)
+	if err != nil {
+		return competitionState{}, fmt.Errorf("태그 JSON 변환 실패: %w", err)
+	}
+
+	return competitionState{
+		Name:      comp.Name,
+		PrizePool: comp.PrizePool,
+		StartDate: comp.StartDate.Format(time.RFC3339),
+		EndDate:   comp.EndDate.Format(time.RFC3339),
+		SeasonID:  comp.SeasonID,
+		Tags:      string(tagsJSON),
+		IsActive:  isActive,
+		TopicID:   comp.TopicID,
+	}, nil
+}
+
+// competitionFieldChange는 변경된 필드 하나의 이전/이후 값입니다
+type competitionFieldChange struct {
+	field    string
+	oldValue string
+	newValue string
+}
+
+// diffCompetitionStates는 두 상태 사이에서 바뀐 필드 목록을 반환합니다
+func diffCompetitionStates(prev, cur competitionState) []competitionFieldChange {
+	var changes []competitionFieldChange
+
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, competitionFieldChange{field: field, oldValue: oldValue, newValue: newValue})
+		}
+	}
+
+	add(competitionFieldName, prev.Name, cur.Name)
+	add(competitionFieldPrizePool, strconv.Itoa(prev.PrizePool), strconv.Itoa(cur.PrizePool))
+	add(competitionFieldStartDate, prev.StartDate, cur.StartDate)
+	add(competitionFieldEndDate, prev.EndDate, cur.EndDate)
+	add(competitionFieldSeasonID, strconv.Itoa(prev.SeasonID), strconv.Itoa(cur.SeasonID))
+	add(competitionFieldTags, normalizeTagsJSON(prev.Tags), normalizeTagsJSON(cur.Tags))
+	add(competitionFieldIsActive, strconv.FormatBool(prev.IsActive), strconv.FormatBool(cur.IsActive))
+
+	return changes
+}
+
+// normalizeTagsJSON은 null과 빈 배열을 같은 값으로 취급하기 위해 태그 JSON을 정규화합니다
+func normalizeTagsJSON(tagsJSON string) string {
+	if tagsJSON == "" || tagsJSON == "null" {
+		return "[]"
+	}
+	return tagsJSON
+}
+
+// loadCompetitionStates는 competitions_v2 테이블의 현재 상태를 경쟁 ID별로 가져옵니다
+func loadCompetitionStates(q sqlQueryer) (map[int]competitionState, error) {
+	rows, err := q.Query(
+		`SELECT id, name, prize_pool, start_date, end_date, season_id, tags, is_active, topic_id FROM competitions_v2`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[int]competitionState)
+	for rows.Next() {
+		var id int
+		var state competitionState
+		var prizePool, seasonID, topicID sql.NullInt64
+		var tags sql.NullString
+
+		if err := rows.Scan(&id, &state.Name, &prizePool, &state.StartDate, &state.EndDate, &seasonID, &tags, &state.IsActive, &topicID); err != nil {
+			return nil, fmt.Errorf("데이터 스캔 실패: %w", err)
+		}
+
+		state.PrizePool = int(prizePool.Int64)
+		state.SeasonID = int(seasonID.Int64)
+		state.Tags = tags.String
+		state.TopicID = int(topicID.Int64)
+		states[id] = state
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	return states, nil
+}
+
+// upsertCompetitionV2는 경쟁 정보를 삽입하거나 갱신하고, 바뀐 필드를 competition_changes에 기록합니다
+// 기록한 변경 이력을 반환합니다
+func upsertCompetitionV2(tx *sql.Tx, comp Competition, listedAsPast, isActive bool, existing map[int]competitionState, timestamp string) ([]CompetitionChange, error) {
+	state, err := newCompetitionState(comp, isActive)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO competitions_v2 (
+			id, name, preview_image_url, description, detailed_description,
+			topic_id, prize_pool, start_date, end_date, season_id,
+			tags, is_active, timestamp, listed_as_past
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			preview_image_url = excluded.preview_image_url,
+			description = excluded.description,
+			detailed_description = excluded.detailed_description,
+			topic_id = excluded.topic_id,
+			prize_pool = excluded.prize_pool,
+			start_date = excluded.start_date,
+			end_date = excluded.end_date,
+			season_id = excluded.season_id,
+			tags = excluded.tags,
+			is_active = excluded.is_active,
+			timestamp = excluded.timestamp,
+			listed_as_past = excluded.listed_as_past`,
+		comp.ID, comp.Name, comp.PreviewImageURL, comp.Description, comp.DetailedDescription,
+		comp.TopicID, comp.PrizePool, state.StartDate, state.EndDate, comp.SeasonID,
+		state.Tags, isActive, timestamp, listedAsPast,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("경쟁 데이터 저장 실패: %w", err)
+	}
+
+	prev, exists := existing[comp.ID]
+	if !exists {
+		created := comp.Name
+		change, err := recordCompetitionChange(tx, comp.ID, competitionFieldCreated, nil, &created, timestamp)
+		if err != nil {
+			return nil, err
+		}
+		return []CompetitionChange{change}, nil
+	}
+
+	var changes []CompetitionChange
+	for _, field := range diffCompetitionStates(prev, state) {
+		oldValue, newValue := field.oldValue, field.newValue
+		change, err := recordCompetitionChange(tx, comp.ID, field.field, &oldValue, &newValue, timestamp)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// recordCompetitionChange는 competition_changes 테이블에 변경 이력 한 건을 추가하고 기록한 이력을 반환합니다
+func recordCompetitionChange(tx *sql.Tx, competitionID int, field string, oldValue, newValue *string, changedAt string) (CompetitionChange, error) {
+	result, err := tx.Exec(
+		"INSERT INTO competition_changes (competition_id, field, old_value, new_value, changed_at) VALUES (?, ?, ?, ?, ?)",
+		competitionID, field, oldValue, newValue, changedAt,
+	)
+	if err != nil {
+		return CompetitionChange{}, fmt.Errorf("경쟁 변경 이력 기록 실패 (ID=%d, 필드=%s): %w", competitionID, field, err)
+	}
+
+	change := CompetitionChange{
+		CompetitionID: competitionID,
+		Field:         field,
+		OldValue:      oldValue,
+		NewValue:      newValue,
+		ChangedAt:     changedAt,
+	}
+	change.ID, _ = result.LastInsertId()
+	return change, nil
+}
+
+// competitionChangeEvents는 커밋 후 발행할 경쟁 변경 이벤트를 만듭니다
+func competitionChangeEvents(changes []CompetitionChange, topicID int) []pendingEvent {
+	events := make([]pendingEvent, 0, len(changes))
+	for _, change := range changes {
+		event := newCompetitionEvent(change, topicID)
+		events = append(events, pendingEvent{eventType: EventTypeCompetition, topicID: event.TopicID, data: event})
+	}
+	return events
+}
+
+// GetCompetitionChanges는 경쟁 필드 변경 이력을 시간순으로 가져옵니다
+// competitionID가 0이면 모든 경쟁의 변경 이력을 가져옵니다
+// changed_at은 UTC RFC3339 문자열이므로 범위도 UTC로 바꿔 문자열로 비교합니다
+func (d *Database) GetCompetitionChanges(competitionID int, start, end time.Time) ([]CompetitionChange, error) {
+	startStr := start.UTC().Format(time.RFC3339)
+	endStr := end.UTC().Format(time.RFC3339)
+
+	if d.debug {
+		log.Printf("GetCompetitionChanges 시작: 경쟁 ID=%d, %s ~ %s", competitionID, startStr, endStr)
+	}
+
+	query := `SELECT id, competition_id, field, old_value, new_value, changed_at
+		FROM competition_changes
+		WHERE changed_at BETWEEN ? AND ?`
+	args := []interface{}{startStr, endStr}
+	if competitionID != 0 {
+		query += " AND competition_id = ?"
+		args = append(args, competitionID)
+	}
+	query += " ORDER BY changed_at, id"
+
+	rows, err := d.reader.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("경쟁 변경 이력 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []CompetitionChange
+	for rows.Next() {
+		var change CompetitionChange
+		var oldValue, newValue sql.NullString
+
+		if err := rows.Scan(&change.ID, &change.CompetitionID, &change.Field, &oldValue, &newValue, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("경쟁 변경 이력 스캔 실패: %w", err)
+		}
+
+		if oldValue.Valid {
+			change.OldValue = &oldValue.String
+		}
+		if newValue.Valid {
+			change.NewValue = &newValue.String
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	if d.debug {
+		log.Printf("GetCompetitionChanges 완료: %d개 변경 이력", len(changes))
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCompetitions는 활성 경쟁 목록으로 경쟁 응답을 만듭니다
+func testCompetitions(competitions ...Competition) *CompetitionsResponse {
+	response := &CompetitionsResponse{}
+	response.PageProps.CompetitionsPage.ActiveAndUpcomingCompetitions = competitions
+	return response
+}
+
+// withLocalTime은 테스트 동안 로컬 시간대를 loc으로 바꿉니다
+func withLocalTime(t *testing.T, loc *time.Location) {
+	t.Helper()
+	previous := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = previous })
+}
+
+func TestDiffCompetitionStates(t *testing.T) {
+	base := competitionState{Name: "A", PrizePool: 100, StartDate: "2025-01-01T00:00:00Z", EndDate: "2025-02-01T00:00:00Z", SeasonID: 1, Tags: "null", IsActive: true, TopicID: 1}
+	tests := []struct {
+		name   string
+		modify func(*competitionState)
+		want   []competitionFieldChange
+	}{
+		{"변경 없음", func(s *competitionState) {}, nil},
+		{"null과 빈 태그는 같음", func(s *competitionState) { s.Tags = "[]" }, nil},
+		{"토픽은 비교하지 않음", func(s *competitionState) { s.TopicID = 2 }, nil},
+		{"이름과 상금", func(s *competitionState) { s.Name = "B"; s.PrizePool = 200 }, []competitionFieldChange{
+			{competitionFieldName, "A", "B"},
+			{competitionFieldPrizePool, "100", "200"},
+		}},
+		{"기간, 시즌, 태그, 활성 여부", func(s *competitionState) {
+			s.EndDate = "2025-03-01T00:00:00Z"
+			s.SeasonID = 2
+			s.Tags = `["new"]`
+			s.IsActive = false
+		}, []competitionFieldChange{
+			{competitionFieldEndDate, "2025-02-01T00:00:00Z", "2025-03-01T00:00:00Z"},
+			{competitionFieldSeasonID, "1", "2"},
+			{competitionFieldTags, "[]", `["new"]`},
+			{competitionFieldIsActive, "true", "false"},
+		}},
+	}
+	for _, tt := range tests {
+		cur := base
+		tt.modify(&cur)
+		if got := diffCompetitionStates(base, cur); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: 변경 = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompetitionChangesTimeline(t *testing.T) {
+	// 서버 로컬 시간대와 다른 시간대로 조회해도 같은 이력을 반환해야 함
+	withLocalTime(t, time.FixedZone("KST", 9*60*60))
+	db := newTestDatabase(t)
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := Competition{ID: 1, Name: "Alpha", TopicID: 1, PrizePool: 1000, StartDate: startDate, EndDate: startDate.AddDate(0, 1, 0), SeasonID: 1}
+	second := Competition{ID: 2, Name: "Beta", TopicID: 2, PrizePool: 500, StartDate: startDate, EndDate: startDate.AddDate(0, 1, 0), SeasonID: 1}
+
+	before := time.Now().Add(-time.Second)
+	if err := db.SaveCompetitions(testCompetitions(first, second)); err != nil {
+		t.Fatal(err)
+	}
+	renamed := first
+	renamed.Name = "Alpha v2"
+	renamed.PrizePool = 2000
+	renamed.Tags = []string{}
+	if err := db.SaveCompetitions(testCompetitions(renamed)); err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now().Add(time.Second)
+
+	type field struct {
+		id   int
+		name string
+	}
+	wantAll := []field{{1, competitionFieldCreated}, {2, competitionFieldCreated}, {1, competitionFieldName}, {1, competitionFieldPrizePool}, {2, competitionFieldRemoved}}
+	for _, loc := range []*time.Location{time.UTC, time.FixedZone("EST", -5*60*60), time.Local} {
+		changes, err := db.GetCompetitionChanges(0, before.In(loc), after.In(loc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []field
+		for _, change := range changes {
+			got = append(got, field{change.CompetitionID, change.Field})
+			if !strings.HasSuffix(change.ChangedAt, "Z") {
+				t.Errorf("changed_at = %s, want UTC", change.ChangedAt)
+			}
+		}
+		// 같은 시각의 이력은 기록 순서(생성 → 필드 변경 → 삭제)로 정렬
+		if !reflect.DeepEqual(got, wantAll) {
+			t.Errorf("%s 범위의 변경 이력 = %v, want %v", loc, got, wantAll)
+		}
+	}
+
+	// 경쟁 필터와 범위 밖 조회
+	changes, err := db.GetCompetitionChanges(1, before, after)
+	if err != nil || len(changes) != 3 {
+		t.Fatalf("경쟁 1 변경 이력 = %+v (%v), want 3개", changes, err)
+	}
+	if changes[1].OldValue == nil || *changes[1].OldValue != "Alpha" || *changes[1].NewValue != "Alpha v2" {
+		t.Errorf("이름 변경 = %+v, want Alpha -> Alpha v2", changes[1])
+	}
+	if changes, err := db.GetCompetitionChanges(0, after, after.Add(time.Hour)); err != nil || len(changes) != 0 {
+		t.Errorf("저장 이후 범위의 변경 이력 = %+v (%v), want 없음", changes, err)
+	}
+
+	// API는 경쟁별 타임라인으로 묶어 반환
+	_, mux := newTestService(t, db)
+	var response CompetitionChangesResponse
+	getJSON(t, mux, "/api/v1/competitions/1/changes?start="+url.QueryEscape(before.In(time.FixedZone("EST", -5*60*60)).Format(time.RFC3339)), http.StatusOK, &response)
+	if response.Count != 3 || len(response.Timelines["1"]) != 3 || len(response.Timelines) != 1 {
+		t.Errorf("타임라인 응답 = %+v, want 경쟁 1의 이력 3개", response)
+	}
+}
+
+func TestMigrateCompetitionChangesUTC(t *testing.T) {
+	db := newTestDatabase(t)
+	for _, changedAt := range []string{"2025-01-01T09:00:00+09:00", "2025-01-01T00:30:00Z", "2024-12-31T19:45:00-05:00"} {
+		if _, err := db.db.Exec("INSERT INTO competition_changes (competition_id, field, new_value, changed_at) VALUES (1, 'name', 'x', ?)", changedAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tx, err := db.db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.migrateCompetitionChangesUTC(tx); err != nil {
+		tx.Rollback()
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := db.GetCompetitionChanges(1, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.ChangedAt)
+	}
+	if want := []string{"2025-01-01T00:00:00Z", "2025-01-01T00:30:00Z", "2025-01-01T00:45:00Z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("변환된 changed_at = %v, want %v", got, want)
+	}
+}
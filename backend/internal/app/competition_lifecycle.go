@@ -0,0 +1,385 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 경쟁 라이프사이클 상태
+const (
+	LifecycleAnnounced  = "announced"   // 공개되었지만 토픽이 없거나 시작까지 오래 남음
+	LifecycleUpcoming   = "upcoming"    // 곧 시작 (또는 시작 시각이 지났지만 체인 토픽이 아직 비활성)
+	LifecycleRunning    = "running"     // 진행 중
+	LifecycleEndingSoon = "ending-soon" // 종료 임박
+	LifecycleEnded      = "ended"       // 종료됨
+	LifecycleArchived   = "archived"    // 종료 후 보관 기간이 지나고 체인 토픽도 비활성
+)
+
+// 라이프사이클 판단 기준
+const (
+	lifecycleUpcomingWindow   = 7 * 24 * time.Hour // 시작 전 이 기간 안이면 upcoming
+	lifecycleEndingSoonWindow = 24 * time.Hour     // 종료 전 이 기간 안이면 ending-soon
+	lifecycleArchiveAfter     = 7 * 24 * time.Hour // 종료 후 이 기간이 지나면 archived
+
+	lifecycleEvaluationInterval = 1 * time.Minute // 저장된 정보로 라이프사이클을 재평가하는 간격
+)
+
+// IsCollectingLifecycle은 해당 상태에서 토픽 추론 데이터를 수집해야 하는지 확인합니다
+func IsCollectingLifecycle(state string) bool {
+	return state == LifecycleRunning || state == LifecycleEndingSoon
+}
+
+// lifecycleInput은 라이프사이클 계산에 필요한 경쟁 정보입니다
+type lifecycleInput struct {
+	TopicID      int
+	StartDate    time.Time
+	EndDate      time.Time
+	ListedAsPast bool  // forge의 pastCompetitions 목록에 포함되었는지 여부
+	ChainActive  *bool // 체인의 토픽 활성 여부 (알 수 없으면 nil)
+}
+
+// computeCompetitionLifecycle은 시작/종료 일시, forge 목록, 체인 토픽 활성 여부로 라이프사이클 상태를 계산합니다
+func computeCompetitionLifecycle(input lifecycleInput, now time.Time) string {
+	ended := input.ListedAsPast || (!input.EndDate.IsZero() && !now.Before(input.EndDate))
+	if ended {
+		// 체인 토픽이 여전히 활성이면 보관하지 않음
+		chainInactive := input.ChainActive == nil || !*input.ChainActive
+		endedAt := input.EndDate
+		if endedAt.IsZero() || endedAt.After(now) {
+			endedAt = now
+		}
+		if chainInactive && now.Sub(endedAt) >= lifecycleArchiveAfter {
+			return LifecycleArchived
+		}
+		return LifecycleEnded
+	}
+
+	if input.TopicID == 0 || input.StartDate.IsZero() {
+		return LifecycleAnnounced
+	}
+
+	if now.Before(input.StartDate) {
+		if input.StartDate.Sub(now) > lifecycleUpcomingWindow {
+			return LifecycleAnnounced
+		}
+		return LifecycleUpcoming
+	}
+
+	// 시작 시각이 지났지만 체인 토픽이 아직 활성화되지 않음
+	if input.ChainActive != nil && !*input.ChainActive {
+		return LifecycleUpcoming
+	}
+
+	if !input.EndDate.IsZero() && input.EndDate.Sub(now) <= lifecycleEndingSoonWindow {
+		return LifecycleEndingSoon
+	}
+
+	return LifecycleRunning
+}
+
+// LifecycleTransition은 경쟁 라이프사이클 상태 전이 기록입니다
+type LifecycleTransition struct {
+	ID             int64   `json:"id"`
+	CompetitionID  int     `json:"competition_id"`
+	FromState      *string `json:"from_state"`
+	ToState        string  `json:"to_state"`
+	TransitionedAt string  `json:"transitioned_at"`
+}
+
+// loadChainActivity는 competition_lifecycle 테이블에 저장된 체인 토픽 활성 여부를 가져옵니다
+func loadChainActivity(q sqlQueryer) (map[int]*bool, error) {
+	rows, err := q.Query("SELECT competition_id, chain_active FROM competition_lifecycle WHERE chain_active IS NOT NULL")
+	if err != nil {
+		return nil, fmt.Errorf("체인 활성 여부 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	activity := make(map[int]*bool)
+	for rows.Next() {
+		var competitionID int
+		var active bool
+		if err := rows.Scan(&competitionID, &active); err != nil {
+			return nil, fmt.Errorf("체인 활성 여부 스캔 실패: %w", err)
+		}
+		activity[competitionID] = &active
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	return activity, nil
+}
+
+// sameChainActivity는 두 체인 활성 여부(알 수 없으면 nil)가 같은지 확인합니다
+func sameChainActivity(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// UpdateCompetitionLifecycles는 모든 경쟁의 라이프사이클을 다시 계산하여 저장합니다
+// topicActivity는 토픽 ID별 체인 활성 여부이며, 포함되지 않은 토픽은 이전에 저장된 값을 사용합니다
+// 상태가 바뀐 경쟁은 전이 기록을 남기고 competitions_v2.is_active도 함께 갱신합니다
+// 상태와 체인 활성 여부가 바뀌지 않은 경쟁은 다시 쓰지 않습니다
+func (d *Database) UpdateCompetitionLifecycles(topicActivity map[int]bool, now time.Time) ([]LifecycleTransition, error) {
+	if d.debug {
+		log.Printf("UpdateCompetitionLifecycles 시작: 체인 활성 정보 %d개", len(topicActivity))
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	storedActivity, err := loadChainActivity(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(`
+		SELECT c.id, c.topic_id, c.start_date, c.end_date, c.listed_as_past, c.is_active, l.state
+		FROM competitions_v2 c
+		LEFT JOIN competition_lifecycle l ON l.competition_id = c.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("경쟁 데이터 조회 실패: %w", err)
+	}
+
+	type lifecycleRow struct {
+		competitionID int
+		input         lifecycleInput
+		isActive      bool
+		state         sql.NullString
+		chainActive   *bool // 저장된 체인 활성 여부
+	}
+
+	var competitions []lifecycleRow
+	for rows.Next() {
+		var row lifecycleRow
+		var topicID sql.NullInt64
+		var startDateStr, endDateStr string
+
+		if err = rows.Scan(&row.competitionID, &topicID, &startDateStr, &endDateStr, &row.input.ListedAsPast, &row.isActive, &row.state); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("경쟁 데이터 스캔 실패: %w", err)
+		}
+
+		row.input.TopicID = int(topicID.Int64)
+		row.input.StartDate, _ = time.Parse(time.RFC3339, startDateStr)
+		row.input.EndDate, _ = time.Parse(time.RFC3339, endDateStr)
+
+		row.chainActive = storedActivity[row.competitionID]
+		if active, ok := topicActivity[row.input.TopicID]; ok && row.input.TopicID != 0 {
+			row.input.ChainActive = &active
+		} else {
+			row.input.ChainActive = row.chainActive
+		}
+
+		competitions = append(competitions, row)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	timestamp := now.UTC().Format(time.RFC3339)
+	var transitions []LifecycleTransition
+	var events []pendingEvent
+
+	for _, comp := range competitions {
+		state := computeCompetitionLifecycle(comp.input, now)
+
+		var chainActive interface{}
+		if comp.input.ChainActive != nil {
+			chainActive = *comp.input.ChainActive
+		}
+
+		if comp.state.Valid && comp.state.String == state {
+			// 상태와 체인 활성 여부가 모두 그대로이면 쓰지 않음
+			if sameChainActivity(comp.chainActive, comp.input.ChainActive) {
+				continue
+			}
+			_, err = tx.Exec(
+				"UPDATE competition_lifecycle SET chain_active = ?, updated_at = ? WHERE competition_id = ?",
+				chainActive, timestamp, comp.competitionID,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("라이프사이클 갱신 실패 (ID=%d): %w", comp.competitionID, err)
+			}
+			continue
+		}
+
+		// 상태 전이 기록
+		_, err = tx.Exec(
+			`INSERT INTO competition_lifecycle (competition_id, state, entered_at, updated_at, chain_active)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(competition_id) DO UPDATE SET
+				state = excluded.state,
+				entered_at = excluded.entered_at,
+				updated_at = excluded.updated_at,
+				chain_active = excluded.chain_active`,
+			comp.competitionID, state, timestamp, timestamp, chainActive,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("라이프사이클 저장 실패 (ID=%d): %w", comp.competitionID, err)
+		}
+
+		transition := LifecycleTransition{
+			CompetitionID:  comp.competitionID,
+			ToState:        state,
+			TransitionedAt: timestamp,
+		}
+		if comp.state.Valid {
+			fromState := comp.state.String
+			transition.FromState = &fromState
+		}
+
+		var result sql.Result
+		result, err = tx.Exec(
+			"INSERT INTO competition_lifecycle_transitions (competition_id, from_state, to_state, transitioned_at) VALUES (?, ?, ?, ?)",
+			transition.CompetitionID, transition.FromState, transition.ToState, transition.TransitionedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("라이프사이클 전이 기록 실패 (ID=%d): %w", comp.competitionID, err)
+		}
+		transition.ID, _ = result.LastInsertId()
+		transitions = append(transitions, transition)
+		events = append(events, competitionChangeEvents([]CompetitionChange{{
+			CompetitionID: transition.CompetitionID,
+			Field:         competitionFieldLifecycle,
+			OldValue:      transition.FromState,
+			NewValue:      &state,
+			ChangedAt:     transition.TransitionedAt,
+		}}, comp.input.TopicID)...)
+
+		// 수집 여부가 바뀌면 competitions_v2.is_active도 갱신
+		isActive := IsCollectingLifecycle(state)
+		if isActive != comp.isActive {
+			if _, err = tx.Exec("UPDATE competitions_v2 SET is_active = ? WHERE id = ?", isActive, comp.competitionID); err != nil {
+				return nil, fmt.Errorf("활성 여부 갱신 실패 (ID=%d): %w", comp.competitionID, err)
+			}
+
+			oldValue, newValue := strconv.FormatBool(comp.isActive), strconv.FormatBool(isActive)
+			var change CompetitionChange
+			if change, err = recordCompetitionChange(tx, comp.competitionID, competitionFieldIsActive, &oldValue, &newValue, timestamp); err != nil {
+				return nil, err
+			}
+			events = append(events, competitionChangeEvents([]CompetitionChange{change}, comp.input.TopicID)...)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+	d.events.publishAll(events)
+
+	if d.debug {
+		log.Printf("UpdateCompetitionLifecycles 완료: 경쟁 %d개, 상태 전이 %d개", len(competitions), len(transitions))
+	}
+
+	return transitions, nil
+}
+
+// GetCollectingTopicIDs는 라이프사이클상 데이터를 수집해야 하는 경쟁의 토픽 ID 목록을 반환합니다
+func (d *Database) GetCollectingTopicIDs() ([]string, error) {
+	rows, err := d.reader.Query(`
+		SELECT DISTINCT c.topic_id
+		FROM competitions_v2 c
+		JOIN competition_lifecycle l ON l.competition_id = c.id
+		WHERE c.topic_id IS NOT NULL AND c.topic_id <> 0 AND l.state IN (?, ?)
+		ORDER BY c.topic_id
+	`, LifecycleRunning, LifecycleEndingSoon)
+	if err != nil {
+		return nil, fmt.Errorf("수집 대상 토픽 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var topicIDs []string
+	for rows.Next() {
+		var topicID int
+		if err := rows.Scan(&topicID); err != nil {
+			return nil, fmt.Errorf("토픽 ID 스캔 실패: %w", err)
+		}
+		topicIDs = append(topicIDs, strconv.Itoa(topicID))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	return topicIDs, nil
+}
+
+// GetLifecycleTransitions는 경쟁 라이프사이클 전이 기록을 시간순으로 가져옵니다
+// competitionID가 0이면 모든 경쟁의 전이 기록을 가져옵니다
+func (d *Database) GetLifecycleTransitions(competitionID int) ([]LifecycleTransition, error) {
+	query := "SELECT id, competition_id, from_state, to_state, transitioned_at FROM competition_lifecycle_transitions"
+	var args []interface{}
+	if competitionID != 0 {
+		query += " WHERE competition_id = ?"
+		args = append(args, competitionID)
+	}
+	query += " ORDER BY transitioned_at, id"
+
+	rows, err := d.reader.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("라이프사이클 전이 기록 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []LifecycleTransition
+	for rows.Next() {
+		var transition LifecycleTransition
+		var fromState sql.NullString
+		if err := rows.Scan(&transition.ID, &transition.CompetitionID, &fromState, &transition.ToState, &transition.TransitionedAt); err != nil {
+			return nil, fmt.Errorf("라이프사이클 전이 기록 스캔 실패: %w", err)
+		}
+		if fromState.Valid {
+			transition.FromState = &fromState.String
+		}
+		transitions = append(transitions, transition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// lifecycleTopicIDs는 체인 활성 여부를 확인할 토픽 ID 목록을 경쟁 응답에서 추출합니다
+// 이미 보관 기간이 지난 과거 경쟁의 토픽은 확인하지 않습니다
+func lifecycleTopicIDs(resp *CompetitionsResponse, now time.Time) []int {
+	seen := make(map[int]bool)
+	var topicIDs []int
+
+	collect := func(competitions []Competition, isPast bool) {
+		for _, comp := range competitions {
+			if comp.TopicID == 0 || seen[comp.TopicID] {
+				continue
+			}
+			if isPast && !comp.EndDate.IsZero() && now.Sub(comp.EndDate) > lifecycleArchiveAfter+24*time.Hour {
+				continue
+			}
+			seen[comp.TopicID] = true
+			topicIDs = append(topicIDs, comp.TopicID)
+		}
+	}
+
+	collect(resp.PageProps.CompetitionsPage.ActiveAndUpcomingCompetitions, false)
+	collect(resp.PageProps.CompetitionsPage.PastCompetitions, true)
+
+	sort.Ints(topicIDs)
+	return topicIDs
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeCompetitionLifecycle(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	active, inactive := true, false
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name  string
+		input lifecycleInput
+		want  string
+	}{
+		{"토픽 없음", lifecycleInput{StartDate: now.Add(-day), EndDate: now.Add(10 * day)}, LifecycleAnnounced},
+		{"시작 일시 없음", lifecycleInput{TopicID: 1, EndDate: now.Add(10 * day)}, LifecycleAnnounced},
+		{"시작까지 7일 초과", lifecycleInput{TopicID: 1, StartDate: now.Add(8 * day), EndDate: now.Add(20 * day)}, LifecycleAnnounced},
+		{"시작까지 정확히 7일", lifecycleInput{TopicID: 1, StartDate: now.Add(lifecycleUpcomingWindow), EndDate: now.Add(20 * day)}, LifecycleUpcoming},
+		{"시작 3일 전", lifecycleInput{TopicID: 1, StartDate: now.Add(3 * day), EndDate: now.Add(20 * day)}, LifecycleUpcoming},
+		{"시작했지만 체인 토픽 비활성", lifecycleInput{TopicID: 1, StartDate: now.Add(-time.Hour), EndDate: now.Add(10 * day), ChainActive: &inactive}, LifecycleUpcoming},
+		{"시작했고 체인 활성 여부 모름", lifecycleInput{TopicID: 1, StartDate: now.Add(-time.Hour), EndDate: now.Add(10 * day)}, LifecycleRunning},
+		{"시작했고 체인 토픽 활성", lifecycleInput{TopicID: 1, StartDate: now, EndDate: now.Add(10 * day), ChainActive: &active}, LifecycleRunning},
+		{"종료 일시 없음", lifecycleInput{TopicID: 1, StartDate: now.Add(-day)}, LifecycleRunning},
+		{"종료까지 정확히 24시간", lifecycleInput{TopicID: 1, StartDate: now.Add(-day), EndDate: now.Add(lifecycleEndingSoonWindow)}, LifecycleEndingSoon},
+		{"종료 1시간 전", lifecycleInput{TopicID: 1, StartDate: now.Add(-day), EndDate: now.Add(time.Hour), ChainActive: &active}, LifecycleEndingSoon},
+		{"종료 시각", lifecycleInput{TopicID: 1, StartDate: now.Add(-day), EndDate: now}, LifecycleEnded},
+		{"과거 목록에 포함되면 종료 일시 전이라도 종료", lifecycleInput{TopicID: 1, StartDate: now.Add(-day), EndDate: now.Add(10 * day), ListedAsPast: true}, LifecycleEnded},
+		{"종료 일시 없는 과거 경쟁", lifecycleInput{TopicID: 1, ListedAsPast: true}, LifecycleEnded},
+		{"종료 후 7일 미만", lifecycleInput{TopicID: 1, StartDate: now.Add(-20 * day), EndDate: now.Add(-6 * day)}, LifecycleEnded},
+		{"종료 후 정확히 7일", lifecycleInput{TopicID: 1, StartDate: now.Add(-20 * day), EndDate: now.Add(-lifecycleArchiveAfter)}, LifecycleArchived},
+		{"종료 후 7일이 지났지만 체인 토픽 활성", lifecycleInput{TopicID: 1, StartDate: now.Add(-20 * day), EndDate: now.Add(-8 * day), ChainActive: &active}, LifecycleEnded},
+		{"종료 후 7일이 지났고 체인 토픽 비활성", lifecycleInput{TopicID: 1, StartDate: now.Add(-20 * day), EndDate: now.Add(-8 * day), ChainActive: &inactive}, LifecycleArchived},
+		{"토픽 없는 과거 경쟁도 보관", lifecycleInput{EndDate: now.Add(-8 * day), ListedAsPast: true}, LifecycleArchived},
+	}
+	for _, tt := range tests {
+		if got := computeCompetitionLifecycle(tt.input, now); got != tt.want {
+			t.Errorf("%s: 상태 = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateCompetitionLifecyclesTransitions(t *testing.T) {
+	// 서버 로컬 시간대와 관계없이 전이 시각은 UTC로 저장
+	withLocalTime(t, time.FixedZone("KST", 9*60*60))
+	db := newTestDatabase(t)
+	start := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 10)
+	if err := db.SaveCompetitions(testCompetitions(Competition{ID: 1, Name: "Alpha", TopicID: 1, StartDate: start, EndDate: end, SeasonID: 1})); err != nil {
+		t.Fatal(err)
+	}
+	isActiveChanges := func() int {
+		return countRows(t, db, "SELECT COUNT(*) FROM competition_changes WHERE competition_id = 1 AND field = ?", competitionFieldIsActive)
+	}
+	baseChanges := isActiveChanges()
+
+	updatedAt := func() string {
+		t.Helper()
+		var value string
+		if err := db.db.QueryRow("SELECT updated_at FROM competition_lifecycle WHERE competition_id = 1").Scan(&value); err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+
+	steps := []struct {
+		name       string
+		now        time.Time
+		activity   map[int]bool
+		want       string // 전이된 상태 (빈 문자열이면 전이 없음)
+		rewritten  bool   // 전이 없이 체인 활성 여부만 갱신되는지 여부
+		collecting bool
+	}{
+		{"최초 평가", start.AddDate(0, 0, -10), nil, LifecycleAnnounced, false, false},
+		{"같은 상태 재평가", start.AddDate(0, 0, -10).Add(time.Minute), nil, "", false, false},
+		{"시작 3일 전", start.AddDate(0, 0, -3), nil, LifecycleUpcoming, false, false},
+		{"시작했지만 체인 토픽 비활성", start.Add(time.Hour), map[int]bool{1: false}, "", true, false},
+		{"체인 토픽 활성화", start.Add(2 * time.Hour), map[int]bool{1: true}, LifecycleRunning, false, true},
+		{"저장된 체인 활성 여부로 재평가", start.Add(3 * time.Hour), nil, "", false, true},
+		{"종료 1시간 전", end.Add(-time.Hour), nil, LifecycleEndingSoon, false, true},
+		{"종료", end.Add(time.Hour), map[int]bool{1: true}, LifecycleEnded, false, false},
+		{"보관 기간이 지났지만 체인 토픽 활성", end.AddDate(0, 0, 8), map[int]bool{1: true}, "", false, false},
+		{"보관", end.AddDate(0, 0, 8).Add(time.Minute), map[int]bool{1: false}, LifecycleArchived, false, false},
+	}
+
+	var want []string
+	previous := ""
+	for _, step := range steps {
+		transitions, err := db.UpdateCompetitionLifecycles(step.activity, step.now.In(time.Local))
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		if step.want == "" {
+			if len(transitions) != 0 {
+				t.Errorf("%s: 전이 = %+v, want 없음", step.name, transitions)
+			}
+			// 상태가 그대로이면 체인 활성 여부가 바뀔 때만 다시 씀
+			wantUpdatedAt := previous
+			if step.rewritten {
+				wantUpdatedAt = step.now.UTC().Format(time.RFC3339)
+			}
+			if got := updatedAt(); got != wantUpdatedAt {
+				t.Errorf("%s: updated_at = %s, want %s", step.name, got, wantUpdatedAt)
+			}
+		} else {
+			if len(transitions) != 1 || transitions[0].ToState != step.want {
+				t.Fatalf("%s: 전이 = %+v, want %s", step.name, transitions, step.want)
+			}
+			want = append(want, step.want)
+		}
+		previous = updatedAt()
+
+		collecting, err := db.GetCollectingTopicIDs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(collecting) == 1; got != step.collecting {
+			t.Errorf("%s: 수집 대상 토픽 = %v, want 수집 %v", step.name, collecting, step.collecting)
+		}
+	}
+
+	// 전이 기록은 이전 상태와 UTC 시각을 포함하여 시간순으로 조회
+	transitions, err := db.GetLifecycleTransitions(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for i, transition := range transitions {
+		got = append(got, transition.ToState)
+		if !strings.HasSuffix(transition.TransitionedAt, "Z") {
+			t.Errorf("transitioned_at = %s, want UTC", transition.TransitionedAt)
+		}
+		if i == 0 && transition.FromState != nil {
+			t.Errorf("최초 전이의 이전 상태 = %s, want nil", *transition.FromState)
+		}
+		if i > 0 && (transition.FromState == nil || *transition.FromState != want[i-1]) {
+			t.Errorf("전이 %d의 이전 상태 = %v, want %s", i, transition.FromState, want[i-1])
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("전이 기록 = %v, want %v", got, want)
+	}
+
+	// 수집 여부는 running으로 바뀔 때와 종료될 때만 기록
+	if changes := isActiveChanges() - baseChanges; changes != 2 {
+		t.Errorf("is_active 변경 이력 %d개, want 2", changes)
+	}
+}
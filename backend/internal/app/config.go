@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"time"
 )
 
 // Config는 애플리케이션 설정 구조체입니다
@@ -26,6 +28,60 @@ type Config struct {
 	// 토픽 추론 데이터 설정
 	TopicUpdateIntervalMinutes int      `json:"topic_update_interval_minutes"`
 	DefaultActiveTopics        []string `json:"default_active_topics"`
+
+	// 계층형 보존 설정 (원본은 DataRetentionDays, 시간 롤업은 HourlyRollupRetentionDays 동안 보존, 일 롤업은 영구 보존)
+	HourlyRollupRetentionDays int                        `json:"hourly_rollup_retention_days"`
+	TopicRetention            map[string]RetentionPolicy `json:"topic_retention"` // 토픽별 보존 정책
+
+	// 백업 설정
+	BackupEnabled       bool   `json:"backup_enabled"`
+	BackupDir           string `json:"backup_dir"` // 비어 있으면 DataDir/backups
+	BackupIntervalHours int    `json:"backup_interval_hours"`
+	BackupKeep          int    `json:"backup_keep"` // 보관할 백업 개수
+
+	// 무결성 검사 설정
+	IntegrityCheckEnabled       bool `json:"integrity_check_enabled"`
+	IntegrityCheckIntervalHours int  `json:"integrity_check_interval_hours"`
+	IntegrityAutoQuarantine     bool `json:"integrity_auto_quarantine"` // 정기 검사에서 문제 행을 자동으로 격리
+
+	// 정리된 데이터를 보관할 Parquet 아카이브 디렉토리 (비어 있으면 DataDir/archive)
+	ArchiveDir string `json:"archive_dir"`
+
+	// 리더 선출 설정 (여러 레플리카가 같은 데이터베이스를 사용할 때 리더만 수집)
+	LeaderElectionEnabled bool   `json:"leader_election_enabled"`
+	LeaderLeaseSeconds    int    `json:"leader_lease_seconds"`
+	InstanceID            string `json:"instance_id"` // 비어 있으면 호스트 이름과 프로세스 ID로 생성
+
+	// 관리자 API 토큰 (비어 있으면 관리자 API 비활성화)
+	AdminToken string `json:"admin_token"`
+
+	// 스냅샷 저장 설정 (키프레임 + 델타)
+	SnapshotDeltaEnabled     bool `json:"snapshot_delta_enabled"`
+	SnapshotKeyframeInterval int  `json:"snapshot_keyframe_interval"`
+
+	// 가공된 스냅샷 캐시 설정 (TTL은 다른 레플리카의 쓰기가 반영되기까지의 최대 지연)
+	SnapshotCacheMB         int `json:"snapshot_cache_mb"`
+	SnapshotCacheTTLSeconds int `json:"snapshot_cache_ttl_seconds"`
+
+	// 실시간 이벤트 스트림에서 재연결 시 다시 보낼 수 있도록 보관하는 최근 이벤트 수
+	EventBufferSize int `json:"event_buffer_size"`
+
+	// 토픽별 샤드 설정 (토픽 스냅샷을 토픽마다 별도의 SQLite 파일에 저장, 메인 데이터베이스는 카탈로그로 사용)
+	TopicShardingEnabled bool   `json:"topic_sharding_enabled"`
+	TopicShardDir        string `json:"topic_shard_dir"` // 비어 있으면 DataDir/topics
+}
+
+// 기본 보존 기간 (일)
+const (
+	defaultRawRetentionDays    = 30
+	defaultHourlyRetentionDays = 180
+)
+
+// RetentionPolicy는 토픽 데이터의 계층별 보존 기간입니다
+// 0인 값은 기본 정책을 따릅니다
+type RetentionPolicy struct {
+	RawDays    int `json:"raw_days"`    // 원본 스냅샷 보존 기간
+	HourlyDays int `json:"hourly_days"` // 시간 롤업 보존 기간
 }
 
 // LoadConfig는 JSON 파일에서 설정을 로드합니다
@@ -64,13 +120,61 @@ func LoadConfig(path string) (*Config, error) {
 	}
 
 	if config.DataRetentionDays <= 0 {
-		config.DataRetentionDays = 30
+		config.DataRetentionDays = defaultRawRetentionDays
 	}
 
 	if config.TopicUpdateIntervalMinutes <= 0 {
 		config.TopicUpdateIntervalMinutes = 5
 	}
 
+	if config.HourlyRollupRetentionDays <= 0 {
+		config.HourlyRollupRetentionDays = defaultHourlyRetentionDays
+	}
+
+	if config.BackupDir == "" {
+		config.BackupDir = filepath.Join(config.DataDir, "backups")
+	}
+
+	if config.ArchiveDir == "" {
+		config.ArchiveDir = filepath.Join(config.DataDir, "archive")
+	}
+
+	if config.TopicShardDir == "" {
+		config.TopicShardDir = filepath.Join(config.DataDir, "topics")
+	}
+
+	if config.BackupIntervalHours <= 0 {
+		config.BackupIntervalHours = 24
+	}
+
+	if config.BackupKeep <= 0 {
+		config.BackupKeep = 7
+	}
+
+	if config.IntegrityCheckIntervalHours <= 0 {
+		config.IntegrityCheckIntervalHours = 24
+	}
+
+	if config.SnapshotKeyframeInterval <= 0 {
+		config.SnapshotKeyframeInterval = defaultSnapshotKeyframeInterval
+	}
+
+	if config.SnapshotCacheMB <= 0 {
+		config.SnapshotCacheMB = defaultSnapshotCacheBytes >> 20
+	}
+
+	if config.SnapshotCacheTTLSeconds <= 0 {
+		config.SnapshotCacheTTLSeconds = int(defaultSnapshotCacheTTL / time.Second)
+	}
+
+	if config.LeaderLeaseSeconds <= 0 {
+		config.LeaderLeaseSeconds = int(defaultLeaderLeaseTTL / time.Second)
+	}
+
+	if config.EventBufferSize <= 0 {
+		config.EventBufferSize = defaultEventBufferSize
+	}
+
 	return &config, nil
 }
 
@@ -93,14 +197,27 @@ func SaveConfig(config *Config, path string) error {
 // CreateDefaultConfig는 기본 설정 파일을 생성합니다
 func CreateDefaultConfig(path string) error {
 	config := &Config{
-		Port:                       "8080",
-		DataDir:                    "data",
-		AlloraBaseURL:              "https://forge.allora.network",
-		APITimeoutSeconds:          30,
-		MonitoringIntervalMinutes:  60,
-		DataRetentionDays:          30,
-		TopicUpdateIntervalMinutes: 5,
-		DefaultActiveTopics:        []string{},
+		Port:                        "8080",
+		DataDir:                     "data",
+		AlloraBaseURL:               "https://forge.allora.network",
+		APITimeoutSeconds:           30,
+		MonitoringIntervalMinutes:   60,
+		DataRetentionDays:           defaultRawRetentionDays,
+		TopicUpdateIntervalMinutes:  5,
+		DefaultActiveTopics:         []string{},
+		HourlyRollupRetentionDays:   defaultHourlyRetentionDays,
+		TopicRetention:              map[string]RetentionPolicy{},
+		BackupDir:                   filepath.Join("data", "backups"),
+		BackupIntervalHours:         24,
+		BackupKeep:                  7,
+		IntegrityCheckIntervalHours: 24,
+		LeaderLeaseSeconds:          int(defaultLeaderLeaseTTL / time.Second),
+		ArchiveDir:                  filepath.Join("data", "archive"),
+		TopicShardDir:               filepath.Join("data", "topics"),
+		SnapshotKeyframeInterval:    defaultSnapshotKeyframeInterval,
+		SnapshotCacheMB:             defaultSnapshotCacheBytes >> 20,
+		SnapshotCacheTTLSeconds:     int(defaultSnapshotCacheTTL / time.Second),
+		EventBufferSize:             defaultEventBufferSize,
 	}
 
 	return SaveConfig(config, path)
@@ -128,15 +245,78 @@ func NewConfig() *Config {
 		dataRetention = 90
 	}
 
+	hourlyRetention, err := strconv.Atoi(getEnv("HOURLY_ROLLUP_RETENTION_DAYS", strconv.Itoa(defaultHourlyRetentionDays)))
+	if err != nil {
+		hourlyRetention = defaultHourlyRetentionDays
+	}
+
+	backupInterval, err := strconv.Atoi(getEnv("BACKUP_INTERVAL_HOURS", "24"))
+	if err != nil {
+		backupInterval = 24
+	}
+
+	backupKeep, err := strconv.Atoi(getEnv("BACKUP_KEEP", "7"))
+	if err != nil {
+		backupKeep = 7
+	}
+
+	integrityInterval, err := strconv.Atoi(getEnv("INTEGRITY_CHECK_INTERVAL_HOURS", "24"))
+	if err != nil {
+		integrityInterval = 24
+	}
+
+	leaderLease, err := strconv.Atoi(getEnv("LEADER_LEASE_SECONDS", strconv.Itoa(int(defaultLeaderLeaseTTL/time.Second))))
+	if err != nil {
+		leaderLease = int(defaultLeaderLeaseTTL / time.Second)
+	}
+
+	cacheMB, err := strconv.Atoi(getEnv("SNAPSHOT_CACHE_MB", strconv.Itoa(defaultSnapshotCacheBytes>>20)))
+	if err != nil {
+		cacheMB = defaultSnapshotCacheBytes >> 20
+	}
+
+	cacheTTL, err := strconv.Atoi(getEnv("SNAPSHOT_CACHE_TTL_SECONDS", strconv.Itoa(int(defaultSnapshotCacheTTL/time.Second))))
+	if err != nil {
+		cacheTTL = int(defaultSnapshotCacheTTL / time.Second)
+	}
+
+	eventBuffer, err := strconv.Atoi(getEnv("EVENT_BUFFER_SIZE", strconv.Itoa(defaultEventBufferSize)))
+	if err != nil {
+		eventBuffer = defaultEventBufferSize
+	}
+
+	dataDir := getEnv("DATA_DIR", "data")
+
 	return &Config{
-		Port:                       strconv.Itoa(port),
-		DataDir:                    getEnv("DATA_DIR", "data"),
-		AlloraBaseURL:              getEnv("ALLORA_API_BASE_URL", "https://forge.allora.network"),
-		APITimeoutSeconds:          apiTimeout,
-		MonitoringIntervalMinutes:  monitoringInterval,
-		DataRetentionDays:          dataRetention,
-		TopicUpdateIntervalMinutes: 5,
-		DefaultActiveTopics:        []string{},
+		Port:                        strconv.Itoa(port),
+		DataDir:                     dataDir,
+		AlloraBaseURL:               getEnv("ALLORA_API_BASE_URL", "https://forge.allora.network"),
+		APITimeoutSeconds:           apiTimeout,
+		MonitoringIntervalMinutes:   monitoringInterval,
+		DataRetentionDays:           dataRetention,
+		TopicUpdateIntervalMinutes:  5,
+		DefaultActiveTopics:         []string{},
+		HourlyRollupRetentionDays:   hourlyRetention,
+		TopicRetention:              map[string]RetentionPolicy{},
+		BackupEnabled:               getEnv("BACKUP_ENABLED", "false") == "true",
+		BackupDir:                   getEnv("BACKUP_DIR", filepath.Join(dataDir, "backups")),
+		BackupIntervalHours:         backupInterval,
+		BackupKeep:                  backupKeep,
+		IntegrityCheckEnabled:       getEnv("INTEGRITY_CHECK_ENABLED", "false") == "true",
+		IntegrityCheckIntervalHours: integrityInterval,
+		IntegrityAutoQuarantine:     getEnv("INTEGRITY_AUTO_QUARANTINE", "false") == "true",
+		LeaderElectionEnabled:       getEnv("LEADER_ELECTION_ENABLED", "false") == "true",
+		LeaderLeaseSeconds:          leaderLease,
+		InstanceID:                  getEnv("INSTANCE_ID", ""),
+		ArchiveDir:                  getEnv("ARCHIVE_DIR", filepath.Join(dataDir, "archive")),
+		AdminToken:                  getEnv("ADMIN_TOKEN", ""),
+		SnapshotDeltaEnabled:        getEnv("SNAPSHOT_DELTA_ENABLED", "false") == "true",
+		SnapshotKeyframeInterval:    defaultSnapshotKeyframeInterval,
+		SnapshotCacheMB:             cacheMB,
+		SnapshotCacheTTLSeconds:     cacheTTL,
+		EventBufferSize:             eventBuffer,
+		TopicShardingEnabled:        getEnv("TOPIC_SHARDING_ENABLED", "false") == "true",
+		TopicShardDir:               getEnv("TOPIC_SHARD_DIR", filepath.Join(dataDir, "topics")),
 	}
 }
 
@@ -3,10 +3,12 @@ package app
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"os"
+	"runtime"
 	"strconv"
 	"time"
 
@@ -14,26 +16,80 @@ import (
 	"github.com/golang/snappy"
 )
 
+const (
+	sqliteBusyTimeoutMillis = 5000 // 잠금 대기 시간 (밀리초)
+	minReadConnections      = 4    // 읽기 전용 연결 풀의 최소 크기
+)
+
 // Database 구조체는 SQLite 데이터베이스 연결과 관련 메서드를 제공합니다
+// 쓰기는 단일 연결(db)로 직렬화하고, 조회는 읽기 전용 연결 풀(reader)에서 처리합니다
 type Database struct {
-	db    *sql.DB
-	debug bool // 디버깅 모드 활성화 여부
+	db               *sql.DB // 쓰기 전용 단일 연결
+	reader           *sql.DB // 읽기 전용 연결 풀
+	debug            bool    // 디버깅 모드 활성화 여부
+	snapshotDelta    bool    // 토픽 스냅샷 델타 저장 활성화 여부
+	keyframeInterval int     // 키프레임 하나에 연결되는 최대 스냅샷 수
+
+	retention      RetentionPolicy            // 기본 계층형 보존 정책
+	topicRetention map[string]RetentionPolicy // 토픽별 보존 정책 (기본 정책을 덮어씀)
+
+	archiver *Archiver // 정리 전 데이터를 보관할 아카이브 (nil이면 보관하지 않음)
+
+	cache *snapshotCache // 가공된 스냅샷 캐시 (최신/블록 높이별 조회)
+
+	main   *topicStore  // 메인 데이터베이스의 topic_inferences (샤드가 없는 토픽)
+	shards *topicShards // 토픽별 샤드 파일 (nil이면 샤딩을 사용하지 않음)
+
+	events *EventBroker // 저장된 변경을 발행할 이벤트 브로커 (nil이면 발행하지 않음)
 }
 
 // NewDatabase는 새로운 데이터베이스 연결을 생성합니다
+// WAL 저널 모드로 열어 수집기의 쓰기와 API 조회가 서로를 막지 않도록 합니다
 func NewDatabase(dbPath string) (*Database, error) {
-	db, err := sql.Open("sqlite", dbPath)
+	db, err := sql.Open("sqlite", sqliteDSN(dbPath,
+		"journal_mode(WAL)",
+		"synchronous(NORMAL)",
+	)+"&_txlock=immediate")
 	if err != nil {
 		return nil, fmt.Errorf("데이터베이스 연결 실패: %w", err)
 	}
+	// SQLite는 동시에 하나의 쓰기만 허용하므로 쓰기 연결을 하나로 제한하여 잠금 경합을 없앰
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
 
-	// 데이터베이스 초기화
+	// 데이터베이스 초기화 (WAL 모드 전환 후 읽기 연결을 열어야 함)
 	if err := initDatabase(db); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("데이터베이스 초기화 실패: %w", err)
 	}
 
-	return &Database{db: db, debug: true}, nil
+	reader, err := sql.Open("sqlite", sqliteDSN(dbPath, "query_only(1)"))
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("읽기 전용 데이터베이스 연결 실패: %w", err)
+	}
+	readConns := max(runtime.NumCPU(), minReadConnections)
+	reader.SetMaxOpenConns(readConns)
+	reader.SetMaxIdleConns(readConns)
+
+	d := &Database{
+		db:               db,
+		reader:           reader,
+		debug:            true,
+		keyframeInterval: defaultSnapshotKeyframeInterval,
+		retention:        RetentionPolicy{RawDays: defaultRawRetentionDays, HourlyDays: defaultHourlyRetentionDays},
+		cache:            newSnapshotCache(defaultSnapshotCacheBytes, defaultSnapshotCacheTTL),
+		main:             &topicStore{path: dbPath, db: db, reader: reader},
+	}
+
+	// 기존 데이터베이스를 현재 스키마 버전으로 변환
+	if err := d.migrateSchema(); err != nil {
+		d.Close()
+		return nil, fmt.Errorf("스키마 마이그레이션 실패: %w", err)
+	}
+
+	return d, nil
 }
 
 // SetDebug는 디버깅 모드를 설정합니다
@@ -41,9 +97,51 @@ func (d *Database) SetDebug(debug bool) {
 	d.debug = debug
 }
 
+// SetEventBroker는 스냅샷 저장과 경쟁 변경을 발행할 이벤트 브로커를 설정합니다
+func (d *Database) SetEventBroker(events *EventBroker) {
+	d.events = events
+}
+
+// SetSnapshotDelta는 토픽 스냅샷 델타 저장 방식을 설정합니다
+// 활성화하면 keyframeInterval개마다 전체 스냅샷(키프레임)을 저장하고 그 사이는 직전 스냅샷 대비 델타로 저장합니다
+func (d *Database) SetSnapshotDelta(enabled bool, keyframeInterval int) {
+	if keyframeInterval <= 0 {
+		keyframeInterval = defaultSnapshotKeyframeInterval
+	}
+	d.snapshotDelta = enabled
+	d.keyframeInterval = keyframeInterval
+}
+
+// SetRetentionPolicy는 계층형 보존 정책을 설정합니다
+// topicPolicies에 지정된 토픽은 0이 아닌 값만 기본 정책을 덮어씁니다
+func (d *Database) SetRetentionPolicy(policy RetentionPolicy, topicPolicies map[string]RetentionPolicy) {
+	if policy.RawDays <= 0 {
+		policy.RawDays = defaultRawRetentionDays
+	}
+	if policy.HourlyDays <= 0 {
+		policy.HourlyDays = defaultHourlyRetentionDays
+	}
+	d.retention = policy
+	d.topicRetention = topicPolicies
+}
+
 // Close는 데이터베이스 연결을 닫습니다
 func (d *Database) Close() error {
-	return d.db.Close()
+	d.closeTopicShards()
+	readErr := d.reader.Close()
+	if err := d.db.Close(); err != nil {
+		return err
+	}
+	return readErr
+}
+
+// sqliteDSN은 잠금 대기 시간과 지정된 PRAGMA를 연결마다 적용하는 DSN을 생성합니다
+func sqliteDSN(dbPath string, pragmas ...string) string {
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)", dbPath, sqliteBusyTimeoutMillis)
+	for _, pragma := range pragmas {
+		dsn += "&_pragma=" + pragma
+	}
+	return dsn
 }
 
 // 데이터베이스 테이블 초기화
@@ -53,13 +151,19 @@ func initDatabase(db *sql.DB) error {
 		CREATE TABLE IF NOT EXISTS competitions (
 			id INTEGER PRIMARY KEY,
 			timestamp TEXT NOT NULL,
-			data BLOB NOT NULL
+			data BLOB NOT NULL,
+			content_hash TEXT
 		)
 	`)
 	if err != nil {
 		return err
 	}
 
+	// 스냅샷/경쟁 데이터 본문 저장소 생성 (해시로 중복 제거)
+	if _, err = db.Exec(snapshotBlobsTableSQL); err != nil {
+		return err
+	}
+
 	// 인덱스 생성
 	_, err = db.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_competitions_timestamp ON competitions(timestamp)
@@ -83,7 +187,8 @@ func initDatabase(db *sql.DB) error {
 			season_id INTEGER,
 			tags TEXT,
 			is_active BOOLEAN NOT NULL,
-			timestamp TEXT NOT NULL
+			timestamp TEXT NOT NULL,
+			listed_as_past BOOLEAN NOT NULL DEFAULT 0
 		)
 	`)
 	if err != nil {
@@ -105,6 +210,68 @@ func initDatabase(db *sql.DB) error {
 		return err
 	}
 
+	// forge의 pastCompetitions 목록 포함 여부 (라이프사이클 계산용)
+	if err := ensureColumn(db, "competitions_v2", "listed_as_past", "BOOLEAN NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// 경쟁 라이프사이클 테이블 생성
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS competition_lifecycle (
+			competition_id INTEGER PRIMARY KEY,
+			state TEXT NOT NULL,
+			entered_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			chain_active BOOLEAN
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// 경쟁 라이프사이클 전이 기록 테이블 생성
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS competition_lifecycle_transitions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			competition_id INTEGER NOT NULL,
+			from_state TEXT,
+			to_state TEXT NOT NULL,
+			transitioned_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_competition_lifecycle_transitions_competition_id ON competition_lifecycle_transitions(competition_id, transitioned_at)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// 경쟁 필드 변경 이력 테이블 생성
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS competition_changes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			competition_id INTEGER NOT NULL,
+			field TEXT NOT NULL,
+			old_value TEXT,
+			new_value TEXT,
+			changed_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_competition_changes_competition_id ON competition_changes(competition_id, changed_at)
+	`)
+	if err != nil {
+		return err
+	}
+
 	// 리더보드 데이터 테이블 생성
 	_, err = db.Exec(`
 		CREATE TABLE IF NOT EXISTS leaderboard_entries (
@@ -149,38 +316,179 @@ func initDatabase(db *sql.DB) error {
 		return err
 	}
 
+	// 워커 주소로 조회하는 API용 인덱스
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_leaderboard_cosmos_address ON leaderboard_entries(cosmos_address, topic_id)
+	`)
+	if err != nil {
+		return err
+	}
+
 	// 토픽 추론 데이터 테이블 생성
+	_, err = db.Exec(topicInferencesTableSQL)
+	if err != nil {
+		return err
+	}
+
+	// 델타 저장 도입 이전에 생성된 테이블에 컬럼 추가
+	if err := ensureColumn(db, "topic_inferences", "encoding", "TEXT NOT NULL DEFAULT 'full'"); err != nil {
+		return err
+	}
+	if err := ensureColumn(db, "topic_inferences", "keyframe_id", "INTEGER"); err != nil {
+		return err
+	}
+
+	// 토픽 추론 데이터 인덱스 생성 (토픽/블록 높이 유일 인덱스와 본문 해시 인덱스는 스키마 마이그레이션에서 생성)
+	for _, index := range topicInferenceIndexes {
+		if _, err = db.Exec(index); err != nil {
+			return err
+		}
+	}
+
+	// 토픽 지표 롤업 테이블 생성 (시간/일 단위)
 	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS topic_inferences (
-			id INTEGER PRIMARY KEY,
+		CREATE TABLE IF NOT EXISTS topic_rollups (
 			topic_id TEXT NOT NULL,
-			timestamp TEXT NOT NULL,
-			inference_block_height TEXT NOT NULL,
-			loss_block_height TEXT NOT NULL,
-			data BLOB NOT NULL
+			resolution TEXT NOT NULL,
+			bucket_start TEXT NOT NULL,
+			sample_count INTEGER NOT NULL,
+			combined_avg REAL,
+			combined_min REAL,
+			combined_max REAL,
+			naive_avg REAL,
+			worker_count REAL,
+			first_height INTEGER,
+			last_height INTEGER,
+			PRIMARY KEY (topic_id, resolution, bucket_start)
 		)
 	`)
 	if err != nil {
 		return err
 	}
 
-	// 토픽 추론 데이터 인덱스 생성
+	// 워커 지표 롤업 테이블 생성 (시간/일 단위)
 	_, err = db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_topic_inferences_topic_id ON topic_inferences(topic_id)
+		CREATE TABLE IF NOT EXISTS worker_rollups (
+			topic_id TEXT NOT NULL,
+			resolution TEXT NOT NULL,
+			bucket_start TEXT NOT NULL,
+			worker TEXT NOT NULL,
+			sample_count INTEGER NOT NULL,
+			inferer_avg REAL,
+			inferer_min REAL,
+			inferer_max REAL,
+			one_out_avg REAL,
+			weight_avg REAL,
+			PRIMARY KEY (topic_id, resolution, bucket_start, worker)
+		)
 	`)
 	if err != nil {
 		return err
 	}
 
+	// 무결성 검사에서 격리된 행 테이블 (원본 행 전체를 JSON으로 보관)
 	_, err = db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_topic_inferences_timestamp ON topic_inferences(timestamp)
+		CREATE TABLE IF NOT EXISTS quarantined_rows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			source_table TEXT NOT NULL,
+			source_id INTEGER NOT NULL,
+			reason TEXT NOT NULL,
+			row_data TEXT NOT NULL,
+			quarantined_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// 토픽 샤드 카탈로그 (샤드 파일 이름은 샤드 디렉토리 기준)
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS topic_shards (
+			topic_id TEXT PRIMARY KEY,
+			file_name TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// 보관된 토픽 샤드 파일 기록
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS topic_shard_archives (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			topic_id TEXT NOT NULL,
+			path TEXT NOT NULL,
+			row_count INTEGER NOT NULL,
+			size_bytes INTEGER NOT NULL,
+			archived_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// 리더 선출 임대 테이블 (임대 이름마다 한 행, 만료 시각은 유닉스 밀리초)
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS leader_leases (
+			name TEXT PRIMARY KEY,
+			holder TEXT NOT NULL,
+			acquired_at TEXT NOT NULL,
+			renewed_at TEXT NOT NULL,
+			expires_at INTEGER NOT NULL
+		)
 	`)
 
 	return err
 }
 
+// topicInferencesTableSQL은 토픽 스냅샷 테이블 정의입니다 (메인 데이터베이스와 토픽 샤드 파일에서 공통으로 사용)
+const topicInferencesTableSQL = `
+	CREATE TABLE IF NOT EXISTS topic_inferences (
+		id INTEGER PRIMARY KEY,
+		topic_id TEXT NOT NULL,
+		timestamp TEXT NOT NULL,
+		inference_block_height INTEGER NOT NULL,
+		loss_block_height INTEGER NOT NULL,
+		data BLOB NOT NULL,
+		encoding TEXT NOT NULL DEFAULT 'full',
+		keyframe_id INTEGER,
+		content_hash TEXT
+	)
+`
+
+// ensureColumn은 테이블에 컬럼이 없으면 추가합니다
+func ensureColumn(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return fmt.Errorf("%s 테이블 정보 조회 실패: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("%s 테이블 정보 스캔 실패: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s 테이블 정보 처리 중 오류: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition); err != nil {
+		return fmt.Errorf("%s.%s 컬럼 추가 실패: %w", table, column, err)
+	}
+	return nil
+}
+
 // SaveCompetitions는 경쟁 데이터를 압축하여 저장합니다
-// 이미 존재하는 데이터는 업데이트하고, 새로운 데이터는 삽입합니다
+// 직전 레코드와 내용이 다를 때만 새 이력 레코드를 추가하고, 경쟁별 필드 변경은 competition_changes에 기록합니다
 func (d *Database) SaveCompetitions(data interface{}) error {
 	if d.debug {
 		log.Println("SaveCompetitions 시작")
@@ -204,63 +512,69 @@ func (d *Database) SaveCompetitions(data interface{}) error {
 		}
 	}
 
-	// 데이터 압축
-	compressedData := snappy.Encode(nil, jsonData)
+	// 본문 해시 계산 및 압축
+	content := newBlobContent(jsonData)
 
 	if d.debug {
-		log.Printf("압축 완료: %d 바이트 -> %d 바이트 (압축률: %.2f%%)",
-			len(jsonData), len(compressedData),
-			100.0-(float64(len(compressedData))/float64(len(jsonData))*100.0))
+		log.Printf("압축 완료: %d 바이트 -> %d 바이트 (압축률: %.2f%%), 해시=%s",
+			len(content.json), len(content.blob),
+			100.0-(float64(len(content.blob))/float64(len(content.json))*100.0), content.hash)
 	}
 
 	// 현재 시간
 	timestamp := time.Now().Format(time.RFC3339)
 
-	// 가장 최근 레코드 확인
+	// 가장 최근 레코드의 본문 해시와 비교하여 변경된 경우에만 새 이력 레코드 추가
 	var latestID int
-	var exists bool
-	err = d.db.QueryRow("SELECT id FROM competitions ORDER BY id DESC LIMIT 1").Scan(&latestID)
+	var latestData []byte
+	var latestHash sql.NullString
+	err = d.reader.QueryRow(
+		"SELECT id, "+blobDataColumn+", content_hash FROM competitions ORDER BY id DESC LIMIT 1",
+	).Scan(&latestID, &latestData, &latestHash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("기존 레코드 확인 실패: %w", err)
+	}
+
+	changed := true
 	if err == nil {
-		exists = true
-		if d.debug {
-			log.Printf("기존 레코드 발견: ID=%d", latestID)
+		if latestHash.Valid {
+			changed = latestHash.String != content.hash
+		} else if latestJSON, decodeErr := snappy.Decode(nil, latestData); decodeErr != nil {
+			// 해시가 없는 이전 레코드는 본문을 직접 비교
+			log.Printf("기존 경쟁 데이터 압축 해제 실패 (ID=%d): %v", latestID, decodeErr)
+		} else {
+			changed = contentHash(latestJSON) != content.hash
 		}
-	} else if err != sql.ErrNoRows {
-		return fmt.Errorf("기존 레코드 확인 실패: %w", err)
 	}
 
-	var result sql.Result
-	if exists {
-		// 기존 레코드 업데이트
-		result, err = d.db.Exec(
-			"UPDATE competitions SET timestamp = ?, data = ? WHERE id = ?",
-			timestamp, compressedData, latestID,
-		)
+	if changed {
+		// 본문은 한 번만 저장하고 이력 레코드는 해시로 참조
+		tx, err := d.db.Begin()
 		if err != nil {
-			return fmt.Errorf("데이터 업데이트 실패: %w", err)
+			return fmt.Errorf("트랜잭션 시작 실패: %w", err)
 		}
+		defer tx.Rollback()
 
-		if d.debug {
-			rowsAffected, _ := result.RowsAffected()
-			log.Printf("데이터 업데이트 완료: 영향받은 행 수=%d, ID=%d",
-				rowsAffected, latestID)
+		if err := putSnapshotBlob(tx, content); err != nil {
+			return err
 		}
-	} else {
-		// 새 레코드 삽입
-		result, err = d.db.Exec(
-			"INSERT INTO competitions (timestamp, data) VALUES (?, ?)",
-			timestamp, compressedData,
+		result, err := tx.Exec(
+			"INSERT INTO competitions (timestamp, data, content_hash) VALUES (?, ?, ?)",
+			timestamp, []byte{}, content.hash,
 		)
 		if err != nil {
 			return fmt.Errorf("데이터 삽입 실패: %w", err)
 		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+		}
 
 		if d.debug {
-			rowsAffected, _ := result.RowsAffected()
 			lastInsertID, _ := result.LastInsertId()
-			log.Printf("새 데이터 삽입 완료: 영향받은 행 수=%d, 마지막 삽입 ID=%d",
-				rowsAffected, lastInsertID)
+			log.Printf("변경된 경쟁 데이터 이력 저장 완료: ID=%d", lastInsertID)
 		}
+	} else if d.debug {
+		log.Printf("경쟁 데이터 변경 없음: 최근 레코드 유지 (ID=%d)", latestID)
 	}
 
 	// 새로운 형식으로 competitions_v2 테이블에 저장
@@ -305,86 +619,84 @@ func (d *Database) saveCompetitionsV2(data interface{}) error {
 		}
 	}()
 
-	// 기존 데이터 삭제
-	_, err = tx.Exec("DELETE FROM competitions_v2")
+	// 기존 데이터 조회 (변경 감지용)
+	existing, err := loadCompetitionStates(tx)
 	if err != nil {
-		return fmt.Errorf("기존 데이터 삭제 실패: %w", err)
+		return fmt.Errorf("기존 경쟁 데이터 조회 실패: %w", err)
 	}
 
-	// 현재 시간
-	timestamp := time.Now().Format(time.RFC3339)
+	// 저장된 체인 토픽 활성 여부 (라이프사이클 계산용)
+	chainActivity, err := loadChainActivity(tx)
+	if err != nil {
+		return err
+	}
 
-	// 활성 및 예정 경쟁 저장
-	for _, comp := range competitionsResp.PageProps.CompetitionsPage.ActiveAndUpcomingCompetitions {
-		isActive := isCompetitionActive(comp, false)
+	// 현재 시간 (변경 이력은 문자열 범위로 조회하므로 UTC로 기록)
+	now := time.Now()
+	timestamp := now.UTC().Format(time.RFC3339)
 
-		// 태그를 JSON 문자열로 변환
-		tagsJSON, err := json.Marshal(comp.Tags)
-		if err != nil {
-			return fmt.Errorf("태그 JSON 변환 실패: %w", err)
-		}
+	seen := make(map[int]bool)
+	changeCount := 0
+	var events []pendingEvent
 
-		// 설명 처리 (NULL 가능)
-		var description *string
-		if comp.Description != nil {
-			description = comp.Description
-		}
+	// 활성 및 예정 경쟁 저장
+	for _, comp := range competitionsResp.PageProps.CompetitionsPage.ActiveAndUpcomingCompetitions {
+		isActive := isCompetitionActive(comp, false, chainActivity[comp.ID], now)
 
-		_, err = tx.Exec(
-			`INSERT INTO competitions_v2 (
-				id, name, preview_image_url, description, detailed_description, 
-				topic_id, prize_pool, start_date, end_date, season_id, 
-				tags, is_active, timestamp
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			comp.ID, comp.Name, comp.PreviewImageURL, description, comp.DetailedDescription,
-			comp.TopicID, comp.PrizePool, comp.StartDate.Format(time.RFC3339), comp.EndDate.Format(time.RFC3339), comp.SeasonID,
-			string(tagsJSON), isActive, timestamp,
-		)
+		var changes []CompetitionChange
+		changes, err = upsertCompetitionV2(tx, comp, false, isActive, existing, timestamp)
 		if err != nil {
-			return fmt.Errorf("활성 경쟁 삽입 실패 (ID=%d): %w", comp.ID, err)
+			return fmt.Errorf("활성 경쟁 저장 실패 (ID=%d): %w", comp.ID, err)
 		}
+		seen[comp.ID] = true
+		changeCount += len(changes)
+		events = append(events, competitionChangeEvents(changes, comp.TopicID)...)
 	}
 
 	// 과거 경쟁 저장
 	for _, comp := range competitionsResp.PageProps.CompetitionsPage.PastCompetitions {
-		isActive := isCompetitionActive(comp, true)
+		isActive := isCompetitionActive(comp, true, chainActivity[comp.ID], now)
 
-		// 태그를 JSON 문자열로 변환
-		tagsJSON, err := json.Marshal(comp.Tags)
+		var changes []CompetitionChange
+		changes, err = upsertCompetitionV2(tx, comp, true, isActive, existing, timestamp)
 		if err != nil {
-			return fmt.Errorf("태그 JSON 변환 실패: %w", err)
+			return fmt.Errorf("과거 경쟁 저장 실패 (ID=%d): %w", comp.ID, err)
 		}
+		seen[comp.ID] = true
+		changeCount += len(changes)
+		events = append(events, competitionChangeEvents(changes, comp.TopicID)...)
+	}
 
-		// 설명 처리 (NULL 가능)
-		var description *string
-		if comp.Description != nil {
-			description = comp.Description
+	// 더 이상 목록에 없는 경쟁 삭제
+	for id, state := range existing {
+		if seen[id] {
+			continue
 		}
 
-		_, err = tx.Exec(
-			`INSERT INTO competitions_v2 (
-				id, name, preview_image_url, description, detailed_description, 
-				topic_id, prize_pool, start_date, end_date, season_id, 
-				tags, is_active, timestamp
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			comp.ID, comp.Name, comp.PreviewImageURL, description, comp.DetailedDescription,
-			comp.TopicID, comp.PrizePool, comp.StartDate.Format(time.RFC3339), comp.EndDate.Format(time.RFC3339), comp.SeasonID,
-			string(tagsJSON), isActive, timestamp,
-		)
-		if err != nil {
-			return fmt.Errorf("과거 경쟁 삽입 실패 (ID=%d): %w", comp.ID, err)
+		if _, err = tx.Exec("DELETE FROM competitions_v2 WHERE id = ?", id); err != nil {
+			return fmt.Errorf("사라진 경쟁 삭제 실패 (ID=%d): %w", id, err)
 		}
+
+		removed := state.Name
+		var change CompetitionChange
+		if change, err = recordCompetitionChange(tx, id, competitionFieldRemoved, &removed, nil, timestamp); err != nil {
+			return err
+		}
+		changeCount++
+		events = append(events, competitionChangeEvents([]CompetitionChange{change}, state.TopicID)...)
 	}
 
 	// 트랜잭션 커밋
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("트랜잭션 커밋 실패: %w", err)
 	}
+	d.events.publishAll(events)
 
 	if d.debug {
-		log.Printf("competitions_v2 테이블 저장 완료: 활성 경쟁 %d개, 과거 경쟁 %d개",
+		log.Printf("competitions_v2 테이블 저장 완료: 활성 경쟁 %d개, 과거 경쟁 %d개, 변경 사항 %d개",
 			len(competitionsResp.PageProps.CompetitionsPage.ActiveAndUpcomingCompetitions),
-			len(competitionsResp.PageProps.CompetitionsPage.PastCompetitions))
+			len(competitionsResp.PageProps.CompetitionsPage.PastCompetitions),
+			changeCount)
 	}
 
 	return nil
@@ -396,10 +708,151 @@ func (d *Database) SaveTopicInference(data map[string]interface{}) error {
 		log.Println("SaveTopicInference 시작")
 	}
 
+	// 토픽 샤드를 사용하면 토픽의 샤드 파일에 저장
+	topicID, _ := data["topic_id"].(string)
+	store, err := d.topicStoreForWrite(topicID)
+	if err != nil {
+		return err
+	}
+	defer store.release()
+
+	// 트랜잭션 시작 (델타 체인 조회와 저장을 원자적으로 처리)
+	tx, err := store.db.Begin()
+	if err != nil {
+		return fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+
+	event, err := d.saveTopicInferenceTx(tx, data)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 트랜잭션 커밋
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+
+	// 커밋 후 캐시 무효화 (이전/다음 블록 높이가 바뀔 수 있으므로 토픽 전체)
+	if topicID != "" {
+		d.cache.invalidateTopic(topicID)
+	}
+
+	// 이벤트를 받은 클라이언트가 새 데이터를 조회할 수 있도록 캐시 무효화 후 발행
+	if event != nil {
+		d.events.publishAll([]pendingEvent{*event})
+	}
+
+	return nil
+}
+
+// SaveTopicInferences는 한 수집 주기의 토픽 추론 데이터를 하나의 트랜잭션으로 저장합니다
+// 토픽 샤드를 사용하면 샤드 파일마다 하나의 트랜잭션으로 저장합니다
+// 각 레코드는 세이브포인트로 감싸 실패한 레코드만 되돌리고 나머지는 저장합니다
+// 저장된 레코드 수와 실패한 레코드의 오류를 반환합니다
+func (d *Database) SaveTopicInferences(records []map[string]interface{}) (int, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	if d.debug {
+		log.Printf("SaveTopicInferences 시작: 레코드 수=%d", len(records))
+	}
+
+	// 저장소별로 레코드를 나눔 (샤딩을 사용하지 않으면 메인 데이터베이스 하나)
+	var stores []*topicStore
+	groups := make(map[*topicStore][]map[string]interface{})
+	var errs []error
+	for _, data := range records {
+		topicID, _ := data["topic_id"].(string)
+		store, err := d.topicStoreForWrite(topicID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("토픽 %s 저장소 준비 실패: %w", topicID, err))
+			continue
+		}
+		if _, ok := groups[store]; ok {
+			store.release() // 저장소마다 참조 하나만 유지
+		} else {
+			stores = append(stores, store)
+		}
+		groups[store] = append(groups[store], data)
+	}
+	defer releaseTopicStores(stores)
+
+	saved := 0
+	for _, store := range stores {
+		count, storeErrs, err := d.saveTopicInferenceBatch(store, groups[store])
+		if err != nil {
+			return saved, err
+		}
+		saved += count
+		errs = append(errs, storeErrs...)
+	}
+
+	if d.debug {
+		log.Printf("SaveTopicInferences 완료: 저장=%d, 실패=%d", saved, len(errs))
+	}
+
+	return saved, errors.Join(errs...)
+}
+
+// saveTopicInferenceBatch는 한 저장소의 레코드를 하나의 트랜잭션으로 저장합니다
+// 저장된 레코드 수, 실패한 레코드의 오류, 트랜잭션 오류를 반환합니다
+func (d *Database) saveTopicInferenceBatch(store *topicStore, records []map[string]interface{}) (int, []error, error) {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return 0, nil, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+
+	saved := 0
+	var errs []error
+	var events []pendingEvent
+	for _, data := range records {
+		if _, err := tx.Exec("SAVEPOINT topic_inference"); err != nil {
+			tx.Rollback()
+			return 0, nil, fmt.Errorf("세이브포인트 생성 실패: %w", err)
+		}
+
+		if event, err := d.saveTopicInferenceTx(tx, data); err != nil {
+			errs = append(errs, fmt.Errorf("토픽 %v 저장 실패: %w", data["topic_id"], err))
+			if _, rbErr := tx.Exec("ROLLBACK TO topic_inference"); rbErr != nil {
+				tx.Rollback()
+				return 0, nil, fmt.Errorf("세이브포인트 롤백 실패: %w", rbErr)
+			}
+		} else {
+			saved++
+			if event != nil {
+				events = append(events, *event)
+			}
+		}
+
+		if _, err := tx.Exec("RELEASE topic_inference"); err != nil {
+			tx.Rollback()
+			return 0, nil, fmt.Errorf("세이브포인트 해제 실패: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+
+	for _, data := range records {
+		if topicID, ok := data["topic_id"].(string); ok {
+			d.cache.invalidateTopic(topicID)
+		}
+	}
+	d.events.publishAll(events)
+
+	return saved, errs, nil
+}
+
+// saveTopicInferenceTx는 주어진 트랜잭션 안에서 토픽 추론 데이터 하나를 저장합니다
+// 커밋 후 발행할 이벤트를 반환하며, 타임스탬프 외에 바뀐 내용이 없으면 nil을 반환합니다
+func (d *Database) saveTopicInferenceTx(tx *sql.Tx, data map[string]interface{}) (*pendingEvent, error) {
 	// 필수 필드 확인
 	topicID, ok := data["topic_id"].(string)
 	if !ok {
-		return fmt.Errorf("topic_id 필드가 없거나 문자열이 아닙니다")
+		return nil, fmt.Errorf("topic_id 필드가 없거나 문자열이 아닙니다")
 	}
 
 	timestamp, ok := data["timestamp"].(string)
@@ -409,21 +862,49 @@ func (d *Database) SaveTopicInference(data map[string]interface{}) error {
 
 	inferenceBlockHeight, ok := data["inference_block_height"].(string)
 	if !ok {
-		return fmt.Errorf("inference_block_height 필드가 없거나 문자열이 아닙니다")
+		return nil, fmt.Errorf("inference_block_height 필드가 없거나 문자열이 아닙니다")
 	}
 
 	lossBlockHeight, ok := data["loss_block_height"].(string)
 	if !ok {
-		return fmt.Errorf("loss_block_height 필드가 없거나 문자열이 아닙니다")
+		return nil, fmt.Errorf("loss_block_height 필드가 없거나 문자열이 아닙니다")
+	}
+
+	// 블록 높이는 INTEGER 컬럼에 저장되므로 정수만 허용
+	if _, err := strconv.ParseInt(inferenceBlockHeight, 10, 64); err != nil {
+		return nil, fmt.Errorf("inference_block_height가 정수가 아닙니다: %q", inferenceBlockHeight)
+	}
+	if _, err := strconv.ParseInt(lossBlockHeight, 10, 64); err != nil {
+		return nil, fmt.Errorf("loss_block_height가 정수가 아닙니다: %q", lossBlockHeight)
+	}
+
+	// 데이터를 JSON 호환 객체로 정규화 (델타 계산에 사용)
+	state, _, err := normalizeSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 타임스탬프와 loss_block_height를 제외한 본문의 해시 계산 및 압축
+	content, err := newSnapshotContent(state)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.debug {
+		log.Printf("토픽 %s 데이터 압축 완료: %d 바이트 -> %d 바이트 (압축률: %.2f%%), 해시=%s",
+			topicID, len(content.json), len(content.blob),
+			100.0-(float64(len(content.blob))/float64(len(content.json))*100.0), content.hash)
 	}
 
 	// 기존 레코드 확인 - topic_id와 inference_block_height만으로 확인
-	var existingID int
+	var existingID int64
+	var existingHash sql.NullString
+	var existingLoss string
 	var exists bool
-	err := d.db.QueryRow(
-		"SELECT id FROM topic_inferences WHERE topic_id = ? AND inference_block_height = ?",
+	err = tx.QueryRow(
+		"SELECT id, content_hash, loss_block_height FROM topic_inferences WHERE topic_id = ? AND inference_block_height = ?",
 		topicID, inferenceBlockHeight,
-	).Scan(&existingID)
+	).Scan(&existingID, &existingHash, &existingLoss)
 
 	if err == nil {
 		exists = true
@@ -431,64 +912,71 @@ func (d *Database) SaveTopicInference(data map[string]interface{}) error {
 			log.Printf("기존 토픽 추론 레코드 발견: ID=%d", existingID)
 		}
 	} else if err != sql.ErrNoRows {
-		return fmt.Errorf("기존 레코드 확인 실패: %w", err)
-	}
-
-	// 데이터를 JSON으로 마샬링
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("JSON 마샬링 실패: %w", err)
-	}
-
-	if d.debug {
-		log.Printf("토픽 %s 데이터 JSON 마샬링 완료: %d 바이트", topicID, len(jsonData))
-	}
-
-	// 데이터 압축
-	compressedData := snappy.Encode(nil, jsonData)
+		return nil, fmt.Errorf("기존 레코드 확인 실패: %w", err)
+	}
+	err = nil
+
+	var encoding string
+	if exists && existingHash.Valid && existingHash.String == content.hash {
+		// 본문이 같으면 본문과 델타 체인은 그대로 두고 메타데이터만 업데이트
+		if _, err := tx.Exec(
+			"UPDATE topic_inferences SET timestamp = ?, loss_block_height = ? WHERE id = ?",
+			timestamp, lossBlockHeight, existingID,
+		); err != nil {
+			return nil, fmt.Errorf("토픽 데이터 업데이트 실패: %w", err)
+		}
 
-	if d.debug {
-		log.Printf("토픽 %s 데이터 압축 완료: %d 바이트 -> %d 바이트 (압축률: %.2f%%)",
-			topicID, len(jsonData), len(compressedData),
-			100.0-(float64(len(compressedData))/float64(len(jsonData))*100.0))
-	}
+		if d.debug {
+			log.Printf("토픽 %s 본문 변경 없음: ID=%d, 메타데이터만 업데이트", topicID, existingID)
+		}
 
-	var result sql.Result
-	if exists {
+		if existingLoss == lossBlockHeight {
+			return nil, nil
+		}
+		return &pendingEvent{eventType: EventTypeLossHeight, topicID: topicID, data: LossHeightEvent{
+			TopicID:                 topicID,
+			InferenceBlockHeight:    inferenceBlockHeight,
+			PreviousLossBlockHeight: existingLoss,
+			LossBlockHeight:         lossBlockHeight,
+		}}, nil
+	} else if exists {
 		// 기존 레코드 업데이트 - loss_block_height도 함께 업데이트
-		result, err = d.db.Exec(
-			"UPDATE topic_inferences SET timestamp = ?, loss_block_height = ?, data = ? WHERE id = ?",
-			timestamp, lossBlockHeight, compressedData, existingID,
-		)
+		encoding, err = d.updateSnapshot(tx, existingID, timestamp, lossBlockHeight, content)
 		if err != nil {
-			return fmt.Errorf("토픽 데이터 업데이트 실패: %w", err)
+			return nil, err
+		}
+
+		// 이전 본문을 더 이상 참조하지 않으면 정리
+		if existingHash.Valid {
+			if _, err := gcSnapshotBlobs(tx, []string{existingHash.String}); err != nil {
+				return nil, err
+			}
 		}
 
 		if d.debug {
-			rowsAffected, _ := result.RowsAffected()
-			log.Printf("토픽 %s 데이터 업데이트 완료: 영향받은 행 수=%d, ID=%d",
-				topicID, rowsAffected, existingID)
+			log.Printf("토픽 %s 데이터 업데이트 완료: ID=%d, 저장 방식=%s", topicID, existingID, encoding)
 		}
 	} else {
 		// 새 레코드 삽입
-		result, err = d.db.Exec(
-			"INSERT INTO topic_inferences (topic_id, timestamp, inference_block_height, loss_block_height, data) VALUES (?, ?, ?, ?, ?)",
-			topicID, timestamp, inferenceBlockHeight, lossBlockHeight, compressedData,
-		)
+		var lastInsertID int64
+		lastInsertID, encoding, err = d.insertSnapshot(tx, topicID, timestamp, inferenceBlockHeight, lossBlockHeight, content)
 		if err != nil {
-			return fmt.Errorf("토픽 데이터 저장 실패: %w", err)
+			return nil, err
 		}
 
 		if d.debug {
-			// 영향받은 행 수 확인
-			rowsAffected, _ := result.RowsAffected()
-			lastInsertID, _ := result.LastInsertId()
-			log.Printf("토픽 %s 데이터 저장 완료: 영향받은 행 수=%d, 마지막 삽입 ID=%d",
-				topicID, rowsAffected, lastInsertID)
+			log.Printf("토픽 %s 데이터 저장 완료: 마지막 삽입 ID=%d, 저장 방식=%s", topicID, lastInsertID, encoding)
 		}
 	}
 
-	return nil
+	return &pendingEvent{eventType: EventTypeSnapshot, topicID: topicID, data: SnapshotEvent{
+		TopicID:              topicID,
+		InferenceBlockHeight: inferenceBlockHeight,
+		LossBlockHeight:      lossBlockHeight,
+		Timestamp:            timestamp,
+		ContentHash:          content.hash,
+		Updated:              exists,
+	}}, nil
 }
 
 // GetLatestTopicInference는 지정된 토픽의 가장 최근 추론 데이터를 가져옵니다
@@ -497,15 +985,29 @@ func (d *Database) GetLatestTopicInference(topicID string) (map[string]interface
 		log.Printf("GetLatestTopicInference 시작: 토픽 ID=%s", topicID)
 	}
 
-	var timestamp string
-	var compressedData []byte
-	var inferenceBlockHeight string
+	// 최신 스냅샷이 캐시에 있으면 SQLite를 조회하지 않음
+	cacheKey := snapshotCacheKey{topicID: topicID}
+	if cached, ok := d.cache.get(cacheKey); ok {
+		return cached, nil
+	}
+	cacheGen := d.cache.generation(topicID)
+
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return nil, err
+	}
+	defer store.release()
+
+	var row snapshotRow
+	var inferenceBlockHeight, lossBlockHeight string
+	var storedHash sql.NullString
 
 	// 가장 최근 데이터 조회
-	err := d.db.QueryRow(
-		"SELECT timestamp, data, inference_block_height FROM topic_inferences WHERE topic_id = ? ORDER BY timestamp DESC LIMIT 1",
+	err = store.reader.QueryRow(
+		"SELECT "+snapshotRowColumns+", "+snapshotRowMetadataColumns+", inference_block_height FROM topic_inferences WHERE topic_id = ? ORDER BY inference_block_height DESC LIMIT 1",
 		topicID,
-	).Scan(&timestamp, &compressedData, &inferenceBlockHeight)
+	).Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data, &lossBlockHeight, &storedHash, &inferenceBlockHeight)
+	timestamp := row.Timestamp
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -518,40 +1020,34 @@ func (d *Database) GetLatestTopicInference(topicID string) (map[string]interface
 	}
 
 	if d.debug {
-		log.Printf("토픽 %s 최근 데이터 조회 완료: 타임스탬프=%s, 압축 데이터 크기=%d 바이트",
-			topicID, timestamp, len(compressedData))
+		log.Printf("토픽 %s 최근 데이터 조회 완료: 타임스탬프=%s, 저장 방식=%s, 압축 데이터 크기=%d 바이트",
+			topicID, timestamp, row.Encoding, len(row.Data))
 	}
 
 	// 이전 블록 높이 조회
 	var prevHeight sql.NullString
-	err = d.db.QueryRow(
-		"SELECT inference_block_height FROM topic_inferences WHERE topic_id = ? AND timestamp < ? ORDER BY timestamp DESC LIMIT 1",
-		topicID, timestamp,
+	err = store.reader.QueryRow(
+		"SELECT inference_block_height FROM topic_inferences WHERE topic_id = ? AND inference_block_height < ? ORDER BY inference_block_height DESC LIMIT 1",
+		topicID, inferenceBlockHeight,
 	).Scan(&prevHeight)
 
-	// 데이터 압축 해제
-	jsonData, err := snappy.Decode(nil, compressedData)
+	// 스냅샷 복원 (압축 해제 및 델타 적용)
+	result, err := newSnapshotDecoder(store.reader).decode(row)
 	if err != nil {
-		return nil, fmt.Errorf("압축 해제 실패: %w", err)
-	}
-
-	if d.debug {
-		log.Printf("토픽 %s 데이터 압축 해제 완료: %d 바이트 -> %d 바이트",
-			topicID, len(compressedData), len(jsonData))
+		return nil, fmt.Errorf("토픽 %s 스냅샷 복원 실패: %w", topicID, err)
 	}
-
-	// JSON 언마샬링
-	var result map[string]interface{}
-	if err := json.Unmarshal(jsonData, &result); err != nil {
-		return nil, fmt.Errorf("JSON 언마샬링 실패: %w", err)
+	snapshotHash, err := finishSnapshot(result, row.Timestamp, lossBlockHeight, storedHash)
+	if err != nil {
+		return nil, fmt.Errorf("토픽 %s 스냅샷 해시 계산 실패: %w", topicID, err)
 	}
 
 	if d.debug {
-		log.Printf("토픽 %s 데이터 JSON 언마샬링 완료", topicID)
+		log.Printf("토픽 %s 데이터 복원 완료", topicID)
 	}
 
 	// 데이터 재가공
 	result = d.processTopicInferenceData(result)
+	result["snapshot_hash"] = snapshotHash
 
 	// 중복된 topic_id 제거
 	// 최상위 topic_id는 유지 (API 응답에서 필요할 수 있음)
@@ -569,6 +1065,8 @@ func (d *Database) GetLatestTopicInference(topicID string) (map[string]interface
 
 	result["next_height"] = nil // 최신 데이터이므로 다음 높이는 없음
 
+	d.cache.put(cacheKey, result, cacheGen)
+
 	return result, nil
 }
 
@@ -807,46 +1305,101 @@ func calculateConfidenceInterval(infererValue string, confidenceIntervalValues [
 }
 
 // GetTopicInferencesByTimeRange는 지정된 시간 범위의 토픽 추론 데이터를 가져옵니다
-func (d *Database) GetTopicInferencesByTimeRange(topicID string, start, end time.Time) ([]map[string]interface{}, error) {
+// 라이브 데이터베이스에서 이미 정리된 구간은 아카이브에서 읽어 함께 반환합니다
+// page를 지정하면 블록 높이 오름차순으로 page.After 다음부터 최대 page.Limit개를 조회하고,
+// 다음 페이지가 있으면 다음 페이지의 기준 높이(이번 페이지의 마지막 높이)를 함께 반환합니다
+func (d *Database) GetTopicInferencesByTimeRange(topicID string, start, end time.Time, page PageQuery) ([]map[string]interface{}, string, error) {
 	startStr := start.Format(time.RFC3339)
 	endStr := end.Format(time.RFC3339)
 
 	if d.debug {
-		log.Printf("GetTopicInferencesByTimeRange 시작: 토픽 ID=%s, %s ~ %s", topicID, startStr, endStr)
+		log.Printf("GetTopicInferencesByTimeRange 시작: 토픽 ID=%s, %s ~ %s, 기준 높이=%s, 제한=%d", topicID, startStr, endStr, page.After, page.Limit)
 	}
 
-	rows, err := d.db.Query(
-		"SELECT timestamp, data FROM topic_inferences WHERE topic_id = ? AND timestamp BETWEEN ? AND ? ORDER BY timestamp",
-		topicID, startStr, endStr,
-	)
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return nil, "", err
+	}
+	defer store.release()
+
+	// 라이브 데이터베이스에 없는 오래된 구간은 아카이브에서 읽음 (라이브 구간보다 항상 앞)
+	archived, err := d.getArchivedTopicInferences(topicID, start, end)
+	if err != nil {
+		return nil, "", fmt.Errorf("아카이브 조회 실패: %w", err)
+	}
+	if page.Paged() {
+		archived = topicSnapshotsAfter(archived, page.After, page.Limit+1)
+	}
+
+	results := archived
+	if !page.Paged() || len(results) <= page.Limit {
+		live, err := d.queryTopicInferencesInRange(store, topicID, startStr, endStr, page, page.Limit+1-len(results))
+		if err != nil {
+			return nil, "", err
+		}
+		results = append(results, live...)
+	}
+
+	var next string
+	if page.Paged() && len(results) > page.Limit {
+		results = results[:page.Limit]
+		next = fmt.Sprint(results[page.Limit-1]["inference_block_height"])
+	}
+
+	if d.debug {
+		log.Printf("GetTopicInferencesByTimeRange 완료: 토픽 ID=%s, %d개 결과 반환", topicID, len(results))
+	}
+
+	return results, next, nil
+}
+
+// queryTopicInferencesInRange는 라이브 데이터베이스에서 기간 내 스냅샷을 복원합니다
+// page를 지정하면 블록 높이 오름차순으로 page.After 다음부터 최대 limit개를, 아니면 전체를 수집 시각 순으로 조회합니다
+func (d *Database) queryTopicInferencesInRange(store *topicStore, topicID, startStr, endStr string, page PageQuery, limit int) ([]map[string]interface{}, error) {
+	query := "SELECT " + snapshotRowColumns + ", " + snapshotRowMetadataColumns + " FROM topic_inferences WHERE topic_id = ? AND timestamp BETWEEN ? AND ?"
+	args := []interface{}{topicID, startStr, endStr}
+	if page.Paged() {
+		if page.After != "" {
+			query += " AND inference_block_height > CAST(? AS INTEGER)"
+			args = append(args, page.After)
+		}
+		query += " ORDER BY inference_block_height LIMIT ?"
+		args = append(args, limit)
+	} else {
+		query += " ORDER BY timestamp"
+	}
+
+	rows, err := store.reader.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("토픽 데이터 조회 실패: %w", err)
 	}
 	defer rows.Close()
 
+	// 같은 체인의 델타를 연속으로 복원할 때 이전 결과를 재사용
+	decoder := newSnapshotDecoder(store.reader)
+
 	var results []map[string]interface{}
 	for rows.Next() {
-		var timestamp string
-		var compressedData []byte
-
-		if err := rows.Scan(&timestamp, &compressedData); err != nil {
+		var row snapshotRow
+		var lossBlockHeight string
+		var storedHash sql.NullString
+		if err := rows.Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data, &lossBlockHeight, &storedHash); err != nil {
 			return nil, fmt.Errorf("데이터 스캔 실패: %w", err)
 		}
 
-		// 데이터 압축 해제
-		jsonData, err := snappy.Decode(nil, compressedData)
+		// 스냅샷 복원 (압축 해제 및 델타 적용)
+		result, err := decoder.decode(row)
 		if err != nil {
-			return nil, fmt.Errorf("압축 해제 실패: %w", err)
+			return nil, fmt.Errorf("스냅샷 복원 실패: %w", err)
 		}
-
-		// JSON 언마샬링
-		var result map[string]interface{}
-		if err := json.Unmarshal(jsonData, &result); err != nil {
-			return nil, fmt.Errorf("JSON 언마샬링 실패: %w", err)
+		snapshotHash, err := finishSnapshot(result, row.Timestamp, lossBlockHeight, storedHash)
+		if err != nil {
+			return nil, fmt.Errorf("스냅샷 해시 계산 실패: %w", err)
 		}
 
 		// 데이터 재가공
 		result = d.processTopicInferenceData(result)
+		result["snapshot_hash"] = snapshotHash
 
 		// 중복된 topic_id 제거
 		if networkInferences, ok := result["network_inferences"].(map[string]interface{}); ok {
@@ -861,35 +1414,56 @@ func (d *Database) GetTopicInferencesByTimeRange(topicID string, start, end time
 		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
 	}
 
-	if d.debug {
-		log.Printf("GetTopicInferencesByTimeRange 완료: 토픽 ID=%s, %d개 결과 반환", topicID, len(results))
-	}
-
 	return results, nil
 }
 
+// topicSnapshotsAfter는 블록 높이 오름차순 스냅샷 중 after보다 높은 스냅샷을 최대 limit개 반환합니다
+func topicSnapshotsAfter(snapshots []map[string]interface{}, after string, limit int) []map[string]interface{} {
+	afterHeight, _ := strconv.ParseInt(after, 10, 64)
+	var results []map[string]interface{}
+	for _, snapshot := range snapshots {
+		height, err := strconv.ParseInt(fmt.Sprint(snapshot["inference_block_height"]), 10, 64)
+		if err != nil || (after != "" && height <= afterHeight) {
+			continue
+		}
+		if len(results) == limit {
+			break
+		}
+		results = append(results, snapshot)
+	}
+	return results
+}
+
 // GetLatestCompetitions는 가장 최근의 경쟁 데이터를 가져옵니다
 func (d *Database) GetLatestCompetitions() (interface{}, error) {
+	result, _, err := d.GetLatestCompetitionsWithHash()
+	return result, err
+}
+
+// GetLatestCompetitionsWithHash는 가장 최근의 경쟁 데이터와 본문 해시를 가져옵니다
+// 해시는 본문이 같으면 항상 같으므로 ETag로 사용할 수 있습니다
+func (d *Database) GetLatestCompetitionsWithHash() (interface{}, string, error) {
 	if d.debug {
 		log.Println("GetLatestCompetitions 시작")
 	}
 
 	var timestamp string
 	var compressedData []byte
+	var storedHash sql.NullString
 
 	// 가장 최근 데이터 조회
-	err := d.db.QueryRow(
-		"SELECT timestamp, data FROM competitions ORDER BY timestamp DESC LIMIT 1",
-	).Scan(&timestamp, &compressedData)
+	err := d.reader.QueryRow(
+		"SELECT timestamp, "+blobDataColumn+", content_hash FROM competitions ORDER BY timestamp DESC LIMIT 1",
+	).Scan(&timestamp, &compressedData, &storedHash)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			if d.debug {
 				log.Println("데이터가 없습니다")
 			}
-			return nil, nil // 데이터가 없는 경우
+			return nil, "", nil // 데이터가 없는 경우
 		}
-		return nil, fmt.Errorf("데이터 조회 실패: %w", err)
+		return nil, "", fmt.Errorf("데이터 조회 실패: %w", err)
 	}
 
 	if d.debug {
@@ -900,7 +1474,7 @@ func (d *Database) GetLatestCompetitions() (interface{}, error) {
 	// 데이터 압축 해제
 	jsonData, err := snappy.Decode(nil, compressedData)
 	if err != nil {
-		return nil, fmt.Errorf("압축 해제 실패: %w", err)
+		return nil, "", fmt.Errorf("압축 해제 실패: %w", err)
 	}
 
 	if d.debug {
@@ -908,91 +1482,140 @@ func (d *Database) GetLatestCompetitions() (interface{}, error) {
 			len(compressedData), len(jsonData))
 	}
 
+	// 해시가 저장되지 않은 이전 레코드는 본문으로 계산
+	hash := storedHash.String
+	if !storedHash.Valid {
+		hash = contentHash(jsonData)
+	}
+
 	// JSON 언마샬링
 	var result interface{}
 	if err := json.Unmarshal(jsonData, &result); err != nil {
-		return nil, fmt.Errorf("JSON 언마샬링 실패: %w", err)
+		return nil, "", fmt.Errorf("JSON 언마샬링 실패: %w", err)
 	}
 
 	if d.debug {
 		log.Println("JSON 언마샬링 완료")
 	}
 
-	return result, nil
+	return result, hash, nil
 }
 
 // GetCompetitionsByTimeRange는 지정된 시간 범위의 경쟁 데이터를 가져옵니다
-func (d *Database) GetCompetitionsByTimeRange(start, end time.Time) ([]interface{}, error) {
+// tier가 auto면 조회 범위와 보존 정책에 맞는 계층을 선택하며, hourly/daily 계층은 구간마다 마지막 이력만 반환합니다
+// page를 지정하면 레코드 ID 순으로 page.After 다음부터 최대 page.Limit개를 조회하고, 다음 페이지가 있으면 다음 페이지의 기준 ID를 함께 반환합니다
+// 선택된 계층을 함께 반환합니다
+func (d *Database) GetCompetitionsByTimeRange(start, end time.Time, tier string, page PageQuery) ([]interface{}, string, string, error) {
 	startStr := start.Format(time.RFC3339)
 	endStr := end.Format(time.RFC3339)
+	if tier == "" || tier == SeriesTierAuto {
+		tier = d.selectSeriesTier("", start, end, time.Now())
+	}
 
 	if d.debug {
-		log.Printf("GetCompetitionsByTimeRange 시작: %s ~ %s", startStr, endStr)
+		log.Printf("GetCompetitionsByTimeRange 시작: %s ~ %s, 계층=%s, 기준 ID=%s, 제한=%d", startStr, endStr, tier, page.After, page.Limit)
 	}
 
-	rows, err := d.db.Query(
-		"SELECT timestamp, data FROM competitions WHERE timestamp BETWEEN ? AND ? ORDER BY timestamp",
-		startStr, endStr,
-	)
+	query := "SELECT id, timestamp, " + blobDataColumn + " FROM competitions WHERE timestamp BETWEEN ? AND ?"
+	args := []interface{}{startStr, endStr}
+	if tier != SeriesTierRaw {
+		// 원본 보존 기간이 지난 이력은 정리 시 구간마다 마지막 행만 남으므로 같은 기준으로 조회
+		query += " AND id IN (SELECT MAX(id) FROM competitions WHERE timestamp BETWEEN ? AND ? GROUP BY " + competitionBucketColumn(tier) + ")"
+		args = append(args, startStr, endStr)
+	}
+	if page.Paged() {
+		// 레코드 ID는 저장 순서와 같으므로 ID를 페이지 키로 사용 (다음 페이지 확인을 위해 하나 더 조회)
+		if page.After != "" {
+			query += " AND id > CAST(? AS INTEGER)"
+			args = append(args, page.After)
+		}
+		query += " ORDER BY id LIMIT ?"
+		args = append(args, page.Limit+1)
+	} else {
+		query += " ORDER BY timestamp"
+	}
+
+	rows, err := d.reader.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("데이터 조회 실패: %w", err)
+		return nil, "", "", fmt.Errorf("데이터 조회 실패: %w", err)
 	}
 	defer rows.Close()
 
 	var results []interface{}
+	var ids []int64
 	for rows.Next() {
+		var id int64
 		var timestamp string
 		var compressedData []byte
 
-		if err := rows.Scan(&timestamp, &compressedData); err != nil {
-			return nil, fmt.Errorf("데이터 스캔 실패: %w", err)
+		if err := rows.Scan(&id, &timestamp, &compressedData); err != nil {
+			return nil, "", "", fmt.Errorf("데이터 스캔 실패: %w", err)
 		}
 
 		// 데이터 압축 해제
 		jsonData, err := snappy.Decode(nil, compressedData)
 		if err != nil {
-			return nil, fmt.Errorf("압축 해제 실패: %w", err)
+			return nil, "", "", fmt.Errorf("압축 해제 실패: %w", err)
 		}
 
 		// JSON 언마샬링
 		var result interface{}
 		if err := json.Unmarshal(jsonData, &result); err != nil {
-			return nil, fmt.Errorf("JSON 언마샬링 실패: %w", err)
+			return nil, "", "", fmt.Errorf("JSON 언마샬링 실패: %w", err)
 		}
 
 		results = append(results, result)
+		ids = append(ids, id)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+		return nil, "", "", fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	var next string
+	if page.Paged() && len(results) > page.Limit {
+		results = results[:page.Limit]
+		next = strconv.FormatInt(ids[page.Limit-1], 10)
 	}
 
 	if d.debug {
 		log.Printf("GetCompetitionsByTimeRange 완료: %d개 결과 반환", len(results))
 	}
 
-	return results, nil
+	return results, next, tier, nil
 }
 
-// PruneOldData는 지정된 기간보다 오래된 데이터를 삭제합니다
+// PruneOldData는 지정된 기간보다 오래된 경쟁 이력을 정리합니다
+// 토픽 데이터의 계층형 보존과 같이 시간 롤업 보존 기간 동안은 시간마다, 그 이후는 하루마다 마지막 이력을 남깁니다
 func (d *Database) PruneOldData(retentionPeriod time.Duration) (int64, error) {
-	cutoffTime := time.Now().Add(-retentionPeriod).Format(time.RFC3339)
+	now := time.Now()
+	cutoffTime := now.Add(-retentionPeriod).Format(time.RFC3339)
+	hourlyCutoff := now.Add(-time.Duration(d.retentionPolicyFor("").HourlyDays) * 24 * time.Hour).Format(time.RFC3339)
 
 	if d.debug {
-		log.Printf("PruneOldData 시작: 기준 시간=%s", cutoffTime)
+		log.Printf("PruneOldData 시작: 기준 시간=%s, 시간 단위 보존 기준=%s", cutoffTime, hourlyCutoff)
 	}
 
-	result, err := d.db.Exec(
-		"DELETE FROM competitions WHERE timestamp < ?",
-		cutoffTime,
+	// 변경이 없으면 새 레코드가 추가되지 않으므로 가장 최근 레코드는 항상 유지
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 삭제한 행만 참조하던 본문도 함께 정리
+	rowsAffected, err := deleteBlobRows(tx,
+		"competitions", `timestamp < ? AND id <> (SELECT MAX(id) FROM competitions)
+			AND id NOT IN (SELECT MAX(id) FROM competitions WHERE timestamp >= ? GROUP BY `+competitionBucketColumn(SeriesTierHourly)+`)
+			AND id NOT IN (SELECT MAX(id) FROM competitions GROUP BY `+competitionBucketColumn(SeriesTierDaily)+`)`,
+		cutoffTime, hourlyCutoff,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("데이터 삭제 실패: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return 0, fmt.Errorf("영향받은 행 수 확인 실패: %w", err)
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
 	}
 
 	if d.debug {
@@ -1002,7 +1625,8 @@ func (d *Database) PruneOldData(retentionPeriod time.Duration) (int64, error) {
 	return rowsAffected, nil
 }
 
-// PruneOldTopicData는 지정된 기간보다 오래된 토픽 데이터를 삭제합니다
+// PruneOldTopicData는 지정된 기간보다 오래된 토픽 데이터와 리더보드 데이터를 삭제합니다
+// 아카이브가 설정되어 있으면 삭제 전에 Parquet 파일로 보관하며, 보관에 실패하면 삭제하지 않습니다
 func (d *Database) PruneOldTopicData(topicID string, retentionPeriod time.Duration) (int64, error) {
 	cutoffTime := time.Now().Add(-retentionPeriod).Format(time.RFC3339)
 
@@ -1010,31 +1634,81 @@ func (d *Database) PruneOldTopicData(topicID string, retentionPeriod time.Durati
 		log.Printf("PruneOldTopicData 시작: 토픽 ID=%s, 기준 시간=%s", topicID, cutoffTime)
 	}
 
-	var result sql.Result
-	var err error
-
-	if topicID == "" {
-		// 모든 토픽 데이터 정리
-		result, err = d.db.Exec(
-			"DELETE FROM topic_inferences WHERE timestamp < ?",
-			cutoffTime,
-		)
-	} else {
-		// 특정 토픽 데이터만 정리
-		result, err = d.db.Exec(
-			"DELETE FROM topic_inferences WHERE topic_id = ? AND timestamp < ?",
-			topicID, cutoffTime,
-		)
+	// 삭제 조건 (모든 토픽 또는 특정 토픽)
+	deleteCondition := "timestamp < ?"
+	args := []interface{}{cutoffTime}
+	if topicID != "" {
+		deleteCondition = "topic_id = ? AND timestamp < ?"
+		args = []interface{}{topicID, cutoffTime}
 	}
 
+	// 토픽 샤드를 사용하면 토픽의 샤드 파일만 (전체 토픽이면 샤드마다 따로) 잠금
+	stores, err := d.topicStoresFor(topicID)
 	if err != nil {
-		return 0, fmt.Errorf("토픽 데이터 삭제 실패: %w", err)
+		return 0, err
+	}
+	defer releaseTopicStores(stores)
+
+	// 삭제하기 전에 스냅샷, 워커 값, 리더보드 데이터를 Parquet 아카이브로 보관
+	if d.archiver != nil {
+		batch, err := d.collectArchiveBatch(stores, deleteCondition, args...)
+		if err != nil {
+			return 0, fmt.Errorf("아카이브 데이터 수집 실패: %w", err)
+		}
+		files, err := d.archiver.write(batch)
+		if err != nil {
+			return 0, fmt.Errorf("아카이브 저장 실패: %w", err)
+		}
+		if d.debug && files > 0 {
+			log.Printf("PruneOldTopicData: 스냅샷 %d개, 리더보드 %d개를 아카이브 파일 %d개로 보관", len(batch.snapshots), len(batch.leaderboard), files)
+		}
+	}
+
+	// 샤드 파일의 스냅샷은 샤드마다 별도의 트랜잭션으로 정리
+	var rowsAffected int64
+	pruneMain := false
+	for _, store := range stores {
+		if !store.isShard() {
+			pruneMain = true
+			continue
+		}
+		deleted, err := d.pruneTopicStore(store, deleteCondition, args...)
+		if err != nil {
+			return rowsAffected, fmt.Errorf("토픽 %s 샤드 정리 실패: %w", store.topicID, err)
+		}
+		rowsAffected += deleted
+		d.cache.invalidateTopic(store.topicID)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	// 트랜잭션 시작 (체인 재구성과 삭제를 원자적으로 처리)
+	tx, err := d.db.Begin()
 	if err != nil {
-		return 0, fmt.Errorf("영향받은 행 수 확인 실패: %w", err)
+		return rowsAffected, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if pruneMain {
+		var deleted int64
+		deleted, err = d.pruneSnapshotsTx(tx, deleteCondition, args...)
+		if err != nil {
+			return rowsAffected, err
+		}
+		rowsAffected += deleted
+	}
+
+	// 같은 기간의 리더보드 데이터도 함께 정리
+	if _, err = tx.Exec("DELETE FROM leaderboard_entries WHERE "+deleteCondition, args...); err != nil {
+		return rowsAffected, fmt.Errorf("리더보드 데이터 삭제 실패: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return rowsAffected, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
 	}
+	d.cache.invalidateTopic(topicID)
 
 	if d.debug {
 		if topicID == "" {
@@ -1047,8 +1721,46 @@ func (d *Database) PruneOldTopicData(topicID string, retentionPeriod time.Durati
 	return rowsAffected, nil
 }
 
+// pruneTopicStore는 토픽 샤드 파일에서 삭제 조건에 해당하는 스냅샷을 하나의 트랜잭션으로 정리합니다
+func (d *Database) pruneTopicStore(store *topicStore, deleteCondition string, args ...interface{}) (int64, error) {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+
+	deleted, err := d.pruneSnapshotsTx(tx, deleteCondition, args...)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+	return deleted, nil
+}
+
+// pruneSnapshotsTx는 삭제될 스냅샷에 의존하는 델타 체인을 재구성한 뒤 스냅샷을 삭제합니다
+func (d *Database) pruneSnapshotsTx(tx *sql.Tx, deleteCondition string, args ...interface{}) (int64, error) {
+	// 삭제될 키프레임/델타에 의존하는 남은 델타를 새 체인으로 재구성
+	rebased, err := d.rebaseSnapshotChains(tx, deleteCondition, args...)
+	if err != nil {
+		return 0, fmt.Errorf("스냅샷 체인 재구성 실패: %w", err)
+	}
+
+	if d.debug && rebased > 0 {
+		log.Printf("PruneOldTopicData: %d개 스냅샷 체인 재구성", rebased)
+	}
+
+	rowsAffected, err := deleteBlobRows(tx, "topic_inferences", deleteCondition, args...)
+	if err != nil {
+		return 0, fmt.Errorf("토픽 데이터 삭제 실패: %w", err)
+	}
+	return rowsAffected, nil
+}
+
 // GetDatabaseStats는 데이터베이스 통계를 반환합니다
-func (d *Database) GetDatabaseStats() (map[string]interface{}, error) {
+func (d *Database) GetDatabaseStats() (*DatabaseStats, error) {
 	if d.debug {
 		log.Println("GetDatabaseStats 시작")
 	}
@@ -1058,79 +1770,100 @@ func (d *Database) GetDatabaseStats() (map[string]interface{}, error) {
 	var totalSizeBytes int64
 
 	// 총 레코드 수
-	err := d.db.QueryRow("SELECT COUNT(*) FROM competitions").Scan(&count)
+	err := d.reader.QueryRow("SELECT COUNT(*) FROM competitions").Scan(&count)
 	if err != nil {
 		return nil, fmt.Errorf("레코드 수 조회 실패: %w", err)
 	}
 
-	// 가장 오래된/최신 타임스탬프
-	err = d.db.QueryRow("SELECT MIN(timestamp) FROM competitions").Scan(&oldestTimestamp)
+	// 가장 오래된/최신 타임스탬프 (레코드가 없으면 빈 문자열)
+	err = d.reader.QueryRow("SELECT COALESCE(MIN(timestamp), '') FROM competitions").Scan(&oldestTimestamp)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("최소 타임스탬프 조회 실패: %w", err)
 	}
 
-	err = d.db.QueryRow("SELECT MAX(timestamp) FROM competitions").Scan(&newestTimestamp)
+	err = d.reader.QueryRow("SELECT COALESCE(MAX(timestamp), '') FROM competitions").Scan(&newestTimestamp)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("최대 타임스탬프 조회 실패: %w", err)
 	}
 
-	// 총 데이터 크기
-	err = d.db.QueryRow("SELECT SUM(LENGTH(data)) FROM competitions").Scan(&totalSizeBytes)
+	// 총 데이터 크기 (본문 저장소를 참조하는 레코드는 참조하는 본문 크기로 계산)
+	err = d.reader.QueryRow("SELECT COALESCE(SUM(LENGTH(" + blobDataColumn + ")), 0) FROM competitions").Scan(&totalSizeBytes)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("데이터 크기 조회 실패: %w", err)
 	}
 
-	// 토픽 데이터 통계
+	// 토픽 데이터 통계 (토픽 샤드를 사용하면 메인 데이터베이스와 모든 샤드를 합산)
 	var topicCount int
 	var topicOldestTimestamp, topicNewestTimestamp string
 	var topicTotalSizeBytes int64
+	uniqueTopics := make(map[string]bool)
 
-	// 총 토픽 레코드 수
-	err = d.db.QueryRow("SELECT COUNT(*) FROM topic_inferences").Scan(&topicCount)
+	stores, err := d.topicStores()
 	if err != nil {
-		return nil, fmt.Errorf("토픽 레코드 수 조회 실패: %w", err)
-	}
-
-	// 가장 오래된/최신 토픽 타임스탬프
-	err = d.db.QueryRow("SELECT MIN(timestamp) FROM topic_inferences").Scan(&topicOldestTimestamp)
-	if err != nil && err != sql.ErrNoRows {
-		return nil, fmt.Errorf("토픽 최소 타임스탬프 조회 실패: %w", err)
-	}
-
-	err = d.db.QueryRow("SELECT MAX(timestamp) FROM topic_inferences").Scan(&topicNewestTimestamp)
-	if err != nil && err != sql.ErrNoRows {
-		return nil, fmt.Errorf("토픽 최대 타임스탬프 조회 실패: %w", err)
+		return nil, err
 	}
-
-	// 총 토픽 데이터 크기
-	err = d.db.QueryRow("SELECT SUM(LENGTH(data)) FROM topic_inferences").Scan(&topicTotalSizeBytes)
-	if err != nil && err != sql.ErrNoRows {
-		return nil, fmt.Errorf("토픽 데이터 크기 조회 실패: %w", err)
+	defer releaseTopicStores(stores)
+	for _, store := range stores {
+		rows, err := store.reader.Query(
+			"SELECT topic_id, COUNT(*), MIN(timestamp), MAX(timestamp), COALESCE(SUM(LENGTH(" + blobDataColumn + ")), 0) FROM topic_inferences GROUP BY topic_id",
+		)
+		if err != nil {
+			return nil, fmt.Errorf("토픽 통계 조회 실패: %w", err)
+		}
+		for rows.Next() {
+			var topicID, oldest, newest string
+			var count int
+			var sizeBytes int64
+			if err := rows.Scan(&topicID, &count, &oldest, &newest, &sizeBytes); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("토픽 통계 스캔 실패: %w", err)
+			}
+			uniqueTopics[topicID] = true
+			topicCount += count
+			topicTotalSizeBytes += sizeBytes
+			if topicOldestTimestamp == "" || oldest < topicOldestTimestamp {
+				topicOldestTimestamp = oldest
+			}
+			if newest > topicNewestTimestamp {
+				topicNewestTimestamp = newest
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("토픽 통계 처리 중 오류: %w", err)
+		}
 	}
+	uniqueTopicCount := len(uniqueTopics)
 
-	// 토픽 종류 수
-	var uniqueTopicCount int
-	err = d.db.QueryRow("SELECT COUNT(DISTINCT topic_id) FROM topic_inferences").Scan(&uniqueTopicCount)
-	if err != nil && err != sql.ErrNoRows {
-		return nil, fmt.Errorf("고유 토픽 수 조회 실패: %w", err)
+	// 본문 저장소 통계 (메인 데이터베이스와 모든 샤드 합산)
+	var blobStats SnapshotBlobStats
+	for _, store := range stores {
+		if err := snapshotBlobStats(store.reader, &blobStats); err != nil {
+			return nil, err
+		}
 	}
 
-	stats := map[string]interface{}{
-		"competitions": map[string]interface{}{
-			"record_count":     count,
-			"oldest_timestamp": oldestTimestamp,
-			"newest_timestamp": newestTimestamp,
-			"total_size_bytes": totalSizeBytes,
-			"total_size_mb":    float64(totalSizeBytes) / (1024 * 1024),
+	stats := &DatabaseStats{
+		Competitions: CompetitionStoreStats{
+			RecordCount:     count,
+			OldestTimestamp: oldestTimestamp,
+			NewestTimestamp: newestTimestamp,
+			TotalSizeBytes:  totalSizeBytes,
+			TotalSizeMB:     float64(totalSizeBytes) / (1024 * 1024),
 		},
-		"topic_inferences": map[string]interface{}{
-			"record_count":       topicCount,
-			"unique_topic_count": uniqueTopicCount,
-			"oldest_timestamp":   topicOldestTimestamp,
-			"newest_timestamp":   topicNewestTimestamp,
-			"total_size_bytes":   topicTotalSizeBytes,
-			"total_size_mb":      float64(topicTotalSizeBytes) / (1024 * 1024),
+		TopicInferences: TopicStoreStats{
+			RecordCount:      topicCount,
+			UniqueTopicCount: uniqueTopicCount,
+			OldestTimestamp:  topicOldestTimestamp,
+			NewestTimestamp:  topicNewestTimestamp,
+			TotalSizeBytes:   topicTotalSizeBytes,
+			TotalSizeMB:      float64(topicTotalSizeBytes) / (1024 * 1024),
 		},
+		SnapshotBlobs: blobStats,
+	}
+
+	if d.shards != nil {
+		stats.TopicInferences.ShardCount = len(stores) - 1
 	}
 
 	if d.debug {
@@ -1143,45 +1876,55 @@ func (d *Database) GetDatabaseStats() (map[string]interface{}, error) {
 }
 
 // GetTopicStats는 특정 토픽의 통계를 반환합니다
-func (d *Database) GetTopicStats(topicID string) (map[string]interface{}, error) {
+// 토픽의 스냅샷이 없으면 nil을 반환합니다
+func (d *Database) GetTopicStats(topicID string) (*TopicStats, error) {
 	if d.debug {
 		log.Printf("GetTopicStats 시작: 토픽 ID=%s", topicID)
 	}
 
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return nil, err
+	}
+	defer store.release()
+
 	var count int
 	var oldestTimestamp, newestTimestamp string
 	var totalSizeBytes int64
 
 	// 총 레코드 수
-	err := d.db.QueryRow("SELECT COUNT(*) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&count)
+	err = store.reader.QueryRow("SELECT COUNT(*) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&count)
 	if err != nil {
 		return nil, fmt.Errorf("토픽 레코드 수 조회 실패: %w", err)
 	}
+	if count == 0 {
+		return nil, nil
+	}
 
 	// 가장 오래된/최신 타임스탬프
-	err = d.db.QueryRow("SELECT MIN(timestamp) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&oldestTimestamp)
+	err = store.reader.QueryRow("SELECT MIN(timestamp) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&oldestTimestamp)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("토픽 최소 타임스탬프 조회 실패: %w", err)
 	}
 
-	err = d.db.QueryRow("SELECT MAX(timestamp) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&newestTimestamp)
+	err = store.reader.QueryRow("SELECT MAX(timestamp) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&newestTimestamp)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("토픽 최대 타임스탬프 조회 실패: %w", err)
 	}
 
 	// 총 데이터 크기
-	err = d.db.QueryRow("SELECT SUM(LENGTH(data)) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&totalSizeBytes)
+	err = store.reader.QueryRow("SELECT SUM(LENGTH("+blobDataColumn+")) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&totalSizeBytes)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("토픽 데이터 크기 조회 실패: %w", err)
 	}
 
-	stats := map[string]interface{}{
-		"topic_id":         topicID,
-		"record_count":     count,
-		"oldest_timestamp": oldestTimestamp,
-		"newest_timestamp": newestTimestamp,
-		"total_size_bytes": totalSizeBytes,
-		"total_size_mb":    float64(totalSizeBytes) / (1024 * 1024),
+	stats := &TopicStats{
+		TopicID:         topicID,
+		RecordCount:     count,
+		OldestTimestamp: oldestTimestamp,
+		NewestTimestamp: newestTimestamp,
+		TotalSizeBytes:  totalSizeBytes,
+		TotalSizeMB:     float64(totalSizeBytes) / (1024 * 1024),
 	}
 
 	if d.debug {
@@ -1192,65 +1935,101 @@ func (d *Database) GetTopicStats(topicID string) (map[string]interface{}, error)
 	return stats, nil
 }
 
-// GetTopicBlockHeights는 특정 토픽의 블록 높이 리스트를 반환합니다
-func (d *Database) GetTopicBlockHeights(topicID string, limit int, offset int) (map[string]interface{}, error) {
+// TopicExists는 토픽의 스냅샷이 하나라도 저장되어 있는지 확인합니다
+func (d *Database) TopicExists(topicID string) (bool, error) {
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return false, err
+	}
+	defer store.release()
+
+	var exists int
+	err = store.reader.QueryRow("SELECT 1 FROM topic_inferences WHERE topic_id = ? LIMIT 1", topicID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("토픽 존재 여부 확인 실패: %w", err)
+	}
+	return true, nil
+}
+
+// GetTopicBlockHeights는 특정 토픽의 블록 높이 리스트를 내림차순으로 반환합니다
+// before가 있으면 before보다 낮은 높이부터(커서), 없으면 offset부터 조회합니다
+// 다음 페이지가 있으면 다음 페이지의 기준 높이(이번 페이지의 마지막 높이)를 함께 반환합니다
+func (d *Database) GetTopicBlockHeights(topicID string, limit int, offset int, before string) (*TopicBlockHeights, string, error) {
 	if d.debug {
-		log.Printf("GetTopicBlockHeights 시작: 토픽 ID=%s, 제한=%d, 오프셋=%d", topicID, limit, offset)
+		log.Printf("GetTopicBlockHeights 시작: 토픽 ID=%s, 제한=%d, 오프셋=%d, 기준 높이=%s", topicID, limit, offset, before)
 	}
 
 	// 기본값 설정
 	if limit <= 0 {
 		limit = 100 // 기본 제한 값
 	}
-	if offset < 0 {
+	if offset < 0 || before != "" {
 		offset = 0
 	}
 
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return nil, "", err
+	}
+	defer store.release()
+
 	// 총 레코드 수 조회
 	var totalCount int
-	err := d.db.QueryRow("SELECT COUNT(*) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&totalCount)
+	err = store.reader.QueryRow("SELECT COUNT(*) FROM topic_inferences WHERE topic_id = ?", topicID).Scan(&totalCount)
 	if err != nil {
-		return nil, fmt.Errorf("토픽 레코드 수 조회 실패: %w", err)
+		return nil, "", fmt.Errorf("토픽 레코드 수 조회 실패: %w", err)
 	}
 
-	// 블록 높이 리스트 조회 (내림차순)
-	rows, err := d.db.Query(
-		"SELECT inference_block_height FROM topic_inferences WHERE topic_id = ? ORDER BY timestamp DESC LIMIT ? OFFSET ?",
-		topicID, limit, offset,
-	)
+	// 블록 높이 리스트 조회 (내림차순, 다음 페이지 확인을 위해 하나 더 조회)
+	query := "SELECT inference_block_height FROM topic_inferences WHERE topic_id = ? ORDER BY inference_block_height DESC LIMIT ? OFFSET ?"
+	args := []interface{}{topicID, limit + 1, offset}
+	if before != "" {
+		query = "SELECT inference_block_height FROM topic_inferences WHERE topic_id = ? AND inference_block_height < CAST(? AS INTEGER) ORDER BY inference_block_height DESC LIMIT ?"
+		args = []interface{}{topicID, before, limit + 1}
+	}
+	rows, err := store.reader.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("블록 높이 조회 실패: %w", err)
+		return nil, "", fmt.Errorf("블록 높이 조회 실패: %w", err)
 	}
 	defer rows.Close()
 
-	var heights []string
+	heights := []string{}
 	for rows.Next() {
 		var inferenceBlockHeight string
 		if err := rows.Scan(&inferenceBlockHeight); err != nil {
-			return nil, fmt.Errorf("데이터 스캔 실패: %w", err)
+			return nil, "", fmt.Errorf("데이터 스캔 실패: %w", err)
 		}
 
 		heights = append(heights, inferenceBlockHeight)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+		return nil, "", fmt.Errorf("결과 처리 중 오류: %w", err)
 	}
 
-	result := map[string]interface{}{
-		"topic_id":      topicID,
-		"total_count":   totalCount,
-		"limit":         limit,
-		"offset":        offset,
-		"heights":       heights,
-		"heights_count": len(heights),
+	var next string
+	if len(heights) > limit {
+		heights = heights[:limit]
+		next = heights[limit-1]
+	}
+
+	result := &TopicBlockHeights{
+		TopicID:      topicID,
+		TotalCount:   totalCount,
+		Limit:        limit,
+		Offset:       offset,
+		Heights:      heights,
+		HeightsCount: len(heights),
 	}
 
 	if d.debug {
 		log.Printf("GetTopicBlockHeights 완료: 토픽 ID=%s, 조회된 높이 수=%d", topicID, len(heights))
 	}
 
-	return result, nil
+	return result, next, nil
 }
 
 // GetTopicInferenceByHeight는 특정 블록 높이에 대한 토픽 추론 데이터를 가져옵니다
@@ -1259,13 +2038,27 @@ func (d *Database) GetTopicInferenceByHeight(topicID string, height string) (map
 		log.Printf("GetTopicInferenceByHeight 시작: 토픽 ID=%s, 블록 높이=%s", topicID, height)
 	}
 
+	cacheKey := snapshotCacheKey{topicID: topicID, height: height}
+	if cached, ok := d.cache.get(cacheKey); ok {
+		return cached, nil
+	}
+	cacheGen := d.cache.generation(topicID)
+
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return nil, err
+	}
+	defer store.release()
+
 	// 특정 블록 높이에 대한 데이터 조회
-	var timestamp string
-	var compressedData []byte
-	err := d.db.QueryRow(
-		"SELECT timestamp, data FROM topic_inferences WHERE topic_id = ? AND inference_block_height = ? ORDER BY timestamp DESC LIMIT 1",
+	var row snapshotRow
+	var lossBlockHeight string
+	var storedHash sql.NullString
+	err = store.reader.QueryRow(
+		"SELECT "+snapshotRowColumns+", "+snapshotRowMetadataColumns+" FROM topic_inferences WHERE topic_id = ? AND inference_block_height = ?",
 		topicID, height,
-	).Scan(&timestamp, &compressedData)
+	).Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data, &lossBlockHeight, &storedHash)
+	timestamp := row.Timestamp
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -1278,47 +2071,41 @@ func (d *Database) GetTopicInferenceByHeight(topicID string, height string) (map
 	}
 
 	if d.debug {
-		log.Printf("토픽 %s 블록 높이 %s 데이터 조회 완료: 타임스탬프=%s, 압축 데이터 크기=%d 바이트",
-			topicID, height, timestamp, len(compressedData))
+		log.Printf("토픽 %s 블록 높이 %s 데이터 조회 완료: 타임스탬프=%s, 저장 방식=%s, 압축 데이터 크기=%d 바이트",
+			topicID, height, timestamp, row.Encoding, len(row.Data))
 	}
 
 	// 이전 블록 높이 조회
 	var prevHeight sql.NullString
-	err = d.db.QueryRow(
-		"SELECT inference_block_height FROM topic_inferences WHERE topic_id = ? AND timestamp < ? ORDER BY timestamp DESC LIMIT 1",
-		topicID, timestamp,
+	err = store.reader.QueryRow(
+		"SELECT inference_block_height FROM topic_inferences WHERE topic_id = ? AND inference_block_height < ? ORDER BY inference_block_height DESC LIMIT 1",
+		topicID, height,
 	).Scan(&prevHeight)
 
 	// 다음 블록 높이 조회
 	var nextHeight sql.NullString
-	err = d.db.QueryRow(
-		"SELECT inference_block_height FROM topic_inferences WHERE topic_id = ? AND timestamp > ? ORDER BY timestamp ASC LIMIT 1",
-		topicID, timestamp,
+	err = store.reader.QueryRow(
+		"SELECT inference_block_height FROM topic_inferences WHERE topic_id = ? AND inference_block_height > ? ORDER BY inference_block_height ASC LIMIT 1",
+		topicID, height,
 	).Scan(&nextHeight)
 
-	// 데이터 압축 해제
-	jsonData, err := snappy.Decode(nil, compressedData)
+	// 스냅샷 복원 (압축 해제 및 델타 적용)
+	result, err := newSnapshotDecoder(store.reader).decode(row)
 	if err != nil {
-		return nil, fmt.Errorf("압축 해제 실패: %w", err)
-	}
-
-	if d.debug {
-		log.Printf("토픽 %s 데이터 압축 해제 완료: %d 바이트 -> %d 바이트",
-			topicID, len(compressedData), len(jsonData))
+		return nil, fmt.Errorf("토픽 %s 스냅샷 복원 실패: %w", topicID, err)
 	}
-
-	// JSON 언마샬링
-	var result map[string]interface{}
-	if err := json.Unmarshal(jsonData, &result); err != nil {
-		return nil, fmt.Errorf("JSON 언마샬링 실패: %w", err)
+	snapshotHash, err := finishSnapshot(result, row.Timestamp, lossBlockHeight, storedHash)
+	if err != nil {
+		return nil, fmt.Errorf("토픽 %s 스냅샷 해시 계산 실패: %w", topicID, err)
 	}
 
 	if d.debug {
-		log.Printf("토픽 %s 데이터 JSON 언마샬링 완료", topicID)
+		log.Printf("토픽 %s 데이터 복원 완료", topicID)
 	}
 
 	// 데이터 재가공
 	result = d.processTopicInferenceData(result)
+	result["snapshot_hash"] = snapshotHash
 
 	// 중복된 topic_id 제거
 	if networkInferences, ok := result["network_inferences"].(map[string]interface{}); ok {
@@ -1363,6 +2150,8 @@ func (d *Database) GetTopicInferenceByHeight(topicID string, height string) (map
 		}
 	}
 
+	d.cache.put(cacheKey, result, cacheGen)
+
 	return result, nil
 }
 
@@ -1382,7 +2171,7 @@ func (d *Database) GetCompetitionIDFromTopicID(topicID string) (string, error) {
 	`
 
 	var competitionID int
-	err := d.db.QueryRow(query, topicID).Scan(&competitionID)
+	err := d.reader.QueryRow(query, topicID).Scan(&competitionID)
 	if err == nil {
 		// competitions_v2 테이블에서 찾은 경우
 		if d.debug {
@@ -1486,24 +2275,18 @@ func (d *Database) GetCompetitionIDFromTopicID(topicID string) (string, error) {
 	return "", fmt.Errorf("토픽 ID %s에 해당하는 경쟁 데이터가 없습니다", topicID)
 }
 
-// isCompetitionActive는 경쟁이 활성 상태인지 확인합니다
-// 활성 상태 조건:
-// 1. topic_id가 0이 아님
-// 2. pastCompetitions에 포함되지 않음
-// 3. tags가 비어있음
-func isCompetitionActive(competition Competition, isPastCompetition bool) bool {
-	// 조건 1: topic_id가 0이 아님
-	if competition.TopicID == 0 {
-		return false
-	}
-
-	// 조건 2: pastCompetitions에 포함되지 않음
-	if isPastCompetition {
-		return false
-	}
-
-	// 조건 3: tags가 비어있음
-	return len(competition.Tags) == 0
+// isCompetitionActive는 경쟁이 활성 상태(데이터 수집 대상)인지 확인합니다
+// 시작/종료 일시, pastCompetitions 포함 여부, 체인 토픽 활성 여부로 계산한
+// 라이프사이클이 running 또는 ending-soon이면 활성 상태입니다
+func isCompetitionActive(competition Competition, isPastCompetition bool, chainActive *bool, now time.Time) bool {
+	state := computeCompetitionLifecycle(lifecycleInput{
+		TopicID:      competition.TopicID,
+		StartDate:    competition.StartDate,
+		EndDate:      competition.EndDate,
+		ListedAsPast: isPastCompetition,
+		ChainActive:  chainActive,
+	}, now)
+	return IsCollectingLifecycle(state)
 }
 
 // CompetitionV2는 새로운 형식의 경쟁 정보 구조체입니다
@@ -1520,6 +2303,9 @@ type CompetitionV2 struct {
 	SeasonID  int       `json:"season_id"`
 	Tags      []string  `json:"tags"`
 	IsActive  bool      `json:"is_active"`
+	// 라이프사이클 상태와 해당 상태에 진입한 시각
+	Lifecycle      string  `json:"lifecycle"`
+	LifecycleSince *string `json:"lifecycle_since"`
 }
 
 // GetCompetitionsV2는 새로운 형식의 경쟁 데이터를 가져옵니다
@@ -1528,12 +2314,13 @@ func (d *Database) GetCompetitionsV2() ([]CompetitionV2, error) {
 		log.Println("GetCompetitionsV2 시작")
 	}
 
-	rows, err := d.db.Query(
+	rows, err := d.reader.Query(
 		`SELECT 
 			id, name, preview_image_url, description, 
 			topic_id, prize_pool, start_date, end_date, season_id, 
-			tags, is_active 
+			tags, is_active, COALESCE(l.state, ''), l.entered_at
 		FROM competitions_v2 
+		LEFT JOIN competition_lifecycle l ON l.competition_id = competitions_v2.id
 		ORDER BY id`,
 	)
 	if err != nil {
@@ -1546,11 +2333,12 @@ func (d *Database) GetCompetitionsV2() ([]CompetitionV2, error) {
 		var comp CompetitionV2
 		var startDateStr, endDateStr string
 		var tagsJSON string
+		var lifecycleSince sql.NullString
 
 		err := rows.Scan(
 			&comp.ID, &comp.Name, &comp.PreviewImageURL, &comp.Description,
 			&comp.TopicID, &comp.PrizePool, &startDateStr, &endDateStr, &comp.SeasonID,
-			&tagsJSON, &comp.IsActive,
+			&tagsJSON, &comp.IsActive, &comp.Lifecycle, &lifecycleSince,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("데이터 스캔 실패: %w", err)
@@ -1572,6 +2360,10 @@ func (d *Database) GetCompetitionsV2() ([]CompetitionV2, error) {
 			return nil, fmt.Errorf("태그 JSON 파싱 실패: %w", err)
 		}
 
+		if lifecycleSince.Valid {
+			comp.LifecycleSince = &lifecycleSince.String
+		}
+
 		competitions = append(competitions, comp)
 	}
 
@@ -1592,12 +2384,13 @@ func (d *Database) GetActiveCompetitionsV2() ([]CompetitionV2, error) {
 		log.Println("GetActiveCompetitionsV2 시작")
 	}
 
-	rows, err := d.db.Query(
+	rows, err := d.reader.Query(
 		`SELECT 
 			id, name, preview_image_url, description, 
 			topic_id, prize_pool, start_date, end_date, season_id, 
-			tags, is_active 
+			tags, is_active, COALESCE(l.state, ''), l.entered_at
 		FROM competitions_v2 
+		LEFT JOIN competition_lifecycle l ON l.competition_id = competitions_v2.id
 		WHERE is_active = 1
 		ORDER BY id`,
 	)
@@ -1611,11 +2404,12 @@ func (d *Database) GetActiveCompetitionsV2() ([]CompetitionV2, error) {
 		var comp CompetitionV2
 		var startDateStr, endDateStr string
 		var tagsJSON string
+		var lifecycleSince sql.NullString
 
 		err := rows.Scan(
 			&comp.ID, &comp.Name, &comp.PreviewImageURL, &comp.Description,
 			&comp.TopicID, &comp.PrizePool, &startDateStr, &endDateStr, &comp.SeasonID,
-			&tagsJSON, &comp.IsActive,
+			&tagsJSON, &comp.IsActive, &comp.Lifecycle, &lifecycleSince,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("데이터 스캔 실패: %w", err)
@@ -1637,6 +2431,10 @@ func (d *Database) GetActiveCompetitionsV2() ([]CompetitionV2, error) {
 			return nil, fmt.Errorf("태그 JSON 파싱 실패: %w", err)
 		}
 
+		if lifecycleSince.Valid {
+			comp.LifecycleSince = &lifecycleSince.String
+		}
+
 		competitions = append(competitions, comp)
 	}
 
@@ -1651,7 +2449,7 @@ func (d *Database) GetActiveCompetitionsV2() ([]CompetitionV2, error) {
 	return competitions, nil
 }
 
-// GetCompetitionByTopicID는 토픽 ID에 해당하는 경쟁 정보를 competitions_v2 테이블에서 가져옵니다
+// GetCompetitionByTopicID는 토픽 ID에 해당하는 경쟁 정보를 competitions_v2 테이블에서 가져옵니다 (없으면 nil)
 func (d *Database) GetCompetitionByTopicID(topicID string) (*CompetitionV2, error) {
 	if d.debug {
 		log.Printf("GetCompetitionByTopicID 시작: 토픽 ID=%s", topicID)
@@ -1663,14 +2461,44 @@ func (d *Database) GetCompetitionByTopicID(topicID string) (*CompetitionV2, erro
 		return nil, fmt.Errorf("토픽 ID를 정수로 변환 실패: %w", err)
 	}
 
-	// competitions_v2 테이블에서 조회
+	comp, err := d.queryCompetitionV2("topic_id = ?", topicIDInt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			if d.debug {
+				log.Printf("토픽 ID %s에 해당하는 경쟁 데이터 없음", topicID)
+			}
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if d.debug {
+		log.Printf("토픽 ID %s의 경쟁 정보 조회 완료: ID=%d, 이름=%s", topicID, comp.ID, comp.Name)
+	}
+
+	return comp, nil
+}
+
+// GetCompetitionV2ByID는 경쟁 ID에 해당하는 경쟁 정보를 가져옵니다 (없으면 nil)
+func (d *Database) GetCompetitionV2ByID(competitionID int) (*CompetitionV2, error) {
+	comp, err := d.queryCompetitionV2("id = ?", competitionID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return comp, err
+}
+
+// queryCompetitionV2는 조건에 맞는 가장 최근 경쟁 정보 한 건을 조회합니다
+// 조건에 맞는 경쟁이 없으면 sql.ErrNoRows를 그대로 반환합니다
+func (d *Database) queryCompetitionV2(condition string, args ...interface{}) (*CompetitionV2, error) {
 	query := `
 		SELECT 
 			id, name, preview_image_url, description, 
 			topic_id, prize_pool, start_date, end_date, season_id, 
-			tags, is_active 
+			tags, is_active, COALESCE(l.state, ''), l.entered_at
 		FROM competitions_v2 
-		WHERE topic_id = ? 
+		LEFT JOIN competition_lifecycle l ON l.competition_id = competitions_v2.id
+		WHERE ` + condition + ` 
 		ORDER BY timestamp DESC 
 		LIMIT 1
 	`
@@ -1678,19 +2506,16 @@ func (d *Database) GetCompetitionByTopicID(topicID string) (*CompetitionV2, erro
 	var comp CompetitionV2
 	var startDateStr, endDateStr string
 	var tagsJSON string
+	var lifecycleSince sql.NullString
 
-	err = d.db.QueryRow(query, topicIDInt).Scan(
+	err := d.reader.QueryRow(query, args...).Scan(
 		&comp.ID, &comp.Name, &comp.PreviewImageURL, &comp.Description,
 		&comp.TopicID, &comp.PrizePool, &startDateStr, &endDateStr, &comp.SeasonID,
-		&tagsJSON, &comp.IsActive,
+		&tagsJSON, &comp.IsActive, &comp.Lifecycle, &lifecycleSince,
 	)
-
 	if err != nil {
 		if err == sql.ErrNoRows {
-			if d.debug {
-				log.Printf("토픽 ID %s에 해당하는 경쟁 데이터 없음", topicID)
-			}
-			return nil, fmt.Errorf("토픽 ID %s에 해당하는 경쟁 데이터가 없습니다", topicID)
+			return nil, err
 		}
 		return nil, fmt.Errorf("경쟁 데이터 조회 실패: %w", err)
 	}
@@ -1711,8 +2536,8 @@ func (d *Database) GetCompetitionByTopicID(topicID string) (*CompetitionV2, erro
 		return nil, fmt.Errorf("태그 JSON 파싱 실패: %w", err)
 	}
 
-	if d.debug {
-		log.Printf("토픽 ID %s의 경쟁 정보 조회 완료: ID=%d, 이름=%s", topicID, comp.ID, comp.Name)
+	if lifecycleSince.Valid {
+		comp.LifecycleSince = &lifecycleSince.String
 	}
 
 	return &comp, nil
@@ -1796,6 +2621,7 @@ func (d *Database) SaveLeaderboardEntries(topicID string, blockHeight string, en
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("트랜잭션 커밋 실패: %w", err)
 	}
+	d.cache.invalidateTopic(topicID)
 
 	if d.debug {
 		log.Printf("리더보드 데이터 저장 완료: 토픽 ID=%s, 블록 높이=%s, 항목 수=%d", topicID, blockHeight, len(entries))
@@ -1811,7 +2637,7 @@ func (d *Database) GetLeaderboardEntries(topicID string, blockHeight string) ([]
 	}
 
 	// 쿼리 실행
-	rows, err := d.db.Query(`
+	rows, err := d.reader.Query(`
 		SELECT 
 			cosmos_address, username, first_name, last_name, 
 			rank, points, score, loss, is_active
@@ -1905,12 +2731,13 @@ func (d *Database) GetInactiveCompetitionsV2() ([]CompetitionV2, error) {
 		log.Println("GetInactiveCompetitionsV2 시작")
 	}
 
-	rows, err := d.db.Query(
+	rows, err := d.reader.Query(
 		`SELECT 
 			id, name, preview_image_url, description, 
 			topic_id, prize_pool, start_date, end_date, season_id, 
-			tags, is_active 
+			tags, is_active, COALESCE(l.state, ''), l.entered_at
 		FROM competitions_v2 
+		LEFT JOIN competition_lifecycle l ON l.competition_id = competitions_v2.id
 		WHERE is_active = 0
 		ORDER BY id`,
 	)
@@ -1924,11 +2751,12 @@ func (d *Database) GetInactiveCompetitionsV2() ([]CompetitionV2, error) {
 		var comp CompetitionV2
 		var startDateStr, endDateStr string
 		var tagsJSON string
+		var lifecycleSince sql.NullString
 
 		err := rows.Scan(
 			&comp.ID, &comp.Name, &comp.PreviewImageURL, &comp.Description,
 			&comp.TopicID, &comp.PrizePool, &startDateStr, &endDateStr, &comp.SeasonID,
-			&tagsJSON, &comp.IsActive,
+			&tagsJSON, &comp.IsActive, &comp.Lifecycle, &lifecycleSince,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("데이터 스캔 실패: %w", err)
@@ -1950,6 +2778,10 @@ func (d *Database) GetInactiveCompetitionsV2() ([]CompetitionV2, error) {
 			return nil, fmt.Errorf("태그 JSON 파싱 실패: %w", err)
 		}
 
+		if lifecycleSince.Valid {
+			comp.LifecycleSince = &lifecycleSince.String
+		}
+
 		competitions = append(competitions, comp)
 	}
 
@@ -0,0 +1,252 @@
+package app
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 벤치마크용 합성 데이터 규모
+const (
+	benchTopics  = 10
+	benchWorkers = 50
+	benchReaders = 8
+)
+
+// benchPool은 비교할 데이터베이스 연결 구성입니다
+// strict이면 쓰기/조회 오류가 벤치마크 실패이고, 아니면 오류 수를 지표로만 보고합니다
+type benchPool struct {
+	name   string
+	open   func(b *testing.B) *Database
+	strict bool
+}
+
+// benchPools는 현재 구성(WAL, 단일 쓰기 연결, 읽기 전용 풀)과 이전 구성(sql.Open 풀 하나)입니다
+var benchPools = []benchPool{
+	{name: "single-writer", open: newBenchDatabase, strict: true},
+	{name: "shared-pool", open: newSharedPoolBenchDatabase, strict: false},
+}
+
+// newBenchDatabase는 스냅샷 캐시 없이 모든 조회가 SQLite 읽기 풀을 거치는 데이터베이스를 만듭니다
+func newBenchDatabase(b *testing.B) *Database {
+	b.Helper()
+	db := newTestDatabase(b)
+	db.cache = nil
+	return db
+}
+
+// newSharedPoolBenchDatabase는 비교 기준으로 WAL과 busy_timeout 없이 sql.Open 풀 하나가
+// 읽기와 쓰기를 모두 처리하던 이전 방식의 데이터베이스를 만듭니다 (스냅샷 캐시 없음)
+func newSharedPoolBenchDatabase(b *testing.B) *Database {
+	b.Helper()
+	path := filepath.Join(b.TempDir(), "shared.db")
+	pool, err := sql.Open("sqlite", path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := initDatabase(pool); err != nil {
+		pool.Close()
+		b.Fatal(err)
+	}
+	db := &Database{
+		db:               pool,
+		reader:           pool,
+		keyframeInterval: defaultSnapshotKeyframeInterval,
+		retention:        RetentionPolicy{RawDays: defaultRawRetentionDays, HourlyDays: defaultHourlyRetentionDays},
+		main:             &topicStore{path: path, db: pool, reader: pool},
+	}
+	if err := db.migrateSchema(); err != nil {
+		pool.Close()
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { pool.Close() })
+	return db
+}
+
+// reportBenchErrors는 오류 수를 지표로 보고하고, strict 구성에서 오류가 있으면 벤치마크를 실패시킵니다
+func reportBenchErrors(b *testing.B, pool benchPool, kind string, errors int64) {
+	b.Helper()
+	b.ReportMetric(float64(errors), kind+"-errors")
+	if pool.strict && errors > 0 {
+		b.Fatalf("%s 오류 %d건", kind, errors)
+	}
+}
+
+// startBenchReaders는 stop이 닫힐 때까지 토픽별 최신 스냅샷을 계속 조회하는 고루틴 n개를 실행합니다
+// 반환된 함수는 조회를 멈추고 성공한 조회 수와 조회 오류 수를 반환합니다
+func startBenchReaders(db *Database, n int) func() (int64, int64) {
+	var reads, readErrors int64
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(reader int) {
+			defer wg.Done()
+			topicID := strconv.Itoa(reader%benchTopics + 1)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if _, err := db.GetLatestTopicInference(topicID); err != nil {
+					atomic.AddInt64(&readErrors, 1)
+				} else {
+					atomic.AddInt64(&reads, 1)
+				}
+			}
+		}(i)
+	}
+
+	return func() (int64, int64) {
+		close(stop)
+		wg.Wait()
+		return reads, readErrors
+	}
+}
+
+// BenchmarkSingleWriterUnderContention은 여러 고루틴이 동시에 스냅샷을 저장할 때
+// 단일 쓰기 연결이 잠금 오류 없이 쓰기를 직렬화하는지와 그동안의 조회 처리량을 이전 공유 풀과 비교합니다
+func BenchmarkSingleWriterUnderContention(b *testing.B) {
+	for _, pool := range benchPools {
+		for _, readers := range []int{0, benchReaders} {
+			pool, readers := pool, readers
+			b.Run("pool="+pool.name+"/readers="+strconv.Itoa(readers), func(b *testing.B) {
+				db := pool.open(b)
+				base := time.Now().Add(-time.Hour)
+				workers := testWorkers(benchWorkers)
+				var next, writeErrors int64
+
+				stopReaders := startBenchReaders(db, readers)
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						n := int(atomic.AddInt64(&next, 1))
+						topicID := strconv.Itoa(n%benchTopics + 1)
+						record := testSnapshot(topicID, 1000000+n*10, base.Add(time.Duration(n)*time.Second), workers, n)
+						if err := db.SaveTopicInference(record); err != nil {
+							atomic.AddInt64(&writeErrors, 1)
+						}
+					}
+				})
+				b.StopTimer()
+				reads, readErrors := stopReaders()
+
+				b.ReportMetric(float64(int64(b.N)-writeErrors)/b.Elapsed().Seconds(), "writes/s")
+				reportBenchErrors(b, pool, "write", writeErrors)
+				if readers > 0 {
+					b.ReportMetric(float64(reads)/b.Elapsed().Seconds(), "reads/s")
+					reportBenchErrors(b, pool, "read", readErrors)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkSaveTopicInferencesBatch는 수집 주기마다 모든 토픽을 한 트랜잭션으로 저장하는 처리량을
+// 레코드마다 트랜잭션 하나로 저장하는 이전 방식과 비교합니다
+func BenchmarkSaveTopicInferencesBatch(b *testing.B) {
+	modes := []struct {
+		name string
+		save func(db *Database, records []map[string]interface{}) error
+	}{
+		{"batch", func(db *Database, records []map[string]interface{}) error {
+			_, err := db.SaveTopicInferences(records)
+			return err
+		}},
+		{"per-record", func(db *Database, records []map[string]interface{}) error {
+			for _, record := range records {
+				if err := db.SaveTopicInference(record); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+	}
+	for _, mode := range modes {
+		mode := mode
+		b.Run("mode="+mode.name, func(b *testing.B) {
+			db := newBenchDatabase(b)
+			base := time.Now().Add(-time.Hour)
+			workers := testWorkers(benchWorkers)
+
+			stopReaders := startBenchReaders(db, benchReaders)
+			b.ResetTimer()
+			for cycle := 0; cycle < b.N; cycle++ {
+				records := make([]map[string]interface{}, 0, benchTopics)
+				for topic := 1; topic <= benchTopics; topic++ {
+					records = append(records, testSnapshot(strconv.Itoa(topic), 1000000+cycle*10, base.Add(time.Duration(cycle)*time.Second), workers, cycle))
+				}
+				if err := mode.save(db, records); err != nil {
+					b.Fatalf("주기 %d 저장 실패: %v", cycle, err)
+				}
+			}
+			b.StopTimer()
+			reads, readErrors := stopReaders()
+			if readErrors > 0 {
+				b.Fatalf("쓰기 중 조회 오류 %d건", readErrors)
+			}
+
+			b.ReportMetric(float64(b.N*benchTopics)/b.Elapsed().Seconds(), "writes/s")
+			b.ReportMetric(float64(reads)/b.Elapsed().Seconds(), "reads/s")
+		})
+	}
+}
+
+// BenchmarkReaderPool은 쓰기가 계속되는 동안 최신 스냅샷 동시 조회 처리량을 읽기 전용 풀과 이전 공유 풀에서 비교합니다
+func BenchmarkReaderPool(b *testing.B) {
+	for _, pool := range benchPools {
+		pool := pool
+		b.Run("pool="+pool.name, func(b *testing.B) {
+			db := pool.open(b)
+			base := time.Now().Add(-time.Hour)
+			workers := testWorkers(benchWorkers)
+			for topic := 1; topic <= benchTopics; topic++ {
+				if err := db.SaveTopicInference(testSnapshot(strconv.Itoa(topic), 1000000, base, workers, 0)); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			// 백그라운드 쓰기: WAL을 사용하면 조회가 쓰기 트랜잭션을 기다리지 않아야 함
+			var writeErrors, readErrors int64
+			stop := make(chan struct{})
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for cycle := 1; ; cycle++ {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					topicID := strconv.Itoa(cycle%benchTopics + 1)
+					if err := db.SaveTopicInference(testSnapshot(topicID, 1000000+cycle*10, base.Add(time.Duration(cycle)*time.Second), workers, cycle)); err != nil {
+						atomic.AddInt64(&writeErrors, 1)
+					}
+				}
+			}()
+
+			var next int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				topicID := strconv.Itoa(int(atomic.AddInt64(&next, 1))%benchTopics + 1)
+				for pb.Next() {
+					if snapshot, err := db.GetLatestTopicInference(topicID); err != nil || snapshot == nil {
+						atomic.AddInt64(&readErrors, 1)
+					}
+				}
+			})
+			b.StopTimer()
+			close(stop)
+			<-done
+
+			b.ReportMetric(float64(int64(b.N)-readErrors)/b.Elapsed().Seconds(), "reads/s")
+			reportBenchErrors(b, pool, "read", readErrors)
+			reportBenchErrors(b, pool, "write", writeErrors)
+		})
+	}
+}
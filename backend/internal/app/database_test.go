@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestDatabase는 테스트 임시 디렉토리에 새 데이터베이스 파일을 만듭니다
+// 디버그 모드는 작업 디렉토리에 파일을 쓰므로 끕니다
+func newTestDatabase(t testing.TB) *Database {
+	t.Helper()
+	return openTestDatabase(t, filepath.Join(t.TempDir(), "test.db"))
+}
+
+// openTestDatabase는 지정한 경로의 데이터베이스를 열고 테스트가 끝나면 닫습니다
+func openTestDatabase(t testing.TB, path string) *Database {
+	t.Helper()
+	db, err := NewDatabase(path)
+	if err != nil {
+		t.Fatalf("데이터베이스 열기 실패: %v", err)
+	}
+	db.SetDebug(false)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// testSnapshot은 workers 순서대로 synthesis_value를 채운 토픽 스냅샷 레코드를 만듭니다
+// cycle이 바뀌면 일부 worker 값만 바뀌므로 연속된 스냅샷은 델타로 저장됩니다
+func testSnapshot(topicID string, height int, timestamp time.Time, workers []string, cycle int) map[string]interface{} {
+	synthesis := make([]interface{}, 0, len(workers))
+	for _, worker := range workers {
+		// 값은 배열 위치가 아닌 worker 번호로 정하므로 순서만 바꿔도 값은 그대로
+		i, _ := strconv.Atoi(strings.TrimPrefix(worker, testWorkerPrefix))
+		value := float64(i)
+		if i%5 == cycle%5 {
+			value += float64(cycle) / 10
+		}
+		synthesis = append(synthesis, map[string]interface{}{
+			"worker":                 worker,
+			"inferer_values":         strconv.FormatFloat(value, 'f', 6, 64),
+			"one_out_inferer_values": strconv.FormatFloat(value/2, 'f', 6, 64),
+			"weight":                 strconv.FormatFloat(float64(i%7)/7, 'f', 6, 64),
+		})
+	}
+
+	return map[string]interface{}{
+		"topic_id":               topicID,
+		"timestamp":              timestamp.Format(time.RFC3339),
+		"inference_block_height": strconv.Itoa(height),
+		"loss_block_height":      strconv.Itoa(height - 10),
+		"network_inferences": map[string]interface{}{
+			"combined_value":  strconv.FormatFloat(float64(cycle)/100, 'f', 6, 64),
+			"naive_value":     strconv.FormatFloat(float64(cycle)/90, 'f', 6, 64),
+			"synthesis_value": synthesis,
+		},
+	}
+}
+
+// testWorkerPrefix는 테스트 worker 주소의 접두사입니다 (뒤에 번호)
+const testWorkerPrefix = "allo1testworker"
+
+// testWorkers는 n개의 worker 주소를 만듭니다
+func testWorkers(n int) []string {
+	workers := make([]string, n)
+	for i := range workers {
+		workers[i] = fmt.Sprintf("%s%04d", testWorkerPrefix, i)
+	}
+	return workers
+}
+
+// storedSnapshot은 저장된 스냅샷 행을 복원해 메타데이터를 제외한 본문, 저장 방식, 저장된 본문 해시(없으면 빈 문자열)를 반환합니다
+func storedSnapshot(t testing.TB, db *Database, topicID, height string) (map[string]interface{}, string, string) {
+	t.Helper()
+	store, err := db.topicStoreFor(topicID)
+	if err != nil {
+		t.Fatalf("토픽 %s 저장소 조회 실패: %v", topicID, err)
+	}
+	defer store.release()
+
+	var row snapshotRow
+	var hash sql.NullString
+	err = store.reader.QueryRow(
+		"SELECT "+snapshotRowColumns+", content_hash FROM topic_inferences WHERE topic_id = ? AND inference_block_height = ?",
+		topicID, height,
+	).Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data, &hash)
+	if err != nil {
+		t.Fatalf("토픽 %s 높이 %s 스냅샷 조회 실패: %v", topicID, height, err)
+	}
+
+	state, err := newSnapshotDecoder(store.reader).decode(row)
+	if err != nil {
+		t.Fatalf("토픽 %s 높이 %s 스냅샷 복원 실패: %v", topicID, height, err)
+	}
+	return stripSnapshotMetadata(state), row.Encoding, hash.String
+}
+
+// expectedSnapshot은 저장 전 레코드를 복원 결과와 비교할 수 있는 형태(JSON 정규화, 메타데이터 제외)로 변환합니다
+func expectedSnapshot(t testing.TB, record map[string]interface{}) *blobContent {
+	t.Helper()
+	state, _, err := normalizeSnapshot(record)
+	if err != nil {
+		t.Fatalf("스냅샷 정규화 실패: %v", err)
+	}
+	content, err := newSnapshotContent(state)
+	if err != nil {
+		t.Fatalf("스냅샷 본문 생성 실패: %v", err)
+	}
+	return content
+}
+
+func TestDatabaseWALReadsDuringWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal.db")
+	db := openTestDatabase(t, path)
+	if err := db.SaveTopicInference(testSnapshot("1", 1000, time.Now(), testWorkers(3), 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	var mode string
+	if err := db.reader.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil || mode != "wal" {
+		t.Fatalf("journal_mode = %q (%v), want wal", mode, err)
+	}
+
+	// 읽기 풀은 읽기 전용
+	if _, err := db.reader.Exec("DELETE FROM topic_inferences"); err == nil {
+		t.Fatal("읽기 연결로 삭제했는데 오류가 없습니다")
+	}
+
+	// 쓰기 트랜잭션이 열려 있는 동안에도 읽기는 기다리지 않고 커밋된 데이터를 봄
+	tx, err := db.db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM topic_inferences"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var count int
+	if err := db.reader.QueryRowContext(ctx, "SELECT COUNT(*) FROM topic_inferences").Scan(&count); err != nil {
+		t.Fatalf("쓰기 트랜잭션 중 조회 실패: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("쓰기 트랜잭션 중 조회된 행 = %d, want 1 (커밋 전 변경이 보이면 안 됨)", count)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.reader.QueryRow("SELECT COUNT(*) FROM topic_inferences").Scan(&count); err != nil || count != 0 {
+		t.Errorf("커밋 후 조회된 행 = %d (%v), want 0", count, err)
+	}
+}
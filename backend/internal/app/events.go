@@ -0,0 +1,385 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 실시간 이벤트
+// 수집기가 새 스냅샷 저장, loss_block_height 갱신, 경쟁 변경, 수집 실패를 EventBroker에 발행하면
+// /api/stream(SSE) 구독자에게 전달됩니다
+// 최근 이벤트는 링 버퍼에 보관하여, 연결이 끊긴 클라이언트가 Last-Event-ID로 놓친 이벤트를 다시 받을 수 있습니다
+
+// 이벤트 종류
+const (
+	EventTypeSnapshot        = "snapshot"         // 새 블록 높이의 스냅샷 저장 또는 같은 높이의 본문 변경
+	EventTypeLossHeight      = "loss_height"      // 기존 스냅샷의 loss_block_height 갱신
+	EventTypeCompetition     = "competition"      // 경쟁 필드 변경 또는 라이프사이클 전이
+	EventTypeCollectionError = "collection_error" // 데이터 수집 실패
+	EventTypeReset           = "reset"            // 요청한 이벤트가 버퍼에 없어 놓친 이벤트가 있음 (클라이언트는 상태를 다시 조회)
+)
+
+// EventTypes는 구독 필터에 사용할 수 있는 이벤트 종류입니다
+var EventTypes = []string{EventTypeSnapshot, EventTypeLossHeight, EventTypeCompetition, EventTypeCollectionError}
+
+// 수집 실패 단계
+const (
+	CollectionStageCompetitions   = "competitions"    // 경쟁 데이터 조회/저장
+	CollectionStageLifecycle      = "lifecycle"       // 경쟁 라이프사이클 갱신
+	CollectionStageTopicInference = "topic_inference" // 토픽 추론 데이터 조회
+	CollectionStageTopicSave      = "topic_save"      // 토픽 스냅샷 저장
+)
+
+// competitionFieldLifecycle은 경쟁 이벤트에서 라이프사이클 전이를 나타내는 필드 이름입니다
+const competitionFieldLifecycle = "lifecycle"
+
+// defaultEventBufferSize는 재전송을 위해 보관하는 최근 이벤트 수입니다
+const defaultEventBufferSize = 1024
+
+// eventSubscriberBuffer는 구독자별 대기 이벤트 수입니다 (가득 차면 구독을 끊음)
+const eventSubscriberBuffer = 256
+
+// SSE 스트림 설정
+const (
+	streamHeartbeatInterval = 15 * time.Second // 프록시가 유휴 연결을 끊지 않도록 보내는 주석 간격
+	streamRetryMillis       = 5000             // 연결이 끊겼을 때 브라우저 EventSource의 재연결 대기 시간
+)
+
+// Event는 구독자에게 전달되는 이벤트입니다
+// ID는 발행 순서대로 증가하며, 브로커 생성 시각(마이크로초)에서 시작하므로 서버를 재시작해도 이전 ID보다 큽니다
+type Event struct {
+	ID        int64       `json:"id"`
+	Type      string      `json:"type"`
+	TopicID   string      `json:"topic_id,omitempty"` // 토픽과 관련 없는 이벤트는 비어 있음
+	Timestamp string      `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// SnapshotEvent는 snapshot 이벤트의 데이터입니다
+type SnapshotEvent struct {
+	TopicID              string `json:"topic_id"`
+	InferenceBlockHeight string `json:"inference_block_height"`
+	LossBlockHeight      string `json:"loss_block_height"`
+	Timestamp            string `json:"timestamp"`
+	ContentHash          string `json:"content_hash"`
+	Updated              bool   `json:"updated"` // 이미 저장된 블록 높이의 본문이 바뀐 경우 true
+}
+
+// LossHeightEvent는 loss_height 이벤트의 데이터입니다
+type LossHeightEvent struct {
+	TopicID                 string `json:"topic_id"`
+	InferenceBlockHeight    string `json:"inference_block_height"`
+	PreviousLossBlockHeight string `json:"previous_loss_block_height"`
+	LossBlockHeight         string `json:"loss_block_height"`
+}
+
+// CompetitionEvent는 competition 이벤트의 데이터입니다
+// Field는 competition_changes의 필드 이름이거나, 라이프사이클 전이이면 lifecycle입니다
+type CompetitionEvent struct {
+	CompetitionID int     `json:"competition_id"`
+	TopicID       string  `json:"topic_id,omitempty"`
+	Field         string  `json:"field"`
+	OldValue      *string `json:"old_value"`
+	NewValue      *string `json:"new_value"`
+	ChangedAt     string  `json:"changed_at"`
+}
+
+// CollectionErrorEvent는 collection_error 이벤트의 데이터입니다
+type CollectionErrorEvent struct {
+	TopicID string `json:"topic_id,omitempty"`
+	Stage   string `json:"stage"`
+	Error   string `json:"error"`
+}
+
+// ResetEvent는 reset 이벤트의 데이터입니다
+type ResetEvent struct {
+	LastEventID   int64 `json:"last_event_id"`   // 클라이언트가 마지막으로 받은 이벤트 ID
+	OldestEventID int64 `json:"oldest_event_id"` // 버퍼에 남아 있는 가장 오래된 이벤트 ID (0이면 버퍼가 비어 있음)
+}
+
+// newCompetitionEvent는 경쟁 변경 이력으로 이벤트 데이터를 만듭니다
+func newCompetitionEvent(change CompetitionChange, topicID int) CompetitionEvent {
+	event := CompetitionEvent{
+		CompetitionID: change.CompetitionID,
+		Field:         change.Field,
+		OldValue:      change.OldValue,
+		NewValue:      change.NewValue,
+		ChangedAt:     change.ChangedAt,
+	}
+	if topicID != 0 {
+		event.TopicID = strconv.Itoa(topicID)
+	}
+	return event
+}
+
+// EventFilter는 구독할 이벤트 조건입니다
+// Topics가 비어 있으면 모든 토픽을, Types가 비어 있으면 모든 종류를 받습니다
+// 토픽과 관련 없는 이벤트(경쟁 목록 수집 실패 등)는 토픽 필터와 관계없이 전달됩니다
+type EventFilter struct {
+	Topics map[string]bool
+	Types  map[string]bool
+}
+
+// Match는 이벤트가 필터 조건에 맞는지 확인합니다
+func (f EventFilter) Match(event Event) bool {
+	if len(f.Types) > 0 && !f.Types[event.Type] {
+		return false
+	}
+	if len(f.Topics) > 0 && event.TopicID != "" && !f.Topics[event.TopicID] {
+		return false
+	}
+	return true
+}
+
+// eventSubscription은 구독자 하나입니다
+// 구독자가 이벤트를 제때 읽지 못해 대기열이 가득 차면 채널이 닫히며, 클라이언트는 Last-Event-ID로 다시 연결합니다
+type eventSubscription struct {
+	events chan Event
+	filter EventFilter
+}
+
+// EventBroker는 이벤트를 발행하고 구독자에게 전달합니다
+// nil 브로커에 발행하면 아무 일도 하지 않으므로 이벤트를 사용하지 않는 명령(백업, 검사 등)에서도 안전합니다
+type EventBroker struct {
+	mu          sync.Mutex
+	buffer      []Event // 최근 이벤트 링 버퍼
+	head        int     // 가장 오래된 이벤트 위치
+	count       int     // 버퍼에 있는 이벤트 수
+	nextID      int64
+	subscribers map[*eventSubscription]struct{}
+	closed      bool
+}
+
+// NewEventBroker는 최근 이벤트를 bufferSize개까지 보관하는 브로커를 생성합니다
+func NewEventBroker(bufferSize int) *EventBroker {
+	if bufferSize <= 0 {
+		bufferSize = defaultEventBufferSize
+	}
+	return &EventBroker{
+		buffer:      make([]Event, bufferSize),
+		nextID:      time.Now().UnixMicro(),
+		subscribers: make(map[*eventSubscription]struct{}),
+	}
+}
+
+// Publish는 이벤트를 버퍼에 추가하고 조건에 맞는 구독자에게 전달합니다
+func (b *EventBroker) Publish(eventType, topicID string, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	event := Event{
+		ID:        b.nextID,
+		Type:      eventType,
+		TopicID:   topicID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Data:      data,
+	}
+	b.nextID++
+
+	if b.count < len(b.buffer) {
+		b.buffer[(b.head+b.count)%len(b.buffer)] = event
+		b.count++
+	} else {
+		b.buffer[b.head] = event
+		b.head = (b.head + 1) % len(b.buffer)
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// 느린 구독자가 발행을 막지 않도록 연결을 끊음
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// PublishCollectionError는 수집 실패 이벤트를 발행합니다
+func (b *EventBroker) PublishCollectionError(stage, topicID string, err error) {
+	b.Publish(EventTypeCollectionError, topicID, CollectionErrorEvent{TopicID: topicID, Stage: stage, Error: err.Error()})
+}
+
+// subscribe는 구독을 등록하고, lastID 이후의 버퍼 이벤트 중 조건에 맞는 이벤트를 함께 반환합니다
+// lastID가 0이면 재전송하지 않으며, lastID 다음 이벤트가 이미 버퍼에서 밀려났으면 reset 이벤트만 반환합니다
+// 브로커가 닫혔으면 닫힌 채널을 가진 구독을 반환합니다
+func (b *EventBroker) subscribe(lastID int64, filter EventFilter) (*eventSubscription, []Event) {
+	sub := &eventSubscription{events: make(chan Event, eventSubscriberBuffer), filter: filter}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(sub.events)
+		return sub, nil
+	}
+	b.subscribers[sub] = struct{}{}
+
+	if lastID <= 0 {
+		return sub, nil
+	}
+
+	oldest := b.nextID // 버퍼가 비어 있으면 다음에 발행될 이벤트부터 받을 수 있음
+	if b.count > 0 {
+		oldest = b.buffer[b.head].ID
+	}
+	if lastID+1 < oldest || lastID >= b.nextID {
+		reset := ResetEvent{LastEventID: lastID}
+		if b.count > 0 {
+			reset.OldestEventID = oldest
+		}
+		// 클라이언트는 상태를 다시 조회하므로 버퍼 이벤트는 보내지 않고, 이후 이벤트부터 이어받도록 마지막 ID를 알려줌
+		return sub, []Event{{
+			ID:        b.nextID - 1,
+			Type:      EventTypeReset,
+			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+			Data:      reset,
+		}}
+	}
+
+	var backlog []Event
+	for i := 0; i < b.count; i++ {
+		event := b.buffer[(b.head+i)%len(b.buffer)]
+		if event.ID > lastID && filter.Match(event) {
+			backlog = append(backlog, event)
+		}
+	}
+	return sub, backlog
+}
+
+// unsubscribe는 구독을 해제합니다
+func (b *EventBroker) unsubscribe(sub *eventSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// isClosed는 브로커가 닫혔는지 반환합니다
+func (b *EventBroker) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// SubscriberCount는 현재 구독자 수를 반환합니다
+func (b *EventBroker) SubscriberCount() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Close는 모든 구독을 끊고 이후 발행을 무시합니다
+// 서버 종료 시 스트림 연결이 끝나지 않아 종료가 지연되지 않도록 http.Server.RegisterOnShutdown에 등록합니다
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// pendingEvent는 트랜잭션 커밋 후 발행할 이벤트입니다
+type pendingEvent struct {
+	eventType string
+	topicID   string
+	data      interface{}
+}
+
+// publishAll은 커밋된 변경의 이벤트를 순서대로 발행합니다
+func (b *EventBroker) publishAll(events []pendingEvent) {
+	for _, event := range events {
+		b.Publish(event.eventType, event.topicID, event.data)
+	}
+}
+
+// parseEventFilter는 topic_id(쉼표 구분 토픽 ID)와 types(쉼표 구분 이벤트 종류) 쿼리 파라미터로 구독 필터를 만듭니다
+func parseEventFilter(r *http.Request) (EventFilter, error) {
+	query := r.URL.Query()
+	var filter EventFilter
+
+	for _, topicID := range splitList(query.Get("topic_id")) {
+		if !paramPatterns[paramInteger].MatchString(topicID) {
+			return filter, fmt.Errorf("Invalid topic_id parameter: must be a comma-separated list of topic IDs")
+		}
+		if filter.Topics == nil {
+			filter.Topics = make(map[string]bool)
+		}
+		filter.Topics[topicID] = true
+	}
+
+	for _, eventType := range splitList(query.Get("types")) {
+		if !containsString(EventTypes, eventType) {
+			return filter, fmt.Errorf("Invalid types parameter: must be a comma-separated list of %s", strings.Join(EventTypes, ", "))
+		}
+		if filter.Types == nil {
+			filter.Types = make(map[string]bool)
+		}
+		filter.Types[eventType] = true
+	}
+
+	return filter, nil
+}
+
+// parseLastEventID는 재연결한 클라이언트가 마지막으로 받은 이벤트 ID를 읽습니다
+// 브라우저 EventSource가 보내는 Last-Event-ID 헤더를 우선하고, 없으면 last_event_id 쿼리 파라미터를 사용합니다
+func parseLastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("Invalid Last-Event-ID: must be a non-negative integer")
+	}
+	return id, nil
+}
+
+// splitList는 쉼표로 구분된 값 목록에서 빈 값을 제외하고 반환합니다
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// writeSSEEvent는 이벤트 하나를 SSE 형식(id, event, data 필드)으로 씁니다
+func writeSSEEvent(w io.Writer, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("이벤트 JSON 변환 실패: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
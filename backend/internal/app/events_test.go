@@ -0,0 +1,320 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// publishTestEvents는 토픽 topicID의 snapshot 이벤트를 n개 발행하고 ID를 반환합니다
+func publishTestEvents(b *EventBroker, topicID string, n int) []int64 {
+	var ids []int64
+	for i := 0; i < n; i++ {
+		b.Publish(EventTypeSnapshot, topicID, SnapshotEvent{TopicID: topicID, InferenceBlockHeight: strconv.Itoa(i)})
+		b.mu.Lock()
+		ids = append(ids, b.nextID-1)
+		b.mu.Unlock()
+	}
+	return ids
+}
+
+func TestEventBrokerReplay(t *testing.T) {
+	broker := NewEventBroker(3)
+	ids := publishTestEvents(broker, "1", 2)
+
+	// Last-Event-ID 다음 이벤트부터 다시 보냄
+	sub, backlog := broker.subscribe(ids[0], EventFilter{})
+	if len(backlog) != 1 || backlog[0].ID != ids[1] {
+		t.Errorf("재전송 = %+v, want ID %d 하나", backlog, ids[1])
+	}
+	broker.unsubscribe(sub)
+	if _, backlog := broker.subscribe(0, EventFilter{}); len(backlog) != 0 {
+		t.Errorf("Last-Event-ID 없이 재전송 %d개, want 0", len(backlog))
+	}
+
+	// 버퍼에서 밀려난 이벤트나 아직 발행되지 않은 ID를 요청하면 reset만 보냄
+	ids = append(ids, publishTestEvents(broker, "1", 3)...)
+	for _, lastID := range []int64{ids[0], ids[len(ids)-1] + 10} {
+		_, backlog := broker.subscribe(lastID, EventFilter{})
+		if len(backlog) != 1 || backlog[0].Type != EventTypeReset {
+			t.Fatalf("Last-Event-ID %d 재전송 = %+v, want reset 하나", lastID, backlog)
+		}
+		reset := backlog[0].Data.(ResetEvent)
+		if reset.LastEventID != lastID || reset.OldestEventID != ids[2] || backlog[0].ID != ids[len(ids)-1] {
+			t.Errorf("reset = %+v (ID %d), want 마지막 %d, 가장 오래된 %d, ID %d", reset, backlog[0].ID, lastID, ids[2], ids[len(ids)-1])
+		}
+	}
+}
+
+func TestEventFilterMatch(t *testing.T) {
+	filter := EventFilter{
+		Topics: map[string]bool{"1": true},
+		Types:  map[string]bool{EventTypeSnapshot: true, EventTypeCollectionError: true},
+	}
+	tests := []struct {
+		event Event
+		want  bool
+	}{
+		{Event{Type: EventTypeSnapshot, TopicID: "1"}, true},
+		{Event{Type: EventTypeSnapshot, TopicID: "2"}, false},
+		{Event{Type: EventTypeLossHeight, TopicID: "1"}, false},
+		{Event{Type: EventTypeCollectionError}, true}, // 토픽과 관련 없는 이벤트는 토픽 필터와 관계없이 전달
+	}
+	for _, tt := range tests {
+		if got := filter.Match(tt.event); got != tt.want {
+			t.Errorf("Match(%s, 토픽 %q) = %v, want %v", tt.event.Type, tt.event.TopicID, got, tt.want)
+		}
+	}
+	if !(EventFilter{}).Match(Event{Type: EventTypeCompetition, TopicID: "9"}) {
+		t.Error("빈 필터가 이벤트를 걸렀습니다")
+	}
+}
+
+func TestEventBrokerDropsSlowSubscriberAndCloses(t *testing.T) {
+	broker := NewEventBroker(0)
+	slow, _ := broker.subscribe(0, EventFilter{})
+	publishTestEvents(broker, "1", eventSubscriberBuffer+1)
+
+	// 대기열이 가득 차면 구독을 끊고 채널을 닫음
+	received := 0
+	for range slow.events {
+		received++
+	}
+	if received != eventSubscriberBuffer || broker.SubscriberCount() != 0 {
+		t.Errorf("느린 구독자가 받은 이벤트 = %d, 구독자 수 = %d; want %d, 0", received, broker.SubscriberCount(), eventSubscriberBuffer)
+	}
+
+	// 닫으면 모든 구독을 끊고 이후 발행과 구독은 무시
+	sub, _ := broker.subscribe(0, EventFilter{})
+	broker.Close()
+	if _, ok := <-sub.events; ok {
+		t.Error("닫은 뒤에도 구독 채널이 열려 있습니다")
+	}
+	broker.Publish(EventTypeSnapshot, "1", nil)
+	late, _ := broker.subscribe(0, EventFilter{})
+	if _, ok := <-late.events; ok {
+		t.Error("닫힌 브로커의 구독 채널이 열려 있습니다")
+	}
+}
+
+// sseReader는 SSE 응답을 이벤트 단위로 읽습니다
+type sseReader struct {
+	scanner *bufio.Scanner
+}
+
+// next는 다음 이벤트(주석과 retry 제외)를 반환합니다
+func (r *sseReader) next() (Event, error) {
+	var event Event
+	var fields map[string]string
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if fields["data"] == "" {
+				fields = nil
+				continue
+			}
+			if err := json.Unmarshal([]byte(fields["data"]), &event); err != nil {
+				return event, err
+			}
+			if fields["id"] != strconv.FormatInt(event.ID, 10) || fields["event"] != event.Type {
+				return event, fmt.Errorf("SSE 필드 id=%s, event=%s가 본문과 다릅니다", fields["id"], fields["event"])
+			}
+			return event, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, ": ")
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		fields[name] = value
+	}
+	if err := r.scanner.Err(); err != nil {
+		return event, err
+	}
+	return event, io.EOF
+}
+
+// openStream은 이벤트 스트림에 연결합니다
+func openStream(t *testing.T, server *httptest.Server, query, lastEventID string) *sseReader {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/stream"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("스트림 연결 실패: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("스트림 응답 = %d (%s), want 200 text/event-stream", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	if !scanner.Scan() || scanner.Text() != "retry: "+strconv.Itoa(streamRetryMillis) {
+		t.Fatalf("스트림 첫 줄 = %q, want retry", scanner.Text())
+	}
+	return &sseReader{scanner: scanner}
+}
+
+// nextEvent는 제한 시간 안에 다음 이벤트를 읽습니다
+func nextEvent(t *testing.T, reader *sseReader) Event {
+	t.Helper()
+	type result struct {
+		event Event
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		event, err := reader.next()
+		done <- result{event, err}
+	}()
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatalf("이벤트 읽기 실패: %v", r.err)
+		}
+		return r.event
+	case <-time.After(5 * time.Second):
+		t.Fatal("이벤트를 받지 못했습니다")
+	}
+	return Event{}
+}
+
+// waitSubscribers는 브로커 구독자 수가 n이 될 때까지 기다립니다
+func waitSubscribers(t *testing.T, broker *EventBroker, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for broker.SubscriberCount() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("구독자 수 = %d, want %d", broker.SubscriberCount(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStreamDeliversFilteredEvents(t *testing.T) {
+	db := newTestDatabase(t)
+	service, mux := newTestService(t, db)
+	broker := NewEventBroker(0)
+	service.SetEventBroker(broker)
+	db.SetEventBroker(broker)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	stream := openStream(t, server, "?topic_id=1&types=snapshot,loss_height", "")
+	waitSubscribers(t, broker, 1)
+
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(3)
+	save := func(record map[string]interface{}) {
+		t.Helper()
+		if err := db.SaveTopicInference(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first := testSnapshot("1", 100, base, workers, 0)
+	save(first)
+	save(testSnapshot("2", 200, base, workers, 0)) // 다른 토픽은 거름
+	relabeled := cloneSnapshot(first)
+	relabeled["loss_block_height"] = "95"
+	save(relabeled)
+
+	snapshot := nextEvent(t, stream)
+	data, _ := snapshot.Data.(map[string]interface{})
+	if snapshot.Type != EventTypeSnapshot || snapshot.TopicID != "1" || data["inference_block_height"] != "100" || data["updated"] != false {
+		t.Errorf("첫 이벤트 = %+v, want 토픽 1 높이 100의 새 snapshot", snapshot)
+	}
+	loss := nextEvent(t, stream)
+	data, _ = loss.Data.(map[string]interface{})
+	if loss.Type != EventTypeLossHeight || loss.ID <= snapshot.ID || data["previous_loss_block_height"] != "90" || data["loss_block_height"] != "95" {
+		t.Errorf("두 번째 이벤트 = %+v, want loss 90 -> 95", loss)
+	}
+
+	// Last-Event-ID로 다시 연결하면 놓친 이벤트부터 받음
+	resumed := openStream(t, server, "?topic_id=1", strconv.FormatInt(snapshot.ID, 10))
+	if replayed := nextEvent(t, resumed); replayed.ID != loss.ID {
+		t.Errorf("재연결 후 첫 이벤트 ID = %d, want %d", replayed.ID, loss.ID)
+	}
+
+	// 브로커를 닫으면 스트림이 끝남
+	broker.Close()
+	if _, err := stream.next(); err != io.EOF {
+		t.Errorf("브로커 종료 후 읽기 = %v, want EOF", err)
+	}
+}
+
+func TestStreamRejectsInvalidParameters(t *testing.T) {
+	db := newTestDatabase(t)
+	service, mux := newTestService(t, db)
+
+	// 브로커가 없으면 503
+	recorder := serveTest(mux, http.MethodGet, "/api/v1/stream", "")
+	if recorder.Code != http.StatusServiceUnavailable || decodeErrorResponse(t, recorder).Error.Code != ErrCodeNotConfigured {
+		t.Errorf("브로커 없이 스트림 = %d %s, want 503 %s", recorder.Code, recorder.Body.String(), ErrCodeNotConfigured)
+	}
+
+	service.SetEventBroker(NewEventBroker(0))
+	for _, target := range []string{
+		"/api/v1/stream?topic_id=1,abc",
+		"/api/v1/stream?types=snapshot,unknown",
+		"/api/v1/stream?last_event_id=-1",
+	} {
+		recorder := serveTest(mux, http.MethodGet, target, "")
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s 상태 코드 = %d, want 400", target, recorder.Code)
+			continue
+		}
+		if response := decodeErrorResponse(t, recorder); response.Error.Code != ErrCodeInvalidParameter {
+			t.Errorf("%s 오류 코드 = %s, want %s", target, response.Error.Code, ErrCodeInvalidParameter)
+		}
+	}
+}
+
+func TestStreamRequiresLeader(t *testing.T) {
+	db := newTestDatabase(t)
+	service, mux := newTestService(t, db)
+	broker := NewEventBroker(0)
+	service.SetEventBroker(broker)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	leader, follower := newElectorPair(t, db)
+
+	// 팔로워는 이벤트가 발생하지 않으므로 리더 ID와 함께 503으로 거부
+	service.SetLeaderElector(follower)
+	recorder := serveTest(mux, http.MethodGet, "/api/v1/stream", "")
+	if recorder.Code != http.StatusServiceUnavailable || recorder.Header().Get(leaderIDHeader) != "instance-a" {
+		t.Fatalf("팔로워 스트림 = %d (%s: %q), want 503과 리더 instance-a", recorder.Code, leaderIDHeader, recorder.Header().Get(leaderIDHeader))
+	}
+	if response := decodeErrorResponse(t, recorder); response.Error.Code != ErrCodeNotLeader || !strings.Contains(response.Error.Message, "instance-a") {
+		t.Errorf("팔로워 스트림 오류 = %+v, want %s와 리더 ID", response.Error, ErrCodeNotLeader)
+	}
+
+	// 리더 자격을 잃으면 열린 스트림이 끝나고, 다시 연결하면 새 리더를 안내
+	service.SetLeaderElector(leader)
+	stream := openStream(t, server, "", "")
+	waitSubscribers(t, broker, 1)
+	handOverLeadership(t, db, leader, follower)
+	if _, err := stream.next(); err != io.EOF {
+		t.Errorf("리더 자격 상실 후 읽기 = %v, want EOF", err)
+	}
+	recorder = serveTest(mux, http.MethodGet, "/api/v1/stream", "")
+	if recorder.Code != http.StatusServiceUnavailable || recorder.Header().Get(leaderIDHeader) != "instance-b" {
+		t.Errorf("이전 리더의 스트림 = %d (%s: %q), want 503과 리더 instance-b", recorder.Code, leaderIDHeader, recorder.Header().Get(leaderIDHeader))
+	}
+}
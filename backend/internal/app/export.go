@@ -0,0 +1,763 @@
+package app
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// 내보내기 데이터셋
+const (
+	ExportDatasetSnapshots    = "snapshots"
+	ExportDatasetWorkers      = "workers"
+	ExportDatasetLeaderboard  = "leaderboard"
+	ExportDatasetCompetitions = "competitions"
+)
+
+// 내보내기 형식
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatParquet = "parquet"
+)
+
+// Parquet 내보내기에서 한 번에 쓰는 행 수와 행 그룹 크기 (메모리 사용량 상한)
+const (
+	exportParquetBatchRows    = 512
+	exportParquetRowGroupRows = 16384
+)
+
+// exportColumnKind는 내보내기 컬럼의 값 타입입니다
+type exportColumnKind int
+
+const (
+	exportKindString exportColumnKind = iota
+	exportKindInt
+	exportKindFloat
+	exportKindBool
+	exportKindJSON // 원본 JSON (CSV/Parquet에서는 문자열)
+)
+
+// exportColumn은 데이터셋 컬럼 정의입니다
+type exportColumn struct {
+	name string
+	kind exportColumnKind
+}
+
+// exportDataset은 데이터셋의 컬럼 목록과 행 생성 함수입니다
+// emit에 전달되는 값은 columns와 같은 순서이며, 값이 없으면 nil입니다
+type exportDataset struct {
+	columns []exportColumn
+	stream  func(d *Database, opts ExportOptions, emit func([]interface{}) error) error
+}
+
+// exportDatasets는 내보낼 수 있는 데이터셋 목록입니다
+var exportDatasets = map[string]exportDataset{
+	ExportDatasetSnapshots: {
+		columns: []exportColumn{
+			{"topic_id", exportKindString},
+			{"timestamp", exportKindString},
+			{"inference_block_height", exportKindInt},
+			{"loss_block_height", exportKindInt},
+			{"combined_value", exportKindFloat},
+			{"naive_value", exportKindFloat},
+			{"worker_count", exportKindInt},
+			{"data", exportKindJSON},
+		},
+		stream: (*Database).streamSnapshotRows,
+	},
+	ExportDatasetWorkers: {
+		columns: []exportColumn{
+			{"topic_id", exportKindString},
+			{"timestamp", exportKindString},
+			{"inference_block_height", exportKindInt},
+			{"worker", exportKindString},
+			{"inferer_value", exportKindFloat},
+			{"one_out_inferer_value", exportKindFloat},
+			{"weight", exportKindFloat},
+		},
+		stream: (*Database).streamWorkerRows,
+	},
+	ExportDatasetLeaderboard: {
+		columns: []exportColumn{
+			{"topic_id", exportKindString},
+			{"timestamp", exportKindString},
+			{"inference_block_height", exportKindInt},
+			{"worker", exportKindString},
+			{"rank", exportKindString},
+			{"cosmos_address", exportKindString},
+			{"username", exportKindString},
+			{"first_name", exportKindString},
+			{"last_name", exportKindString},
+			{"points", exportKindFloat},
+			{"score", exportKindFloat},
+			{"loss", exportKindFloat},
+			{"is_active", exportKindBool},
+		},
+		stream: (*Database).streamLeaderboardRows,
+	},
+	ExportDatasetCompetitions: {
+		columns: []exportColumn{
+			{"id", exportKindInt},
+			{"name", exportKindString},
+			{"topic_id", exportKindString},
+			{"prize_pool", exportKindInt},
+			{"start_date", exportKindString},
+			{"end_date", exportKindString},
+			{"season_id", exportKindInt},
+			{"tags", exportKindJSON},
+			{"is_active", exportKindBool},
+			{"listed_as_past", exportKindBool},
+			{"description", exportKindString},
+			{"updated_at", exportKindString},
+		},
+		stream: (*Database).streamCompetitionRows,
+	},
+}
+
+// ExportOptions는 내보내기 조건입니다
+// 시간과 블록 높이 범위는 0(zero 값)이면 제한하지 않습니다
+// 경쟁 데이터셋은 시간 범위를 경쟁 기간과의 겹침으로 적용하고 블록 높이 범위는 무시합니다
+type ExportOptions struct {
+	Dataset   string
+	Format    string
+	TopicID   string // 비어 있으면 전체 토픽
+	Start     time.Time
+	End       time.Time
+	MinHeight int64
+	MaxHeight int64
+	Columns   []string // 비어 있으면 전체 컬럼
+	Gzip      bool
+}
+
+// Validate는 데이터셋, 형식, 컬럼 이름과 범위를 검사합니다
+func (opts ExportOptions) Validate() error {
+	_, _, err := opts.resolve()
+	return err
+}
+
+// resolve는 데이터셋 정의와 선택된 컬럼의 인덱스를 반환합니다
+func (opts ExportOptions) resolve() (exportDataset, []int, error) {
+	dataset, ok := exportDatasets[opts.Dataset]
+	if !ok {
+		return exportDataset{}, nil, fmt.Errorf("알 수 없는 데이터셋: %q (사용 가능: %s, %s, %s, %s)",
+			opts.Dataset, ExportDatasetSnapshots, ExportDatasetWorkers, ExportDatasetLeaderboard, ExportDatasetCompetitions)
+	}
+
+	switch opts.Format {
+	case ExportFormatCSV, ExportFormatNDJSON, ExportFormatParquet:
+	default:
+		return exportDataset{}, nil, fmt.Errorf("알 수 없는 형식: %q (사용 가능: %s, %s, %s)",
+			opts.Format, ExportFormatCSV, ExportFormatNDJSON, ExportFormatParquet)
+	}
+
+	if !opts.Start.IsZero() && !opts.End.IsZero() && opts.End.Before(opts.Start) {
+		return exportDataset{}, nil, fmt.Errorf("종료 시각이 시작 시각보다 앞섭니다")
+	}
+	if opts.MinHeight > 0 && opts.MaxHeight > 0 && opts.MaxHeight < opts.MinHeight {
+		return exportDataset{}, nil, fmt.Errorf("최대 블록 높이가 최소 블록 높이보다 작습니다")
+	}
+
+	if len(opts.Columns) == 0 {
+		indexes := make([]int, len(dataset.columns))
+		for i := range dataset.columns {
+			indexes[i] = i
+		}
+		return dataset, indexes, nil
+	}
+
+	indexes := make([]int, 0, len(opts.Columns))
+	seen := make(map[string]bool, len(opts.Columns))
+	for _, name := range opts.Columns {
+		index := -1
+		for i, column := range dataset.columns {
+			if column.name == name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return exportDataset{}, nil, fmt.Errorf("%s 데이터셋에 %q 컬럼이 없습니다", opts.Dataset, name)
+		}
+		if seen[name] {
+			return exportDataset{}, nil, fmt.Errorf("컬럼 %q가 중복되었습니다", name)
+		}
+		seen[name] = true
+		indexes = append(indexes, index)
+	}
+	return dataset, indexes, nil
+}
+
+// FileName은 내보내기 결과의 기본 파일 이름을 반환합니다 (예: topic-13-snapshots.csv.gz)
+func (opts ExportOptions) FileName() string {
+	name := "all"
+	if opts.TopicID != "" {
+		name = "topic-" + opts.TopicID
+	}
+	name += "-" + opts.Dataset + "." + opts.Format
+	if opts.Gzip {
+		name += ".gz"
+	}
+	return name
+}
+
+// ContentType은 내보내기 결과의 MIME 타입을 반환합니다
+func (opts ExportOptions) ContentType() string {
+	if opts.Gzip {
+		return "application/gzip"
+	}
+	switch opts.Format {
+	case ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ExportFormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+// Export는 조건에 맞는 행을 지정한 형식으로 w에 순차적으로 씁니다
+// 행은 데이터베이스 커서에서 하나씩 읽어 바로 기록하므로 전체 결과를 메모리에 올리지 않습니다
+// 기록한 행 수를 반환합니다
+func (d *Database) Export(w io.Writer, opts ExportOptions) (int64, error) {
+	dataset, indexes, err := opts.resolve()
+	if err != nil {
+		return 0, err
+	}
+
+	var gz *gzip.Writer
+	if opts.Gzip {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+
+	columns := make([]exportColumn, len(indexes))
+	for i, index := range indexes {
+		columns[i] = dataset.columns[index]
+	}
+
+	writer, err := newExportWriter(w, opts.Format, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	selected := make([]interface{}, len(indexes))
+	streamErr := dataset.stream(d, opts, func(values []interface{}) error {
+		for i, index := range indexes {
+			selected[i] = values[index]
+		}
+		if err := writer.writeRow(selected); err != nil {
+			return fmt.Errorf("행 쓰기 실패: %w", err)
+		}
+		count++
+		return nil
+	})
+	if streamErr != nil {
+		return count, streamErr
+	}
+
+	if err := writer.close(); err != nil {
+		return count, fmt.Errorf("%s 내보내기 마무리 실패: %w", opts.Format, err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return count, fmt.Errorf("gzip 압축 마무리 실패: %w", err)
+		}
+	}
+
+	return count, nil
+}
+
+// exportSnapshotCondition은 토픽 스냅샷 조회 조건과 인자를 만듭니다
+func exportSnapshotCondition(opts ExportOptions) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+
+	if opts.TopicID != "" {
+		conditions = append(conditions, "topic_id = ?")
+		args = append(args, opts.TopicID)
+	}
+	if !opts.Start.IsZero() {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, opts.Start.Format(time.RFC3339))
+	}
+	if !opts.End.IsZero() {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, opts.End.Format(time.RFC3339))
+	}
+	if opts.MinHeight > 0 {
+		conditions = append(conditions, "inference_block_height >= ?")
+		args = append(args, opts.MinHeight)
+	}
+	if opts.MaxHeight > 0 {
+		conditions = append(conditions, "inference_block_height <= ?")
+		args = append(args, opts.MaxHeight)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// exportSnapshot은 내보내기 중 복원한 스냅샷 하나입니다
+type exportSnapshot struct {
+	TopicID              string
+	Timestamp            string
+	InferenceBlockHeight int64
+	LossBlockHeight      int64
+	State                map[string]interface{}
+}
+
+// synthesisWorkers는 스냅샷의 synthesis_value 워커 목록을 반환합니다
+func (s exportSnapshot) synthesisWorkers() []map[string]interface{} {
+	networkInferences, ok := s.State["network_inferences"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	synthesisValue, _ := networkInferences["synthesis_value"].([]interface{})
+
+	workers := make([]map[string]interface{}, 0, len(synthesisValue))
+	for _, item := range synthesisValue {
+		if workerData, ok := item.(map[string]interface{}); ok {
+			workers = append(workers, workerData)
+		}
+	}
+	return workers
+}
+
+// forEachExportSnapshot은 조건에 맞는 스냅샷을 토픽별 ID 순으로 하나씩 복원해 fn에 전달합니다
+// 같은 체인의 델타가 연속되도록 토픽별로 정렬하여 디코더가 이전 상태를 재사용하게 합니다
+// 토픽 샤드를 사용하면 메인 데이터베이스, 샤드 순서로 읽습니다
+func (d *Database) forEachExportSnapshot(opts ExportOptions, fn func(exportSnapshot) error) error {
+	stores, err := d.topicStoresFor(opts.TopicID)
+	if err != nil {
+		return err
+	}
+	defer releaseTopicStores(stores)
+
+	condition, args := exportSnapshotCondition(opts)
+	for _, store := range stores {
+		if err := forEachStoreSnapshot(store.reader, condition, args, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachStoreSnapshot은 저장소 하나에서 조건에 맞는 스냅샷을 복원해 fn에 전달합니다
+func forEachStoreSnapshot(q sqlQueryer, condition string, args []interface{}, fn func(exportSnapshot) error) error {
+	rows, err := q.Query(
+		"SELECT "+snapshotRowColumns+", topic_id, inference_block_height, loss_block_height FROM topic_inferences WHERE "+condition+" ORDER BY topic_id, id",
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("내보내기 대상 스냅샷 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	decoder := newSnapshotDecoder(q)
+	for rows.Next() {
+		var row snapshotRow
+		var snapshot exportSnapshot
+		if err := rows.Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data,
+			&snapshot.TopicID, &snapshot.InferenceBlockHeight, &snapshot.LossBlockHeight); err != nil {
+			return fmt.Errorf("데이터 스캔 실패: %w", err)
+		}
+
+		state, err := decoder.decode(row)
+		if err != nil {
+			return fmt.Errorf("스냅샷 복원 실패 (ID=%d): %w", row.ID, err)
+		}
+		restoreSnapshotMetadata(state, row.Timestamp, strconv.FormatInt(snapshot.LossBlockHeight, 10))
+		snapshot.Timestamp = row.Timestamp
+		snapshot.State = state
+
+		if err := fn(snapshot); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+	return nil
+}
+
+// streamSnapshotRows는 스냅샷마다 한 행을 생성합니다 (data 컬럼은 전체 스냅샷 JSON)
+func (d *Database) streamSnapshotRows(opts ExportOptions, emit func([]interface{}) error) error {
+	return d.forEachExportSnapshot(opts, func(snapshot exportSnapshot) error {
+		var combinedValue, naiveValue interface{}
+		if networkInferences, ok := snapshot.State["network_inferences"].(map[string]interface{}); ok {
+			combinedValue = exportFloatValue(networkInferences["combined_value"])
+			naiveValue = exportFloatValue(networkInferences["naive_value"])
+		}
+
+		data, err := json.Marshal(snapshot.State)
+		if err != nil {
+			return fmt.Errorf("JSON 마샬링 실패: %w", err)
+		}
+
+		return emit([]interface{}{
+			snapshot.TopicID,
+			snapshot.Timestamp,
+			snapshot.InferenceBlockHeight,
+			snapshot.LossBlockHeight,
+			combinedValue,
+			naiveValue,
+			int64(len(snapshot.synthesisWorkers())),
+			json.RawMessage(data),
+		})
+	})
+}
+
+// streamWorkerRows는 스냅샷의 synthesis_value를 워커별 행으로 펼칩니다
+func (d *Database) streamWorkerRows(opts ExportOptions, emit func([]interface{}) error) error {
+	return d.forEachExportSnapshot(opts, func(snapshot exportSnapshot) error {
+		for _, workerData := range snapshot.synthesisWorkers() {
+			worker := getStringValue(workerData, "worker", "")
+			if worker == "" {
+				continue
+			}
+			if err := emit([]interface{}{
+				snapshot.TopicID,
+				snapshot.Timestamp,
+				snapshot.InferenceBlockHeight,
+				worker,
+				exportFloatValue(workerData["inferer_values"]),
+				exportFloatValue(workerData["one_out_inferer_values"]),
+				exportFloatValue(workerData["weight"]),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// streamLeaderboardRows는 스냅샷의 워커별 leaderboard 정보를 행으로 펼칩니다
+// 수집기는 리더보드를 synthesis_value 안에 저장하므로 leaderboard_entries 테이블이 아닌 스냅샷에서 읽습니다
+func (d *Database) streamLeaderboardRows(opts ExportOptions, emit func([]interface{}) error) error {
+	return d.forEachExportSnapshot(opts, func(snapshot exportSnapshot) error {
+		for _, workerData := range snapshot.synthesisWorkers() {
+			leaderboard, ok := workerData["leaderboard"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := emit([]interface{}{
+				snapshot.TopicID,
+				snapshot.Timestamp,
+				snapshot.InferenceBlockHeight,
+				exportStringValue(workerData["worker"]),
+				exportStringValue(leaderboard["rank"]),
+				exportStringValue(leaderboard["cosmos_address"]),
+				exportStringValue(leaderboard["username"]),
+				exportStringValue(leaderboard["first_name"]),
+				exportStringValue(leaderboard["last_name"]),
+				exportFloatValue(leaderboard["points"]),
+				exportFloatValue(leaderboard["score"]),
+				exportFloatValue(leaderboard["loss"]),
+				exportBoolValue(leaderboard["is_active"]),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// streamCompetitionRows는 competitions_v2의 경쟁을 행으로 생성합니다
+// 시간 범위는 경쟁 기간(start_date ~ end_date)과 겹치는 경쟁을 선택합니다
+func (d *Database) streamCompetitionRows(opts ExportOptions, emit func([]interface{}) error) error {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if opts.TopicID != "" {
+		conditions = append(conditions, "CAST(topic_id AS TEXT) = ?")
+		args = append(args, opts.TopicID)
+	}
+	if !opts.Start.IsZero() {
+		conditions = append(conditions, "end_date >= ?")
+		args = append(args, opts.Start.Format(time.RFC3339))
+	}
+	if !opts.End.IsZero() {
+		conditions = append(conditions, "start_date <= ?")
+		args = append(args, opts.End.Format(time.RFC3339))
+	}
+
+	rows, err := d.reader.Query(
+		`SELECT id, name, topic_id, prize_pool, start_date, end_date, season_id, tags,
+			is_active, listed_as_past, COALESCE(description, ''), timestamp
+		FROM competitions_v2 WHERE `+strings.Join(conditions, " AND ")+` ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("내보내기 대상 경쟁 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var name, startDate, endDate, description, updatedAt string
+		var topicID, prizePool, seasonID *int64
+		var tags *string
+		var isActive, listedAsPast bool
+		if err := rows.Scan(&id, &name, &topicID, &prizePool, &startDate, &endDate, &seasonID, &tags,
+			&isActive, &listedAsPast, &description, &updatedAt); err != nil {
+			return fmt.Errorf("경쟁 데이터 스캔 실패: %w", err)
+		}
+
+		values := []interface{}{id, name, nil, nil, startDate, endDate, nil, nil, isActive, listedAsPast, description, updatedAt}
+		if topicID != nil {
+			values[2] = strconv.FormatInt(*topicID, 10)
+		}
+		if prizePool != nil {
+			values[3] = *prizePool
+		}
+		if seasonID != nil {
+			values[6] = *seasonID
+		}
+		if tags != nil && json.Valid([]byte(*tags)) {
+			values[7] = json.RawMessage(*tags)
+		}
+
+		if err := emit(values); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+	return nil
+}
+
+// exportFloatValue는 숫자 또는 숫자 문자열을 float64로 변환합니다 (변환할 수 없으면 nil)
+func exportFloatValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// exportStringValue는 문자열 또는 숫자를 문자열로 변환합니다 (값이 없으면 nil)
+func exportStringValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return nil
+}
+
+// exportBoolValue는 불리언 값을 반환합니다 (값이 없으면 nil)
+func exportBoolValue(value interface{}) interface{} {
+	if v, ok := value.(bool); ok {
+		return v
+	}
+	return nil
+}
+
+// exportWriter는 한 가지 형식으로 행을 순차적으로 기록합니다
+type exportWriter interface {
+	writeRow(values []interface{}) error
+	close() error
+}
+
+// newExportWriter는 형식에 맞는 행 기록기를 생성합니다
+func newExportWriter(w io.Writer, format string, columns []exportColumn) (exportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return newCSVExportWriter(w, columns)
+	case ExportFormatNDJSON:
+		return newNDJSONExportWriter(w, columns), nil
+	case ExportFormatParquet:
+		return newParquetExportWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("알 수 없는 형식: %q", format)
+	}
+}
+
+// csvExportWriter는 헤더 행이 있는 CSV를 기록합니다
+type csvExportWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVExportWriter(w io.Writer, columns []exportColumn) (*csvExportWriter, error) {
+	writer := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("CSV 헤더 쓰기 실패: %w", err)
+	}
+	return &csvExportWriter{writer: writer, record: make([]string, len(columns))}, nil
+}
+
+func (c *csvExportWriter) writeRow(values []interface{}) error {
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+			c.record[i] = ""
+		case string:
+			c.record[i] = v
+		case int64:
+			c.record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			c.record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			c.record[i] = strconv.FormatBool(v)
+		case json.RawMessage:
+			c.record[i] = string(v)
+		default:
+			c.record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.writer.Write(c.record)
+}
+
+func (c *csvExportWriter) close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// ndjsonExportWriter는 행마다 컬럼 순서를 유지한 JSON 객체 한 줄을 기록합니다
+type ndjsonExportWriter struct {
+	writer *bufio.Writer
+	keys   [][]byte
+}
+
+func newNDJSONExportWriter(w io.Writer, columns []exportColumn) *ndjsonExportWriter {
+	keys := make([][]byte, len(columns))
+	for i, column := range columns {
+		key, _ := json.Marshal(column.name)
+		keys[i] = key
+	}
+	return &ndjsonExportWriter{writer: bufio.NewWriter(w), keys: keys}
+}
+
+func (n *ndjsonExportWriter) writeRow(values []interface{}) error {
+	n.writer.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			n.writer.WriteByte(',')
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("JSON 마샬링 실패: %w", err)
+		}
+		n.writer.Write(n.keys[i])
+		n.writer.WriteByte(':')
+		n.writer.Write(encoded)
+	}
+	n.writer.WriteByte('}')
+	return n.writer.WriteByte('\n')
+}
+
+func (n *ndjsonExportWriter) close() error {
+	return n.writer.Flush()
+}
+
+// parquetExportWriter는 선택된 컬럼으로 만든 스키마(모든 컬럼 optional)로 Parquet을 기록합니다
+// 행 그룹 크기를 제한하여 출력 중 메모리에 머무는 행 수를 제한합니다
+type parquetExportWriter struct {
+	writer  *parquet.Writer
+	leaves  []int // 선택된 컬럼 순서 -> 스키마 리프 인덱스
+	pending []parquet.Row
+}
+
+func newParquetExportWriter(w io.Writer, columns []exportColumn) *parquetExportWriter {
+	group := make(parquet.Group, len(columns))
+	for _, column := range columns {
+		var node parquet.Node
+		switch column.kind {
+		case exportKindInt:
+			node = parquet.Int(64)
+		case exportKindFloat:
+			node = parquet.Leaf(parquet.DoubleType)
+		case exportKindBool:
+			node = parquet.Leaf(parquet.BooleanType)
+		default:
+			node = parquet.String()
+		}
+		group[column.name] = parquet.Optional(node)
+	}
+	schema := parquet.NewSchema("export", group)
+
+	// Group은 필드를 이름순으로 정렬하므로 리프 인덱스를 이름으로 찾음
+	leafIndex := make(map[string]int, len(columns))
+	for i, field := range schema.Fields() {
+		leafIndex[field.Name()] = i
+	}
+	leaves := make([]int, len(columns))
+	for i, column := range columns {
+		leaves[i] = leafIndex[column.name]
+	}
+
+	return &parquetExportWriter{
+		writer: parquet.NewWriter(w, schema,
+			parquet.Compression(&parquet.Zstd),
+			parquet.MaxRowsPerRowGroup(exportParquetRowGroupRows),
+		),
+		leaves:  leaves,
+		pending: make([]parquet.Row, 0, exportParquetBatchRows),
+	}
+}
+
+func (p *parquetExportWriter) writeRow(values []interface{}) error {
+	row := make(parquet.Row, len(values))
+	for i, value := range values {
+		leaf := p.leaves[i]
+		switch v := value.(type) {
+		case nil:
+			row[leaf] = parquet.NullValue().Level(0, 0, leaf)
+		case json.RawMessage:
+			row[leaf] = parquet.ValueOf(string(v)).Level(0, 1, leaf)
+		default:
+			row[leaf] = parquet.ValueOf(v).Level(0, 1, leaf)
+		}
+	}
+
+	p.pending = append(p.pending, row)
+	if len(p.pending) >= exportParquetBatchRows {
+		return p.flush()
+	}
+	return nil
+}
+
+func (p *parquetExportWriter) flush() error {
+	if len(p.pending) == 0 {
+		return nil
+	}
+	if _, err := p.writer.WriteRows(p.pending); err != nil {
+		return err
+	}
+	p.pending = p.pending[:0]
+	return nil
+}
+
+func (p *parquetExportWriter) close() error {
+	if err := p.flush(); err != nil {
+		return err
+	}
+	return p.writer.Close()
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// exportedSnapshot은 Parquet 스냅샷 내보내기의 일부 컬럼입니다
+type exportedSnapshot struct {
+	TopicID              *string  `parquet:"topic_id,optional"`
+	InferenceBlockHeight *int64   `parquet:"inference_block_height,optional"`
+	CombinedValue        *float64 `parquet:"combined_value,optional"`
+	WorkerCount          *int64   `parquet:"worker_count,optional"`
+}
+
+// getExport는 내보내기 응답 본문을 읽고 콘텐츠 타입과 파일 이름을 확인합니다
+func getExport(t *testing.T, mux http.Handler, target, contentType, fileName string) []byte {
+	t.Helper()
+	recorder := serveTest(mux, http.MethodGet, target, "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("%s 상태 코드 = %d, want 200 (본문: %s)", target, recorder.Code, recorder.Body.String())
+	}
+	if got := recorder.Header().Get("Content-Type"); got != contentType {
+		t.Errorf("%s 콘텐츠 타입 = %q, want %q", target, got, contentType)
+	}
+	if got := recorder.Header().Get("Content-Disposition"); got != `attachment; filename="`+fileName+`"` {
+		t.Errorf("%s Content-Disposition = %q, want 파일 이름 %s", target, got, fileName)
+	}
+	return recorder.Body.Bytes()
+}
+
+func TestExportStreamsRows(t *testing.T) {
+	db := newTestDatabase(t)
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	workers := testWorkers(3)
+	for i := 0; i < 5; i++ {
+		if err := db.SaveTopicInference(testSnapshot("1", 100+i*10, base.Add(time.Duration(i)*time.Minute), workers, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := db.SaveTopicInference(testSnapshot("2", 500+i*10, base.Add(time.Duration(i)*time.Minute), workers, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, mux := newTestService(t, db)
+
+	// NDJSON: 한 줄에 스냅샷 하나, 키는 컬럼 순서
+	body := getExport(t, mux, "/api/v1/export?topic_id=1", "application/x-ndjson", "topic-1-snapshots.ndjson")
+	var heights []int64
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, `{"topic_id":"1","timestamp":`) {
+			t.Errorf("NDJSON 줄이 컬럼 순서로 시작하지 않습니다: %.80s", line)
+		}
+		var row struct {
+			InferenceBlockHeight int64           `json:"inference_block_height"`
+			WorkerCount          int64           `json:"worker_count"`
+			Data                 json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			t.Fatalf("NDJSON 줄 디코딩 실패: %v", err)
+		}
+		if row.WorkerCount != 3 || !json.Valid(row.Data) {
+			t.Errorf("높이 %d의 워커 수 = %d, data = %.40s, want 3과 JSON 원본", row.InferenceBlockHeight, row.WorkerCount, row.Data)
+		}
+		heights = append(heights, row.InferenceBlockHeight)
+	}
+	if want := []int64{100, 110, 120, 130, 140}; !reflect.DeepEqual(heights, want) {
+		t.Errorf("NDJSON 높이 = %v, want %v", heights, want)
+	}
+
+	// gzip CSV: 헤더는 선택한 컬럼 순서, 높이 범위 안의 스냅샷마다 워커 3행
+	body = getExport(t, mux, "/api/v1/export?dataset=workers&format=csv&gzip=true&topic_id=1&min_height=110&max_height=130&columns=worker,inference_block_height,inferer_value",
+		"application/gzip", "topic-1-workers.csv.gz")
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("gzip 해제 실패: %v", err)
+	}
+	records, err := csv.NewReader(gz).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV 파싱 실패: %v", err)
+	}
+	if len(records) != 1+3*3 {
+		t.Fatalf("CSV 행 %d개, want 헤더 1개와 9개", len(records))
+	}
+	if want := []string{"worker", "inference_block_height", "inferer_value"}; !reflect.DeepEqual(records[0], want) {
+		t.Errorf("CSV 헤더 = %v, want %v", records[0], want)
+	}
+	for _, record := range records[1:] {
+		height, err := strconv.Atoi(record[1])
+		if err != nil || height < 110 || height > 130 || !strings.HasPrefix(record[0], testWorkerPrefix) {
+			t.Errorf("CSV 행 = %v, want 높이 110~130의 워커 값", record)
+		}
+		if _, err := strconv.ParseFloat(record[2], 64); err != nil {
+			t.Errorf("CSV inferer_value = %q, want 숫자", record[2])
+		}
+	}
+
+	// Parquet: 전체 토픽의 스냅샷, 타입이 있는 컬럼
+	body = getExport(t, mux, "/api/v1/export?format=parquet", "application/vnd.apache.parquet", "all-snapshots.parquet")
+	rows, err := parquet.Read[exportedSnapshot](bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("Parquet 읽기 실패: %v", err)
+	}
+	topicRows := make(map[string]int)
+	for _, row := range rows {
+		if row.TopicID == nil || row.InferenceBlockHeight == nil || row.CombinedValue == nil || row.WorkerCount == nil || *row.WorkerCount != 3 {
+			t.Errorf("Parquet 행 = %+v, want 모든 컬럼 값", row)
+			continue
+		}
+		topicRows[*row.TopicID]++
+	}
+	if want := map[string]int{"1": 5, "2": 2}; !reflect.DeepEqual(topicRows, want) {
+		t.Errorf("Parquet 토픽별 행 수 = %v, want %v", topicRows, want)
+	}
+
+	// 결과가 없어도 헤더만 있는 파일로 응답
+	body = getExport(t, mux, "/api/v1/export?format=csv&topic_id=99&columns=topic_id,timestamp", "text/csv; charset=utf-8", "topic-99-snapshots.csv")
+	if string(body) != "topic_id,timestamp\n" {
+		t.Errorf("빈 CSV = %q, want 헤더만", body)
+	}
+}
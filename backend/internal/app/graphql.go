@@ -0,0 +1,798 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// GraphQL 조회 API
+// 프론트엔드가 한 화면을 그리기 위해 경쟁 목록, 블록 높이, 스냅샷, 통계를 차례로 요청하지 않도록
+// Competition, Topic, InferenceSnapshot, WorkerInference, LeaderboardEntry 스키마를 Database 조회 메서드 위에 제공합니다
+// 필드 이름은 REST 응답과 같은 snake_case이므로 프론트엔드는 기존 타입을 그대로 사용할 수 있습니다
+// 실행 전에 쿼리 깊이와 복잡도(목록 필드는 하위 필드 비용에 limit 또는 예상 항목 수를 곱함)를 계산하여 한도를 넘는 쿼리는 거부합니다
+
+// 쿼리 제한
+const (
+	graphqlMaxDepth        = 8         // 최대 필드 중첩 깊이 (최상위 필드가 1)
+	graphqlMaxComplexity   = 10000     // 최대 복잡도
+	graphqlMaxQueryBytes   = 16 << 10  // 쿼리 문자열 최대 크기
+	graphqlMaxRequestBytes = 256 << 10 // POST 요청 본문 최대 크기 (변수 포함)
+	graphqlDefaultListSize = 100       // limit이 없는 목록 필드의 예상 항목 수
+)
+
+// graphqlListSizes는 limit 인자가 없을 때 복잡도 계산에 사용하는 목록 필드별 예상 항목 수입니다 ("타입.필드")
+var graphqlListSizes = map[string]int{
+	"Query.competitions":            50,
+	"Query.topics":                  20,
+	"InferenceSnapshot.workers":     200,
+	"InferenceSnapshot.leaderboard": 200,
+}
+
+// GraphQLRequest는 POST 요청 본문입니다 (GET은 같은 이름의 쿼리 파라미터, variables는 JSON 문자열)
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// GraphQLResponse는 GraphQL 응답 본문입니다
+// 쿼리 오류도 200으로 응답하며, 쿼리를 실행하지 못하면 data 없이 errors만 반환합니다
+type GraphQLResponse struct {
+	Data   interface{}    `json:"data,omitempty"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// GraphQLError는 GraphQL 오류 하나입니다 (extensions.code는 REST 오류 응답과 같은 오류 코드)
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Locations  []GraphQLLocation      `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions GraphQLErrorExtensions `json:"extensions"`
+}
+
+// GraphQLLocation은 쿼리 문자열에서 오류가 난 위치입니다
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLErrorExtensions는 오류의 기계 판독용 정보입니다
+type GraphQLErrorExtensions struct {
+	Code ErrorCode `json:"code"`
+}
+
+// graphqlError는 오류 코드를 가진 리졸버 오류입니다 (gqlerrors.ExtendedError)
+type graphqlError struct {
+	code    ErrorCode
+	message string
+}
+
+func (e graphqlError) Error() string {
+	return e.message
+}
+
+// Extensions는 응답의 extensions 필드입니다
+func (e graphqlError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": string(e.code)}
+}
+
+// graphqlTopic은 Topic 타입의 값입니다 (하위 필드는 토픽 ID로 조회)
+type graphqlTopic struct {
+	ID string `json:"id"`
+}
+
+// graphqlContextKey는 리졸버가 데이터베이스를 찾는 컨텍스트 키입니다
+type graphqlContextKey struct{}
+
+// graphqlDatabase는 요청 컨텍스트의 데이터베이스를 반환합니다
+func graphqlDatabase(p graphql.ResolveParams) *Database {
+	return p.Context.Value(graphqlContextKey{}).(*Database)
+}
+
+// graphqlSchema는 처음 요청될 때 한 번 생성한 스키마입니다
+var graphqlSchema = sync.OnceValues(newGraphQLSchema)
+
+// newGraphQLSchema는 GraphQL 스키마를 생성합니다
+func newGraphQLSchema() (graphql.Schema, error) {
+	var competitionType, topicType *graphql.Object
+
+	leaderboardEntryType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "LeaderboardEntry",
+		Description: "리더보드 참가자 기록",
+		Fields: graphql.Fields{
+			"cosmos_address": &graphql.Field{Type: graphql.String},
+			"username":       &graphql.Field{Type: graphql.String},
+			"first_name":     &graphql.Field{Type: graphql.String},
+			"last_name":      &graphql.Field{Type: graphql.String},
+			"rank":           &graphql.Field{Type: graphql.String},
+			"points":         &graphql.Field{Type: graphql.Float},
+			"score":          &graphql.Field{Type: graphql.Float},
+			"loss":           &graphql.Field{Type: graphql.Float},
+			"is_active":      &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	workerInferenceType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "WorkerInference",
+		Description: "스냅샷의 워커 추론 값 (synthesis_value 항목)",
+		Fields: graphql.Fields{
+			"worker":                   &graphql.Field{Type: graphql.String, Description: "워커 주소"},
+			"inferer_values":           &graphql.Field{Type: graphql.String},
+			"one_out_inferer_values":   &graphql.Field{Type: graphql.String},
+			"weight":                   &graphql.Field{Type: graphql.String},
+			"confidential_percentiles": &graphql.Field{Type: graphql.String, Description: "추론 값이 속한 신뢰 구간 백분위수 (예: 84.13~97.72)"},
+			"leaderboard":              &graphql.Field{Type: leaderboardEntryType, Description: "수집 시점의 리더보드 기록"},
+		},
+	})
+
+	inferenceSnapshotType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "InferenceSnapshot",
+		Description: "블록 높이 하나의 토픽 추론 스냅샷",
+		Fields: graphql.Fields{
+			"topic_id":                            &graphql.Field{Type: graphql.String},
+			"inference_block_height":              &graphql.Field{Type: graphql.String},
+			"loss_block_height":                   &graphql.Field{Type: graphql.String},
+			"timestamp":                           &graphql.Field{Type: graphql.String},
+			"snapshot_hash":                       &graphql.Field{Type: graphql.String},
+			"prev_height":                         &graphql.Field{Type: graphql.String, Description: "이전 스냅샷의 블록 높이"},
+			"next_height":                         &graphql.Field{Type: graphql.String, Description: "다음 스냅샷의 블록 높이 (최신이면 null)"},
+			"confidence_interval_raw_percentiles": &graphql.Field{Type: graphql.NewList(graphql.String)},
+			"confidence_interval_values":          &graphql.Field{Type: graphql.NewList(graphql.String)},
+			"combined_value": &graphql.Field{
+				Type:    graphql.String,
+				Resolve: resolveNetworkInference("combined_value"),
+			},
+			"naive_value": &graphql.Field{
+				Type:    graphql.String,
+				Resolve: resolveNetworkInference("naive_value"),
+			},
+			"worker_count": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return len(snapshotWorkers(p.Source)), nil
+				},
+			},
+			"workers": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(workerInferenceType))),
+				Description: "워커 추론 값 (address로 워커 하나만 조회)",
+				Args: graphql.FieldConfigArgument{
+					"limit":   &graphql.ArgumentConfig{Type: graphql.Int},
+					"address": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: resolveSnapshotWorkers,
+			},
+			"leaderboard": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(leaderboardEntryType))),
+				Description: "스냅샷 블록 높이에 저장된 리더보드 (순위 순)",
+				Args: graphql.FieldConfigArgument{
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: resolveSnapshotLeaderboard,
+			},
+		},
+	})
+
+	topicHeightsType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TopicHeights",
+		Description: "토픽의 블록 높이 목록 (내림차순)",
+		Fields: graphql.Fields{
+			"total_count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"heights":     &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+			"next_before": &graphql.Field{Type: graphql.String, Description: "다음 페이지를 조회할 before 값 (마지막 페이지이면 null)"},
+		},
+	})
+
+	topicStatsType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TopicStats",
+		Description: "토픽 저장 통계",
+		Fields: graphql.Fields{
+			"record_count":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"oldest_timestamp": &graphql.Field{Type: graphql.String},
+			"newest_timestamp": &graphql.Field{Type: graphql.String},
+			"total_size_bytes": &graphql.Field{Type: graphql.Float},
+			"total_size_mb":    &graphql.Field{Type: graphql.Float},
+		},
+	})
+
+	topicType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Topic",
+		Description: "토픽과 저장된 추론 스냅샷",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"competition": &graphql.Field{
+					Type:    competitionType,
+					Resolve: resolveTopicCompetition,
+				},
+				"latest_inference": &graphql.Field{
+					Type:    inferenceSnapshotType,
+					Resolve: resolveLatestInference,
+				},
+				"inference": &graphql.Field{
+					Type:        inferenceSnapshotType,
+					Description: "블록 높이의 스냅샷 (없으면 null)",
+					Args: graphql.FieldConfigArgument{
+						"height": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: resolveInferenceByHeight,
+				},
+				"heights": &graphql.Field{
+					Type: graphql.NewNonNull(topicHeightsType),
+					Args: graphql.FieldConfigArgument{
+						"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageLimit},
+						"before": &graphql.ArgumentConfig{Type: graphql.String, Description: "이 높이보다 낮은 높이부터 조회"},
+					},
+					Resolve: resolveTopicHeights,
+				},
+				"stats": &graphql.Field{
+					Type:    topicStatsType,
+					Resolve: resolveTopicStats,
+				},
+			}
+		}),
+	})
+
+	competitionType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Competition",
+		Description: "경쟁 (competitions_v2)",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"name":              &graphql.Field{Type: graphql.String},
+				"preview_image_url": &graphql.Field{Type: graphql.String},
+				"description":       &graphql.Field{Type: graphql.String},
+				"topic_id":          &graphql.Field{Type: graphql.Int},
+				"prize_pool":        &graphql.Field{Type: graphql.Int},
+				"start_date":        &graphql.Field{Type: graphql.String, Resolve: resolveCompetitionTime(func(c CompetitionV2) time.Time { return c.StartDate })},
+				"end_date":          &graphql.Field{Type: graphql.String, Resolve: resolveCompetitionTime(func(c CompetitionV2) time.Time { return c.EndDate })},
+				"season_id":         &graphql.Field{Type: graphql.Int},
+				"tags":              &graphql.Field{Type: graphql.NewList(graphql.String)},
+				"is_active":         &graphql.Field{Type: graphql.Boolean},
+				"lifecycle":         &graphql.Field{Type: graphql.String},
+				"lifecycle_since":   &graphql.Field{Type: graphql.String},
+				"topic": &graphql.Field{
+					Type: topicType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						competition := p.Source.(CompetitionV2)
+						if competition.TopicID == 0 {
+							return nil, nil
+						}
+						return graphqlTopic{ID: strconv.Itoa(competition.TopicID)}, nil
+					},
+				},
+			}
+		}),
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"competitions": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(competitionType))),
+				Description: "경쟁 목록 (active: 활성 여부, lifecycle: 라이프사이클 상태)",
+				Args: graphql.FieldConfigArgument{
+					"active":    &graphql.ArgumentConfig{Type: graphql.Boolean},
+					"lifecycle": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+				},
+				Resolve: resolveCompetitions,
+			},
+			"competition": &graphql.Field{
+				Type: competitionType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: resolveCompetition,
+			},
+			"topics": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(topicType))),
+				Description: "데이터를 수집 중인 경쟁의 토픽",
+				Resolve:     resolveCollectingTopics,
+			},
+			"topic": &graphql.Field{
+				Type:        topicType,
+				Description: "토픽 (스냅샷과 경쟁이 모두 없으면 null)",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: resolveTopic,
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+	if err != nil {
+		return schema, fmt.Errorf("GraphQL 스키마 생성 실패: %w", err)
+	}
+	return schema, nil
+}
+
+// graphqlInternalError는 조회 실패를 기록하고 내부 오류로 반환합니다
+func graphqlInternalError(message string, err error) error {
+	log.Printf("GraphQL 조회 실패 (%s): %v", message, err)
+	return graphqlError{code: ErrCodeInternal, message: "Failed to retrieve " + message}
+}
+
+// graphqlLimit는 limit 인자를 확인합니다 (없으면 0 = 전체)
+func graphqlLimit(args map[string]interface{}) (int, error) {
+	limit, ok := args["limit"].(int)
+	if !ok {
+		return 0, nil
+	}
+	if limit < 1 || limit > maxPageLimit {
+		return 0, graphqlError{code: ErrCodeInvalidParameter, message: fmt.Sprintf("Invalid limit %d: must be between 1 and %d", limit, maxPageLimit)}
+	}
+	return limit, nil
+}
+
+// graphqlIntegerArg는 정수 형식이어야 하는 문자열 인자(토픽 ID, 블록 높이)를 확인합니다
+func graphqlIntegerArg(args map[string]interface{}, name string) (string, error) {
+	value, _ := args[name].(string)
+	if !paramPatterns[paramInteger].MatchString(value) {
+		return "", graphqlError{code: ErrCodeInvalidParameter, message: fmt.Sprintf("Invalid %s %q: must be a non-negative integer", name, value)}
+	}
+	return value, nil
+}
+
+// resolveCompetitions는 경쟁 목록을 조회합니다
+func resolveCompetitions(p graphql.ResolveParams) (interface{}, error) {
+	db := graphqlDatabase(p)
+
+	var competitions []CompetitionV2
+	var err error
+	active, filtered := p.Args["active"].(bool)
+	switch {
+	case !filtered:
+		competitions, err = db.GetCompetitionsV2()
+	case active:
+		competitions, err = db.GetActiveCompetitionsV2()
+	default:
+		competitions, err = db.GetInactiveCompetitionsV2()
+	}
+	if err != nil {
+		return nil, graphqlInternalError("competition data", err)
+	}
+
+	if states, ok := p.Args["lifecycle"].([]interface{}); ok {
+		allowed := make(map[string]bool)
+		for _, state := range states {
+			allowed[state.(string)] = true
+		}
+		matched := make([]CompetitionV2, 0, len(competitions))
+		for _, competition := range competitions {
+			if allowed[competition.Lifecycle] {
+				matched = append(matched, competition)
+			}
+		}
+		competitions = matched
+	}
+
+	if competitions == nil {
+		competitions = []CompetitionV2{}
+	}
+	return competitions, nil
+}
+
+// resolveCompetition은 경쟁 ID로 경쟁을 조회합니다
+func resolveCompetition(p graphql.ResolveParams) (interface{}, error) {
+	id := p.Args["id"].(int)
+	competition, err := graphqlDatabase(p).GetCompetitionV2ByID(id)
+	if err != nil {
+		return nil, graphqlInternalError(fmt.Sprintf("competition %d", id), err)
+	}
+	if competition == nil {
+		return nil, nil
+	}
+	return *competition, nil
+}
+
+// resolveCompetitionTime은 경쟁 시각 필드를 REST 응답과 같은 RFC3339 문자열로 반환합니다
+func resolveCompetitionTime(field func(CompetitionV2) time.Time) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(p.Source.(CompetitionV2)).Format(time.RFC3339), nil
+	}
+}
+
+// resolveCollectingTopics는 데이터를 수집 중인 토픽 목록을 조회합니다
+func resolveCollectingTopics(p graphql.ResolveParams) (interface{}, error) {
+	topicIDs, err := graphqlDatabase(p).GetCollectingTopicIDs()
+	if err != nil {
+		return nil, graphqlInternalError("collecting topics", err)
+	}
+	topics := make([]graphqlTopic, 0, len(topicIDs))
+	for _, topicID := range topicIDs {
+		topics = append(topics, graphqlTopic{ID: topicID})
+	}
+	return topics, nil
+}
+
+// resolveTopic은 토픽 ID로 토픽을 조회합니다
+// 아직 스냅샷이 없어도 경쟁에 연결된 토픽이면 반환합니다
+func resolveTopic(p graphql.ResolveParams) (interface{}, error) {
+	topicID, err := graphqlIntegerArg(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+	db := graphqlDatabase(p)
+
+	exists, err := db.TopicExists(topicID)
+	if err != nil {
+		return nil, graphqlInternalError(fmt.Sprintf("topic %s", topicID), err)
+	}
+	if !exists {
+		competition, err := db.GetCompetitionByTopicID(topicID)
+		if err != nil {
+			return nil, graphqlInternalError(fmt.Sprintf("competition for topic %s", topicID), err)
+		}
+		if competition == nil {
+			return nil, nil
+		}
+	}
+	return graphqlTopic{ID: topicID}, nil
+}
+
+// resolveTopicCompetition은 토픽에 연결된 경쟁을 조회합니다
+func resolveTopicCompetition(p graphql.ResolveParams) (interface{}, error) {
+	topicID := p.Source.(graphqlTopic).ID
+	competition, err := graphqlDatabase(p).GetCompetitionByTopicID(topicID)
+	if err != nil {
+		return nil, graphqlInternalError(fmt.Sprintf("competition for topic %s", topicID), err)
+	}
+	if competition == nil {
+		return nil, nil
+	}
+	return *competition, nil
+}
+
+// resolveLatestInference는 토픽의 최신 스냅샷을 조회합니다
+func resolveLatestInference(p graphql.ResolveParams) (interface{}, error) {
+	topicID := p.Source.(graphqlTopic).ID
+	inference, err := graphqlDatabase(p).GetLatestTopicInference(topicID)
+	if err != nil {
+		return nil, graphqlInternalError(fmt.Sprintf("latest inference for topic %s", topicID), err)
+	}
+	if inference == nil {
+		return nil, nil
+	}
+	return inference, nil
+}
+
+// resolveInferenceByHeight는 토픽의 블록 높이 스냅샷을 조회합니다
+func resolveInferenceByHeight(p graphql.ResolveParams) (interface{}, error) {
+	topicID := p.Source.(graphqlTopic).ID
+	height, err := graphqlIntegerArg(p.Args, "height")
+	if err != nil {
+		return nil, err
+	}
+	inference, err := graphqlDatabase(p).GetTopicInferenceByHeight(topicID, height)
+	if err != nil {
+		return nil, graphqlInternalError(fmt.Sprintf("inference for topic %s at height %s", topicID, height), err)
+	}
+	if inference == nil {
+		return nil, nil
+	}
+	return inference, nil
+}
+
+// resolveTopicHeights는 토픽의 블록 높이 목록을 조회합니다
+func resolveTopicHeights(p graphql.ResolveParams) (interface{}, error) {
+	topicID := p.Source.(graphqlTopic).ID
+	limit, err := graphqlLimit(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	before := ""
+	if _, ok := p.Args["before"]; ok {
+		if before, err = graphqlIntegerArg(p.Args, "before"); err != nil {
+			return nil, err
+		}
+	}
+
+	heights, next, err := graphqlDatabase(p).GetTopicBlockHeights(topicID, limit, 0, before)
+	if err != nil {
+		return nil, graphqlInternalError(fmt.Sprintf("block heights for topic %s", topicID), err)
+	}
+	if heights == nil {
+		heights = &TopicBlockHeights{TopicID: topicID}
+	}
+	result := map[string]interface{}{
+		"total_count": heights.TotalCount,
+		"heights":     heights.Heights,
+		"next_before": nil,
+	}
+	if heights.Heights == nil {
+		result["heights"] = []string{}
+	}
+	if next != "" {
+		result["next_before"] = next
+	}
+	return result, nil
+}
+
+// resolveTopicStats는 토픽 저장 통계를 조회합니다
+func resolveTopicStats(p graphql.ResolveParams) (interface{}, error) {
+	topicID := p.Source.(graphqlTopic).ID
+	stats, err := graphqlDatabase(p).GetTopicStats(topicID)
+	if err != nil {
+		return nil, graphqlInternalError(fmt.Sprintf("statistics for topic %s", topicID), err)
+	}
+	if stats == nil {
+		return nil, nil
+	}
+	return stats, nil
+}
+
+// resolveNetworkInference는 스냅샷 network_inferences의 값 하나를 반환합니다
+func resolveNetworkInference(key string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		snapshot, _ := p.Source.(map[string]interface{})
+		networkInferences, _ := snapshot["network_inferences"].(map[string]interface{})
+		return networkInferences[key], nil
+	}
+}
+
+// snapshotWorkers는 스냅샷의 워커 추론 값 목록을 반환합니다
+// 저장된 스냅샷은 []interface{}, 조회 시 리더보드로 대체된 목록은 []map[string]interface{}입니다
+func snapshotWorkers(source interface{}) []map[string]interface{} {
+	snapshot, _ := source.(map[string]interface{})
+	networkInferences, _ := snapshot["network_inferences"].(map[string]interface{})
+	switch items := networkInferences["synthesis_value"].(type) {
+	case []map[string]interface{}:
+		return items
+	case []interface{}:
+		workers := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			if worker, ok := item.(map[string]interface{}); ok {
+				workers = append(workers, worker)
+			}
+		}
+		return workers
+	}
+	return nil
+}
+
+// resolveSnapshotWorkers는 스냅샷의 워커 추론 값을 저장된 순서대로 반환합니다
+func resolveSnapshotWorkers(p graphql.ResolveParams) (interface{}, error) {
+	limit, err := graphqlLimit(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	address, _ := p.Args["address"].(string)
+
+	workers := make([]map[string]interface{}, 0)
+	for _, worker := range snapshotWorkers(p.Source) {
+		if address != "" && worker["worker"] != address {
+			continue
+		}
+		workers = append(workers, worker)
+		if limit > 0 && len(workers) == limit {
+			break
+		}
+	}
+	return workers, nil
+}
+
+// resolveSnapshotLeaderboard는 스냅샷 블록 높이에 저장된 리더보드를 조회합니다
+func resolveSnapshotLeaderboard(p graphql.ResolveParams) (interface{}, error) {
+	limit, err := graphqlLimit(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, _ := p.Source.(map[string]interface{})
+	topicID := getStringValue(snapshot, "topic_id", "")
+	height := getStringValue(snapshot, "inference_block_height", "")
+
+	entries, err := graphqlDatabase(p).GetLeaderboardEntries(topicID, height)
+	if err != nil {
+		return nil, graphqlInternalError(fmt.Sprintf("leaderboard for topic %s at height %s", topicID, height), err)
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	if entries == nil {
+		entries = []map[string]interface{}{}
+	}
+	return entries, nil
+}
+
+// graphqlComplexity는 실행할 연산의 최대 깊이와 복잡도를 계산합니다
+// 필드 하나의 비용은 1이고, 객체 목록 필드는 하위 필드 비용에 limit 인자(없으면 예상 항목 수)를 곱합니다
+// 인트로스펙션 필드(__schema, __type, __typename)는 계산하지 않습니다
+func graphqlComplexity(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) (int, int) {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operation == nil && (operationName == "" || (definition.Name != nil && definition.Name.Value == operationName)) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil || schema.QueryType() == nil {
+		return 0, 0
+	}
+
+	var walk func(selections *ast.SelectionSet, parent *graphql.Object, depth int, visiting map[string]bool) (int, int)
+	walk = func(selections *ast.SelectionSet, parent *graphql.Object, depth int, visiting map[string]bool) (int, int) {
+		maxDepth, cost := depth-1, 0
+		if selections == nil {
+			return maxDepth, cost
+		}
+		for _, selection := range selections.Selections {
+			var childDepth, childCost int
+			switch selection := selection.(type) {
+			case *ast.Field:
+				name := selection.Name.Value
+				field := parent.Fields()[name]
+				if strings.HasPrefix(name, "__") || field == nil {
+					continue
+				}
+				childDepth, childCost = depth, 1
+				if object, ok := graphql.GetNamed(field.Type).(*graphql.Object); ok {
+					subDepth, subCost := walk(selection.SelectionSet, object, depth+1, visiting)
+					multiplier := 1
+					if _, ok := graphql.GetNullable(field.Type).(*graphql.List); ok {
+						multiplier = graphqlListSize(parent.Name()+"."+name, selection.Arguments, variables)
+					}
+					childDepth, childCost = subDepth, 1+multiplier*subCost
+				}
+			case *ast.InlineFragment:
+				childDepth, childCost = walk(selection.SelectionSet, parent, depth, visiting)
+			case *ast.FragmentSpread:
+				name := selection.Name.Value
+				fragment, ok := fragments[name]
+				if !ok || visiting[name] {
+					continue
+				}
+				visiting[name] = true
+				childDepth, childCost = walk(fragment.SelectionSet, parent, depth, visiting)
+				delete(visiting, name)
+			}
+			if childDepth > maxDepth {
+				maxDepth = childDepth
+			}
+			cost += childCost
+		}
+		return maxDepth, cost
+	}
+	return walk(operation.SelectionSet, schema.QueryType(), 1, make(map[string]bool))
+}
+
+// graphqlListSize는 목록 필드의 limit 인자 값(리터럴 또는 변수)을, 없으면 예상 항목 수를 반환합니다
+func graphqlListSize(key string, arguments []*ast.Argument, variables map[string]interface{}) int {
+	for _, argument := range arguments {
+		if argument.Name.Value != "limit" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if limit, err := strconv.Atoi(value.Value); err == nil && limit > 0 {
+				return limit
+			}
+		case *ast.Variable:
+			if limit, ok := variables[value.Name.Value].(float64); ok && limit > 0 {
+				return int(limit)
+			}
+		}
+	}
+	if size, ok := graphqlListSizes[key]; ok {
+		return size
+	}
+	return graphqlDefaultListSize
+}
+
+// graphqlErrors는 graphql-go 오류를 응답 형식으로 바꿉니다 (오류 코드가 없으면 defaultCode)
+func graphqlErrors(errs []gqlerrors.FormattedError, defaultCode ErrorCode) []GraphQLError {
+	var result []GraphQLError
+	for _, err := range errs {
+		converted := GraphQLError{Message: err.Message, Path: err.Path, Extensions: GraphQLErrorExtensions{Code: defaultCode}}
+		if code, ok := err.Extensions["code"].(string); ok {
+			converted.Extensions.Code = ErrorCode(code)
+		}
+		for _, location := range err.Locations {
+			converted.Locations = append(converted.Locations, GraphQLLocation{Line: location.Line, Column: location.Column})
+		}
+		result = append(result, converted)
+	}
+	return result
+}
+
+// executeGraphQL은 쿼리를 파싱하고 검증한 뒤, 깊이와 복잡도가 한도 이내이면 실행합니다
+func (s *Service) executeGraphQL(ctx context.Context, schema *graphql.Schema, request GraphQLRequest) GraphQLResponse {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(request.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return GraphQLResponse{Errors: graphqlErrors(gqlerrors.FormatErrors(err), ErrCodeInvalidQuery)}
+	}
+
+	validation := graphql.ValidateDocument(schema, doc, nil)
+	if !validation.IsValid {
+		return GraphQLResponse{Errors: graphqlErrors(validation.Errors, ErrCodeInvalidQuery)}
+	}
+
+	depth, complexity := graphqlComplexity(schema, doc, request.OperationName, request.Variables)
+	if depth > graphqlMaxDepth {
+		return GraphQLResponse{Errors: []GraphQLError{{
+			Message:    fmt.Sprintf("Query depth %d exceeds the limit of %d", depth, graphqlMaxDepth),
+			Extensions: GraphQLErrorExtensions{Code: ErrCodeQueryTooComplex},
+		}}}
+	}
+	if complexity > graphqlMaxComplexity {
+		return GraphQLResponse{Errors: []GraphQLError{{
+			Message:    fmt.Sprintf("Query complexity %d exceeds the limit of %d (lower list limits or select fewer fields)", complexity, graphqlMaxComplexity),
+			Extensions: GraphQLErrorExtensions{Code: ErrCodeQueryTooComplex},
+		}}}
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        *schema,
+		AST:           doc,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       context.WithValue(ctx, graphqlContextKey{}, s.db),
+	})
+
+	// 실행 전에 실패하면(변수 형식 오류, 없는 연산 이름) data가 없으므로 쿼리 오류로 분류
+	defaultCode := ErrCodeInternal
+	if result.Data == nil {
+		defaultCode = ErrCodeInvalidQuery
+	}
+	return GraphQLResponse{Data: result.Data, Errors: graphqlErrors(result.Errors, defaultCode)}
+}
+
+// HandleGraphQL은 GraphQL 쿼리를 실행하는 핸들러입니다
+// GET은 query, variables(JSON), operationName 쿼리 파라미터로, POST는 JSON 본문으로 요청합니다
+func (s *Service) HandleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var request GraphQLRequest
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, graphqlMaxRequestBytes)).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequestBody, fmt.Sprintf("Invalid GraphQL request body: %v", err))
+			return
+		}
+	} else {
+		query := r.URL.Query()
+		request.Query = query.Get("query")
+		request.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid variables: must be a JSON object")
+				return
+			}
+		}
+	}
+
+	if strings.TrimSpace(request.Query) == "" {
+		writeError(w, http.StatusBadRequest, ErrCodeMissingParameter, "Missing query")
+		return
+	}
+	if len(request.Query) > graphqlMaxQueryBytes {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, fmt.Sprintf("Query is too large: at most %d bytes", graphqlMaxQueryBytes))
+		return
+	}
+
+	schema, err := graphqlSchema()
+	if err != nil {
+		log.Printf("GraphQL 스키마 생성 실패: %v", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to build GraphQL schema")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.executeGraphQL(r.Context(), &schema, request))
+}
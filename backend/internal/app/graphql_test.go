@@ -0,0 +1,180 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// queryGraphQL은 POST로 GraphQL 쿼리를 실행하고 응답을 반환합니다 (쿼리 오류도 200)
+func queryGraphQL(t *testing.T, mux http.Handler, request GraphQLRequest) GraphQLResponse {
+	t.Helper()
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := serveTest(mux, http.MethodPost, "/api/v1/graphql", string(body))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GraphQL 상태 코드 = %d, want 200: %s", recorder.Code, recorder.Body.String())
+	}
+	var response GraphQLResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("GraphQL 응답 파싱 실패: %v", err)
+	}
+	return response
+}
+
+// graphqlErrorCode는 응답의 첫 오류 코드를 반환합니다 (오류가 없으면 빈 문자열)
+func graphqlErrorCode(response GraphQLResponse) ErrorCode {
+	if len(response.Errors) == 0 {
+		return ""
+	}
+	return response.Errors[0].Extensions.Code
+}
+
+// nestedWorkersQuery는 경쟁 목록(예상 50개)마다 워커 limit개를 조회하는 쿼리입니다
+// 복잡도는 1 + 50*(3+limit)
+func nestedWorkersQuery(limit int) string {
+	return fmt.Sprintf(`{ competitions { topic { latest_inference { workers(limit: %d) { worker } } } } }`, limit)
+}
+
+func TestGraphQLComplexity(t *testing.T) {
+	schema, err := graphqlSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		query      string
+		variables  map[string]interface{}
+		depth      int
+		complexity int
+	}{
+		{"스칼라 필드", `{ topic(id: "1") { id } }`, nil, 2, 2},
+		{"limit 리터럴", `{ topic(id: "1") { latest_inference { workers(limit: 10) { worker weight } } } }`, nil, 4, 1 + 1 + 1 + 10*2},
+		{"limit 변수", `query($n: Int) { topic(id: "1") { latest_inference { workers(limit: $n) { worker } } } }`, map[string]interface{}{"n": float64(7)}, 4, 1 + 1 + 1 + 7},
+		{"예상 항목 수", `{ competitions { id } }`, nil, 2, 1 + 50},
+		{"프래그먼트", `{ topic(id: "1") { ...T } } fragment T on Topic { id stats { record_count } }`, nil, 3, 1 + 1 + 2},
+		{"인트로스펙션 제외", `{ __typename topic(id: "1") { id } }`, nil, 2, 2},
+	}
+	for _, tt := range tests {
+		doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+		if err != nil {
+			t.Fatalf("%s: 쿼리 파싱 실패: %v", tt.name, err)
+		}
+		depth, complexity := graphqlComplexity(&schema, doc, "", tt.variables)
+		if depth != tt.depth || complexity != tt.complexity {
+			t.Errorf("%s: 깊이 %d, 복잡도 %d; want %d, %d", tt.name, depth, complexity, tt.depth, tt.complexity)
+		}
+	}
+}
+
+func TestGraphQLQueryLimits(t *testing.T) {
+	env := newConformanceEnv(t)
+
+	// 한도 바로 아래(1 + 50*199 = 9951)는 실행하고 바로 위(1 + 50*200 = 10001)는 거부
+	accepted := queryGraphQL(t, env.mux, GraphQLRequest{Query: nestedWorkersQuery(196)})
+	if len(accepted.Errors) != 0 || accepted.Data == nil {
+		t.Errorf("한도 이내 쿼리 오류 = %+v, want 데이터", accepted.Errors)
+	}
+	data, _ := accepted.Data.(map[string]interface{})
+	if competitions, _ := data["competitions"].([]interface{}); len(competitions) != 1 {
+		t.Errorf("한도 이내 쿼리 경쟁 = %v, want 1개", data["competitions"])
+	}
+	rejected := queryGraphQL(t, env.mux, GraphQLRequest{Query: nestedWorkersQuery(197)})
+	if graphqlErrorCode(rejected) != ErrCodeQueryTooComplex || rejected.Data != nil {
+		t.Errorf("한도 초과 쿼리 = %+v, want %s, data 없음", rejected, ErrCodeQueryTooComplex)
+	}
+
+	// 변수로 지정한 limit도 복잡도에 반영
+	variable := queryGraphQL(t, env.mux, GraphQLRequest{
+		Query:     `query($n: Int) { competitions { topic { latest_inference { workers(limit: $n) { worker } } } } }`,
+		Variables: map[string]interface{}{"n": 197},
+	})
+	if graphqlErrorCode(variable) != ErrCodeQueryTooComplex {
+		t.Errorf("변수 limit 한도 초과 쿼리 오류 코드 = %s, want %s", graphqlErrorCode(variable), ErrCodeQueryTooComplex)
+	}
+
+	// 중첩 깊이 한도 초과
+	deep := `{ competitions { topic { competition { topic { competition { topic { competition { topic { id } } } } } } } } }`
+	if response := queryGraphQL(t, env.mux, GraphQLRequest{Query: deep}); graphqlErrorCode(response) != ErrCodeQueryTooComplex || !strings.Contains(response.Errors[0].Message, "depth") {
+		t.Errorf("깊이 초과 쿼리 = %+v, want %s (depth)", response.Errors, ErrCodeQueryTooComplex)
+	}
+}
+
+func TestGraphQLQueryErrors(t *testing.T) {
+	env := newConformanceEnv(t)
+
+	tests := []struct {
+		name  string
+		query string
+		code  ErrorCode
+	}{
+		{"문법 오류", `{ topic(id: "1") { latest_inference { worker_count`, ErrCodeInvalidQuery},
+		{"없는 필드", `{ topic(id: "1") { unknown_field } }`, ErrCodeInvalidQuery},
+		{"잘못된 limit", `{ topic(id: "1") { heights(limit: 0) { heights } } }`, ErrCodeInvalidParameter},
+		{"정수가 아닌 토픽 ID", `{ topic(id: "abc") { id } }`, ErrCodeInvalidParameter},
+		{"복잡도 초과", `{ competitions { topic { latest_inference { workers { worker leaderboard { rank username } } } } } }`, ErrCodeQueryTooComplex},
+	}
+	for _, tt := range tests {
+		response := queryGraphQL(t, env.mux, GraphQLRequest{Query: tt.query})
+		if len(response.Errors) == 0 {
+			t.Errorf("%s: 오류가 없습니다 (%v)", tt.name, response.Data)
+			continue
+		}
+		if code := graphqlErrorCode(response); code != tt.code {
+			t.Errorf("%s: 오류 코드 = %s (%s), want %s", tt.name, code, response.Errors[0].Message, tt.code)
+		}
+	}
+
+	// 리졸버 오류는 해당 필드 경로와 함께 반환하고 나머지 필드는 실행
+	response := queryGraphQL(t, env.mux, GraphQLRequest{Query: `{ bad: topic(id: "abc") { id } good: topic(id: "1") { id } }`})
+	data, _ := response.Data.(map[string]interface{})
+	good, _ := data["good"].(map[string]interface{})
+	if len(response.Errors) != 1 || fmt.Sprint(response.Errors[0].Path) != "[bad]" || good["id"] != "1" {
+		t.Errorf("부분 오류 응답 = %+v, want bad 경로 오류와 good 데이터", response)
+	}
+}
+
+func TestGraphQLRequestErrors(t *testing.T) {
+	env := newConformanceEnv(t)
+	query := url.QueryEscape(`{ topic(id: "1") { id } }`)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		code   ErrorCode
+	}{
+		{"쿼리 누락", http.MethodGet, "/api/v1/graphql", "", ErrCodeMissingParameter},
+		{"빈 쿼리", http.MethodPost, "/api/v1/graphql", `{"query": "  "}`, ErrCodeMissingParameter},
+		{"JSON이 아닌 변수", http.MethodGet, "/api/v1/graphql?query=" + query + "&variables=bogus", "", ErrCodeInvalidParameter},
+		{"본문 없음", http.MethodPost, "/api/v1/graphql", "", ErrCodeInvalidRequestBody},
+		{"JSON이 아닌 본문", http.MethodPost, "/api/v1/graphql", "bogus", ErrCodeInvalidRequestBody},
+		{"너무 큰 쿼리", http.MethodPost, "/api/v1/graphql", `{"query": "{` + strings.Repeat(" ", graphqlMaxQueryBytes) + `}"}`, ErrCodeInvalidParameter},
+	}
+	for _, tt := range tests {
+		recorder := serveTest(env.mux, tt.method, tt.target, tt.body)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: 상태 코드 = %d, want 400", tt.name, recorder.Code)
+			continue
+		}
+		if response := decodeErrorResponse(t, recorder); response.Error.Code != tt.code {
+			t.Errorf("%s: 오류 코드 = %s, want %s", tt.name, response.Error.Code, tt.code)
+		}
+	}
+
+	// GET 쿼리 파라미터로 변수와 연산 이름 전달
+	var response GraphQLResponse
+	getJSON(t, env.mux, "/api/v1/graphql?operationName=Q&query="+url.QueryEscape(`query Q($id: ID!) { topic(id: $id) { id } }`)+"&variables="+url.QueryEscape(`{"id": "1"}`), http.StatusOK, &response)
+	data, _ := response.Data.(map[string]interface{})
+	if topic, _ := data["topic"].(map[string]interface{}); len(response.Errors) != 0 || topic["id"] != "1" {
+		t.Errorf("GET 쿼리 응답 = %+v, want 토픽 1", response)
+	}
+}
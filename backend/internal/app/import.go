@@ -0,0 +1,576 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// 가져오기 원본 형식
+const (
+	ImportFormatAuto    = "auto"
+	ImportFormatNDJSON  = "ndjson"
+	ImportFormatParquet = "parquet"
+	ImportFormatSQLite  = "sqlite"
+)
+
+// 가져오기 기본값
+const (
+	defaultImportBatchSize = 500
+	maxImportReportErrors  = 50 // 보고서에 기록하는 오류 메시지 수 상한
+)
+
+// ImportOptions는 가져오기 설정입니다
+type ImportOptions struct {
+	Format    string // auto(확장자와 내용으로 판별), ndjson, parquet, sqlite
+	TopicID   string // 비어 있으면 전체 토픽
+	BatchSize int    // 트랜잭션 하나에 병합하는 스냅샷 수
+	DryRun    bool   // 병합 결과만 계산하고 저장하지 않음
+}
+
+// ImportTopicReport는 토픽별 가져오기 결과입니다
+type ImportTopicReport struct {
+	Added     int   `json:"added"`
+	Updated   int   `json:"updated"`
+	Skipped   int   `json:"skipped"`
+	MinHeight int64 `json:"min_height"`
+	MaxHeight int64 `json:"max_height"`
+}
+
+// ImportReport는 가져오기 결과입니다
+// 같은 토픽/추론 블록 높이가 이미 있으면 loss_block_height가 더 높은(더 최근) 스냅샷만 반영합니다
+type ImportReport struct {
+	Source     string                        `json:"source"`
+	Format     string                        `json:"format"`
+	DryRun     bool                          `json:"dry_run"`
+	Read       int                           `json:"read"`
+	Added      int                           `json:"added"`
+	Updated    int                           `json:"updated"` // 기존 행보다 loss_block_height가 높아 교체됨
+	Skipped    int                           `json:"skipped"` // 기존 행과 같거나 오래됨
+	Invalid    int                           `json:"invalid"` // 필수 필드가 없거나 저장에 실패함
+	Topics     map[string]*ImportTopicReport `json:"topics"`  // 추가/교체된 토픽별 결과
+	Errors     []string                      `json:"errors"`  // 처음 maxImportReportErrors개의 오류
+	DurationMs int64                         `json:"duration_ms"`
+}
+
+// addError는 잘못된 레코드를 기록합니다
+func (r *ImportReport) addError(err error) {
+	r.Invalid++
+	if len(r.Errors) < maxImportReportErrors {
+		r.Errors = append(r.Errors, err.Error())
+	}
+}
+
+// topic은 토픽별 결과를 반환합니다 (없으면 생성)
+func (r *ImportReport) topic(topicID string) *ImportTopicReport {
+	report, ok := r.Topics[topicID]
+	if !ok {
+		report = &ImportTopicReport{}
+		r.Topics[topicID] = report
+	}
+	return report
+}
+
+// importRecord는 병합할 스냅샷 하나입니다
+type importRecord struct {
+	topicID              string
+	inferenceBlockHeight int64
+	lossBlockHeight      int64
+	snapshot             map[string]interface{}
+}
+
+// ImportTopicData는 내보낸 NDJSON/Parquet 파일이나 다른 allora-monitor SQLite 파일의 토픽 스냅샷을 병합합니다
+// NDJSON/Parquet은 snapshots 데이터셋(data 컬럼)이나 Parquet 아카이브 파일을 읽을 수 있으며, gzip 압축된 NDJSON도 읽습니다
+// 토픽/추론 블록 높이가 같은 스냅샷은 loss_block_height가 더 높은 쪽을 남깁니다
+func (d *Database) ImportTopicData(path string, opts ImportOptions) (*ImportReport, error) {
+	startTime := time.Now()
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultImportBatchSize
+	}
+
+	format, err := detectImportFormat(path, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		Source: path,
+		Format: format,
+		DryRun: opts.DryRun,
+		Topics: make(map[string]*ImportTopicReport),
+		Errors: []string{},
+	}
+
+	batch := make([]importRecord, 0, opts.BatchSize)
+	emit := func(snapshot map[string]interface{}, readErr error) error {
+		if readErr != nil {
+			report.Read++
+			report.addError(fmt.Errorf("레코드 %d: %w", report.Read, readErr))
+			return nil
+		}
+		if opts.TopicID != "" && importStringField(snapshot["topic_id"]) != opts.TopicID {
+			return nil
+		}
+
+		report.Read++
+		record, err := newImportRecord(snapshot)
+		if err != nil {
+			report.addError(fmt.Errorf("레코드 %d: %w", report.Read, err))
+			return nil
+		}
+
+		batch = append(batch, record)
+		if len(batch) < opts.BatchSize {
+			return nil
+		}
+		err = d.mergeTopicInferences(batch, report, opts.DryRun)
+		batch = batch[:0]
+		return err
+	}
+
+	switch format {
+	case ImportFormatNDJSON:
+		err = readImportNDJSON(path, emit)
+	case ImportFormatParquet:
+		err = readImportParquet(path, emit)
+	case ImportFormatSQLite:
+		err = readImportSQLite(path, opts.TopicID, emit)
+	}
+	if err == nil && len(batch) > 0 {
+		err = d.mergeTopicInferences(batch, report, opts.DryRun)
+	}
+	report.DurationMs = time.Since(startTime).Milliseconds()
+	if err != nil {
+		return report, err
+	}
+
+	log.Printf("가져오기 완료 (%s, %s): 읽음=%d, 추가=%d, 교체=%d, 건너뜀=%d, 잘못됨=%d, 소요 시간=%dms",
+		path, format, report.Read, report.Added, report.Updated, report.Skipped, report.Invalid, report.DurationMs)
+
+	return report, nil
+}
+
+// detectImportFormat은 지정된 형식을 확인하거나 확장자와 파일 시작 바이트로 형식을 판별합니다
+func detectImportFormat(path, format string) (string, error) {
+	switch format {
+	case ImportFormatNDJSON, ImportFormatParquet, ImportFormatSQLite:
+		return format, nil
+	case "", ImportFormatAuto:
+	default:
+		return "", fmt.Errorf("알 수 없는 가져오기 형식: %q (사용 가능: %s, %s, %s, %s)",
+			format, ImportFormatAuto, ImportFormatNDJSON, ImportFormatParquet, ImportFormatSQLite)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("가져올 파일 열기 실패: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 16)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("가져올 파일 읽기 실패: %w", err)
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, []byte("SQLite format 3\x00")):
+		return ImportFormatSQLite, nil
+	case bytes.HasPrefix(header, []byte("PAR1")):
+		return ImportFormatParquet, nil
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}), bytes.HasPrefix(bytes.TrimLeft(header, " \t\r\n"), []byte("{")):
+		return ImportFormatNDJSON, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(strings.TrimSuffix(path, ".gz"))) {
+	case ".ndjson", ".jsonl", ".json":
+		return ImportFormatNDJSON, nil
+	case ".parquet":
+		return ImportFormatParquet, nil
+	case ".db", ".sqlite", ".sqlite3":
+		return ImportFormatSQLite, nil
+	}
+	return "", fmt.Errorf("가져올 파일 형식을 판별할 수 없습니다: %s (-format으로 지정하세요)", path)
+}
+
+// newImportRecord는 스냅샷의 필수 필드를 확인하고 블록 높이를 정수로 변환합니다
+func newImportRecord(snapshot map[string]interface{}) (importRecord, error) {
+	topicID := importStringField(snapshot["topic_id"])
+	if topicID == "" {
+		return importRecord{}, fmt.Errorf("topic_id가 없습니다")
+	}
+
+	inferenceBlockHeight, err := strconv.ParseInt(importStringField(snapshot["inference_block_height"]), 10, 64)
+	if err != nil {
+		return importRecord{}, fmt.Errorf("토픽 %s: inference_block_height가 정수가 아닙니다: %v", topicID, snapshot["inference_block_height"])
+	}
+	lossBlockHeight, err := strconv.ParseInt(importStringField(snapshot["loss_block_height"]), 10, 64)
+	if err != nil {
+		return importRecord{}, fmt.Errorf("토픽 %s: loss_block_height가 정수가 아닙니다: %v", topicID, snapshot["loss_block_height"])
+	}
+
+	// 저장 형식은 수집기와 같이 문자열 필드를 사용
+	snapshot["topic_id"] = topicID
+	snapshot["inference_block_height"] = strconv.FormatInt(inferenceBlockHeight, 10)
+	snapshot["loss_block_height"] = strconv.FormatInt(lossBlockHeight, 10)
+	if timestamp := importStringField(snapshot["timestamp"]); timestamp != "" {
+		snapshot["timestamp"] = timestamp
+	} else {
+		delete(snapshot, "timestamp")
+	}
+
+	return importRecord{
+		topicID:              topicID,
+		inferenceBlockHeight: inferenceBlockHeight,
+		lossBlockHeight:      lossBlockHeight,
+		snapshot:             snapshot,
+	}, nil
+}
+
+// importStringField는 문자열 또는 숫자 필드를 문자열로 변환합니다
+func importStringField(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return ""
+}
+
+// mergeTopicInferences는 스냅샷 묶음을 저장소(메인 데이터베이스 또는 토픽 샤드)마다 하나의 트랜잭션으로 병합합니다
+// dryRun이면 샤드를 새로 만들지 않고 현재 저장소에서 결과만 집계합니다
+func (d *Database) mergeTopicInferences(records []importRecord, report *ImportReport, dryRun bool) error {
+	var stores []*topicStore
+	groups := make(map[*topicStore][]importRecord)
+	for _, record := range records {
+		storeFor := d.topicStoreForWrite
+		if dryRun {
+			storeFor = d.topicStoreFor
+		}
+		store, err := storeFor(record.topicID)
+		if err != nil {
+			releaseTopicStores(stores)
+			return err
+		}
+		if _, ok := groups[store]; ok {
+			store.release() // 저장소마다 참조 하나만 유지
+		} else {
+			stores = append(stores, store)
+		}
+		groups[store] = append(groups[store], record)
+	}
+	defer releaseTopicStores(stores)
+
+	for _, store := range stores {
+		if err := d.mergeStoreInferences(store, groups[store], report, dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeStoreInferences는 한 저장소의 스냅샷 묶음을 하나의 트랜잭션으로 병합합니다
+// 레코드마다 세이브포인트를 사용하여 저장에 실패한 레코드만 되돌립니다
+// dryRun이면 결과만 집계하고 트랜잭션을 되돌립니다
+func (d *Database) mergeStoreInferences(store *topicStore, records []importRecord, report *ImportReport, dryRun bool) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, record := range records {
+		var existingLoss int64
+		err := tx.QueryRow(
+			"SELECT loss_block_height FROM topic_inferences WHERE topic_id = ? AND inference_block_height = ?",
+			record.topicID, record.inferenceBlockHeight,
+		).Scan(&existingLoss)
+		exists := err == nil
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("기존 레코드 확인 실패: %w", err)
+		}
+
+		topicReport := report.topic(record.topicID)
+		if exists && record.lossBlockHeight <= existingLoss {
+			report.Skipped++
+			topicReport.Skipped++
+			continue
+		}
+
+		if _, err := tx.Exec("SAVEPOINT import_record"); err != nil {
+			return fmt.Errorf("세이브포인트 생성 실패: %w", err)
+		}
+		if _, err := d.saveTopicInferenceTx(tx, record.snapshot); err != nil {
+			report.addError(fmt.Errorf("토픽 %s 블록 높이 %d 저장 실패: %w", record.topicID, record.inferenceBlockHeight, err))
+			if _, err := tx.Exec("ROLLBACK TO import_record"); err != nil {
+				return fmt.Errorf("세이브포인트 롤백 실패: %w", err)
+			}
+		} else if exists {
+			report.Updated++
+			topicReport.Updated++
+		} else {
+			report.Added++
+			topicReport.Added++
+		}
+		if _, err := tx.Exec("RELEASE import_record"); err != nil {
+			return fmt.Errorf("세이브포인트 해제 실패: %w", err)
+		}
+
+		if topicReport.MinHeight == 0 || record.inferenceBlockHeight < topicReport.MinHeight {
+			topicReport.MinHeight = record.inferenceBlockHeight
+		}
+		if record.inferenceBlockHeight > topicReport.MaxHeight {
+			topicReport.MaxHeight = record.inferenceBlockHeight
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+	invalidated := make(map[string]bool)
+	for _, record := range records {
+		if !invalidated[record.topicID] {
+			invalidated[record.topicID] = true
+			d.cache.invalidateTopic(record.topicID)
+		}
+	}
+	return nil
+}
+
+// readImportNDJSON은 NDJSON 파일(gzip 가능)의 각 줄을 스냅샷으로 읽습니다
+// snapshots 데이터셋처럼 data 필드가 있으면 그 객체를, 없으면 줄 전체를 스냅샷으로 사용합니다
+func readImportNDJSON(path string, emit func(map[string]interface{}, error) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("가져올 파일 열기 실패: %w", err)
+	}
+	defer file.Close()
+
+	buffered := bufio.NewReader(file)
+	var reader io.Reader = buffered
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return fmt.Errorf("gzip 압축 해제 실패: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var line map[string]interface{}
+		if err := decoder.Decode(&line); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("NDJSON 파싱 실패: %w", err)
+		}
+
+		snapshot := line
+		if data, ok := line["data"].(map[string]interface{}); ok {
+			snapshot = data
+			for _, key := range []string{"topic_id", "timestamp", "inference_block_height", "loss_block_height"} {
+				if _, ok := snapshot[key]; !ok && line[key] != nil {
+					snapshot[key] = line[key]
+				}
+			}
+		}
+		if err := emit(snapshot, nil); err != nil {
+			return err
+		}
+	}
+}
+
+// readImportParquet은 data 컬럼(전체 스냅샷 JSON)이 있는 Parquet 파일을 행 단위로 읽습니다
+func readImportParquet(path string, emit func(map[string]interface{}, error) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("가져올 파일 열기 실패: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("가져올 파일 정보 조회 실패: %w", err)
+	}
+	parquetFile, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		return fmt.Errorf("Parquet 파일 열기 실패: %w", err)
+	}
+
+	leafIndex := make(map[string]int)
+	for i, path := range parquetFile.Schema().Columns() {
+		leafIndex[strings.Join(path, ".")] = i
+	}
+	dataIndex, ok := leafIndex["data"]
+	if !ok {
+		return fmt.Errorf("Parquet 파일에 data 컬럼이 없습니다 (snapshots 데이터셋만 가져올 수 있습니다)")
+	}
+
+	reader := parquet.NewReader(parquetFile)
+	defer reader.Close()
+
+	rows := make([]parquet.Row, exportParquetBatchRows)
+	for {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			values := make(map[int]parquet.Value, len(row))
+			for _, value := range row {
+				values[value.Column()] = value
+			}
+
+			var snapshot map[string]interface{}
+			if value, ok := values[dataIndex]; ok && !value.IsNull() {
+				if err := json.Unmarshal(value.ByteArray(), &snapshot); err != nil {
+					if err := emit(nil, fmt.Errorf("data 컬럼 JSON 파싱 실패: %w", err)); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			if snapshot == nil {
+				snapshot = map[string]interface{}{}
+			}
+			for _, key := range []string{"topic_id", "timestamp", "inference_block_height", "loss_block_height"} {
+				index, ok := leafIndex[key]
+				if !ok {
+					continue
+				}
+				if _, exists := snapshot[key]; exists {
+					continue
+				}
+				if value, ok := values[index]; ok && !value.IsNull() {
+					switch value.Kind() {
+					case parquet.Int64, parquet.Int32:
+						snapshot[key] = strconv.FormatInt(value.Int64(), 10)
+					case parquet.ByteArray:
+						snapshot[key] = string(value.ByteArray())
+					}
+				}
+			}
+
+			if err := emit(snapshot, nil); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Parquet 행 읽기 실패: %w", err)
+		}
+	}
+}
+
+// readImportSQLite는 다른 allora-monitor 데이터베이스의 topic_inferences를 읽기 전용으로 열어 스냅샷으로 복원합니다
+func readImportSQLite(path, topicID string, emit func(map[string]interface{}, error) error) error {
+	source, err := sql.Open("sqlite", sqliteDSN(path, "query_only(1)"))
+	if err != nil {
+		return fmt.Errorf("원본 데이터베이스 연결 실패: %w", err)
+	}
+	defer source.Close()
+
+	var tableCount int
+	if err := source.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'topic_inferences'").Scan(&tableCount); err != nil {
+		return fmt.Errorf("원본 데이터베이스 확인 실패: %w", err)
+	}
+	if tableCount == 0 {
+		return fmt.Errorf("원본 데이터베이스에 topic_inferences 테이블이 없습니다: %s", path)
+	}
+
+	// 키프레임/델타 저장 이전의 데이터베이스는 encoding/keyframe_id 컬럼이 없음
+	encodingColumn := "'" + snapshotEncodingFull + "'"
+	keyframeColumn := "NULL"
+	if _, err := columnDeclaredType(source, "topic_inferences", "encoding"); err == nil {
+		encodingColumn = "encoding"
+		keyframeColumn = "keyframe_id"
+	}
+	// 본문 저장소 도입 이전의 데이터베이스는 data에 본문이 그대로 있음
+	decoder := newSnapshotDecoder(source)
+	dataColumn := blobDataColumn
+	if _, err := columnDeclaredType(source, "topic_inferences", "content_hash"); err != nil {
+		dataColumn = "data"
+		decoder.columns = "id, timestamp, encoding, keyframe_id, data"
+	}
+
+	query := `SELECT id, timestamp, ` + encodingColumn + `, ` + keyframeColumn + `, ` + dataColumn + `, topic_id,
+		CAST(inference_block_height AS TEXT), CAST(loss_block_height AS TEXT)
+		FROM topic_inferences`
+	var args []interface{}
+	if topicID != "" {
+		query += " WHERE topic_id = ?"
+		args = append(args, topicID)
+	}
+	// 같은 체인의 델타가 연속되도록 토픽별 ID 순으로 읽음
+	rows, err := source.Query(query+" ORDER BY topic_id, id", args...)
+	if err != nil {
+		return fmt.Errorf("원본 스냅샷 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row snapshotRow
+		var rowTopicID, inferenceBlockHeight, lossBlockHeight string
+		if err := rows.Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data,
+			&rowTopicID, &inferenceBlockHeight, &lossBlockHeight); err != nil {
+			return fmt.Errorf("원본 스냅샷 스캔 실패: %w", err)
+		}
+
+		snapshot, err := decoder.decode(row)
+		if err != nil {
+			// 복원할 수 없는 행은 잘못된 레코드로 집계
+			if err := emit(nil, fmt.Errorf("원본 스냅샷 복원 실패 (ID=%d): %w", row.ID, err)); err != nil {
+				return err
+			}
+			continue
+		}
+		snapshot["topic_id"] = rowTopicID
+		snapshot["timestamp"] = row.Timestamp
+		snapshot["inference_block_height"] = inferenceBlockHeight
+		snapshot["loss_block_height"] = lossBlockHeight
+
+		if err := emit(snapshot, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("원본 스냅샷 처리 중 오류: %w", err)
+	}
+	return nil
+}
+
+// SortedTopicIDs는 보고서의 토픽 ID를 정렬하여 반환합니다
+func (r *ImportReport) SortedTopicIDs() []string {
+	topicIDs := make([]string, 0, len(r.Topics))
+	for topicID := range r.Topics {
+		topicIDs = append(topicIDs, topicID)
+	}
+	sort.Strings(topicIDs)
+	return topicIDs
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withLoss는 레코드의 loss_block_height를 바꾼 복사본을 반환합니다
+func withLoss(record map[string]interface{}, loss string) map[string]interface{} {
+	copied := cloneSnapshot(record)
+	copied["loss_block_height"] = loss
+	return copied
+}
+
+// writeNDJSON은 각 값을 한 줄씩 JSON으로 쓴 파일을 만듭니다 (문자열은 그대로 씀)
+func writeNDJSON(t *testing.T, path string, lines ...interface{}) {
+	t.Helper()
+	var builder strings.Builder
+	for _, line := range lines {
+		if raw, ok := line.(string); ok {
+			builder.WriteString(raw + "\n")
+			continue
+		}
+		data, err := json.Marshal(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		builder.Write(data)
+		builder.WriteByte('\n')
+	}
+	if err := os.WriteFile(path, []byte(builder.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lossHeight는 저장된 스냅샷의 loss_block_height를 반환합니다
+func lossHeight(t *testing.T, db *Database, topicID string, height int) int {
+	t.Helper()
+	return countRows(t, db, "SELECT loss_block_height FROM topic_inferences WHERE topic_id = ? AND inference_block_height = ?", topicID, height)
+}
+
+func TestImportTopicDataMergesByLossHeight(t *testing.T) {
+	dir := t.TempDir()
+	db := newTestDatabase(t)
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(4)
+	snapshot := func(topicID string, height, cycle int) map[string]interface{} {
+		return testSnapshot(topicID, height, base.Add(time.Duration(cycle)*time.Minute), workers, cycle)
+	}
+
+	// 기존 데이터: 토픽 1 높이 100 (loss 90), 110 (loss 100)
+	for _, record := range []map[string]interface{}{
+		withLoss(snapshot("1", 100, 0), "90"),
+		withLoss(snapshot("1", 110, 1), "100"),
+	} {
+		if err := db.SaveTopicInference(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newer := withLoss(snapshot("1", 100, 2), "95")
+	added := snapshot("1", 120, 3)
+	otherTopic := snapshot("2", 5, 4)
+	path := filepath.Join(dir, "snapshots.ndjson")
+	writeNDJSON(t, path,
+		newer,                                  // loss가 더 높음: 교체
+		withLoss(snapshot("1", 110, 5), "100"), // loss가 같음: 건너뜀
+		map[string]interface{}{"topic_id": "1", "data": added}, // snapshots 데이터셋 형식
+		otherTopic,
+		map[string]interface{}{"topic_id": "1", "inference_block_height": "abc", "loss_block_height": "1"},
+		map[string]interface{}{"inference_block_height": "1"}, // topic_id 없음
+	)
+
+	// 깨진 JSON은 파일 전체를 거부
+	broken := filepath.Join(dir, "broken.ndjson")
+	writeNDJSON(t, broken, newer, "{not json")
+	if _, err := db.ImportTopicData(broken, ImportOptions{}); err == nil {
+		t.Fatal("깨진 NDJSON을 가져왔는데 오류가 없습니다")
+	}
+
+	// 미리보기는 결과만 집계하고 저장하지 않음
+	preview, err := db.ImportTopicData(path, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("미리보기 실패: %v", err)
+	}
+	if preview.Added != 2 || preview.Updated != 1 || countRows(t, db, "SELECT COUNT(*) FROM topic_inferences") != 2 {
+		t.Fatalf("미리보기 결과 = %+v, 저장된 행 = %d; want 추가 2, 교체 1, 저장 없음",
+			preview, countRows(t, db, "SELECT COUNT(*) FROM topic_inferences"))
+	}
+
+	report, err := db.ImportTopicData(path, ImportOptions{})
+	if err != nil {
+		t.Fatalf("가져오기 실패: %v", err)
+	}
+	if report.Format != ImportFormatNDJSON || report.Read != 6 || report.Added != 2 || report.Updated != 1 || report.Skipped != 1 || report.Invalid != 2 {
+		t.Errorf("가져오기 결과 = %+v; want ndjson, 읽음 6, 추가 2, 교체 1, 건너뜀 1, 잘못됨 2", report)
+	}
+	want := map[string]ImportTopicReport{
+		"1": {Added: 1, Updated: 1, Skipped: 1, MinHeight: 100, MaxHeight: 120},
+		"2": {Added: 1, MinHeight: 5, MaxHeight: 5},
+	}
+	for topicID, expected := range want {
+		if got := report.Topics[topicID]; got == nil || *got != expected {
+			t.Errorf("토픽 %s 결과 = %+v, want %+v", topicID, got, expected)
+		}
+	}
+
+	for _, check := range []struct {
+		topicID string
+		height  int
+		loss    int
+		record  map[string]interface{}
+	}{
+		{"1", 100, 95, newer},
+		{"1", 110, 100, snapshot("1", 110, 1)},
+		{"1", 120, 110, added},
+		{"2", 5, -5, otherTopic},
+	} {
+		if got := lossHeight(t, db, check.topicID, check.height); got != check.loss {
+			t.Errorf("토픽 %s 높이 %d의 loss = %d, want %d", check.topicID, check.height, got, check.loss)
+		}
+		state, _, _ := storedSnapshot(t, db, check.topicID, strings.TrimSpace(check.record["inference_block_height"].(string)))
+		if !reflect.DeepEqual(state, expectedSnapshot(t, check.record).state) {
+			t.Errorf("토픽 %s 높이 %d 본문이 가져온 스냅샷과 다릅니다", check.topicID, check.height)
+		}
+	}
+}
+
+func TestImportTopicDataFromSQLite(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(6)
+
+	// 원본: 델타 체인으로 저장된 다른 allora-monitor 데이터베이스
+	sourcePath := filepath.Join(dir, "source.db")
+	source := openTestDatabase(t, sourcePath)
+	source.SetSnapshotDelta(true, 4)
+	var records []map[string]interface{}
+	for cycle := 0; cycle < 6; cycle++ {
+		record := testSnapshot("1", 100+cycle*10, base.Add(time.Duration(cycle)*time.Minute), workers, cycle)
+		records = append(records, record)
+		if err := source.SaveTopicInference(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := source.SaveTopicInference(testSnapshot("3", 1, base, workers, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if count := countRows(t, source, "SELECT COUNT(*) FROM topic_inferences WHERE encoding = ?", snapshotEncodingDelta); count == 0 {
+		t.Fatal("원본에 델타 행이 없습니다")
+	}
+	source.Close()
+
+	// 대상에는 높이 100이 더 높은 loss로 이미 있음
+	db := newTestDatabase(t)
+	existing := withLoss(testSnapshot("1", 100, base, workers, 9), "200")
+	if err := db.SaveTopicInference(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := db.ImportTopicData(sourcePath, ImportOptions{TopicID: "1", BatchSize: 2})
+	if err != nil {
+		t.Fatalf("가져오기 실패: %v", err)
+	}
+	if report.Format != ImportFormatSQLite || report.Read != 6 || report.Added != 5 || report.Skipped != 1 || report.Invalid != 0 {
+		t.Errorf("가져오기 결과 = %+v; want sqlite, 읽음 6, 추가 5, 건너뜀 1", report)
+	}
+	if _, ok := report.Topics["3"]; ok || countRows(t, db, "SELECT COUNT(*) FROM topic_inferences WHERE topic_id = '3'") != 0 {
+		t.Error("토픽 필터에 없는 토픽 3을 가져왔습니다")
+	}
+
+	// 원본 델타는 전체 스냅샷으로 복원되어 저장됨
+	for i, record := range records {
+		if i == 0 {
+			record = existing
+		}
+		height := record["inference_block_height"].(string)
+		state, _, _ := storedSnapshot(t, db, "1", height)
+		if !reflect.DeepEqual(state, expectedSnapshot(t, record).state) {
+			t.Errorf("높이 %s 본문이 원본과 다릅니다", height)
+		}
+	}
+}
+
+func TestImportTopicDataFromLegacySQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(3)
+	rows := []legacyRow{
+		{id: 1, height: "100", loss: "90", record: testSnapshot("1", 100, base, workers, 0)},
+		{id: 2, height: "110", loss: "100", keyframe: 1, record: testSnapshot("1", 110, base.Add(time.Minute), workers, 1)},
+	}
+	createLegacyDatabase(t, path, rows)
+
+	// 마이그레이션 전 스키마(본문 해시 없음, TEXT 블록 높이)도 그대로 읽음
+	db := newTestDatabase(t)
+	report, err := db.ImportTopicData(path, ImportOptions{})
+	if err != nil {
+		t.Fatalf("가져오기 실패: %v", err)
+	}
+	if report.Format != ImportFormatSQLite || report.Added != len(rows) || report.Invalid != 0 {
+		t.Fatalf("가져오기 결과 = %+v; want sqlite, 추가 %d", report, len(rows))
+	}
+	for _, row := range rows {
+		state, _, hash := storedSnapshot(t, db, "1", row.height)
+		want := expectedSnapshot(t, row.record)
+		if !reflect.DeepEqual(state, want.state) || hash != want.hash {
+			t.Errorf("높이 %s 본문 또는 해시(%s)가 원본과 다릅니다", row.height, hash)
+		}
+	}
+}
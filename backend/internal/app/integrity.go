@@ -0,0 +1,764 @@
+package app
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+// 무결성 검사 문제 유형
+const (
+	IntegrityIssueSQLite    = "sqlite"    // PRAGMA integrity_check 실패
+	IntegrityIssueDecode    = "decode"    // snappy 압축 해제 실패
+	IntegrityIssueJSON      = "json"      // JSON 파싱 실패
+	IntegrityIssueChain     = "chain"     // 키프레임/델타 체인 복원 실패
+	IntegrityIssueShape     = "shape"     // 필수 필드 누락 또는 타입 불일치
+	IntegrityIssueTimestamp = "timestamp" // RFC3339로 파싱할 수 없는 타임스탬프
+	IntegrityIssueHeight    = "height"    // 정수가 아닌 블록 높이
+	IntegrityIssueDuplicate = "duplicate" // 같은 토픽/블록 높이의 중복 행
+	IntegrityIssueOrphan    = "orphan"    // 스냅샷이 없는 리더보드 행
+	IntegrityIssueBlob      = "blob"      // 참조하는 본문이 snapshot_blobs에 없음
+	IntegrityIssueHash      = "hash"      // 복원한 본문의 해시가 content_hash와 다름
+)
+
+// 문제 행에 적용된 조치
+const (
+	integrityActionQuarantined = "quarantined"
+	integrityActionRepaired    = "repaired"
+)
+
+// IntegrityOptions는 무결성 검사 후 조치 방식을 지정합니다
+type IntegrityOptions struct {
+	Quarantine bool // 읽을 수 없거나 형식이 잘못된 행을 quarantined_rows로 옮김
+	Repair     bool // 중복 행과 고아 리더보드 행을 정리 (정리된 행도 quarantined_rows에 보관)
+}
+
+// IntegrityIssue는 무결성 검사에서 발견된 문제 하나입니다
+type IntegrityIssue struct {
+	Table  string `json:"table"`
+	Shard  string `json:"shard,omitempty"` // 토픽 샤드 파일의 토픽 ID (메인 데이터베이스의 행이면 비어 있음)
+	RowID  int64  `json:"row_id,omitempty"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+	Action string `json:"action,omitempty"`
+}
+
+// IntegrityReport는 무결성 검사 결과입니다
+type IntegrityReport struct {
+	StartedAt       string           `json:"started_at"`
+	FinishedAt      string           `json:"finished_at"`
+	DurationMs      int64            `json:"duration_ms"`
+	SQLiteIntegrity []string         `json:"sqlite_integrity"`
+	RowsChecked     map[string]int   `json:"rows_checked"`
+	Issues          []IntegrityIssue `json:"issues"`
+	Quarantined     int              `json:"quarantined"`
+	Repaired        int              `json:"repaired"`
+}
+
+// OK는 조치되지 않은 문제가 남아 있지 않은지 확인합니다
+func (r *IntegrityReport) OK() bool {
+	return r.Unresolved() == 0
+}
+
+// Unresolved는 조치되지 않은 문제 수를 반환합니다
+func (r *IntegrityReport) Unresolved() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Action == "" {
+			count++
+		}
+	}
+	return count
+}
+
+// addIssue는 보고서에 문제를 추가합니다
+func (r *IntegrityReport) addIssue(table string, rowID int64, kind, detail string) {
+	r.Issues = append(r.Issues, IntegrityIssue{Table: table, RowID: rowID, Kind: kind, Detail: detail})
+}
+
+// addSnapshotIssue는 저장소(메인 데이터베이스 또는 토픽 샤드)의 topic_inferences 행 문제를 추가합니다
+func (r *IntegrityReport) addSnapshotIssue(store *topicStore, rowID int64, kind, detail string) {
+	r.Issues = append(r.Issues, IntegrityIssue{Table: "topic_inferences", Shard: store.topicID, RowID: rowID, Kind: kind, Detail: detail})
+}
+
+// CheckIntegrity는 데이터베이스 전체의 무결성을 검사합니다
+// PRAGMA integrity_check, 모든 압축 블롭의 복원, JSON 형식, 타임스탬프와 블록 높이,
+// 중복 토픽/블록 높이 행, 스냅샷이 없는 리더보드 행을 확인하고 옵션에 따라 문제 행을 격리하거나 정리합니다
+func (d *Database) CheckIntegrity(opts IntegrityOptions) (*IntegrityReport, error) {
+	startTime := time.Now()
+	report := &IntegrityReport{
+		StartedAt:   startTime.UTC().Format(time.RFC3339),
+		RowsChecked: map[string]int{"competitions": 0, "topic_inferences": 0, "leaderboard_entries": 0},
+		Issues:      []IntegrityIssue{},
+	}
+
+	if d.debug {
+		log.Printf("CheckIntegrity 시작: 격리=%v, 정리=%v", opts.Quarantine, opts.Repair)
+	}
+
+	// 토픽 샤드를 사용하면 샤드 파일마다 검사
+	stores, err := d.topicStores()
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTopicStores(stores)
+
+	for _, store := range stores {
+		if err := d.checkSQLiteIntegrity(report, store); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.checkCompetitionRows(report); err != nil {
+		return nil, err
+	}
+	for _, store := range stores {
+		if err := d.checkTopicInferenceRows(report, store); err != nil {
+			return nil, err
+		}
+		if err := d.checkDuplicateTopicHeights(report, store); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.checkLeaderboardRows(report); err != nil {
+		return nil, err
+	}
+
+	if opts.Quarantine || opts.Repair {
+		if err := d.resolveIntegrityIssues(report, opts); err != nil {
+			return report, err
+		}
+	}
+
+	finishedAt := time.Now()
+	report.FinishedAt = finishedAt.UTC().Format(time.RFC3339)
+	report.DurationMs = finishedAt.Sub(startTime).Milliseconds()
+
+	if d.debug {
+		log.Printf("CheckIntegrity 완료: 문제 %d개 (격리 %d, 정리 %d), 소요 시간=%v",
+			len(report.Issues), report.Quarantined, report.Repaired, finishedAt.Sub(startTime))
+	}
+
+	return report, nil
+}
+
+// checkSQLiteIntegrity는 PRAGMA integrity_check 결과를 보고서에 기록합니다 (샤드 결과에는 파일 이름을 붙임)
+func (d *Database) checkSQLiteIntegrity(report *IntegrityReport, store *topicStore) error {
+	rows, err := store.reader.Query("PRAGMA integrity_check")
+	if err != nil {
+		return fmt.Errorf("무결성 검사 실행 실패: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return fmt.Errorf("무결성 검사 결과 스캔 실패: %w", err)
+		}
+		ok := line == "ok"
+		if store.isShard() {
+			line = filepath.Base(store.path) + ": " + line
+		}
+		report.SQLiteIntegrity = append(report.SQLiteIntegrity, line)
+		if !ok {
+			report.Issues = append(report.Issues, IntegrityIssue{Shard: store.topicID, Kind: IntegrityIssueSQLite, Detail: line})
+		}
+	}
+	return rows.Err()
+}
+
+// checkCompetitionRows는 경쟁 데이터 블롭을 복원하고 형식을 검사합니다
+func (d *Database) checkCompetitionRows(report *IntegrityReport) error {
+	rows, err := d.reader.Query("SELECT id, timestamp, " + blobDataColumn + ", content_hash FROM competitions ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("경쟁 데이터 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var timestamp string
+		var data []byte
+		var storedHash sql.NullString
+		if err := rows.Scan(&id, &timestamp, &data, &storedHash); err != nil {
+			return fmt.Errorf("경쟁 데이터 스캔 실패: %w", err)
+		}
+		report.RowsChecked["competitions"]++
+
+		if _, err := time.Parse(time.RFC3339, timestamp); err != nil {
+			report.addIssue("competitions", id, IntegrityIssueTimestamp, fmt.Sprintf("잘못된 타임스탬프: %q", timestamp))
+		}
+
+		if data == nil && storedHash.Valid {
+			report.addIssue("competitions", id, IntegrityIssueBlob, fmt.Sprintf("본문 %s가 snapshot_blobs에 없습니다", storedHash.String))
+			continue
+		}
+		state, kind, err := decodeIntegrityBlob(data)
+		if err != nil {
+			report.addIssue("competitions", id, kind, err.Error())
+			continue
+		}
+		if storedHash.Valid {
+			// 경쟁 데이터의 해시는 압축 해제한 JSON 그대로의 해시
+			if jsonData, err := snappy.Decode(nil, data); err == nil && contentHash(jsonData) != storedHash.String {
+				report.addIssue("competitions", id, IntegrityIssueHash,
+					fmt.Sprintf("본문 해시가 content_hash(%s)와 다릅니다", storedHash.String))
+			}
+		}
+		if _, ok := state["pageProps"].(map[string]interface{}); !ok {
+			report.addIssue("competitions", id, IntegrityIssueShape, "pageProps 객체가 없습니다")
+		}
+	}
+	return rows.Err()
+}
+
+// checkTopicInferenceRows는 저장소 하나의 토픽 스냅샷을 블롭 단위와 체인 단위로 복원하고 형식을 검사합니다
+func (d *Database) checkTopicInferenceRows(report *IntegrityReport, store *topicStore) error {
+	rows, err := store.reader.Query(
+		"SELECT id, topic_id, timestamp, inference_block_height, loss_block_height, encoding, keyframe_id, " + blobDataColumn + ", content_hash FROM topic_inferences ORDER BY id",
+	)
+	if err != nil {
+		return fmt.Errorf("토픽 데이터 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	decoder := newSnapshotDecoder(store.reader)
+	for rows.Next() {
+		var row snapshotRow
+		var topicID, inferenceHeight, lossHeight string
+		var storedHash sql.NullString
+		if err := rows.Scan(&row.ID, &topicID, &row.Timestamp, &inferenceHeight, &lossHeight, &row.Encoding, &row.KeyframeID, &row.Data, &storedHash); err != nil {
+			return fmt.Errorf("토픽 데이터 스캔 실패: %w", err)
+		}
+		report.RowsChecked["topic_inferences"]++
+
+		if _, err := time.Parse(time.RFC3339, row.Timestamp); err != nil {
+			report.addSnapshotIssue(store, row.ID, IntegrityIssueTimestamp, fmt.Sprintf("잘못된 타임스탬프: %q", row.Timestamp))
+		}
+		if _, err := strconv.ParseInt(inferenceHeight, 10, 64); err != nil {
+			report.addSnapshotIssue(store, row.ID, IntegrityIssueHeight, fmt.Sprintf("잘못된 inference_block_height: %q", inferenceHeight))
+		}
+		if _, err := strconv.ParseInt(lossHeight, 10, 64); err != nil {
+			report.addSnapshotIssue(store, row.ID, IntegrityIssueHeight, fmt.Sprintf("잘못된 loss_block_height: %q", lossHeight))
+		}
+
+		// 블롭 자체를 먼저 확인한 뒤 체인 전체를 복원
+		if row.Data == nil && storedHash.Valid {
+			report.addSnapshotIssue(store, row.ID, IntegrityIssueBlob, fmt.Sprintf("본문 %s가 snapshot_blobs에 없습니다", storedHash.String))
+			continue
+		}
+		if _, kind, err := decodeIntegrityBlob(row.Data); err != nil {
+			report.addSnapshotIssue(store, row.ID, kind, err.Error())
+			continue
+		}
+		state, err := decoder.decode(row)
+		if err != nil {
+			report.addSnapshotIssue(store, row.ID, IntegrityIssueChain, err.Error())
+			continue
+		}
+		if storedHash.Valid {
+			if hash, err := snapshotContentHash(state); err == nil && hash != storedHash.String {
+				report.addSnapshotIssue(store, row.ID, IntegrityIssueHash,
+					fmt.Sprintf("복원한 스냅샷의 해시(%s)가 content_hash(%s)와 다릅니다", hash, storedHash.String))
+			}
+		}
+
+		if _, ok := state["network_inferences"].(map[string]interface{}); !ok {
+			report.addSnapshotIssue(store, row.ID, IntegrityIssueShape, "network_inferences 객체가 없습니다")
+		}
+		if stateTopicID, ok := state["topic_id"]; ok && fmt.Sprint(stateTopicID) != topicID {
+			report.addSnapshotIssue(store, row.ID, IntegrityIssueShape,
+				fmt.Sprintf("스냅샷의 topic_id(%v)가 행의 topic_id(%s)와 다릅니다", stateTopicID, topicID))
+		}
+	}
+	return rows.Err()
+}
+
+// checkDuplicateTopicHeights는 저장소 하나에서 같은 토픽/블록 높이를 가진 중복 행을 찾습니다
+// loss_block_height가 가장 높은 행(같으면 가장 최근 행)을 남길 행으로 보고 나머지를 문제로 기록합니다
+func (d *Database) checkDuplicateTopicHeights(report *IntegrityReport, store *topicStore) error {
+	rows, err := store.reader.Query(`
+		SELECT id, topic_id, inference_block_height, loss_block_height FROM topic_inferences
+		WHERE (topic_id, inference_block_height) IN (
+			SELECT topic_id, inference_block_height FROM topic_inferences
+			GROUP BY topic_id, inference_block_height HAVING COUNT(*) > 1
+		)
+		ORDER BY topic_id, inference_block_height, CAST(loss_block_height AS INTEGER) DESC, id DESC
+	`)
+	if err != nil {
+		return fmt.Errorf("중복 행 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var keptID int64
+	var lastKey string
+	for rows.Next() {
+		var id int64
+		var topicID, inferenceHeight, lossHeight string
+		if err := rows.Scan(&id, &topicID, &inferenceHeight, &lossHeight); err != nil {
+			return fmt.Errorf("중복 행 스캔 실패: %w", err)
+		}
+
+		key := topicID + "/" + inferenceHeight
+		if key != lastKey {
+			lastKey = key
+			keptID = id
+			continue
+		}
+		report.addSnapshotIssue(store, id, IntegrityIssueDuplicate,
+			fmt.Sprintf("토픽 %s 블록 높이 %s 중복 (loss_block_height=%s, 유지할 행 ID=%d)", topicID, inferenceHeight, lossHeight, keptID))
+	}
+	return rows.Err()
+}
+
+// checkLeaderboardRows는 리더보드 행의 타임스탬프와 참조하는 스냅샷 존재 여부를 검사합니다
+// 스냅샷은 토픽 샤드에 있을 수 있으므로 토픽별 블록 높이 목록을 저장소에서 읽어 비교합니다
+func (d *Database) checkLeaderboardRows(report *IntegrityReport) error {
+	rows, err := d.reader.Query(
+		"SELECT id, topic_id, inference_block_height, timestamp FROM leaderboard_entries ORDER BY id",
+	)
+	if err != nil {
+		return fmt.Errorf("리더보드 데이터 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	topicHeights := make(map[string]map[int64]bool)
+	for rows.Next() {
+		var id int64
+		var topicID, blockHeight, timestamp string
+		if err := rows.Scan(&id, &topicID, &blockHeight, &timestamp); err != nil {
+			return fmt.Errorf("리더보드 데이터 스캔 실패: %w", err)
+		}
+		report.RowsChecked["leaderboard_entries"]++
+
+		heights, ok := topicHeights[topicID]
+		if !ok {
+			if heights, err = d.snapshotHeights(topicID); err != nil {
+				return err
+			}
+			topicHeights[topicID] = heights
+		}
+		height, parseErr := strconv.ParseInt(blockHeight, 10, 64)
+		hasSnapshot := parseErr == nil && heights[height]
+
+		if _, err := time.Parse(time.RFC3339, timestamp); err != nil {
+			report.addIssue("leaderboard_entries", id, IntegrityIssueTimestamp, fmt.Sprintf("잘못된 타임스탬프: %q", timestamp))
+		}
+		if !hasSnapshot {
+			report.addIssue("leaderboard_entries", id, IntegrityIssueOrphan,
+				fmt.Sprintf("토픽 %s 블록 높이 %s의 스냅샷이 없습니다", topicID, blockHeight))
+		}
+	}
+	return rows.Err()
+}
+
+// snapshotHeights는 토픽 스냅샷의 블록 높이 집합을 반환합니다
+func (d *Database) snapshotHeights(topicID string) (map[int64]bool, error) {
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return nil, err
+	}
+	defer store.release()
+
+	rows, err := store.reader.Query("SELECT inference_block_height FROM topic_inferences WHERE topic_id = ?", topicID)
+	if err != nil {
+		return nil, fmt.Errorf("토픽 %s 블록 높이 조회 실패: %w", topicID, err)
+	}
+	defer rows.Close()
+
+	heights := make(map[int64]bool)
+	for rows.Next() {
+		var height int64
+		if err := rows.Scan(&height); err != nil {
+			return nil, fmt.Errorf("블록 높이 스캔 실패: %w", err)
+		}
+		heights[height] = true
+	}
+	return heights, rows.Err()
+}
+
+// decodeIntegrityBlob은 압축된 JSON 블롭을 복원하고 실패 시 문제 유형을 함께 반환합니다
+func decodeIntegrityBlob(data []byte) (map[string]interface{}, string, error) {
+	jsonData, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, IntegrityIssueDecode, fmt.Errorf("압축 해제 실패: %w", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(jsonData, &state); err != nil {
+		return nil, IntegrityIssueJSON, fmt.Errorf("JSON 파싱 실패: %w", err)
+	}
+	return state, "", nil
+}
+
+// resolveIntegrityIssues는 옵션에 따라 문제 행을 quarantined_rows로 옮기고 원본에서 삭제합니다
+// 복원할 수 없는 스냅샷을 먼저 삭제한 뒤, 복원 가능한 행은 체인을 재구성한 다음 삭제합니다
+func (d *Database) resolveIntegrityIssues(report *IntegrityReport, opts IntegrityOptions) error {
+	// 테이블별, 단계별 삭제 대상 (같은 행에 문제가 여러 개여도 한 번만 처리)
+	type target struct {
+		table     string
+		shard     string
+		undecoded bool
+	}
+	type rowKey struct {
+		table string
+		shard string
+	}
+	targets := make(map[target]map[int64][]int) // 대상 -> 행 ID -> 문제 인덱스
+	for i, issue := range report.Issues {
+		if issue.RowID == 0 {
+			continue
+		}
+		var resolve bool
+		switch issue.Kind {
+		case IntegrityIssueDecode, IntegrityIssueJSON, IntegrityIssueChain, IntegrityIssueShape,
+			IntegrityIssueTimestamp, IntegrityIssueHeight, IntegrityIssueBlob, IntegrityIssueHash:
+			resolve = opts.Quarantine
+		case IntegrityIssueDuplicate, IntegrityIssueOrphan:
+			resolve = opts.Repair
+		}
+		if !resolve {
+			continue
+		}
+
+		undecoded := issue.Kind == IntegrityIssueDecode || issue.Kind == IntegrityIssueJSON || issue.Kind == IntegrityIssueChain ||
+			issue.Kind == IntegrityIssueBlob
+		key := target{table: issue.Table, shard: issue.Shard, undecoded: undecoded}
+		if targets[key] == nil {
+			targets[key] = make(map[int64][]int)
+		}
+		targets[key][issue.RowID] = append(targets[key][issue.RowID], i)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// 격리 사본은 메인 데이터베이스의 quarantined_rows에 저장하고, 토픽 샤드의 행은 샤드 트랜잭션에서 삭제
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	shardTxs := make(map[string]*sql.Tx)
+	var shardStores []*topicStore // 트랜잭션이 끝난 뒤 release
+	defer func() { releaseTopicStores(shardStores) }()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			for _, shardTx := range shardTxs {
+				shardTx.Rollback()
+			}
+		}
+	}()
+	sourceTx := func(shard string) (*sql.Tx, error) {
+		if shard == "" {
+			return tx, nil
+		}
+		if shardTx, ok := shardTxs[shard]; ok {
+			return shardTx, nil
+		}
+		store, err := d.topicStoreFor(shard)
+		if err != nil {
+			return nil, err
+		}
+		shardStores = append(shardStores, store)
+		if !store.isShard() {
+			return nil, fmt.Errorf("토픽 %s 샤드를 찾을 수 없습니다", shard)
+		}
+		shardTx, err := store.db.Begin()
+		if err != nil {
+			return nil, fmt.Errorf("토픽 %s 샤드 트랜잭션 시작 실패: %w", shard, err)
+		}
+		shardTxs[shard] = shardTx
+		return shardTx, nil
+	}
+
+	quarantinedAt := time.Now().UTC().Format(time.RFC3339)
+	handled := make(map[rowKey]map[int64]bool)
+
+	// 복원할 수 없는 행을 먼저 처리해야 남은 체인을 다시 인코딩할 수 있음
+	for _, undecoded := range []bool{true, false} {
+		for key, rowIssues := range targets {
+			if key.undecoded != undecoded {
+				continue
+			}
+			handledKey := rowKey{table: key.table, shard: key.shard}
+			if handled[handledKey] == nil {
+				handled[handledKey] = make(map[int64]bool)
+			}
+
+			ids := make([]int64, 0, len(rowIssues))
+			for id := range rowIssues {
+				if !handled[handledKey][id] {
+					ids = append(ids, id)
+				}
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+			reasons := make(map[int64]string, len(ids))
+			for _, id := range ids {
+				kinds := make([]string, 0, len(rowIssues[id]))
+				for _, index := range rowIssues[id] {
+					kinds = append(kinds, report.Issues[index].Kind)
+				}
+				reasons[id] = strings.Join(kinds, ",")
+			}
+
+			var source *sql.Tx
+			if source, err = sourceTx(key.shard); err != nil {
+				return err
+			}
+			if err = d.quarantineRows(source, tx, key.table, ids, reasons, quarantinedAt, !undecoded); err != nil {
+				return err
+			}
+			for _, id := range ids {
+				handled[handledKey][id] = true
+			}
+		}
+	}
+
+	// 격리 사본을 먼저 커밋한 뒤 샤드에서 삭제 (샤드 커밋이 실패해도 원본 행은 남음)
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+	for shard, shardTx := range shardTxs {
+		delete(shardTxs, shard)
+		if commitErr := shardTx.Commit(); commitErr != nil {
+			for _, remaining := range shardTxs {
+				remaining.Rollback()
+			}
+			d.cache.invalidateAll()
+			return fmt.Errorf("토픽 %s 샤드 트랜잭션 커밋 실패: %w", shard, commitErr)
+		}
+	}
+	d.cache.invalidateAll()
+
+	// 처리된 행의 모든 문제에 조치 기록
+	for i := range report.Issues {
+		issue := &report.Issues[i]
+		if issue.RowID == 0 || !handled[rowKey{table: issue.Table, shard: issue.Shard}][issue.RowID] {
+			continue
+		}
+		if issue.Kind == IntegrityIssueDuplicate || issue.Kind == IntegrityIssueOrphan {
+			issue.Action = integrityActionRepaired
+		} else {
+			issue.Action = integrityActionQuarantined
+		}
+	}
+	for key, ids := range handled {
+		for id := range ids {
+			repaired := true
+			for _, issue := range report.Issues {
+				if issue.Table == key.table && issue.Shard == key.shard && issue.RowID == id && issue.Action == integrityActionQuarantined {
+					repaired = false
+					break
+				}
+			}
+			if repaired {
+				report.Repaired++
+			} else {
+				report.Quarantined++
+			}
+		}
+	}
+
+	return nil
+}
+
+// quarantineRows는 source의 행 전체를 JSON으로 tx의 quarantined_rows에 복사한 뒤 source 테이블에서 삭제합니다
+// source와 tx는 토픽 샤드의 행을 격리할 때만 다릅니다
+// rebase가 true이면 토픽 스냅샷 체인에서 삭제될 행 이후의 델타를 다시 인코딩합니다
+func (d *Database) quarantineRows(source, tx *sql.Tx, table string, ids []int64, reasons map[int64]string, quarantinedAt string, rebase bool) error {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	condition := "id IN (" + placeholders + ")"
+
+	// 본문 저장소를 참조하는 행은 삭제 후 본문이 정리될 수 있으므로 본문을 함께 보관
+	query := "SELECT * FROM " + table + " WHERE " + condition
+	if _, err := columnDeclaredType(source, table, "content_hash"); err == nil {
+		query = "SELECT *, " + blobDataColumn + " AS resolved_data FROM " + table + " WHERE " + condition
+	}
+	rows, err := source.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("%s 격리 대상 조회 실패: %w", table, err)
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return fmt.Errorf("%s 컬럼 조회 실패: %w", table, err)
+	}
+
+	type quarantined struct {
+		id   int64
+		data []byte
+	}
+	var copies []quarantined
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			rows.Close()
+			return fmt.Errorf("%s 격리 대상 스캔 실패: %w", table, err)
+		}
+
+		record := make(map[string]interface{}, len(columns))
+		var id int64
+		for i, column := range columns {
+			record[column] = values[i]
+			if column == "id" {
+				id, _ = values[i].(int64)
+			}
+		}
+		if resolved, ok := record["resolved_data"]; ok {
+			record["data"] = resolved
+			delete(record, "resolved_data")
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("%s 행(ID=%d) 직렬화 실패: %w", table, id, err)
+		}
+		copies = append(copies, quarantined{id: id, data: data})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s 격리 대상 처리 중 오류: %w", table, err)
+	}
+
+	for _, c := range copies {
+		if _, err := tx.Exec(
+			"INSERT INTO quarantined_rows (source_table, source_id, reason, row_data, quarantined_at) VALUES (?, ?, ?, ?, ?)",
+			table, c.id, reasons[c.id], string(c.data), quarantinedAt,
+		); err != nil {
+			return fmt.Errorf("%s 행(ID=%d) 격리 실패: %w", table, c.id, err)
+		}
+	}
+
+	if rebase && table == "topic_inferences" {
+		if _, err := d.rebaseSnapshotChains(source, condition, args...); err != nil {
+			return fmt.Errorf("스냅샷 체인 재구성 실패: %w", err)
+		}
+	}
+
+	if _, err := deleteBlobRows(source, table, condition, args...); err != nil {
+		return fmt.Errorf("%s 격리 행 삭제 실패: %w", table, err)
+	}
+
+	if d.debug {
+		log.Printf("%s 테이블에서 %d개 행을 격리했습니다", table, len(copies))
+	}
+	return nil
+}
+
+// IntegrityChecker는 무결성 검사를 주기적으로 실행하고 마지막 보고서를 보관합니다
+type IntegrityChecker struct {
+	db           *Database
+	interval     time.Duration
+	options      IntegrityOptions // 정기 검사에 적용할 조치
+	stopChan     chan struct{}
+	isRunning    bool
+	runningMutex sync.Mutex
+	checkMutex   sync.Mutex // 검사가 동시에 실행되지 않도록 보호
+	lastReport   *IntegrityReport
+	reportMutex  sync.RWMutex
+}
+
+// NewIntegrityChecker는 새로운 무결성 검사기를 생성합니다
+func NewIntegrityChecker(db *Database, interval time.Duration, options IntegrityOptions) *IntegrityChecker {
+	return &IntegrityChecker{
+		db:       db,
+		interval: interval,
+		options:  options,
+	}
+}
+
+// Start는 주기적인 무결성 검사를 시작합니다
+func (c *IntegrityChecker) Start() error {
+	c.runningMutex.Lock()
+	defer c.runningMutex.Unlock()
+
+	if c.isRunning {
+		return fmt.Errorf("무결성 검사기가 이미 실행 중입니다")
+	}
+	if c.interval <= 0 {
+		return fmt.Errorf("무결성 검사 주기가 설정되지 않았습니다")
+	}
+
+	c.stopChan = make(chan struct{})
+	c.isRunning = true
+
+	go func(stopChan chan struct{}) {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				report, err := c.Run(c.options)
+				if err != nil {
+					log.Printf("정기 무결성 검사 실패: %v", err)
+				} else if len(report.Issues) > 0 {
+					log.Printf("정기 무결성 검사: 문제 %d개 발견 (격리 %d, 정리 %d, 미해결 %d)",
+						len(report.Issues), report.Quarantined, report.Repaired, report.Unresolved())
+				}
+			case <-stopChan:
+				return
+			}
+		}
+	}(c.stopChan)
+
+	log.Printf("무결성 검사기가 시작되었습니다. 간격: %v", c.interval)
+	return nil
+}
+
+// Stop은 주기적인 무결성 검사를 중지합니다
+func (c *IntegrityChecker) Stop() error {
+	c.runningMutex.Lock()
+	defer c.runningMutex.Unlock()
+
+	if !c.isRunning {
+		return fmt.Errorf("무결성 검사기가 실행 중이 아닙니다")
+	}
+
+	close(c.stopChan)
+	c.isRunning = false
+	log.Println("무결성 검사기가 중지되었습니다")
+	return nil
+}
+
+// Run은 무결성 검사를 즉시 실행하고 결과를 마지막 보고서로 보관합니다
+func (c *IntegrityChecker) Run(options IntegrityOptions) (*IntegrityReport, error) {
+	c.checkMutex.Lock()
+	defer c.checkMutex.Unlock()
+
+	report, err := c.db.CheckIntegrity(options)
+	if report != nil {
+		c.reportMutex.Lock()
+		c.lastReport = report
+		c.reportMutex.Unlock()
+	}
+	return report, err
+}
+
+// LastReport는 마지막 무결성 검사 보고서를 반환합니다 (검사 전이면 nil)
+func (c *IntegrityChecker) LastReport() *IntegrityReport {
+	c.reportMutex.RLock()
+	defer c.reportMutex.RUnlock()
+	return c.lastReport
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// insertOrphanLeaderboardRow는 스냅샷이 없는 블록 높이의 리더보드 행을 추가합니다
+func insertOrphanLeaderboardRow(t *testing.T, db *Database) {
+	t.Helper()
+	if _, err := db.db.Exec(
+		"INSERT INTO leaderboard_entries (topic_id, inference_block_height, timestamp, cosmos_address) VALUES (?, ?, ?, ?)",
+		"1", "999", time.Now().UTC().Format(time.RFC3339), "allo1orphan",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIntegrityEndpointOnlyReports(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.SaveTopicInference(testSnapshot("1", 100, time.Now().Add(-time.Hour), testWorkers(3), 0)); err != nil {
+		t.Fatal(err)
+	}
+	insertOrphanLeaderboardRow(t, db)
+	service, mux := newTestService(t, db)
+	service.SetAdminToken(testAdminToken)
+	service.SetIntegrityChecker(NewIntegrityChecker(db, 0, IntegrityOptions{}))
+
+	// HTTP로 실행한 검사는 repair, quarantine을 지정해도 보고만 함
+	recorder := serveTest(adminHandler(mux), http.MethodPost, "/api/v1/admin/integrity?repair=true&quarantine=true", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("무결성 검사 상태 코드 = %d, want 200: %s", recorder.Code, recorder.Body.String())
+	}
+	var response IntegrityResponse
+	getJSON(t, adminHandler(mux), "/api/v1/admin/integrity", http.StatusOK, &response)
+	if response.OK || response.Unresolved != 1 || response.Report.Repaired != 0 || response.Report.Quarantined != 0 {
+		t.Errorf("보고서 = %+v, want 조치되지 않은 고아 행 1개", response.Report)
+	}
+	if count := countRows(t, db, "SELECT COUNT(*) FROM leaderboard_entries"); count != 1 {
+		t.Errorf("HTTP 검사 후 리더보드 행 = %d, want 1 (삭제하지 않음)", count)
+	}
+
+	// 정리는 check 명령이 사용하는 CheckIntegrity로만 실행
+	report, err := db.CheckIntegrity(IntegrityOptions{Repair: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Repaired != 1 || !report.OK() {
+		t.Errorf("정리 보고서 = %+v, want 정리 1개", report)
+	}
+	if count := countRows(t, db, "SELECT COUNT(*) FROM leaderboard_entries"); count != 0 {
+		t.Errorf("정리 후 리더보드 행 = %d, want 0", count)
+	}
+}
@@ -0,0 +1,317 @@
+package app
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// 리더 선출 기본값
+const (
+	collectorLeaseName     = "collector" // 데이터 수집 리더 임대 이름
+	defaultLeaderLeaseTTL  = 30 * time.Second
+	leaderRenewDivisor     = 3 // 임대 기간의 1/3마다 갱신
+	leaderReleaseTimeLimit = 5 * time.Second
+)
+
+// leaderIDHeader는 리더가 아닌 레플리카가 이벤트 연결을 거부할 때 현재 리더 인스턴스 ID를 알리는 응답 헤더입니다
+const leaderIDHeader = "X-Leader-ID"
+
+// LeaderLease는 leader_leases 테이블의 임대 행입니다
+type LeaderLease struct {
+	Name       string `json:"name"`
+	Holder     string `json:"holder"`
+	AcquiredAt string `json:"acquired_at"`
+	RenewedAt  string `json:"renewed_at"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+// AcquireLease는 임대를 획득하거나 갱신합니다
+// 임대가 없거나 만료되었거나 이미 holder가 보유 중이면 holder의 임대로 기록합니다
+// 현재 임대 보유자 정보와 holder의 획득 여부를 반환합니다
+func (d *Database) AcquireLease(name, holder string, ttl time.Duration, now time.Time) (*LeaderLease, bool, error) {
+	nowText := now.UTC().Format(time.RFC3339Nano)
+	_, err := d.db.Exec(`
+		INSERT INTO leader_leases (name, holder, acquired_at, renewed_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			acquired_at = CASE WHEN leader_leases.holder = excluded.holder THEN leader_leases.acquired_at ELSE excluded.acquired_at END,
+			holder = excluded.holder,
+			renewed_at = excluded.renewed_at,
+			expires_at = excluded.expires_at
+		WHERE leader_leases.holder = excluded.holder OR leader_leases.expires_at <= ?
+	`, name, holder, nowText, nowText, now.Add(ttl).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return nil, false, fmt.Errorf("임대 획득 실패: %w", err)
+	}
+
+	lease, err := d.GetLease(name)
+	if err != nil {
+		return nil, false, err
+	}
+	if lease == nil {
+		return nil, false, fmt.Errorf("임대 %s를 찾을 수 없습니다", name)
+	}
+	return lease, lease.Holder == holder, nil
+}
+
+// ReleaseLease는 holder가 보유한 임대를 반납합니다 (다른 인스턴스의 임대는 변경하지 않음)
+func (d *Database) ReleaseLease(name, holder string) error {
+	if _, err := d.db.Exec("DELETE FROM leader_leases WHERE name = ? AND holder = ?", name, holder); err != nil {
+		return fmt.Errorf("임대 반납 실패: %w", err)
+	}
+	return nil
+}
+
+// GetLease는 임대 정보를 반환합니다 (없으면 nil)
+func (d *Database) GetLease(name string) (*LeaderLease, error) {
+	var lease LeaderLease
+	var expiresAt int64
+	err := d.reader.QueryRow(
+		"SELECT name, holder, acquired_at, renewed_at, expires_at FROM leader_leases WHERE name = ?",
+		name,
+	).Scan(&lease.Name, &lease.Holder, &lease.AcquiredAt, &lease.RenewedAt, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("임대 조회 실패: %w", err)
+	}
+
+	lease.ExpiresAt = time.UnixMilli(expiresAt).UTC().Format(time.RFC3339Nano)
+	return &lease, nil
+}
+
+// LeaderStatus는 리더 선출 상태입니다 (/api/health에 표시)
+type LeaderStatus struct {
+	Enabled      bool   `json:"enabled"`
+	InstanceID   string `json:"instance_id"`
+	IsLeader     bool   `json:"is_leader"`
+	LeaderID     string `json:"leader_id,omitempty"`
+	LeaseExpires string `json:"lease_expires_at,omitempty"`
+	LastRenewed  string `json:"last_renewed_at,omitempty"`
+	LastError    string `json:"last_error,omitempty"`
+}
+
+// LeaderElector는 데이터베이스 임대 행과 주기적인 갱신(하트비트)으로 리더를 선출합니다
+// 여러 레플리카가 같은 데이터베이스를 사용할 때 리더만 수집 작업을 실행하고, 모든 레플리카는 조회 API를 계속 제공합니다
+type LeaderElector struct {
+	db            *Database
+	name          string
+	instanceID    string
+	ttl           time.Duration
+	renewInterval time.Duration
+	onElected     func()
+	onRevoked     func()
+
+	mu           sync.Mutex
+	isLeader     bool
+	leaseExpires time.Time     // 마지막으로 획득/갱신한 자신의 임대 만료 시각
+	revoked      chan struct{} // 리더인 동안 열려 있고 리더 자격을 잃으면 닫힘
+	status       LeaderStatus
+
+	stopChan     chan struct{}
+	doneChan     chan struct{}
+	isRunning    bool
+	runningMutex sync.Mutex
+}
+
+// NewLeaderElector는 새로운 리더 선출기를 생성합니다
+// instanceID가 비어 있으면 호스트 이름, 프로세스 ID, 임의 값으로 만듭니다
+func NewLeaderElector(db *Database, instanceID string, ttl time.Duration) *LeaderElector {
+	if instanceID == "" {
+		instanceID = defaultInstanceID()
+	}
+	if ttl <= 0 {
+		ttl = defaultLeaderLeaseTTL
+	}
+	revoked := make(chan struct{})
+	close(revoked)
+	return &LeaderElector{
+		db:            db,
+		name:          collectorLeaseName,
+		instanceID:    instanceID,
+		ttl:           ttl,
+		renewInterval: ttl / leaderRenewDivisor,
+		revoked:       revoked,
+		status:        LeaderStatus{Enabled: true, InstanceID: instanceID},
+	}
+}
+
+// defaultInstanceID는 레플리카를 구분하는 인스턴스 ID를 생성합니다
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "allora-monitor"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// SetCallbacks는 리더가 되었을 때와 리더 자격을 잃었을 때 실행할 함수를 설정합니다
+// 콜백은 선출 루프에서 순서대로 호출되므로 onElected의 오래 걸리는 작업은 고루틴으로 실행해야 합니다
+// onRevoked는 진행 중인 쓰기가 끝난 뒤에 반환해야 합니다 (Stop은 onRevoked가 반환된 뒤 임대를 반납)
+func (e *LeaderElector) SetCallbacks(onElected, onRevoked func()) {
+	e.onElected = onElected
+	e.onRevoked = onRevoked
+}
+
+// InstanceID는 이 인스턴스의 ID를 반환합니다
+func (e *LeaderElector) InstanceID() string {
+	return e.instanceID
+}
+
+// Start는 임대 획득/갱신 루프를 시작합니다
+func (e *LeaderElector) Start() error {
+	e.runningMutex.Lock()
+	defer e.runningMutex.Unlock()
+
+	if e.isRunning {
+		return fmt.Errorf("리더 선출기가 이미 실행 중입니다")
+	}
+
+	e.stopChan = make(chan struct{})
+	e.doneChan = make(chan struct{})
+	e.isRunning = true
+
+	go func(stopChan, doneChan chan struct{}) {
+		defer close(doneChan)
+
+		e.tick()
+		ticker := time.NewTicker(e.renewInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.tick()
+			case <-stopChan:
+				return
+			}
+		}
+	}(e.stopChan, e.doneChan)
+
+	log.Printf("리더 선출기가 시작되었습니다. 인스턴스=%s, 임대 기간=%v, 갱신 간격=%v", e.instanceID, e.ttl, e.renewInterval)
+	return nil
+}
+
+// Stop은 선출 루프를 중지하고, 리더였다면 수집 작업을 멈춘 뒤 임대를 반납하여 다른 레플리카가 바로 이어받게 합니다
+func (e *LeaderElector) Stop() error {
+	e.runningMutex.Lock()
+	defer e.runningMutex.Unlock()
+
+	if !e.isRunning {
+		return fmt.Errorf("리더 선출기가 실행 중이 아닙니다")
+	}
+
+	close(e.stopChan)
+	<-e.doneChan
+	e.isRunning = false
+
+	e.mu.Lock()
+	wasLeader := e.isLeader
+	e.isLeader = false
+	e.status.IsLeader = false
+	if wasLeader {
+		close(e.revoked)
+	}
+	e.mu.Unlock()
+
+	if wasLeader {
+		if e.onRevoked != nil {
+			e.onRevoked()
+		}
+		released := make(chan error, 1)
+		go func() { released <- e.db.ReleaseLease(e.name, e.instanceID) }()
+		select {
+		case err := <-released:
+			if err != nil {
+				log.Printf("리더 임대 반납 실패: %v", err)
+			}
+		case <-time.After(leaderReleaseTimeLimit):
+			log.Printf("리더 임대 반납 시간 초과, 임대 만료 후 다른 레플리카가 이어받습니다")
+		}
+	}
+
+	log.Println("리더 선출기가 중지되었습니다")
+	return nil
+}
+
+// tick은 임대를 획득하거나 갱신하고 리더 상태 변화에 따라 콜백을 호출합니다
+func (e *LeaderElector) tick() {
+	now := time.Now()
+	lease, acquired, err := e.db.AcquireLease(e.name, e.instanceID, e.ttl, now)
+
+	e.mu.Lock()
+	wasLeader := e.isLeader
+	if err != nil {
+		e.status.LastError = err.Error()
+		// 갱신에 실패해도 자신의 임대가 만료되기 전까지는 리더를 유지 (만료 후에는 다른 레플리카가 획득할 수 있음)
+		if e.isLeader && !now.Before(e.leaseExpires) {
+			e.isLeader = false
+		}
+	} else {
+		e.status.LastError = ""
+		e.isLeader = acquired
+		e.status.LeaderID = lease.Holder
+		e.status.LeaseExpires = lease.ExpiresAt
+		if acquired {
+			e.leaseExpires = now.Add(e.ttl)
+			e.status.LastRenewed = now.UTC().Format(time.RFC3339)
+		}
+	}
+	e.status.IsLeader = e.isLeader
+	isLeader := e.isLeader
+	switch {
+	case isLeader && !wasLeader:
+		e.revoked = make(chan struct{})
+	case !isLeader && wasLeader:
+		close(e.revoked)
+	}
+	e.mu.Unlock()
+
+	if err != nil {
+		log.Printf("리더 임대 갱신 실패: %v", err)
+	}
+
+	switch {
+	case isLeader && !wasLeader:
+		log.Printf("리더로 선출되었습니다: 인스턴스=%s", e.instanceID)
+		if e.onElected != nil {
+			e.onElected()
+		}
+	case !isLeader && wasLeader:
+		log.Printf("리더 자격을 잃었습니다: 인스턴스=%s, 현재 리더=%s", e.instanceID, e.Status().LeaderID)
+		if e.onRevoked != nil {
+			e.onRevoked()
+		}
+	}
+}
+
+// IsLeader는 이 인스턴스가 현재 리더인지 반환합니다
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.isLeader
+}
+
+// Revoked는 이 인스턴스가 리더 자격을 잃으면 닫히는 채널을 반환합니다 (리더가 아니면 이미 닫힌 채널)
+func (e *LeaderElector) Revoked() <-chan struct{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.revoked
+}
+
+// Status는 현재 리더 선출 상태를 반환합니다
+func (e *LeaderElector) Status() LeaderStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status
+}
@@ -0,0 +1,168 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingCompetitionsServer는 release가 호출될 때까지 응답을 보류하는 경쟁 데이터 서버입니다
+// 요청이 도착하면 requested로 알리며, 테스트가 중간에 실패해도 정리할 때 응답을 보냅니다
+func blockingCompetitionsServer(t *testing.T) (server *httptest.Server, requested <-chan struct{}, release func()) {
+	t.Helper()
+	body, err := json.Marshal(&CompetitionsResponse{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	arrived := make(chan struct{}, 1)
+	released := make(chan struct{})
+	var once sync.Once
+	release = func() { once.Do(func() { close(released) }) }
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case arrived <- struct{}{}:
+		default:
+		}
+		<-released
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(release)
+	return server, arrived, release
+}
+
+// newCollectingMonitor는 url에서 경쟁 데이터를 수집하는 모니터를 만듭니다
+func newCollectingMonitor(db *Database, url string) *Monitor {
+	monitor := NewMonitor(NewAlloraAPIClient("", 5*time.Second), db, &Config{MonitoringIntervalMinutes: 60})
+	monitor.SetDebug(false)
+	monitor.SetDirectURL(url)
+	return monitor
+}
+
+func TestLeaderReleasesLeaseAfterCollectionExits(t *testing.T) {
+	db := newTestDatabase(t)
+	server, requested, release := blockingCompetitionsServer(t)
+	monitor := newCollectingMonitor(db, server.URL)
+
+	elector := NewLeaderElector(db, "instance-a", time.Minute)
+	elector.SetCallbacks(
+		func() {
+			if err := monitor.Start(); err != nil {
+				t.Errorf("모니터 시작 실패: %v", err)
+			}
+		},
+		func() {
+			if err := monitor.Stop(); err != nil {
+				t.Errorf("모니터 중지 실패: %v", err)
+			}
+		},
+	)
+	if err := elector.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-requested:
+	case <-time.After(5 * time.Second):
+		t.Fatal("수집 요청이 시작되지 않았습니다")
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- elector.Stop() }()
+
+	// 수집이 진행 중인 동안에는 중지가 끝나지 않고 임대도 유지
+	select {
+	case <-stopped:
+		t.Fatal("진행 중인 수집이 끝나기 전에 리더 선출기가 중지되었습니다")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if lease, err := db.GetLease(collectorLeaseName); err != nil || lease == nil || lease.Holder != "instance-a" {
+		t.Fatalf("수집 중 임대 = %+v (%v), want instance-a 보유", lease, err)
+	}
+
+	release()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("리더 선출기 중지 실패: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("수집이 끝난 뒤에도 리더 선출기가 중지되지 않았습니다")
+	}
+
+	// 수집 결과는 임대를 반납하기 전에 저장됨
+	if count := countRows(t, db, "SELECT COUNT(*) FROM competitions"); count != 1 {
+		t.Errorf("저장된 경쟁 이력 = %d, want 1", count)
+	}
+	if lease, err := db.GetLease(collectorLeaseName); err != nil || lease != nil {
+		t.Errorf("중지 후 임대 = %+v (%v), want 반납됨", lease, err)
+	}
+	if monitor.IsRunning() || monitor.GetTopicInferenceStore().IsRunning() {
+		t.Error("중지 후에도 수집이 실행 중입니다")
+	}
+}
+
+// newElectorPair는 instance-a가 리더이고 instance-b가 팔로워인 두 선출기를 만듭니다 (선출 루프는 시작하지 않음)
+func newElectorPair(t *testing.T, db *Database) (leader, follower *LeaderElector) {
+	t.Helper()
+	leader = NewLeaderElector(db, "instance-a", time.Minute)
+	follower = NewLeaderElector(db, "instance-b", time.Minute)
+	leader.tick()
+	follower.tick()
+	if !leader.IsLeader() || follower.IsLeader() || follower.Status().LeaderID != "instance-a" {
+		t.Fatalf("선출 상태 = %+v, %+v; want instance-a 리더", leader.Status(), follower.Status())
+	}
+	return leader, follower
+}
+
+// handOverLeadership은 from의 임대를 반납하고 to가 리더가 되게 한 뒤 from에 리더 자격 상실을 반영합니다
+func handOverLeadership(t *testing.T, db *Database, from, to *LeaderElector) {
+	t.Helper()
+	if err := db.ReleaseLease(collectorLeaseName, from.InstanceID()); err != nil {
+		t.Fatal(err)
+	}
+	to.tick()
+	from.tick()
+	if from.IsLeader() || !to.IsLeader() {
+		t.Fatalf("리더 교체 후 상태 = %+v, %+v", from.Status(), to.Status())
+	}
+}
+
+func TestLeaderRevokedChannel(t *testing.T) {
+	db := newTestDatabase(t)
+	if revoked := NewLeaderElector(db, "instance-c", time.Minute).Revoked(); !isClosed(revoked) {
+		t.Error("리더가 아닌 선출기의 Revoked 채널이 열려 있습니다")
+	}
+
+	leader, follower := newElectorPair(t, db)
+	revoked := leader.Revoked()
+	if isClosed(revoked) || !isClosed(follower.Revoked()) {
+		t.Fatal("리더의 Revoked 채널만 열려 있어야 합니다")
+	}
+	leader.tick() // 갱신해도 같은 채널 유지
+	if leader.Revoked() != revoked || isClosed(revoked) {
+		t.Error("임대 갱신 후 Revoked 채널이 바뀌었습니다")
+	}
+
+	handOverLeadership(t, db, leader, follower)
+	if !isClosed(revoked) {
+		t.Error("리더 자격을 잃은 뒤에도 Revoked 채널이 열려 있습니다")
+	}
+	if isClosed(follower.Revoked()) {
+		t.Error("새 리더의 Revoked 채널이 닫혀 있습니다")
+	}
+}
+
+// isClosed는 채널이 닫혔는지 기다리지 않고 확인합니다
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,343 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// LeaderboardPoint는 특정 블록 높이에서의 참가자 리더보드 기록입니다
+// 변화량은 참가자가 직전에 등장한 스냅샷과 비교한 값이며, 첫 등장에는 nil입니다
+type LeaderboardPoint struct {
+	InferenceBlockHeight string   `json:"inference_block_height"`
+	Timestamp            string   `json:"timestamp"`
+	Rank                 *int     `json:"rank"`
+	Points               float64  `json:"points"`
+	Score                float64  `json:"score"`
+	Loss                 float64  `json:"loss"`
+	IsActive             bool     `json:"is_active"`
+	RankDelta            *int     `json:"rank_delta"` // 양수면 순위 상승
+	PointsDelta          *float64 `json:"points_delta"`
+	ScoreDelta           *float64 `json:"score_delta"`
+	LossDelta            *float64 `json:"loss_delta"`
+}
+
+// LeaderboardAppearance는 참가자가 리더보드에 등장한 스냅샷 위치입니다
+type LeaderboardAppearance struct {
+	InferenceBlockHeight string `json:"inference_block_height"`
+	Timestamp            string `json:"timestamp"`
+}
+
+// LeaderboardHistory는 경쟁 내 한 참가자의 리더보드 시계열입니다
+type LeaderboardHistory struct {
+	CompetitionID int                    `json:"competition_id"`
+	TopicID       string                 `json:"topic_id"`
+	CosmosAddress string                 `json:"cosmos_address"`
+	Username      string                 `json:"username"`
+	FirstSeen     *LeaderboardAppearance `json:"first_seen"`
+	LastSeen      *LeaderboardAppearance `json:"last_seen"`
+	History       []LeaderboardPoint     `json:"history"`
+}
+
+// LeaderboardMover는 기간 동안 순위가 크게 변한 참가자입니다
+type LeaderboardMover struct {
+	CosmosAddress string  `json:"cosmos_address"`
+	Username      string  `json:"username"`
+	FromRank      int     `json:"from_rank"`
+	ToRank        int     `json:"to_rank"`
+	RankChange    int     `json:"rank_change"` // 양수면 순위 상승
+	PointsChange  float64 `json:"points_change"`
+	ScoreChange   float64 `json:"score_change"`
+}
+
+// LeaderboardMovers는 두 스냅샷 사이의 순위 변동 결과입니다
+type LeaderboardMovers struct {
+	CompetitionID int                    `json:"competition_id"`
+	TopicID       string                 `json:"topic_id"`
+	From          *LeaderboardAppearance `json:"from"`
+	To            *LeaderboardAppearance `json:"to"`
+	Movers        []LeaderboardMover     `json:"movers"`
+}
+
+// getCompetitionTopicID는 경쟁 ID에 해당하는 토픽 ID를 조회합니다
+// 경쟁이 없거나 연결된 토픽이 없으면 빈 문자열을 반환합니다
+func (d *Database) getCompetitionTopicID(competitionID int) (string, error) {
+	var topicID sql.NullInt64
+	err := d.reader.QueryRow("SELECT topic_id FROM competitions_v2 WHERE id = ?", competitionID).Scan(&topicID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("경쟁 토픽 조회 실패: %w", err)
+	}
+	if !topicID.Valid || topicID.Int64 == 0 {
+		return "", nil
+	}
+	return strconv.FormatInt(topicID.Int64, 10), nil
+}
+
+// parseLeaderboardRank는 문자열 순위를 정수로 변환합니다 (순위가 없으면 nil)
+func parseLeaderboardRank(rank string) *int {
+	value, err := strconv.Atoi(rank)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+// GetLeaderboardHistory는 경쟁 내 참가자의 리더보드 기록을 블록 높이 순으로 가져옵니다
+// 경쟁이 없거나 연결된 토픽이 없으면 nil을 반환합니다
+// page를 지정하면 page.After 다음 높이부터 최대 page.Limit개를 조회하고, 다음 페이지가 있으면 다음 페이지의 기준 높이를 함께 반환합니다
+// 페이지의 첫 기록도 변화량을 계산할 수 있도록 기준 높이의 기록을 함께 읽어 비교에만 사용합니다
+func (d *Database) GetLeaderboardHistory(competitionID int, cosmosAddress string, start, end time.Time, page PageQuery) (*LeaderboardHistory, string, error) {
+	if d.debug {
+		log.Printf("GetLeaderboardHistory 시작: 경쟁 ID=%d, 주소=%s, 기준 높이=%s, 제한=%d", competitionID, cosmosAddress, page.After, page.Limit)
+	}
+
+	topicID, err := d.getCompetitionTopicID(competitionID)
+	if err != nil || topicID == "" {
+		return nil, "", err
+	}
+
+	query := `
+		SELECT inference_block_height, timestamp, username, rank, points, score, loss, is_active
+		FROM leaderboard_entries
+		WHERE topic_id = ? AND cosmos_address = ? AND timestamp BETWEEN ? AND ?`
+	args := []interface{}{topicID, cosmosAddress, start.Format(time.RFC3339), end.Format(time.RFC3339)}
+	if page.After != "" {
+		query += " AND CAST(inference_block_height AS INTEGER) >= CAST(? AS INTEGER)"
+		args = append(args, page.After)
+	}
+	query += " ORDER BY CAST(inference_block_height AS INTEGER)"
+	if page.Paged() {
+		// 기준 높이의 기록과 다음 페이지 확인용 기록을 포함
+		query += " LIMIT ?"
+		args = append(args, page.Limit+2)
+	}
+
+	rows, err := d.reader.Query(query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("리더보드 기록 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	history := &LeaderboardHistory{
+		CompetitionID: competitionID,
+		TopicID:       topicID,
+		CosmosAddress: cosmosAddress,
+		History:       []LeaderboardPoint{},
+	}
+
+	var prev *LeaderboardPoint
+	for rows.Next() {
+		var point LeaderboardPoint
+		var username, rank sql.NullString
+		var points, score, loss sql.NullFloat64
+		var isActive sql.NullBool
+
+		if err := rows.Scan(&point.InferenceBlockHeight, &point.Timestamp, &username, &rank, &points, &score, &loss, &isActive); err != nil {
+			return nil, "", fmt.Errorf("리더보드 기록 스캔 실패: %w", err)
+		}
+
+		point.Rank = parseLeaderboardRank(rank.String)
+		point.Points = points.Float64
+		point.Score = score.Float64
+		point.Loss = loss.Float64
+		point.IsActive = isActive.Bool
+		if username.String != "" {
+			history.Username = username.String
+		}
+
+		if prev != nil {
+			if prev.Rank != nil && point.Rank != nil {
+				rankDelta := *prev.Rank - *point.Rank
+				point.RankDelta = &rankDelta
+			}
+			pointsDelta := point.Points - prev.Points
+			scoreDelta := point.Score - prev.Score
+			lossDelta := point.Loss - prev.Loss
+			point.PointsDelta = &pointsDelta
+			point.ScoreDelta = &scoreDelta
+			point.LossDelta = &lossDelta
+		}
+
+		if page.After != "" && point.InferenceBlockHeight == page.After {
+			// 이전 페이지의 마지막 기록은 변화량 계산에만 사용
+			seed := point
+			prev = &seed
+			continue
+		}
+
+		history.History = append(history.History, point)
+		prev = &history.History[len(history.History)-1]
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	var next string
+	if page.Paged() && len(history.History) > page.Limit {
+		history.History = history.History[:page.Limit]
+		next = history.History[page.Limit-1].InferenceBlockHeight
+	}
+
+	// 첫/마지막 등장은 조회 기간과 관계없이 전체 기록 기준
+	history.FirstSeen, err = d.findLeaderboardAppearance(topicID, cosmosAddress, "ASC")
+	if err != nil {
+		return nil, "", err
+	}
+	history.LastSeen, err = d.findLeaderboardAppearance(topicID, cosmosAddress, "DESC")
+	if err != nil {
+		return nil, "", err
+	}
+
+	if d.debug {
+		log.Printf("GetLeaderboardHistory 완료: %d개 기록", len(history.History))
+	}
+
+	return history, next, nil
+}
+
+// findLeaderboardAppearance는 참가자가 처음(ASC) 또는 마지막(DESC)으로 등장한 스냅샷을 찾습니다
+func (d *Database) findLeaderboardAppearance(topicID, cosmosAddress, order string) (*LeaderboardAppearance, error) {
+	var appearance LeaderboardAppearance
+	err := d.reader.QueryRow(`
+		SELECT inference_block_height, timestamp
+		FROM leaderboard_entries
+		WHERE topic_id = ? AND cosmos_address = ?
+		ORDER BY CAST(inference_block_height AS INTEGER) `+order+`
+		LIMIT 1
+	`, topicID, cosmosAddress).Scan(&appearance.InferenceBlockHeight, &appearance.Timestamp)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("리더보드 등장 기록 조회 실패: %w", err)
+	}
+	return &appearance, nil
+}
+
+// findLeaderboardSnapshot은 기준 시각 조건에 맞는 리더보드 스냅샷 위치를 찾습니다
+// latest가 true면 at 이전의 마지막 스냅샷, false면 at 이후의 첫 스냅샷을 찾습니다
+func (d *Database) findLeaderboardSnapshot(topicID string, at time.Time, latest bool) (*LeaderboardAppearance, error) {
+	query := `
+		SELECT inference_block_height, MIN(timestamp)
+		FROM leaderboard_entries
+		WHERE topic_id = ? AND timestamp >= ?
+		GROUP BY inference_block_height
+		ORDER BY CAST(inference_block_height AS INTEGER) ASC
+		LIMIT 1`
+	if latest {
+		query = `
+		SELECT inference_block_height, MAX(timestamp)
+		FROM leaderboard_entries
+		WHERE topic_id = ? AND timestamp <= ?
+		GROUP BY inference_block_height
+		ORDER BY CAST(inference_block_height AS INTEGER) DESC
+		LIMIT 1`
+	}
+
+	var snapshot LeaderboardAppearance
+	err := d.reader.QueryRow(query, topicID, at.Format(time.RFC3339)).Scan(&snapshot.InferenceBlockHeight, &snapshot.Timestamp)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("리더보드 스냅샷 조회 실패: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// GetLeaderboardMovers는 기간 시작과 끝 스냅샷을 비교하여 순위 변동이 큰 참가자를 가져옵니다
+// 두 스냅샷 모두에 순위가 있는 참가자만 비교하며, limit이 0 이하면 전체를 반환합니다
+// 경쟁이 없거나 연결된 토픽이 없으면 nil을 반환합니다
+func (d *Database) GetLeaderboardMovers(competitionID int, window time.Duration, end time.Time, limit int) (*LeaderboardMovers, error) {
+	if d.debug {
+		log.Printf("GetLeaderboardMovers 시작: 경쟁 ID=%d, 기간=%v", competitionID, window)
+	}
+
+	topicID, err := d.getCompetitionTopicID(competitionID)
+	if err != nil || topicID == "" {
+		return nil, err
+	}
+
+	result := &LeaderboardMovers{
+		CompetitionID: competitionID,
+		TopicID:       topicID,
+		Movers:        []LeaderboardMover{},
+	}
+
+	result.To, err = d.findLeaderboardSnapshot(topicID, end, true)
+	if err != nil {
+		return nil, err
+	}
+	result.From, err = d.findLeaderboardSnapshot(topicID, end.Add(-window), false)
+	if err != nil {
+		return nil, err
+	}
+	if result.From == nil || result.To == nil || result.From.InferenceBlockHeight == result.To.InferenceBlockHeight {
+		return result, nil
+	}
+
+	fromEntries, err := d.GetLeaderboardEntries(topicID, result.From.InferenceBlockHeight)
+	if err != nil {
+		return nil, err
+	}
+	toEntries, err := d.GetLeaderboardEntries(topicID, result.To.InferenceBlockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	fromByAddress := make(map[string]map[string]interface{}, len(fromEntries))
+	for _, entry := range fromEntries {
+		fromByAddress[getStringValue(entry, "cosmos_address", "")] = entry
+	}
+
+	for _, entry := range toEntries {
+		address := getStringValue(entry, "cosmos_address", "")
+		fromEntry, ok := fromByAddress[address]
+		if !ok {
+			continue
+		}
+
+		fromRank := parseLeaderboardRank(getStringValue(fromEntry, "rank", ""))
+		toRank := parseLeaderboardRank(getStringValue(entry, "rank", ""))
+		if fromRank == nil || toRank == nil {
+			continue
+		}
+
+		result.Movers = append(result.Movers, LeaderboardMover{
+			CosmosAddress: address,
+			Username:      getStringValue(entry, "username", ""),
+			FromRank:      *fromRank,
+			ToRank:        *toRank,
+			RankChange:    *fromRank - *toRank,
+			PointsChange:  getFloatValue(entry, "points", 0) - getFloatValue(fromEntry, "points", 0),
+			ScoreChange:   getFloatValue(entry, "score", 0) - getFloatValue(fromEntry, "score", 0),
+		})
+	}
+
+	// 변동 폭이 큰 순서, 같으면 현재 순위 순
+	sort.SliceStable(result.Movers, func(i, j int) bool {
+		a, b := math.Abs(float64(result.Movers[i].RankChange)), math.Abs(float64(result.Movers[j].RankChange))
+		if a != b {
+			return a > b
+		}
+		return result.Movers[i].ToRank < result.Movers[j].ToRank
+	})
+
+	if limit > 0 && len(result.Movers) > limit {
+		result.Movers = result.Movers[:limit]
+	}
+
+	if d.debug {
+		log.Printf("GetLeaderboardMovers 완료: %s -> %s, %d명", result.From.InferenceBlockHeight, result.To.InferenceBlockHeight, len(result.Movers))
+	}
+
+	return result, nil
+}
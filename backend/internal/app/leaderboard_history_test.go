@@ -0,0 +1,208 @@
+package app
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// leaderboardTestRanks는 참가자별 스냅샷 순위입니다 (높이 100, 110, 120, 130)
+var leaderboardTestRanks = map[string][]int{
+	"allo1a": {1, 2, 4, 3},
+	"allo1b": {2, 1, 1, 1},
+	"allo1c": {3, 3, 2, 4},
+	"allo1d": {4, 4, 3, 2},
+}
+
+// seedLeaderboardHistory는 경쟁 1(토픽 1)에 1시간 간격의 리더보드 스냅샷 4개를 저장하고 첫 스냅샷 시각을 반환합니다
+// allo1a의 점수와 포인트는 스냅샷마다 1.5, 2, 1.25, 3 / 10, 12, 15, 15입니다
+func seedLeaderboardHistory(t *testing.T, db *Database) time.Time {
+	t.Helper()
+	base := time.Now().UTC().Add(-10 * time.Hour).Truncate(time.Second)
+	if err := db.SaveCompetitions(testCompetitions(Competition{ID: 1, Name: "Alpha", TopicID: 1, StartDate: base.Add(-24 * time.Hour), EndDate: base.Add(24 * time.Hour), SeasonID: 1})); err != nil {
+		t.Fatal(err)
+	}
+
+	scores := []float64{1.5, 2, 1.25, 3}
+	points := []float64{10, 12, 15, 15}
+	for i := range scores {
+		height := strconv.Itoa(100 + i*10)
+		var entries []map[string]interface{}
+		for address, ranks := range leaderboardTestRanks {
+			entry := map[string]interface{}{
+				"cosmos_address": address,
+				"username":       strings.TrimPrefix(address, "allo1"),
+				"rank":           strconv.Itoa(ranks[i]),
+				"points":         float64(ranks[i]),
+				"score":          float64(ranks[i]),
+				"is_active":      true,
+			}
+			if address == "allo1a" {
+				entry["points"], entry["score"] = points[i], scores[i]
+			}
+			entries = append(entries, entry)
+		}
+		if err := db.SaveLeaderboardEntries("1", height, entries); err != nil {
+			t.Fatal(err)
+		}
+		// 저장 시각 대신 스냅샷마다 다른 시각을 사용
+		timestamp := base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		if _, err := db.db.Exec("UPDATE leaderboard_entries SET timestamp = ? WHERE inference_block_height = ?", timestamp, height); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return base
+}
+
+// leaderboardDeltas는 기록의 높이와 순위/점수/포인트 변화량을 비교하기 쉬운 문자열로 만듭니다
+func leaderboardDeltas(points []LeaderboardPoint) []string {
+	var deltas []string
+	for _, point := range points {
+		delta := point.InferenceBlockHeight
+		if point.RankDelta != nil {
+			delta += " rank=" + strconv.Itoa(*point.RankDelta)
+		}
+		if point.ScoreDelta != nil {
+			delta += " score=" + strconv.FormatFloat(*point.ScoreDelta, 'g', -1, 64)
+		}
+		if point.PointsDelta != nil {
+			delta += " points=" + strconv.FormatFloat(*point.PointsDelta, 'g', -1, 64)
+		}
+		deltas = append(deltas, delta)
+	}
+	return deltas
+}
+
+func TestLeaderboardHistoryDeltas(t *testing.T) {
+	db := newTestDatabase(t)
+	base := seedLeaderboardHistory(t, db)
+	start, end := base.Add(-time.Minute), base.Add(4*time.Hour)
+
+	history, next, err := db.GetLeaderboardHistory(1, "allo1a", start, end, PageQuery{})
+	if err != nil || history == nil || next != "" {
+		t.Fatalf("리더보드 기록 = %+v, 다음 %q (%v)", history, next, err)
+	}
+	// 첫 기록은 변화량이 없고, 순위 변화량은 순위가 오르면 양수
+	want := []string{
+		"100",
+		"110 rank=-1 score=0.5 points=2",
+		"120 rank=-2 score=-0.75 points=3",
+		"130 rank=1 score=1.75 points=0",
+	}
+	if got := leaderboardDeltas(history.History); !reflect.DeepEqual(got, want) {
+		t.Errorf("변화량 = %v, want %v", got, want)
+	}
+	if history.Username != "a" || history.TopicID != "1" {
+		t.Errorf("참가자 정보 = %s / 토픽 %s, want a / 1", history.Username, history.TopicID)
+	}
+
+	// 조회 기간을 줄여도 첫/마지막 등장은 전체 기록 기준이고, 기간의 첫 기록은 변화량이 없음
+	history, _, err = db.GetLeaderboardHistory(1, "allo1a", base.Add(90*time.Minute), base.Add(150*time.Minute), PageQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := leaderboardDeltas(history.History); !reflect.DeepEqual(got, []string{"120"}) {
+		t.Errorf("기간을 줄인 변화량 = %v, want [120]", got)
+	}
+	if history.FirstSeen == nil || history.FirstSeen.InferenceBlockHeight != "100" || history.LastSeen == nil || history.LastSeen.InferenceBlockHeight != "130" {
+		t.Errorf("첫/마지막 등장 = %+v / %+v, want 100 / 130", history.FirstSeen, history.LastSeen)
+	}
+
+	// 페이지를 나눠도 각 페이지의 첫 기록은 이전 페이지의 마지막 기록과 비교
+	for _, limit := range []int{1, 2, 3} {
+		var got, cursors []string
+		page := PageQuery{Limit: limit}
+		for {
+			history, next, err := db.GetLeaderboardHistory(1, "allo1a", start, end, page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history.History) > limit {
+				t.Fatalf("페이지 크기 %d의 기록 %d개", limit, len(history.History))
+			}
+			got = append(got, leaderboardDeltas(history.History)...)
+			if next == "" {
+				break
+			}
+			cursors = append(cursors, next)
+			page.After = next
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("페이지 크기 %d의 변화량 = %v, want %v", limit, got, want)
+		}
+		if wantPages := (len(want) - 1) / limit; len(cursors) != wantPages {
+			t.Errorf("페이지 크기 %d의 다음 페이지 기준 = %v, want %d개", limit, cursors, wantPages)
+		}
+	}
+
+	// 경쟁이 없으면 nil
+	if history, _, err := db.GetLeaderboardHistory(99, "allo1a", start, end, PageQuery{}); err != nil || history != nil {
+		t.Errorf("없는 경쟁의 기록 = %+v (%v), want nil", history, err)
+	}
+}
+
+func TestLeaderboardMoversOrdering(t *testing.T) {
+	db := newTestDatabase(t)
+	base := seedLeaderboardHistory(t, db)
+	end := base.Add(3*time.Hour + time.Minute)
+
+	// 높이 100 → 130: a 1→3, b 2→1, c 3→4, d 4→2
+	// 변동 폭이 큰 순서, 같으면 현재 순위 순
+	movers, err := db.GetLeaderboardMovers(1, 3*time.Hour+time.Minute, end, 0)
+	if err != nil || movers == nil {
+		t.Fatalf("순위 변동 = %+v (%v)", movers, err)
+	}
+	if movers.From == nil || movers.From.InferenceBlockHeight != "100" || movers.To == nil || movers.To.InferenceBlockHeight != "130" {
+		t.Fatalf("비교 스냅샷 = %+v -> %+v, want 100 -> 130", movers.From, movers.To)
+	}
+	var got []string
+	for _, mover := range movers.Movers {
+		got = append(got, mover.CosmosAddress+" "+strconv.Itoa(mover.RankChange))
+	}
+	want := []string{"allo1d 2", "allo1a -2", "allo1b 1", "allo1c -1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("순위 변동 순서 = %v, want %v", got, want)
+	}
+	if a := movers.Movers[1]; a.FromRank != 1 || a.ToRank != 3 || a.ScoreChange != 1.5 || a.PointsChange != 5 {
+		t.Errorf("allo1a 변동 = %+v, want 1 -> 3, 점수 +1.5, 포인트 +5", a)
+	}
+
+	// limit만큼만 반환
+	movers, err = db.GetLeaderboardMovers(1, 3*time.Hour+time.Minute, end, 2)
+	if err != nil || len(movers.Movers) != 2 || movers.Movers[0].CosmosAddress != "allo1d" || movers.Movers[1].CosmosAddress != "allo1a" {
+		t.Errorf("limit 2 순위 변동 = %+v (%v), want allo1d, allo1a", movers, err)
+	}
+
+	// 기간 안에 스냅샷이 하나뿐이면 비교하지 않음
+	movers, err = db.GetLeaderboardMovers(1, 30*time.Minute, end, 0)
+	if err != nil || movers.From == nil || movers.From.InferenceBlockHeight != "130" || len(movers.Movers) != 0 {
+		t.Errorf("스냅샷 하나의 순위 변동 = %+v (%v), want 빈 목록", movers, err)
+	}
+}
+
+func TestLeaderboardHandlersHideDatabaseErrors(t *testing.T) {
+	db := newTestDatabase(t)
+	seedLeaderboardHistory(t, db)
+	_, mux := newTestService(t, db)
+	if _, err := db.db.Exec("DROP TABLE leaderboard_entries"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{
+		"/api/v1/competitions/1/leaderboard/allo1a",
+		"/api/v1/competitions/1/leaderboard/movers",
+	} {
+		recorder := serveTest(mux, http.MethodGet, target, "")
+		if recorder.Code != http.StatusInternalServerError {
+			t.Errorf("%s 상태 코드 = %d, want 500", target, recorder.Code)
+			continue
+		}
+		response := decodeErrorResponse(t, recorder)
+		if response.Error.Code != ErrCodeInternal || strings.Contains(response.Error.Message, "leaderboard_entries") || strings.Contains(response.Error.Message, "실패") {
+			t.Errorf("%s 오류 = %+v, want 내부 오류 내용이 없는 %s", target, response.Error, ErrCodeInternal)
+		}
+	}
+}
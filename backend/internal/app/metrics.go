@@ -0,0 +1,551 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prometheus 지표
+// 외부 라이브러리나 서비스 없이 Prometheus 텍스트 형식(0.0.4)으로 /metrics를 제공합니다
+// 수집 결과, 상위 API 요청, HTTP 요청은 발생할 때 누적하고, 캐시 통계는 스크레이프할 때 조회합니다
+// 저장소 크기/행 수는 테이블마다 COUNT(*)를 실행하므로 storageMetricsTTL 동안 마지막 조회 결과를 재사용합니다
+// nil *Metrics의 메서드는 아무것도 하지 않으므로 지표를 설정하지 않은 구성(CLI 명령, 검사)에서도 그대로 호출할 수 있습니다
+
+const metricsNamespace = "allora_monitor"
+
+// storageMetricsTTL은 저장소 지표 조회 결과를 재사용하는 기간입니다 (스크레이프 간격보다 길면 여러 스크레이프가 같은 값을 받음)
+const storageMetricsTTL = time.Minute
+
+// 토픽 수집 결과
+const (
+	CollectionOutcomeSuccess    = "success"     // 새 스냅샷 저장
+	CollectionOutcomeUnchanged  = "unchanged"   // 조회에 성공했지만 저장할 변경이 없음
+	CollectionOutcomeFetchError = "fetch_error" // 상위 API 조회 실패
+	CollectionOutcomeSaveError  = "save_error"  // 데이터베이스 저장 실패
+)
+
+// 히스토그램 버킷 (초)
+var (
+	collectionDurationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+	upstreamDurationBuckets   = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	httpDurationBuckets       = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+)
+
+// metricTables는 행 수를 노출하는 메인 데이터베이스 테이블입니다 (topic_inferences는 토픽별로 따로 노출)
+var metricTables = []string{
+	"competitions", "competitions_v2", "competition_changes", "competition_lifecycle", "competition_lifecycle_transitions",
+	"leaderboard_entries", "snapshot_blobs", "topic_rollups", "worker_rollups", "quarantined_rows",
+}
+
+// Metrics는 수집기와 API의 Prometheus 지표입니다
+type Metrics struct {
+	db        *Database
+	startedAt time.Time
+
+	mu                sync.Mutex
+	collections       *histogramVec        // 토픽 수집 소요 시간 (topic, outcome)
+	lastSuccess       map[string]time.Time // 토픽별 마지막 수집 성공 시각
+	upstreamDuration  *histogramVec        // 상위 API 응답 시간 (host)
+	upstreamResponses *counterVec          // 상위 API 응답 수 (host, code)
+	httpDuration      *histogramVec        // HTTP 처리 시간 (route, method)
+	httpRequests      *counterVec          // HTTP 요청 수 (route, method, code)
+
+	storageMu  sync.Mutex      // 동시 스크레이프가 저장소 지표를 한 번만 조회하도록 잠금
+	storageTTL time.Duration   // 저장소 지표 재사용 기간
+	storage    *StorageMetrics // 마지막으로 조회한 저장소 지표
+	storageAt  time.Time       // storage 조회 시각
+}
+
+// NewMetrics는 지표 저장소를 생성합니다 (db가 nil이면 저장소 지표를 노출하지 않음)
+func NewMetrics(db *Database) *Metrics {
+	return &Metrics{
+		db:        db,
+		startedAt: time.Now(),
+		collections: newHistogramVec(metricsNamespace+"_collection_duration_seconds",
+			"토픽 추론 수집 소요 시간 (결과별)", collectionDurationBuckets, "topic", "outcome"),
+		lastSuccess: make(map[string]time.Time),
+		upstreamDuration: newHistogramVec(metricsNamespace+"_upstream_request_duration_seconds",
+			"상위 API 요청 응답 시간", upstreamDurationBuckets, "host"),
+		upstreamResponses: newCounterVec(metricsNamespace+"_upstream_requests_total",
+			"상위 API 요청 수 (code는 HTTP 상태 코드, 연결 실패는 error)", "host", "code"),
+		httpDuration: newHistogramVec(metricsNamespace+"_http_request_duration_seconds",
+			"API 요청 처리 시간 (스트림과 WebSocket은 연결 유지 시간)", httpDurationBuckets, "route", "method"),
+		httpRequests: newCounterVec(metricsNamespace+"_http_requests_total",
+			"API 요청 수", "route", "method", "code"),
+		storageTTL: storageMetricsTTL,
+	}
+}
+
+// ObserveCollection은 토픽 하나의 수집 결과와 소요 시간을 기록합니다
+// 성공과 변경 없음은 마지막 수집 성공 시각을 갱신합니다
+func (m *Metrics) ObserveCollection(topicID, outcome string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.collections.observe(duration.Seconds(), topicID, outcome)
+	if outcome == CollectionOutcomeSuccess || outcome == CollectionOutcomeUnchanged {
+		m.lastSuccess[topicID] = time.Now()
+	}
+}
+
+// observeUpstream은 상위 API 요청 하나를 기록합니다 (code가 0이면 연결 실패)
+func (m *Metrics) observeUpstream(host string, code int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := "error"
+	if code != 0 {
+		status = strconv.Itoa(code)
+	}
+	m.upstreamDuration.observe(duration.Seconds(), host)
+	m.upstreamResponses.add(1, host, status)
+}
+
+// observeHTTP는 API 요청 하나를 기록합니다
+func (m *Metrics) observeHTTP(route, method string, code int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.httpDuration.observe(duration.Seconds(), route, method)
+	m.httpRequests.add(1, route, method, strconv.Itoa(code))
+}
+
+// Transport는 상위 API 요청의 응답 시간과 상태 코드를 호스트별로 기록하는 RoundTripper를 반환합니다
+func (m *Metrics) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if m == nil {
+		return next
+	}
+	return &metricsTransport{metrics: m, next: next}
+}
+
+// metricsTransport는 상위 API 요청을 기록하는 RoundTripper입니다
+type metricsTransport struct {
+	metrics *Metrics
+	next    http.RoundTripper
+}
+
+// RoundTrip은 요청을 보내고 응답 헤더를 받을 때까지의 시간을 기록합니다
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	code := 0
+	if err == nil {
+		code = resp.StatusCode
+	}
+	t.metrics.observeUpstream(req.URL.Host, code, time.Since(start))
+	return resp, err
+}
+
+// instrumentHandler는 라우트 패턴(route)별로 요청 수, 상태 코드, 처리 시간을 기록하는 미들웨어입니다
+func (m *Metrics) instrumentHandler(route string, next http.Handler) http.Handler {
+	if m == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		code := recorder.status
+		if code == 0 {
+			code = http.StatusOK
+		}
+		m.observeHTTP(route, r.Method, code, time.Since(start))
+	})
+}
+
+// statusRecorder는 응답 상태 코드를 기록하는 ResponseWriter입니다
+// 이벤트 스트림의 Flush와 WebSocket 업그레이드의 Hijack은 원래 ResponseWriter로 전달합니다
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+// Unwrap은 http.ResponseController가 원래 ResponseWriter를 찾도록 합니다
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Flush는 버퍼된 응답을 보냅니다
+func (r *statusRecorder) Flush() {
+	http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Hijack은 연결을 가로챕니다 (WebSocket 업그레이드는 101로 기록)
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// StorageMetrics는 저장소 파일 크기와 테이블 행 수입니다
+type StorageMetrics struct {
+	FileSizes      map[string]int64 // 데이터베이스 파일별 크기 ("main" 또는 "shard:<토픽 ID>", 페이지 수 × 페이지 크기)
+	TableRows      map[string]int64 // 메인 데이터베이스 테이블별 행 수 (snapshot_blobs는 샤드 포함)
+	TopicSnapshots map[string]int64 // 토픽별 스냅샷 행 수
+}
+
+// GetStorageMetrics는 /metrics에 노출할 저장소 크기와 행 수를 조회합니다
+// 행 본문을 읽지 않는 COUNT와 PRAGMA만 사용하므로 /api/stats보다 가볍습니다
+func (d *Database) GetStorageMetrics() (*StorageMetrics, error) {
+	metrics := &StorageMetrics{
+		FileSizes:      make(map[string]int64),
+		TableRows:      make(map[string]int64),
+		TopicSnapshots: make(map[string]int64),
+	}
+
+	for _, table := range metricTables {
+		var count int64
+		if err := d.reader.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+			return nil, fmt.Errorf("%s 행 수 조회 실패: %w", table, err)
+		}
+		metrics.TableRows[table] = count
+	}
+
+	stores, err := d.topicStores()
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTopicStores(stores)
+	for _, store := range stores {
+		name := "main"
+		if store.isShard() {
+			name = "shard:" + store.topicID
+		}
+		var pageCount, pageSize int64
+		if err := store.reader.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+			return nil, fmt.Errorf("%s 페이지 수 조회 실패: %w", name, err)
+		}
+		if err := store.reader.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+			return nil, fmt.Errorf("%s 페이지 크기 조회 실패: %w", name, err)
+		}
+		metrics.FileSizes[name] = pageCount * pageSize
+
+		rows, err := store.reader.Query("SELECT topic_id, COUNT(*) FROM topic_inferences GROUP BY topic_id")
+		if err != nil {
+			return nil, fmt.Errorf("%s 토픽별 행 수 조회 실패: %w", name, err)
+		}
+		for rows.Next() {
+			var topicID string
+			var count int64
+			if err := rows.Scan(&topicID, &count); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("%s 토픽별 행 수 스캔 실패: %w", name, err)
+			}
+			metrics.TopicSnapshots[topicID] += count
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("%s 토픽별 행 수 조회 중 오류: %w", name, err)
+		}
+
+		if store.isShard() {
+			var blobs int64
+			if err := store.reader.QueryRow("SELECT COUNT(*) FROM snapshot_blobs").Scan(&blobs); err != nil {
+				return nil, fmt.Errorf("%s 본문 수 조회 실패: %w", name, err)
+			}
+			metrics.TableRows["snapshot_blobs"] += blobs
+		}
+	}
+	return metrics, nil
+}
+
+// WriteTo는 모든 지표를 Prometheus 텍스트 형식으로 씁니다
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	out := &metricsWriter{w: bufio.NewWriter(w)}
+	now := time.Now()
+
+	out.family(metricsNamespace+"_uptime_seconds", "프로세스 시작 후 경과 시간", "gauge")
+	out.sample(metricsNamespace+"_uptime_seconds", nil, nil, now.Sub(m.startedAt).Seconds())
+	out.family("go_goroutines", "실행 중인 고루틴 수", "gauge")
+	out.sample("go_goroutines", nil, nil, float64(runtime.NumGoroutine()))
+
+	m.mu.Lock()
+	m.collections.write(out)
+	out.family(metricsNamespace+"_collection_last_success_age_seconds", "토픽별 마지막 수집 성공(변경 없음 포함) 후 경과 시간", "gauge")
+	for _, topicID := range sortedMetricKeys(m.lastSuccess) {
+		out.sample(metricsNamespace+"_collection_last_success_age_seconds", []string{"topic"}, []string{topicID}, now.Sub(m.lastSuccess[topicID]).Seconds())
+	}
+	m.upstreamDuration.write(out)
+	m.upstreamResponses.write(out)
+	m.httpDuration.write(out)
+	m.httpRequests.write(out)
+	m.mu.Unlock()
+
+	if m.db != nil {
+		m.writeStorage(out, now)
+		m.writeSnapshotCache(out)
+	}
+
+	if out.err == nil {
+		out.err = out.w.Flush()
+	}
+	return out.n, out.err
+}
+
+// storageMetrics는 저장소 지표를 반환합니다
+// 마지막 조회 후 storageTTL이 지나지 않았으면 조회하지 않고 이전 결과와 조회 시각을 반환합니다
+func (m *Metrics) storageMetrics(now time.Time) (*StorageMetrics, time.Time, error) {
+	m.storageMu.Lock()
+	defer m.storageMu.Unlock()
+
+	if m.storage != nil && now.Sub(m.storageAt) < m.storageTTL {
+		return m.storage, m.storageAt, nil
+	}
+	storage, err := m.db.GetStorageMetrics()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	m.storage = storage
+	m.storageAt = now
+	return storage, now, nil
+}
+
+// writeStorage는 저장소 크기와 행 수를 씁니다 (조회에 실패하면 기록하고 생략)
+func (m *Metrics) writeStorage(out *metricsWriter, now time.Time) {
+	storage, collectedAt, err := m.storageMetrics(now)
+	if err != nil {
+		log.Printf("저장소 지표 조회 실패: %v", err)
+		return
+	}
+
+	out.family(metricsNamespace+"_db_stats_age_seconds", "저장소 크기/행 수 지표를 조회한 뒤 경과 시간", "gauge")
+	out.sample(metricsNamespace+"_db_stats_age_seconds", nil, nil, now.Sub(collectedAt).Seconds())
+
+	out.family(metricsNamespace+"_db_size_bytes", "데이터베이스 파일 크기", "gauge")
+	for _, name := range sortedMetricKeys(storage.FileSizes) {
+		out.sample(metricsNamespace+"_db_size_bytes", []string{"database"}, []string{name}, float64(storage.FileSizes[name]))
+	}
+	out.family(metricsNamespace+"_db_rows", "테이블 행 수", "gauge")
+	for _, table := range sortedMetricKeys(storage.TableRows) {
+		out.sample(metricsNamespace+"_db_rows", []string{"table"}, []string{table}, float64(storage.TableRows[table]))
+	}
+	out.family(metricsNamespace+"_topic_snapshots", "토픽별 저장된 스냅샷 수", "gauge")
+	for _, topicID := range sortedMetricKeys(storage.TopicSnapshots) {
+		out.sample(metricsNamespace+"_topic_snapshots", []string{"topic"}, []string{topicID}, float64(storage.TopicSnapshots[topicID]))
+	}
+}
+
+// writeSnapshotCache는 스냅샷 캐시 통계를 씁니다
+func (m *Metrics) writeSnapshotCache(out *metricsWriter) {
+	stats := m.db.SnapshotCacheStats()
+	counters := []struct {
+		name, help string
+		value      uint64
+	}{
+		{"hits_total", "스냅샷 캐시 적중 수", stats.Hits},
+		{"misses_total", "스냅샷 캐시 실패 수", stats.Misses},
+		{"evictions_total", "용량 초과로 제거된 캐시 항목 수", stats.Evictions},
+		{"invalidations_total", "저장/정리로 무효화된 캐시 항목 수", stats.Invalidations},
+	}
+	for _, counter := range counters {
+		name := metricsNamespace + "_snapshot_cache_" + counter.name
+		out.family(name, counter.help, "counter")
+		out.sample(name, nil, nil, float64(counter.value))
+	}
+
+	gauges := []struct {
+		name, help string
+		value      float64
+	}{
+		{"hit_ratio", "스냅샷 캐시 적중률 (시작 후 누적)", stats.HitRatio},
+		{"entries", "스냅샷 캐시 항목 수", float64(stats.Entries)},
+		{"bytes", "스냅샷 캐시 사용 크기", float64(stats.Bytes)},
+		{"max_bytes", "스냅샷 캐시 최대 크기", float64(stats.MaxBytes)},
+	}
+	for _, gauge := range gauges {
+		name := metricsNamespace + "_snapshot_cache_" + gauge.name
+		out.family(name, gauge.help, "gauge")
+		out.sample(name, nil, nil, gauge.value)
+	}
+}
+
+// HandleMetrics는 Prometheus 지표를 반환하는 핸들러입니다
+func (s *Service) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.metrics == nil {
+		writeError(w, http.StatusServiceUnavailable, ErrCodeNotConfigured, "Metrics are not configured")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := s.metrics.WriteTo(w); err != nil {
+		log.Printf("지표 응답 쓰기 실패: %v", err)
+	}
+}
+
+// metricsWriter는 Prometheus 텍스트 형식 작성기입니다 (첫 쓰기 오류 이후는 무시)
+type metricsWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (out *metricsWriter) printf(format string, args ...interface{}) {
+	if out.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(out.w, format, args...)
+	out.n += int64(n)
+	out.err = err
+}
+
+// family는 지표의 HELP와 TYPE 줄을 씁니다
+func (out *metricsWriter) family(name, help, kind string) {
+	out.printf("# HELP %s %s\n# TYPE %s %s\n", name, strings.ReplaceAll(help, "\n", " "), name, kind)
+}
+
+// sample은 값 하나를 씁니다
+func (out *metricsWriter) sample(name string, labels, values []string, value float64) {
+	out.printf("%s%s %s\n", name, formatLabels(labels, values), formatMetricValue(value))
+}
+
+// formatLabels는 {name="value",...} 레이블 목록을 만듭니다
+func formatLabels(labels, values []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(label)
+		b.WriteString(`="`)
+		b.WriteString(metricLabelEscaper.Replace(values[i]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// metricLabelEscaper는 레이블 값의 역슬래시, 따옴표, 줄바꿈을 이스케이프합니다
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatMetricValue는 지표 값을 씁니다
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// sortedMetricKeys는 맵의 키를 정렬하여 반환합니다
+func sortedMetricKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// metricKey는 레이블 값 목록으로 시계열 키를 만듭니다
+func metricKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// counterVec은 레이블별 누적 카운터입니다 (호출자가 잠금)
+type counterVec struct {
+	name, help string
+	labels     []string
+	values     map[string]float64
+	series     map[string][]string // 키 → 레이블 값
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	return &counterVec{name: name, help: help, labels: labels, values: make(map[string]float64), series: make(map[string][]string)}
+}
+
+func (c *counterVec) add(delta float64, values ...string) {
+	key := metricKey(values)
+	if _, ok := c.series[key]; !ok {
+		c.series[key] = values
+	}
+	c.values[key] += delta
+}
+
+func (c *counterVec) write(out *metricsWriter) {
+	out.family(c.name, c.help, "counter")
+	for _, key := range sortedMetricKeys(c.values) {
+		out.sample(c.name, c.labels, c.series[key], c.values[key])
+	}
+}
+
+// histogramVec은 레이블별 히스토그램입니다 (호출자가 잠금)
+type histogramVec struct {
+	name, help string
+	labels     []string
+	buckets    []float64
+	series     map[string]*histogramSeries
+}
+
+// histogramSeries는 레이블 조합 하나의 버킷별 관측 수(누적 아님)와 합계입니다
+type histogramSeries struct {
+	values []string
+	counts []uint64 // buckets와 같은 순서, 마지막 항목은 +Inf
+	sum    float64
+	count  uint64
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *histogramVec {
+	return &histogramVec{name: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*histogramSeries)}
+}
+
+func (h *histogramVec) observe(value float64, values ...string) {
+	key := metricKey(values)
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{values: values, counts: make([]uint64, len(h.buckets)+1)}
+		h.series[key] = series
+	}
+	index := sort.SearchFloat64s(h.buckets, value) // value 이상인 첫 버킷 (le는 경계 포함)
+	series.counts[index]++
+	series.sum += value
+	series.count++
+}
+
+func (h *histogramVec) write(out *metricsWriter) {
+	out.family(h.name, h.help, "histogram")
+	labels := append(append([]string{}, h.labels...), "le")
+	for _, key := range sortedMetricKeys(h.series) {
+		series := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += series.counts[i]
+			out.sample(h.name+"_bucket", labels, append(append([]string{}, series.values...), formatMetricValue(bound)), float64(cumulative))
+		}
+		out.sample(h.name+"_bucket", labels, append(append([]string{}, series.values...), "+Inf"), float64(series.count))
+		out.sample(h.name+"_sum", h.labels, series.values, series.sum)
+		out.sample(h.name+"_count", h.labels, series.values, float64(series.count))
+	}
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrapeMetrics는 /metrics 응답을 "이름{레이블}" → 값 맵과 샘플 줄 순서로 반환합니다
+func scrapeMetrics(t *testing.T, mux http.Handler) (map[string]float64, []string) {
+	t.Helper()
+	recorder := serveTest(mux, http.MethodGet, "/metrics", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("/metrics 상태 코드 = %d, want 200", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("/metrics 콘텐츠 타입 = %q, want Prometheus 텍스트 형식", contentType)
+	}
+
+	samples := make(map[string]float64)
+	var order []string
+	for _, line := range strings.Split(strings.TrimSpace(recorder.Body.String()), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndex(line, " ")
+		if i < 0 {
+			t.Fatalf("샘플 줄 형식 오류: %q", line)
+		}
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			t.Fatalf("샘플 값 파싱 실패: %q", line)
+		}
+		samples[line[:i]] = value
+		order = append(order, line[:i])
+	}
+	return samples, order
+}
+
+// newMetricsTestService는 지표를 연결한 서비스의 mux를 만듭니다 (지표는 라우트 등록 전에 설정해야 함)
+func newMetricsTestService(t *testing.T, db *Database) (*Metrics, *http.ServeMux) {
+	t.Helper()
+	monitor := NewMonitor(nil, db, &Config{})
+	monitor.debug = false
+	monitor.GetTopicInferenceStore().SetDebug(false)
+	service := NewService(monitor, db)
+	metrics := NewMetrics(db)
+	service.SetMetrics(metrics)
+	mux := http.NewServeMux()
+	service.RegisterRoutes(mux)
+	return metrics, mux
+}
+
+func TestMetricsHistogramExposition(t *testing.T) {
+	metrics, mux := newMetricsTestService(t, newTestDatabase(t))
+
+	// 경계값(0.1)은 해당 버킷에 포함, 마지막 경계를 넘는 값은 +Inf에만 포함
+	for _, duration := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 100 * time.Second} {
+		metrics.ObserveCollection("1", CollectionOutcomeSuccess, duration)
+	}
+	metrics.ObserveCollection("a\"b\\c\nd", CollectionOutcomeFetchError, time.Second)
+	serveTest(mux, http.MethodGet, "/api/v1/topics/abc/stats", "")
+
+	samples, order := scrapeMetrics(t, mux)
+
+	name := metricsNamespace + "_collection_duration_seconds"
+	series := `topic="1",outcome="success"`
+	want := map[string]float64{"0.1": 1, "0.25": 1, "0.5": 2, "30": 2, "60": 2, "+Inf": 3}
+	for le, count := range want {
+		key := name + "_bucket{" + series + `,le="` + le + `"}`
+		if got, ok := samples[key]; !ok || got != count {
+			t.Errorf("%s = %v (있음 %v), want %v", key, got, ok, count)
+		}
+	}
+	if got := samples[name+"_count{"+series+"}"]; got != 3 {
+		t.Errorf("_count = %v, want 3", got)
+	}
+	if got := samples[name+"_sum{"+series+"}"]; got < 100.39 || got > 100.41 {
+		t.Errorf("_sum = %v, want 100.4", got)
+	}
+
+	// 레이블 값의 따옴표, 역슬래시, 줄바꿈은 이스케이프
+	escaped := name + `_count{topic="a\"b\\c\nd",outcome="fetch_error"}`
+	if samples[escaped] != 1 {
+		t.Errorf("이스케이프된 레이블 시계열 %s가 없습니다", escaped)
+	}
+
+	// 모든 히스토그램 시계열의 버킷은 누적 값이고 +Inf 버킷은 _count와 같음
+	previous := make(map[string]float64)
+	histograms := 0
+	for _, key := range order {
+		metric, labels, ok := strings.Cut(key, "_bucket{")
+		if !ok {
+			continue
+		}
+		i := strings.LastIndex(labels, `le="`)
+		if i < 0 {
+			t.Fatalf("le 레이블이 없는 버킷: %s", key)
+		}
+		base := strings.TrimSuffix(labels[:i], ",")
+		seriesKey := metric + "{" + base + "}"
+		if samples[key] < previous[seriesKey] {
+			t.Errorf("%s = %v, 이전 버킷 %v보다 작음", key, samples[key], previous[seriesKey])
+		}
+		previous[seriesKey] = samples[key]
+		if strings.HasSuffix(labels, `le="+Inf"}`) {
+			histograms++
+			if count := samples[metric+"_count{"+base+"}"]; count != samples[key] {
+				t.Errorf("%s = %v, want _count %v", key, samples[key], count)
+			}
+		}
+	}
+	if histograms < 3 {
+		t.Errorf("+Inf 버킷이 있는 히스토그램 시계열 %d개, want 수집 2개와 HTTP 요청 이상", histograms)
+	}
+	if samples[metricsNamespace+`_http_requests_total{route="/api/v1/topics/{id}/stats",method="GET",code="400"}`] != 1 {
+		t.Error("라우트 패턴별 HTTP 요청 수가 없습니다")
+	}
+}
+
+func TestMetricsStorageIsCached(t *testing.T) {
+	db := newTestDatabase(t)
+	metrics, mux := newMetricsTestService(t, db)
+	base := time.Now().Add(-time.Hour)
+	if err := db.SaveTopicInference(testSnapshot("1", 100, base, testWorkers(3), 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots := metricsNamespace + `_topic_snapshots{topic="1"}`
+	age := metricsNamespace + "_db_stats_age_seconds"
+	samples, _ := scrapeMetrics(t, mux)
+	if samples[snapshots] != 1 {
+		t.Fatalf("%s = %v, want 1", snapshots, samples[snapshots])
+	}
+
+	// 재사용 기간 안에는 다시 조회하지 않음
+	if err := db.SaveTopicInference(testSnapshot("1", 110, base.Add(time.Minute), testWorkers(3), 1)); err != nil {
+		t.Fatal(err)
+	}
+	samples, _ = scrapeMetrics(t, mux)
+	if samples[snapshots] != 1 {
+		t.Errorf("재사용 기간 안의 %s = %v, want 이전 값 1", snapshots, samples[snapshots])
+	}
+
+	// 재사용 기간이 지나면 다시 조회
+	metrics.storageMu.Lock()
+	metrics.storageAt = metrics.storageAt.Add(-storageMetricsTTL)
+	metrics.storageMu.Unlock()
+	samples, _ = scrapeMetrics(t, mux)
+	if samples[snapshots] != 2 {
+		t.Errorf("재사용 기간 후 %s = %v, want 2", snapshots, samples[snapshots])
+	}
+	if samples[age] >= 1 {
+		t.Errorf("다시 조회한 직후 %s = %v, want 1초 미만", age, samples[age])
+	}
+}
@@ -16,11 +16,13 @@ type Monitor struct {
 	config              *Config
 	ticker              *time.Ticker
 	stopChan            chan struct{}
+	doneChan            chan struct{} // 수집 루프가 끝나면 닫힘
 	isRunning           bool
 	runningMutex        sync.Mutex
 	directURL           string               // 직접 사용할 URL (디버깅용)
 	debug               bool                 // 디버깅 모드 활성화 여부
 	topicInferenceStore *TopicInferenceStore // 토픽 추론 데이터 저장소
+	events              *EventBroker         // 수집 실패를 발행할 이벤트 브로커 (nil이면 발행하지 않음)
 }
 
 // NewMonitor는 새로운 모니터링 서비스를 생성합니다
@@ -29,7 +31,6 @@ func NewMonitor(apiClient *AlloraAPIClient, db *Database, config *Config) *Monit
 		apiClient: apiClient,
 		db:        db,
 		config:    config,
-		stopChan:  make(chan struct{}),
 		debug:     true, // 디버깅 모드 활성화
 	}
 
@@ -47,6 +48,20 @@ func (m *Monitor) SetDebug(debug bool) {
 	m.topicInferenceStore.SetDebug(debug)
 }
 
+// SetEventBroker는 수집 결과와 실패를 발행할 이벤트 브로커를 설정합니다
+// 데이터베이스(저장된 변경)와 토픽 추론 데이터 저장소(토픽 수집 실패)에도 같은 브로커를 설정합니다
+func (m *Monitor) SetEventBroker(events *EventBroker) {
+	m.events = events
+	m.db.SetEventBroker(events)
+	m.topicInferenceStore.SetEventBroker(events)
+}
+
+// SetMetrics는 수집 결과와 상위 API 요청을 기록할 지표를 설정합니다
+func (m *Monitor) SetMetrics(metrics *Metrics) {
+	m.apiClient.SetMetrics(metrics)
+	m.topicInferenceStore.SetMetrics(metrics)
+}
+
 // SetDirectURL은 직접 사용할 URL을 설정합니다 (디버깅용)
 func (m *Monitor) SetDirectURL(url string) {
 	m.directURL = url
@@ -67,6 +82,9 @@ func (m *Monitor) Start() error {
 	// 모니터링 간격 설정
 	interval := time.Duration(m.config.MonitoringIntervalMinutes) * time.Minute
 	m.ticker = time.NewTicker(interval)
+	// 리더 선출로 중지 후 다시 시작할 수 있도록 시작할 때마다 새 채널 사용
+	m.stopChan = make(chan struct{})
+	m.doneChan = make(chan struct{})
 	m.isRunning = true
 
 	if m.debug {
@@ -78,33 +96,42 @@ func (m *Monitor) Start() error {
 		log.Printf("토픽 추론 데이터 저장소 시작 실패: %v", err)
 	}
 
+	// 라이프사이클 재평가 간격 (시작/종료 시각에 맞춰 수집 대상을 바꾸기 위함)
+	lifecycleTicker := time.NewTicker(lifecycleEvaluationInterval)
+
 	// 즉시 첫 번째 데이터 수집 실행
-	go func() {
+	go func(ticker *time.Ticker, stopChan, doneChan chan struct{}) {
+		defer close(doneChan)
 		m.collectData()
 
 		// 이후 정기적으로 데이터 수집
 		for {
 			select {
-			case <-m.ticker.C:
+			case <-ticker.C:
 				if m.debug {
 					log.Printf("정기 데이터 수집 시작 (간격: %v)", interval)
 				}
 				m.collectData()
-			case <-m.stopChan:
-				m.ticker.Stop()
+			case <-lifecycleTicker.C:
+				m.evaluateLifecycles(nil)
+			case <-stopChan:
+				ticker.Stop()
+				lifecycleTicker.Stop()
 				if m.debug {
 					log.Println("모니터링 루프 종료")
 				}
 				return
 			}
 		}
-	}()
+	}(m.ticker, m.stopChan, m.doneChan)
 
 	log.Printf("모니터링 서비스가 시작되었습니다. 간격: %v", interval)
 	return nil
 }
 
 // Stop은 모니터링 서비스를 중지합니다
+// 진행 중인 수집이 끝날 때까지 기다리므로, 반환된 뒤에는 이 인스턴스가 더 이상 데이터를 저장하지 않습니다
+// (리더 자격을 잃었을 때 임대를 반납하기 전에 호출)
 func (m *Monitor) Stop() error {
 	m.runningMutex.Lock()
 	defer m.runningMutex.Unlock()
@@ -123,6 +150,7 @@ func (m *Monitor) Stop() error {
 	}
 
 	close(m.stopChan)
+	<-m.doneChan
 	m.isRunning = false
 	log.Println("모니터링 서비스가 중지되었습니다")
 	return nil
@@ -163,6 +191,7 @@ func (m *Monitor) collectData() {
 
 	if err != nil {
 		log.Printf("데이터 가져오기 실패: %v", err)
+		m.events.PublishCollectionError(CollectionStageCompetitions, "", err)
 		return
 	}
 
@@ -176,6 +205,7 @@ func (m *Monitor) collectData() {
 	// 데이터베이스에 저장
 	if err := m.db.SaveCompetitions(resp); err != nil {
 		log.Printf("데이터 저장 실패: %v", err)
+		m.events.PublishCollectionError(CollectionStageCompetitions, "", err)
 		return
 	}
 
@@ -183,12 +213,19 @@ func (m *Monitor) collectData() {
 		log.Println("데이터베이스 저장 완료")
 	}
 
-	// 활성 토픽 ID 목록 추출 및 설정
-	activeTopicIDs := m.extractActiveTopicIDs(resp)
-	m.topicInferenceStore.SetActiveTopics(activeTopicIDs)
+	// 경쟁/토픽/워커 검색 인덱스 갱신
+	if _, err := m.db.RebuildSearchIndex(); err != nil {
+		log.Printf("검색 인덱스 갱신 실패: %v", err)
+	}
 
-	if m.debug {
-		log.Printf("활성 토픽 ID 목록 설정: %v", activeTopicIDs)
+	// 체인 토픽 활성 여부를 반영하여 라이프사이클 갱신 및 수집 대상 토픽 설정
+	if !m.evaluateLifecycles(m.fetchTopicActivity(lifecycleTopicIDs(resp, time.Now()))) {
+		activeTopicIDs := m.extractActiveTopicIDs(resp)
+		m.topicInferenceStore.SetActiveTopics(activeTopicIDs)
+
+		if m.debug {
+			log.Printf("활성 토픽 ID 목록 설정 (라이프사이클 사용 불가): %v", activeTopicIDs)
+		}
 	}
 
 	// 오래된 데이터 정리 (설정된 보존 기간보다 오래된 데이터)
@@ -200,8 +237,8 @@ func (m *Monitor) collectData() {
 		log.Printf("%d개의 오래된 레코드가 정리되었습니다", rowsDeleted)
 	}
 
-	// 오래된 토픽 데이터 정리
-	topicRowsDeleted, err := m.db.PruneOldTopicData("", retentionPeriod)
+	// 토픽 데이터 롤업 및 계층별 보존 기간에 따른 정리
+	topicRowsDeleted, err := m.db.ApplyTopicRetention(time.Now())
 	if err != nil {
 		log.Printf("오래된 토픽 데이터 정리 실패: %v", err)
 	} else if topicRowsDeleted > 0 {
@@ -210,12 +247,9 @@ func (m *Monitor) collectData() {
 
 	// 데이터베이스 통계 로깅
 	if stats, err := m.db.GetDatabaseStats(); err == nil {
-		compStats := stats["competitions"].(map[string]interface{})
-		topicStats := stats["topic_inferences"].(map[string]interface{})
-
 		log.Printf("DB 통계: 경쟁 레코드=%d (%.2fMB), 토픽 레코드=%d (%.2fMB)",
-			compStats["record_count"], compStats["total_size_mb"],
-			topicStats["record_count"], topicStats["total_size_mb"])
+			stats.Competitions.RecordCount, stats.Competitions.TotalSizeMB,
+			stats.TopicInferences.RecordCount, stats.TopicInferences.TotalSizeMB)
 	}
 
 	elapsedTime := time.Since(startTime)
@@ -227,6 +261,70 @@ func (m *Monitor) collectData() {
 	log.Println(logMsg)
 }
 
+// fetchTopicActivity는 체인에서 각 토픽의 활성 여부를 조회합니다
+// 조회에 실패한 토픽은 결과에서 제외되어 이전에 저장된 값이 사용됩니다
+func (m *Monitor) fetchTopicActivity(topicIDs []int) map[int]bool {
+	activity := make(map[int]bool, len(topicIDs))
+	for _, topicID := range topicIDs {
+		isActive, err := m.apiClient.FetchTopicIsActive(topicID)
+		if err != nil {
+			log.Printf("토픽 %d 활성 여부 조회 실패: %v", topicID, err)
+			continue
+		}
+		activity[topicID] = isActive
+	}
+	return activity
+}
+
+// evaluateLifecycles는 경쟁 라이프사이클을 다시 계산하고 수집 대상 토픽을 갱신합니다
+// 라이프사이클 갱신에 실패하면 false를 반환합니다
+func (m *Monitor) evaluateLifecycles(topicActivity map[int]bool) bool {
+	transitions, err := m.db.UpdateCompetitionLifecycles(topicActivity, time.Now())
+	if err != nil {
+		log.Printf("경쟁 라이프사이클 갱신 실패: %v", err)
+		m.events.PublishCollectionError(CollectionStageLifecycle, "", err)
+		return false
+	}
+
+	for _, transition := range transitions {
+		fromState := "(없음)"
+		if transition.FromState != nil {
+			fromState = *transition.FromState
+		}
+		log.Printf("경쟁 라이프사이클 전이: ID=%d, %s -> %s", transition.CompetitionID, fromState, transition.ToState)
+	}
+
+	topicIDs, err := m.db.GetCollectingTopicIDs()
+	if err != nil {
+		log.Printf("수집 대상 토픽 조회 실패: %v", err)
+		return false
+	}
+
+	if !sameTopicIDs(m.topicInferenceStore.GetActiveTopics(), topicIDs) {
+		m.topicInferenceStore.SetActiveTopics(topicIDs)
+		log.Printf("수집 대상 토픽 변경: %v", topicIDs)
+	}
+
+	return true
+}
+
+// sameTopicIDs는 두 토픽 ID 목록이 순서와 관계없이 같은지 확인합니다
+func sameTopicIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+	for _, id := range b {
+		if !set[id] {
+			return false
+		}
+	}
+	return true
+}
+
 // extractActiveTopicIDs는 경쟁 데이터에서 활성 토픽 ID 목록을 추출합니다
 func (m *Monitor) extractActiveTopicIDs(resp *CompetitionsResponse) []string {
 	if resp == nil {
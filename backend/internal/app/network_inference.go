@@ -70,9 +70,13 @@ type TopicInferenceStore struct {
 	monitor        *Monitor // Monitor 인스턴스 추가
 	updateInterval time.Duration
 	stopChan       chan struct{}
+	doneChan       chan struct{} // 수집 루프가 끝나면 닫힘
 	isRunning      bool
 	runningMutex   sync.Mutex
 	debug          bool
+	events         *EventBroker // 토픽 수집 실패를 발행할 이벤트 브로커 (nil이면 발행하지 않음)
+	metrics        *Metrics     // 수집 결과와 상위 API 요청을 기록할 지표 (nil이면 기록하지 않음)
+	httpClient     *http.Client // 노드 API 요청에 사용할 클라이언트
 }
 
 // NewTopicInferenceStore는 새로운 토픽 추론 데이터 저장소를 생성합니다
@@ -84,8 +88,8 @@ func NewTopicInferenceStore(db *Database, monitor *Monitor, updateInterval time.
 		db:             db,
 		monitor:        monitor,
 		updateInterval: updateInterval,
-		stopChan:       make(chan struct{}),
 		debug:          true,
+		httpClient:     http.DefaultClient,
 	}
 }
 
@@ -94,6 +98,17 @@ func (s *TopicInferenceStore) SetDebug(debug bool) {
 	s.debug = debug
 }
 
+// SetEventBroker는 토픽 수집 실패를 발행할 이벤트 브로커를 설정합니다
+func (s *TopicInferenceStore) SetEventBroker(events *EventBroker) {
+	s.events = events
+}
+
+// SetMetrics는 수집 결과와 노드 API 요청을 기록할 지표를 설정합니다
+func (s *TopicInferenceStore) SetMetrics(metrics *Metrics) {
+	s.metrics = metrics
+	s.httpClient = &http.Client{Transport: metrics.Transport(nil)}
+}
+
 // SetActiveTopics는 활성 토픽 ID 목록을 설정합니다
 func (s *TopicInferenceStore) SetActiveTopics(topicIDs []string) {
 	s.mu.Lock()
@@ -162,6 +177,8 @@ func (s *TopicInferenceStore) Start() error {
 		return fmt.Errorf("토픽 추론 데이터 수집이 이미 실행 중입니다")
 	}
 
+	s.stopChan = make(chan struct{})
+	s.doneChan = make(chan struct{})
 	s.isRunning = true
 
 	if s.debug {
@@ -169,7 +186,8 @@ func (s *TopicInferenceStore) Start() error {
 	}
 
 	// 즉시 첫 번째 데이터 수집 실행
-	go func() {
+	go func(stopChan, doneChan chan struct{}) {
+		defer close(doneChan)
 		s.collectAllTopicData()
 
 		// 이후 정기적으로 데이터 수집
@@ -183,20 +201,20 @@ func (s *TopicInferenceStore) Start() error {
 					log.Printf("정기 토픽 추론 데이터 수집 시작 (간격: %v)", s.updateInterval)
 				}
 				s.collectAllTopicData()
-			case <-s.stopChan:
+			case <-stopChan:
 				if s.debug {
 					log.Println("토픽 추론 데이터 수집 루프 종료")
 				}
 				return
 			}
 		}
-	}()
+	}(s.stopChan, s.doneChan)
 
 	log.Printf("토픽 추론 데이터 수집이 시작되었습니다. 간격: %v", s.updateInterval)
 	return nil
 }
 
-// Stop은 토픽 추론 데이터 수집을 중지합니다
+// Stop은 토픽 추론 데이터 수집을 중지하고 진행 중인 수집이 끝날 때까지 기다립니다
 func (s *TopicInferenceStore) Stop() error {
 	s.runningMutex.Lock()
 	defer s.runningMutex.Unlock()
@@ -210,6 +228,7 @@ func (s *TopicInferenceStore) Stop() error {
 	}
 
 	close(s.stopChan)
+	<-s.doneChan
 	s.isRunning = false
 	log.Println("토픽 추론 데이터 수집이 중지되었습니다")
 	return nil
@@ -241,9 +260,44 @@ func (s *TopicInferenceStore) collectAllTopicData() {
 	}
 
 	// 각 토픽에 대해 데이터 수집
+	records := make([]map[string]interface{}, 0, len(activeTopics))
+	fetched := make([]string, 0, len(activeTopics))                     // records와 같은 순서의 토픽 ID
+	fetchDurations := make(map[string]time.Duration, len(activeTopics)) // 토픽별 조회 소요 시간
 	for _, topicID := range activeTopics {
-		if err := s.collectTopicData(topicID); err != nil {
+		fetchStart := time.Now()
+		record, err := s.fetchTopicData(topicID)
+		fetchDurations[topicID] = time.Since(fetchStart)
+		if err != nil {
 			log.Printf("토픽 %s 데이터 수집 실패: %v", topicID, err)
+			s.events.PublishCollectionError(CollectionStageTopicInference, topicID, err)
+			s.metrics.ObserveCollection(topicID, CollectionOutcomeFetchError, fetchDurations[topicID])
+			continue
+		}
+		if record != nil {
+			records = append(records, record)
+			fetched = append(fetched, topicID)
+		} else {
+			s.metrics.ObserveCollection(topicID, CollectionOutcomeUnchanged, fetchDurations[topicID])
+		}
+	}
+
+	// 수집 주기의 변경분을 하나의 트랜잭션으로 저장
+	if len(records) > 0 {
+		saveStart := time.Now()
+		saved, err := s.db.SaveTopicInferences(records)
+		saveDuration := time.Since(saveStart)
+		outcome := CollectionOutcomeSuccess
+		if err != nil {
+			log.Printf("토픽 데이터 일괄 저장 중 오류: %v", err)
+			s.events.PublishCollectionError(CollectionStageTopicSave, "", err)
+			outcome = CollectionOutcomeSaveError
+		}
+		// 일괄 저장 시간은 저장한 모든 토픽의 소요 시간에 포함
+		for _, topicID := range fetched {
+			s.metrics.ObserveCollection(topicID, outcome, fetchDurations[topicID]+saveDuration)
+		}
+		if s.debug {
+			log.Printf("토픽 데이터 일괄 저장: %d/%d개", saved, len(records))
 		}
 	}
 
@@ -472,7 +526,7 @@ func (s *TopicInferenceStore) getBlockTimestamp(blockHeight string) (string, err
 		log.Printf("Block API 요청 URL: %s", url)
 	}
 
-	resp, err := http.Get(url)
+	resp, err := s.httpClient.Get(url)
 	if err != nil {
 		return "", fmt.Errorf("블록 API 요청 실패: %w", err)
 	}
@@ -503,8 +557,33 @@ func (s *TopicInferenceStore) getBlockTimestamp(blockHeight string) (string, err
 	return blockResponse.Block.Header.Time, nil
 }
 
-// collectTopicData는 지정된 토픽의 추론 데이터를 수집합니다
+// collectTopicData는 지정된 토픽의 추론 데이터를 수집하여 바로 저장합니다
 func (s *TopicInferenceStore) collectTopicData(topicID string) error {
+	startTime := time.Now()
+	record, err := s.fetchTopicData(topicID)
+	if err != nil {
+		s.events.PublishCollectionError(CollectionStageTopicInference, topicID, err)
+		s.metrics.ObserveCollection(topicID, CollectionOutcomeFetchError, time.Since(startTime))
+		return err
+	}
+	if record == nil {
+		s.metrics.ObserveCollection(topicID, CollectionOutcomeUnchanged, time.Since(startTime))
+		return nil
+	}
+
+	outcome := CollectionOutcomeSuccess
+	if err := s.db.SaveTopicInference(record); err != nil {
+		log.Printf("토픽 %s 데이터 저장 실패: %v", topicID, err)
+		s.events.PublishCollectionError(CollectionStageTopicSave, topicID, err)
+		outcome = CollectionOutcomeSaveError
+	}
+	s.metrics.ObserveCollection(topicID, outcome, time.Since(startTime))
+	return nil
+}
+
+// fetchTopicData는 지정된 토픽의 추론 데이터를 수집하고 데이터베이스에 저장할 레코드를 반환합니다
+// 변경된 데이터가 없거나 데이터베이스가 설정되지 않은 경우 nil을 반환합니다
+func (s *TopicInferenceStore) fetchTopicData(topicID string) (map[string]interface{}, error) {
 	if s.debug {
 		log.Printf("토픽 %s 데이터 수집 시작", topicID)
 	}
@@ -516,21 +595,21 @@ func (s *TopicInferenceStore) collectTopicData(topicID string) error {
 		log.Printf("API 요청 URL: %s", url)
 	}
 
-	resp, err := http.Get(url)
+	resp, err := s.httpClient.Get(url)
 	if err != nil {
-		return fmt.Errorf("API 요청 실패: %w", err)
+		return nil, fmt.Errorf("API 요청 실패: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// 응답 상태 코드 확인
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API 응답 오류: %d %s", resp.StatusCode, resp.Status)
+		return nil, fmt.Errorf("API 응답 오류: %d %s", resp.StatusCode, resp.Status)
 	}
 
 	// 응답 본문 디코딩
 	var networkInference NetworkInference
 	if err := json.NewDecoder(resp.Body).Decode(&networkInference); err != nil {
-		return fmt.Errorf("JSON 디코딩 실패: %w", err)
+		return nil, fmt.Errorf("JSON 디코딩 실패: %w", err)
 	}
 
 	// 각 인퍼러에 대한 weight 값을 고루틴을 사용하여 병렬로 가져오기
@@ -562,7 +641,7 @@ func (s *TopicInferenceStore) collectTopicData(topicID string) error {
 				log.Printf("인퍼러 weight API 요청: %s", weightURL)
 			}
 
-			weightResp, err := http.Get(weightURL)
+			weightResp, err := s.httpClient.Get(weightURL)
 			if err != nil {
 				log.Printf("인퍼러 weight API 요청 실패 (worker=%s): %v", worker, err)
 				return
@@ -615,7 +694,7 @@ func (s *TopicInferenceStore) collectTopicData(topicID string) error {
 				log.Printf("토픽 %s: 변경 없음 (inference_block_height=%s, loss_block_height=%s)",
 					topicID, networkInference.InferenceBlockHeight, networkInference.LossBlockHeight)
 			}
-			return nil
+			return nil, nil
 		}
 	}
 
@@ -628,56 +707,54 @@ func (s *TopicInferenceStore) collectTopicData(topicID string) error {
 			topicID, networkInference.InferenceBlockHeight, networkInference.LossBlockHeight)
 	}
 
-	// 데이터베이스에 저장
-	if s.db != nil {
-		// 데이터 가공 및 중복 필드 제거를 위한 처리
-		// 먼저 필요한 데이터를 추출하여 synthesis_value 생성
-		synthesisValue := s.createSynthesisData(networkInference, topicID)
+	// 데이터베이스에 저장할 레코드 구성
+	if s.db == nil {
+		return nil, nil
+	}
+
+	// 데이터 가공 및 중복 필드 제거를 위한 처리
+	// 먼저 필요한 데이터를 추출하여 synthesis_value 생성
+	synthesisValue := s.createSynthesisData(networkInference, topicID)
 
-		// 블록 타임스탬프 가져오기
-		blockTimestamp := ""
-		if networkInference.InferenceBlockHeight != "" {
-			timestamp, err := s.getBlockTimestamp(networkInference.InferenceBlockHeight)
-			if err != nil {
-				if s.debug {
-					log.Printf("블록 타임스탬프 조회 실패: %v, 현재 시간 사용", err)
-				}
-				blockTimestamp = time.Now().Format(time.RFC3339)
-			} else {
-				blockTimestamp = timestamp
+	// 블록 타임스탬프 가져오기
+	blockTimestamp := ""
+	if networkInference.InferenceBlockHeight != "" {
+		timestamp, err := s.getBlockTimestamp(networkInference.InferenceBlockHeight)
+		if err != nil {
+			if s.debug {
+				log.Printf("블록 타임스탬프 조회 실패: %v, 현재 시간 사용", err)
 			}
-		} else {
 			blockTimestamp = time.Now().Format(time.RFC3339)
+		} else {
+			blockTimestamp = timestamp
 		}
-
-		// 저장할 데이터 구성
-		storeData := map[string]interface{}{
-			"topic_id":  topicID,
-			"timestamp": blockTimestamp,
-			"network_inferences": map[string]interface{}{
-				"reputer_request_nonce":             networkInference.NetworkInferences.ReputerRequestNonce,
-				"reputer":                           networkInference.NetworkInferences.Reputer,
-				"extra_data":                        networkInference.NetworkInferences.ExtraData,
-				"combined_value":                    networkInference.NetworkInferences.CombinedValue,
-				"naive_value":                       networkInference.NetworkInferences.NaiveValue,
-				"forecaster_values":                 networkInference.NetworkInferences.ForecasterValues,
-				"one_out_forecaster_values":         networkInference.NetworkInferences.OneOutForecasterValues,
-				"one_in_forecaster_values":          networkInference.NetworkInferences.OneInForecasterValues,
-				"one_out_inferer_forecaster_values": networkInference.NetworkInferences.OneOutInfererForecasterValues,
-				"synthesis_value":                   synthesisValue,
-			},
-			"inference_block_height":              networkInference.InferenceBlockHeight,
-			"loss_block_height":                   networkInference.LossBlockHeight,
-			"confidence_interval_raw_percentiles": networkInference.ConfidenceIntervalRawPercentiles,
-			"confidence_interval_values":          networkInference.ConfidenceIntervalValues,
-		}
-
-		if err := s.db.SaveTopicInference(storeData); err != nil {
-			log.Printf("토픽 %s 데이터 저장 실패: %v", topicID, err)
-		}
-	}
-
-	return nil
+	} else {
+		blockTimestamp = time.Now().Format(time.RFC3339)
+	}
+
+	// 저장할 데이터 구성
+	storeData := map[string]interface{}{
+		"topic_id":  topicID,
+		"timestamp": blockTimestamp,
+		"network_inferences": map[string]interface{}{
+			"reputer_request_nonce":             networkInference.NetworkInferences.ReputerRequestNonce,
+			"reputer":                           networkInference.NetworkInferences.Reputer,
+			"extra_data":                        networkInference.NetworkInferences.ExtraData,
+			"combined_value":                    networkInference.NetworkInferences.CombinedValue,
+			"naive_value":                       networkInference.NetworkInferences.NaiveValue,
+			"forecaster_values":                 networkInference.NetworkInferences.ForecasterValues,
+			"one_out_forecaster_values":         networkInference.NetworkInferences.OneOutForecasterValues,
+			"one_in_forecaster_values":          networkInference.NetworkInferences.OneInForecasterValues,
+			"one_out_inferer_forecaster_values": networkInference.NetworkInferences.OneOutInfererForecasterValues,
+			"synthesis_value":                   synthesisValue,
+		},
+		"inference_block_height":              networkInference.InferenceBlockHeight,
+		"loss_block_height":                   networkInference.LossBlockHeight,
+		"confidence_interval_raw_percentiles": networkInference.ConfidenceIntervalRawPercentiles,
+		"confidence_interval_values":          networkInference.ConfidenceIntervalValues,
+	}
+
+	return storeData, nil
 }
 
 // GetTopicInference는 지정된 토픽의 최신 추론 데이터를 반환합니다
@@ -0,0 +1,463 @@
+package app
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OpenAPI 문서
+// apiRoutes의 경로/메서드/파라미터 선언과 응답 타입(api_types.go)을 리플렉션하여 OpenAPI 3.0 문서를 생성합니다
+// 프론트엔드는 /api/openapi.json에서 경로와 응답 형태를 가져오고, /api/docs에서 문서를 확인할 수 있습니다
+
+const (
+	openAPIVersion    = "3.0.3"
+	apiVersion        = "1.0.0"
+	schemaRefPrefix   = "#/components/schemas/"
+	adminSecurityName = "adminToken"
+)
+
+// jsonSchema는 OpenAPI 3.0 스키마 객체 중 문서 생성에 사용하는 부분입니다
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	AllOf                []*jsonSchema          `json:"allOf,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Nullable             bool                   `json:"nullable,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"` // false 또는 *jsonSchema (생략하면 허용)
+}
+
+// schemaProvider는 리플렉션 대신 직접 스키마를 제공하는 타입입니다 (필드가 고정되지 않은 맵 타입 등)
+type schemaProvider interface {
+	openAPISchema() *jsonSchema
+}
+
+// OpenAPIDocument는 OpenAPI 3.0 문서입니다
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+// OpenAPIInfo는 문서 정보입니다
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIOperation은 경로 하나의 메서드 하나입니다
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+// OpenAPIParameter는 경로 또는 쿼리 파라미터입니다
+type OpenAPIParameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *jsonSchema `json:"schema"`
+}
+
+// OpenAPIRequestBody는 요청 본문입니다
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse는 상태 코드 하나의 응답입니다
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Headers     map[string]OpenAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIHeader는 응답 헤더 하나입니다
+type OpenAPIHeader struct {
+	Description string      `json:"description,omitempty"`
+	Schema      *jsonSchema `json:"schema"`
+}
+
+// OpenAPIMediaType은 요청/응답 콘텐츠 타입 하나의 스키마입니다
+type OpenAPIMediaType struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+// OpenAPIComponents는 재사용 스키마와 인증 방식입니다
+type OpenAPIComponents struct {
+	Schemas         map[string]*jsonSchema           `json:"schemas"`
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes"`
+}
+
+// OpenAPISecurityScheme은 인증 방식입니다
+type OpenAPISecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description,omitempty"`
+}
+
+// BuildOpenAPIDocument는 API 라우트 선언으로 OpenAPI 문서를 생성합니다
+// 라우트 목록은 핸들러 메서드 값만 사용하므로 빈 Service로 만들어도 됩니다
+func BuildOpenAPIDocument() *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: OpenAPIInfo{
+			Title:       "Allora Monitor API",
+			Description: "Allora 경쟁, 토픽 추론 스냅샷, 리더보드 조회 API. /api/v1 경로를 사용하고, 쿼리 파라미터 방식의 이전 경로는 호환을 위해 유지됩니다.",
+			Version:     apiVersion,
+		},
+		Paths: make(map[string]map[string]*OpenAPIOperation),
+		Components: OpenAPIComponents{
+			SecuritySchemes: map[string]OpenAPISecurityScheme{
+				adminSecurityName: {Type: "http", Scheme: "bearer", Description: "관리자 API 토큰 (admin_token 설정)"},
+			},
+		},
+	}
+	generator := &schemaGenerator{components: make(map[string]*jsonSchema)}
+
+	addOperation := func(path, method string, op *OpenAPIOperation) {
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*OpenAPIOperation)
+		}
+		doc.Paths[path][strings.ToLower(method)] = op
+	}
+
+	for _, route := range (&Service{}).apiRoutes() {
+		for _, method := range route.methods {
+			if route.path != "" {
+				addOperation(apiVersionPrefix+openAPIPath(route.path), method, route.operation(generator, method, true))
+			}
+			if route.legacy != "" {
+				addOperation(route.legacy, method, route.operation(generator, method, false))
+			}
+		}
+	}
+
+	doc.Components.Schemas = generator.components
+	return doc
+}
+
+// openAPIDocumentJSON은 처음 요청될 때 한 번 생성한 문서입니다
+var openAPIDocumentJSON = sync.OnceValues(func() ([]byte, error) {
+	return json.Marshal(BuildOpenAPIDocument())
+})
+
+// HandleOpenAPI는 OpenAPI 문서를 반환하는 핸들러입니다
+func (s *Service) HandleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	data, err := openAPIDocumentJSON()
+	if err != nil {
+		log.Printf("OpenAPI 문서 생성 실패: %v", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to build OpenAPI document")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// apiDocsPage는 /api/openapi.json을 읽어 표시하는 Swagger UI 페이지입니다
+const apiDocsPage = `<!DOCTYPE html>
+<html lang="ko">
+<head>
+  <meta charset="utf-8">
+  <title>Allora Monitor API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// HandleAPIDocs는 API 문서 페이지를 반환하는 핸들러입니다
+func (s *Service) HandleAPIDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(apiDocsPage))
+}
+
+// operation은 라우트의 메서드 하나를 OpenAPI 작업으로 변환합니다
+// versioned가 false이면 쿼리 파라미터 방식의 이전 경로로 문서화합니다
+func (route apiRoute) operation(generator *schemaGenerator, method string, versioned bool) *OpenAPIOperation {
+	path := apiVersionPrefix + route.path
+	if !versioned {
+		path = route.legacy
+	}
+
+	op := &OpenAPIOperation{
+		OperationID: operationID(method, path),
+		Summary:     route.summary,
+		Tags:        []string{route.tag()},
+		Deprecated:  !versioned && route.path != "",
+		Responses:   make(map[string]OpenAPIResponse),
+	}
+
+	for _, param := range route.params {
+		parameter := OpenAPIParameter{Name: param.name, In: "query", Required: param.required, Schema: param.schema()}
+		if versioned && param.path != "" {
+			parameter = OpenAPIParameter{Name: param.path, In: "path", Required: true, Schema: param.schema()}
+		}
+		op.Parameters = append(op.Parameters, parameter)
+	}
+
+	if route.request != nil {
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  map[string]OpenAPIMediaType{"application/json": {Schema: generator.schemaFor(reflect.TypeOf(route.request))}},
+		}
+	}
+
+	success := OpenAPIResponse{Description: "성공", Content: make(map[string]OpenAPIMediaType)}
+	if route.response != nil {
+		success.Content["application/json"] = OpenAPIMediaType{Schema: generator.schemaFor(reflect.TypeOf(route.response))}
+	}
+	for _, contentType := range route.produces {
+		success.Content[contentType] = OpenAPIMediaType{Schema: &jsonSchema{Type: "string", Format: "binary"}}
+	}
+	if route.paged() {
+		success.Headers = map[string]OpenAPIHeader{
+			"Link": {Description: `limit 또는 cursor를 지정하면 첫 페이지(rel="first")와 다음 페이지(rel="next") URL`, Schema: &jsonSchema{Type: "string"}},
+		}
+	}
+	op.Responses[strconv.Itoa(route.successStatus())] = success
+
+	if route.conditional {
+		op.Responses[strconv.Itoa(http.StatusNotModified)] = OpenAPIResponse{Description: "If-None-Match가 ETag(스냅샷 해시)와 일치"}
+	}
+	op.Responses["default"] = OpenAPIResponse{
+		Description: "오류 (error.code로 오류 종류를 구분)",
+		Content:     map[string]OpenAPIMediaType{"application/json": {Schema: generator.schemaFor(reflect.TypeOf(ErrorResponse{}))}},
+	}
+
+	if route.tag() == "admin" {
+		op.Security = []map[string][]string{{adminSecurityName: {}}}
+	}
+	return op
+}
+
+// paged는 라우트가 커서 페이지네이션을 지원하는지 반환합니다
+func (route apiRoute) paged() bool {
+	for _, param := range route.params {
+		if param.name == "cursor" {
+			return true
+		}
+	}
+	return false
+}
+
+// successStatus는 성공 응답의 상태 코드입니다
+func (route apiRoute) successStatus() int {
+	if route.status != 0 {
+		return route.status
+	}
+	return http.StatusOK
+}
+
+// tag는 문서에서 라우트를 묶는 이름입니다 (리소스 경로의 첫 구간)
+func (route apiRoute) tag() string {
+	path := route.path
+	if path == "" {
+		path = strings.TrimPrefix(route.legacy, "/api")
+	}
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return segment
+}
+
+// schema는 파라미터 형식의 스키마입니다
+func (param routeParam) schema() *jsonSchema {
+	schema := &jsonSchema{Type: "string", Enum: param.enum}
+	switch param.kind {
+	case paramInteger:
+		schema.Type = "integer"
+	case paramBoolean:
+		schema.Type = "boolean"
+	case paramTime:
+		schema.Format = "date-time"
+	case paramDuration:
+		schema.Description = "Go duration (예: 24h, 90m)"
+	}
+	if pattern, ok := paramPatterns[param.kind]; ok && schema.Type == "string" {
+		schema.Pattern = pattern.String()
+	}
+	return schema
+}
+
+// openAPIPath는 ServeMux 패턴 경로를 OpenAPI 경로로 바꿉니다 ({name...} 와일드카드의 접미사 제거)
+func openAPIPath(path string) string {
+	return strings.ReplaceAll(path, "...}", "}")
+}
+
+// operationID는 메서드와 경로로 작업 ID를 만듭니다 (예: GET /api/v1/topics/{id}/stats → getApiV1TopicsByIdStats)
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") {
+			b.WriteString("By")
+			segment = strings.Trim(segment, "{}.")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// schemaGenerator는 Go 타입을 OpenAPI 스키마로 변환하고 이름 있는 구조체를 components에 모읍니다
+type schemaGenerator struct {
+	components map[string]*jsonSchema
+}
+
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	schemaProviderType = reflect.TypeOf((*schemaProvider)(nil)).Elem()
+)
+
+// schemaFor는 타입의 스키마를 반환합니다
+// 이름 있는 구조체와 schemaProvider 타입은 components에 등록하고 $ref로 참조합니다
+// 포인터, 슬라이스, 맵은 nil이면 null로 인코딩되므로 nullable입니다
+func (g *schemaGenerator) schemaFor(t reflect.Type) *jsonSchema {
+	if t.Kind() == reflect.Pointer {
+		return nullable(g.schemaFor(t.Elem()))
+	}
+
+	switch {
+	case t == timeType:
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	case t.Implements(schemaProviderType):
+		return g.component(t, func() *jsonSchema {
+			return reflect.Zero(t).Interface().(schemaProvider).openAPISchema()
+		})
+	case t.Implements(jsonMarshalerType):
+		return &jsonSchema{} // 직접 인코딩하는 타입은 형태를 알 수 없음
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema := &jsonSchema{Type: "integer"}
+		if t.Size() == 8 {
+			schema.Format = "int64"
+		}
+		return schema
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &jsonSchema{Type: "string", Format: "byte", Nullable: t.Kind() == reflect.Slice}
+		}
+		return &jsonSchema{Type: "array", Items: g.schemaFor(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.component(t, func() *jsonSchema { return g.structSchema(t) })
+	default:
+		return &jsonSchema{} // interface{} 등 임의의 값
+	}
+}
+
+// component는 이름 있는 타입을 components에 등록하고 참조 스키마를 반환합니다
+func (g *schemaGenerator) component(t reflect.Type, build func() *jsonSchema) *jsonSchema {
+	name := t.Name()
+	if _, ok := g.components[name]; !ok {
+		// 자기 참조 타입이 무한히 재귀하지 않도록 먼저 자리를 잡아 둠
+		g.components[name] = &jsonSchema{}
+		g.components[name] = build()
+	}
+	return &jsonSchema{Ref: schemaRefPrefix + name}
+}
+
+// structSchema는 구조체의 json 태그로 객체 스키마를 만듭니다
+// omitempty가 없는 필드는 항상 인코딩되므로 필수이며, 선언되지 않은 필드는 허용하지 않습니다
+func (g *schemaGenerator) structSchema(t reflect.Type) *jsonSchema {
+	schema := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema), AdditionalProperties: false}
+	g.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// addFields는 구조체 필드를 스키마 속성으로 추가합니다 (태그 없는 임베디드 구조체는 펼침)
+func (g *schemaGenerator) addFields(schema *jsonSchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			g.addFields(schema, fieldType)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := g.schemaFor(field.Type)
+		if hasTagOption(options, "string") {
+			fieldSchema = &jsonSchema{Type: "string"}
+		}
+		schema.Properties[name] = fieldSchema
+		if !hasTagOption(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// hasTagOption은 json 태그 옵션 목록에 옵션이 있는지 확인합니다
+func hasTagOption(options, option string) bool {
+	for _, candidate := range strings.Split(options, ",") {
+		if candidate == option {
+			return true
+		}
+	}
+	return false
+}
+
+// nullable은 스키마가 null도 허용하도록 합니다 ($ref는 형제 속성을 가질 수 없으므로 allOf로 감쌈)
+func nullable(schema *jsonSchema) *jsonSchema {
+	if schema.Ref != "" {
+		return &jsonSchema{AllOf: []*jsonSchema{schema}, Nullable: true}
+	}
+	schema.Nullable = true
+	return schema
+}
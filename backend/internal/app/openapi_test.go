@@ -0,0 +1,598 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// API 응답 검사
+// 임시 데이터베이스에 샘플 데이터를 저장한 뒤 모든 라우트를 실제 핸들러로 호출하고,
+// 응답 상태 코드와 본문이 OpenAPI 문서의 스키마와 일치하는지 확인합니다
+// 핸들러 응답을 바꾸고 응답 타입(api_types.go)을 갱신하지 않으면 테스트가 실패합니다
+
+// 검사용 샘플 데이터
+const (
+	conformanceTopicID       = "1"
+	conformanceCompetitionID = 1
+	conformanceSnapshots     = 3
+	conformanceWorkers       = 4
+
+	conformanceStreamTimeout = 50 * time.Millisecond // 이벤트 스트림 응답을 받는 시간
+)
+
+// conformanceGraphQLQuery는 SynthesisTable 화면에 필요한 데이터를 한 번에 가져오는 GraphQL 쿼리입니다
+const conformanceGraphQLQuery = `{
+  active: competitions(active: true) { id name topic_id prize_pool start_date end_date is_active lifecycle }
+  inactive: competitions(active: false) { id name topic_id is_active lifecycle }
+  topic(id: "` + conformanceTopicID + `") {
+    heights(limit: 100) { total_count heights next_before }
+    stats { record_count newest_timestamp }
+    latest_inference {
+      inference_block_height timestamp prev_height next_height
+      confidence_interval_raw_percentiles confidence_interval_values
+      workers {
+        worker inferer_values one_out_inferer_values weight confidential_percentiles
+        leaderboard { rank username first_name last_name is_active loss score points }
+      }
+    }
+  }
+}`
+
+// conformanceEnv는 샘플 데이터가 저장된 서비스와 라우트가 등록된 mux, 실제 HTTP 서버입니다
+type conformanceEnv struct {
+	service *Service
+	mux     *http.ServeMux
+	server  *httptest.Server
+	samples map[string]string
+}
+
+// newConformanceEnv는 백업, 아카이브, 무결성 검사, 이벤트, WebSocket까지 연결된 서비스를 만듭니다
+// WebSocket 경로는 연결을 가로채야 하므로 실제 HTTP 서버도 함께 띄웁니다
+func newConformanceEnv(t *testing.T) *conformanceEnv {
+	t.Helper()
+	dir := t.TempDir()
+	db := newTestDatabase(t)
+	samples := seedConformanceData(t, db)
+
+	monitor := NewMonitor(nil, db, &Config{})
+	monitor.debug = false
+	monitor.GetTopicInferenceStore().SetDebug(false)
+	monitor.GetTopicInferenceStore().SetActiveTopics([]string{conformanceTopicID})
+
+	service := NewService(monitor, db)
+	backups := NewBackupManager(db, filepath.Join(dir, "backups"), 1, 0)
+	backups.SetDebug(false)
+	service.SetBackupManager(backups)
+	service.SetArchiver(NewArchiver(filepath.Join(dir, "archive")))
+	integrity := NewIntegrityChecker(db, 0, IntegrityOptions{})
+	if _, err := integrity.Run(IntegrityOptions{}); err != nil {
+		t.Fatalf("무결성 검사 실패: %v", err)
+	}
+	service.SetIntegrityChecker(integrity)
+	service.SetAdminToken(testAdminToken)
+	events := NewEventBroker(0)
+	service.SetEventBroker(events)
+	hub := NewWebSocketHub(db, events)
+	if err := hub.Start(); err != nil {
+		t.Fatalf("WebSocket 허브 시작 실패: %v", err)
+	}
+	t.Cleanup(hub.Stop)
+	service.SetWebSocketHub(hub)
+
+	mux := http.NewServeMux()
+	service.RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return &conformanceEnv{service: service, mux: mux, server: server, samples: samples}
+}
+
+// reportErrors는 검사에서 나온 불일치 항목을 테스트 실패로 기록합니다
+func reportErrors(t *testing.T, errs []string) {
+	t.Helper()
+	for _, err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestAPIConformance는 모든 API 라우트의 응답이 OpenAPI 문서와 일치하는지 검사합니다
+// 이전 경로와 /api/v1 경로를 모두 호출하고, 페이지 라우트는 Link 헤더를 따라 모든 페이지를 검사합니다
+func TestAPIConformance(t *testing.T) {
+	env := newConformanceEnv(t)
+	doc := BuildOpenAPIDocument()
+	validator := &schemaValidator{components: doc.Components.Schemas}
+
+	for _, route := range env.service.apiRoutes() {
+		for _, method := range route.methods {
+			for _, versioned := range []bool{true, false} {
+				docPath := route.legacy
+				if versioned {
+					docPath = apiVersionPrefix + openAPIPath(route.path)
+				}
+				// 이전 경로는 같은 핸들러의 별칭이므로 상태를 바꾸는 요청은 /api/v1 경로로 한 번만 호출
+				if docPath == "" || (!versioned && method != http.MethodGet && route.path != "") {
+					continue
+				}
+				route, method := route, method
+				target := conformanceURL(route, versioned, env.samples)
+
+				t.Run(method+" "+target, func(t *testing.T) {
+					op := doc.Paths[docPath][strings.ToLower(method)]
+					if op == nil {
+						t.Fatal("OpenAPI 문서에 작업이 없음")
+					}
+					if route.successStatus() == http.StatusSwitchingProtocols {
+						reportErrors(t, checkWebSocket(env.server.URL, target))
+						return
+					}
+
+					recorder := httptest.NewRecorder()
+					req, cancel := conformanceRequest(route, method, target)
+					defer cancel()
+					env.mux.ServeHTTP(recorder, req)
+					reportErrors(t, validator.validateResponse(op, recorder))
+				})
+			}
+		}
+	}
+
+	for _, route := range env.service.apiRoutes() {
+		if !route.paged() {
+			continue
+		}
+		route := route
+		t.Run("pages "+conformanceURL(route, true, env.samples), func(t *testing.T) {
+			reportErrors(t, checkPagination(env.mux, validator, doc, route, env.samples))
+		})
+	}
+}
+
+// TestGraphQLConformance는 샘플 쿼리가 오류 없이 SynthesisTable에 필요한 데이터를 모두 반환하고
+// 응답이 OpenAPI 문서와 일치하는지 확인합니다
+func TestGraphQLConformance(t *testing.T) {
+	env := newConformanceEnv(t)
+	doc := BuildOpenAPIDocument()
+	validator := &schemaValidator{components: doc.Components.Schemas}
+	target := apiVersionPrefix + "/graphql"
+
+	body, _ := json.Marshal(GraphQLRequest{Query: conformanceGraphQLQuery})
+	recorder := httptest.NewRecorder()
+	env.mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body)))
+	reportErrors(t, validator.validateResponse(doc.Paths[target][strings.ToLower(http.MethodPost)], recorder))
+
+	var response GraphQLResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("GraphQL 응답 디코딩 실패: %v", err)
+	}
+	for _, err := range response.Errors {
+		t.Errorf("GraphQL 오류 %s: %s", err.Extensions.Code, err.Message)
+	}
+
+	var data struct {
+		Active []json.RawMessage `json:"active"`
+		Topic  *struct {
+			Heights struct {
+				Heights []string `json:"heights"`
+			} `json:"heights"`
+			LatestInference *struct {
+				Workers []struct {
+					Leaderboard json.RawMessage `json:"leaderboard"`
+				} `json:"workers"`
+			} `json:"latest_inference"`
+		} `json:"topic"`
+	}
+	encoded, _ := json.Marshal(response.Data)
+	json.Unmarshal(encoded, &data)
+	switch {
+	case len(data.Active) == 0:
+		t.Error("활성 경쟁이 없음")
+	case data.Topic == nil || data.Topic.LatestInference == nil:
+		t.Error("토픽 최신 스냅샷이 없음")
+	case len(data.Topic.Heights.Heights) != conformanceSnapshots:
+		t.Errorf("블록 높이 %d개, 기대 %d개", len(data.Topic.Heights.Heights), conformanceSnapshots)
+	case len(data.Topic.LatestInference.Workers) != conformanceWorkers:
+		t.Errorf("워커 %d개, 기대 %d개", len(data.Topic.LatestInference.Workers), conformanceWorkers)
+	}
+}
+
+// conformanceRequest는 검사 요청을 만듭니다
+// 요청 본문을 받는 라우트(GraphQL)는 샘플 쿼리를 본문으로 보냅니다
+// 이벤트 스트림은 클라이언트가 끊을 때까지 응답하므로 잠시 후 취소되는 컨텍스트를 사용합니다
+func conformanceRequest(route apiRoute, method, target string) (*http.Request, context.CancelFunc) {
+	req := httptest.NewRequest(method, target, nil)
+	if _, ok := route.request.(GraphQLRequest); ok {
+		body, _ := json.Marshal(GraphQLRequest{Query: conformanceGraphQLQuery})
+		req = httptest.NewRequest(method, target, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if route.tag() == "admin" {
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	}
+	if !containsString(route.produces, "text/event-stream") {
+		return req, func() {}
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), conformanceStreamTimeout)
+	return req.WithContext(ctx), cancel
+}
+
+// checkWebSocket은 WebSocket 경로에 연결하여 구독 요청과 응답 메시지가 README의 프로토콜과 일치하는지 확인합니다
+func checkWebSocket(serverURL, target string) []string {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(serverURL, "http")+target, nil)
+	if err != nil {
+		return []string{fmt.Sprintf("WebSocket 연결 실패: %v", err)}
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	read := func() (WSServerMessage, map[string]interface{}, error) {
+		var message WSServerMessage
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return message, nil, err
+		}
+		var raw map[string]interface{}
+		if err := json.Unmarshal(data, &message); err != nil {
+			return message, nil, err
+		}
+		json.Unmarshal(data, &raw)
+		return message, raw, nil
+	}
+	expect := func(request WSClientMessage, types ...string) []string {
+		if request.Op != "" {
+			if err := conn.WriteJSON(request); err != nil {
+				return []string{fmt.Sprintf("요청 전송 실패: %v", err)}
+			}
+		}
+		var errs []string
+		for _, expected := range types {
+			message, raw, err := read()
+			if err != nil {
+				return append(errs, fmt.Sprintf("%s 메시지 수신 실패: %v", expected, err))
+			}
+			if message.Type != expected {
+				errs = append(errs, fmt.Sprintf("메시지 종류 %q, 기대 %q: %v", message.Type, expected, raw))
+				continue
+			}
+			if request.ID != "" && message.Channel == "" && message.ID != request.ID {
+				errs = append(errs, fmt.Sprintf("%s 응답의 id %q, 기대 %q", expected, message.ID, request.ID))
+			}
+			if expected == WSMessageSnapshot && (message.Height == "" || raw["data"] == nil) {
+				errs = append(errs, "snapshot 메시지에 height 또는 data가 없음")
+			}
+		}
+		return errs
+	}
+
+	var errs []string
+	errs = append(errs, expect(WSClientMessage{}, WSMessageWelcome)...)
+	errs = append(errs, expect(WSClientMessage{Op: WSOpSubscribe, ID: "1", Channels: []string{
+		wsChannel(WSChannelTopic, conformanceTopicID), wsChannel(WSChannelCompetition, strconv.Itoa(conformanceCompetitionID)),
+	}}, WSMessageSubscribed, WSMessageSnapshot)...)
+	errs = append(errs, expect(WSClientMessage{Op: WSOpUnsubscribe, ID: "2", Channels: []string{wsChannel(WSChannelTopic, conformanceTopicID)}}, WSMessageUnsubscribed)...)
+	errs = append(errs, expect(WSClientMessage{Op: WSOpPing, ID: "3"}, WSMessagePong)...)
+
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return errs
+}
+
+// seedConformanceData는 모든 라우트가 데이터를 반환하도록 경쟁, 스냅샷, 리더보드를 저장하고
+// 경로/쿼리 파라미터에 사용할 샘플 값을 반환합니다
+func seedConformanceData(t testing.TB, db *Database) map[string]string {
+	t.Helper()
+	now := time.Now().UTC()
+	description := "검사용 경쟁"
+	competitions := &CompetitionsResponse{}
+	competitions.PageProps.CompetitionsPage.ActiveAndUpcomingCompetitions = []Competition{{
+		ID:          conformanceCompetitionID,
+		Name:        "Conformance Competition",
+		Description: &description,
+		TopicID:     1,
+		PrizePool:   1000,
+		StartDate:   now.Add(-48 * time.Hour).Truncate(time.Second),
+		EndDate:     now.Add(48 * time.Hour).Truncate(time.Second),
+		SeasonID:    1,
+		Tags:        []string{"conformance"},
+	}}
+	if err := db.SaveCompetitions(competitions); err != nil {
+		t.Fatalf("샘플 경쟁 저장 실패: %v", err)
+	}
+
+	// 최근 스냅샷이 시계열 기본 조회 범위(24시간)에 들어가도록 현재 시각 직전에 배치
+	base := now.Add(-time.Duration(conformanceSnapshots) * time.Minute)
+	workers := testWorkers(conformanceWorkers)
+	var lastHeight string
+	for cycle := 0; cycle < conformanceSnapshots; cycle++ {
+		snapshot := testSnapshot(conformanceTopicID, 1000000+cycle*10, base.Add(time.Duration(cycle)*time.Minute), workers, cycle)
+		if err := db.SaveTopicInference(snapshot); err != nil {
+			t.Fatalf("샘플 스냅샷 저장 실패: %v", err)
+		}
+
+		lastHeight = snapshot["inference_block_height"].(string)
+		var entries []map[string]interface{}
+		for w, worker := range workers {
+			entries = append(entries, map[string]interface{}{
+				"cosmos_address": worker,
+				"username":       fmt.Sprintf("worker-%d", w),
+				"rank":           strconv.Itoa((w+cycle)%conformanceWorkers + 1),
+				"points":         float64(100 - w + cycle),
+				"score":          float64(w) / 10,
+				"loss":           float64(cycle) / 100,
+				"is_active":      true,
+			})
+		}
+		if err := db.SaveLeaderboardEntries(conformanceTopicID, lastHeight, entries); err != nil {
+			t.Fatalf("샘플 리더보드 저장 실패: %v", err)
+		}
+	}
+
+	return map[string]string{
+		"topic_id":       conformanceTopicID,
+		"competition_id": strconv.Itoa(conformanceCompetitionID),
+		"address":        workers[0],
+		"height":         lastHeight,
+		"q":              "conformance",
+		"query":          conformanceGraphQLQuery,
+		"start":          now.Add(-24 * time.Hour).Format(time.RFC3339),
+		"end":            now.Add(time.Hour).Format(time.RFC3339),
+	}
+}
+
+// conformanceURL은 라우트의 경로 와일드카드와 필수 쿼리 파라미터를 샘플 값으로 채운 요청 URL을 만듭니다
+func conformanceURL(route apiRoute, versioned bool, samples map[string]string) string {
+	path := route.legacy
+	if versioned {
+		path = apiVersionPrefix + route.path
+	}
+
+	query := url.Values{}
+	for _, param := range route.params {
+		value := samples[param.name]
+		if versioned && param.path != "" {
+			path = strings.ReplaceAll(path, "{"+param.path+"}", url.PathEscape(value))
+			continue
+		}
+		if param.required || param.path != "" {
+			query.Set(param.name, value)
+		}
+	}
+
+	if encoded := query.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	return path
+}
+
+// checkPagination은 limit=1로 첫 페이지부터 rel="next" 링크를 따라가며 각 페이지를 검사하고,
+// 페이지별 항목 수의 합이 한 번에 조회한 항목 수와 같은지 확인합니다
+func checkPagination(mux *http.ServeMux, validator *schemaValidator, doc *OpenAPIDocument, route apiRoute, samples map[string]string) []string {
+	base := conformanceURL(route, true, samples)
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	op := doc.Paths[apiVersionPrefix+openAPIPath(route.path)][strings.ToLower(http.MethodGet)]
+
+	var errs []string
+	fetch := func(target string) (*httptest.ResponseRecorder, int) {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		errs = append(errs, validator.validateResponse(op, recorder)...)
+		return recorder, pageItemCount(recorder.Body.Bytes())
+	}
+
+	_, expected := fetch(base + separator + "limit=" + strconv.Itoa(maxPageLimit))
+	total, pages := 0, 0
+	for target := base + separator + "limit=1"; target != "" && pages <= expected; pages++ {
+		recorder, count := fetch(target)
+		if len(errs) > 0 {
+			return errs
+		}
+		total += count
+		target = nextPageLink(recorder.Header().Get("Link"))
+	}
+	if total != expected || expected == 0 {
+		errs = append(errs, fmt.Sprintf("페이지 항목 합계 %d개, 전체 조회 %d개 (%d페이지)", total, expected, pages))
+	}
+	return errs
+}
+
+// pageItemCount는 목록 응답 본문의 항목 수를 반환합니다 (배열 또는 count/heights_count 필드)
+func pageItemCount(body []byte) int {
+	var items []json.RawMessage
+	if json.Unmarshal(body, &items) == nil {
+		return len(items)
+	}
+	var envelope struct {
+		Count *int `json:"count"`
+		Data  struct {
+			HeightsCount int `json:"heights_count"`
+		} `json:"data"`
+	}
+	json.Unmarshal(body, &envelope)
+	if envelope.Count != nil {
+		return *envelope.Count
+	}
+	return envelope.Data.HeightsCount
+}
+
+// nextPageLink는 Link 헤더에서 rel="next" URL을 찾습니다
+func nextPageLink(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		target, params, _ := strings.Cut(strings.TrimSpace(link), ";")
+		if strings.TrimSpace(params) == `rel="next"` {
+			return strings.Trim(target, "<>")
+		}
+	}
+	return ""
+}
+
+// schemaValidator는 JSON 값을 OpenAPI 스키마로 검사합니다 (문서 생성에 쓰는 키워드만 지원)
+type schemaValidator struct {
+	components map[string]*jsonSchema
+}
+
+// validateResponse는 응답의 상태 코드, 콘텐츠 타입, 본문을 작업 문서와 비교합니다
+func (v *schemaValidator) validateResponse(op *OpenAPIOperation, recorder *httptest.ResponseRecorder) []string {
+	documented, ok := op.Responses[strconv.Itoa(recorder.Code)]
+	if !ok || recorder.Code >= http.StatusBadRequest {
+		body := strings.TrimSpace(recorder.Body.String())
+		if len(body) > 200 {
+			body = body[:200] + "..."
+		}
+		return []string{fmt.Sprintf("문서에 없는 상태 코드 %d: %s", recorder.Code, body)}
+	}
+
+	contentType := recorder.Header().Get("Content-Type")
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	media, ok := documented.Content[strings.TrimSpace(mediaType)]
+	if !ok {
+		if len(documented.Content) == 0 {
+			return nil
+		}
+		return []string{fmt.Sprintf("문서에 없는 콘텐츠 타입 %q", contentType)}
+	}
+	if mediaType != "application/json" {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(recorder.Body.Bytes()))
+	decoder.UseNumber()
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return []string{fmt.Sprintf("JSON 본문 파싱 실패: %v", err)}
+	}
+	return v.validate(media.Schema, body, "$")
+}
+
+// validateError는 오류 응답의 상태 코드, 오류 코드, 본문을 ErrorResponse 스키마와 비교합니다
+func (v *schemaValidator) validateError(recorder *httptest.ResponseRecorder, status int, code ErrorCode) []string {
+	if recorder.Code != status {
+		return []string{fmt.Sprintf("상태 코드 %d 기대, %d 응답: %s", status, recorder.Code, strings.TrimSpace(recorder.Body.String()))}
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		return []string{fmt.Sprintf("오류 응답의 콘텐츠 타입이 JSON이 아님 (%q)", contentType)}
+	}
+	if status == http.StatusMethodNotAllowed && recorder.Header().Get("Allow") == "" {
+		return []string{"405 응답에 Allow 헤더 없음"}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(recorder.Body.Bytes()))
+	decoder.UseNumber()
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return []string{fmt.Sprintf("JSON 본문 파싱 실패: %v", err)}
+	}
+	errs := v.validate(&jsonSchema{Ref: schemaRefPrefix + "ErrorResponse"}, body, "$")
+
+	var response ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err == nil && response.Error.Code != code {
+		errs = append(errs, fmt.Sprintf("오류 코드 %s 기대, %s 응답", code, response.Error.Code))
+	}
+	return errs
+}
+
+// validate는 값이 스키마와 일치하는지 검사하고 불일치 항목을 경로와 함께 반환합니다
+func (v *schemaValidator) validate(schema *jsonSchema, value interface{}, path string) []string {
+	if schema.Ref != "" {
+		target, ok := v.components[strings.TrimPrefix(schema.Ref, schemaRefPrefix)]
+		if !ok {
+			return []string{fmt.Sprintf("%s: 정의되지 않은 스키마 %s", path, schema.Ref)}
+		}
+		return v.validate(target, value, path)
+	}
+
+	if value == nil {
+		if schema.Nullable || (schema.Type == "" && len(schema.AllOf) == 0) {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: null은 허용되지 않음", path)}
+	}
+
+	var errs []string
+	for _, part := range schema.AllOf {
+		errs = append(errs, v.validate(part, value, path)...)
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: 객체가 아님 (%T)", path, value))
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: 필수 속성 %s 없음", path, name))
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := path + "." + name
+			if property, ok := schema.Properties[name]; ok {
+				errs = append(errs, v.validate(property, object[name], child)...)
+				continue
+			}
+			switch additional := schema.AdditionalProperties.(type) {
+			case bool:
+				if !additional {
+					errs = append(errs, fmt.Sprintf("%s: 문서에 없는 속성", child))
+				}
+			case *jsonSchema:
+				errs = append(errs, v.validate(additional, object[name], child)...)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: 배열이 아님 (%T)", path, value))
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				errs = append(errs, v.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: 문자열이 아님 (%T)", path, value))
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: RFC3339 시각이 아님 (%q)", path, str))
+			}
+		}
+		if len(schema.Enum) > 0 && !containsString(schema.Enum, str) {
+			errs = append(errs, fmt.Sprintf("%s: 허용되지 않은 값 %q", path, str))
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if _, err := number.Int64(); !ok || err != nil {
+			return append(errs, fmt.Sprintf("%s: 정수가 아님 (%v)", path, value))
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return append(errs, fmt.Sprintf("%s: 숫자가 아님 (%T)", path, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return append(errs, fmt.Sprintf("%s: 불리언이 아님 (%T)", path, value))
+		}
+	}
+	return errs
+}
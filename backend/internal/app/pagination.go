@@ -0,0 +1,158 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 커서 페이지네이션
+// 목록 API는 정렬 키(블록 높이, 경쟁 ID, 레코드 ID)를 기준으로 마지막 항목 다음부터 조회하는 커서를 사용합니다
+// OFFSET과 달리 조회 중에 새 스냅샷이 저장되어도 항목이 건너뛰어지거나 반복되지 않고, 인덱스로 바로 다음 위치를 찾습니다
+// 커서는 목록 종류와 마지막 키를 인코딩한 불투명 문자열이며, 클라이언트는 응답의 next_cursor나 Link 헤더의 URL을 그대로 사용합니다
+
+// 페이지 크기
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// 커서 종류 (다른 목록의 커서를 거부하기 위해 커서에 포함)
+const (
+	cursorTopicHeights       = "heights"             // 토픽 블록 높이 (내림차순)
+	cursorTopicSnapshots     = "snapshots"           // 기간 내 토픽 스냅샷 (블록 높이 오름차순)
+	cursorLeaderboard        = "leaderboard"         // 참가자 리더보드 기록 (블록 높이 오름차순)
+	cursorCompetitions       = "competitions"        // 경쟁 목록 (경쟁 ID 오름차순)
+	cursorCompetitionHistory = "competition_history" // 경쟁 원본 데이터 이력 (레코드 ID 오름차순)
+)
+
+// PageQuery는 키 기반 페이지 조회 조건입니다
+// After가 비어 있지 않으면 정렬 키가 After 다음인 항목부터 조회하며, Limit이 0 이하면 전체를 조회합니다
+type PageQuery struct {
+	After string
+	Limit int
+}
+
+// Paged는 페이지 크기가 지정되었는지 반환합니다
+func (p PageQuery) Paged() bool {
+	return p.Limit > 0
+}
+
+// pageCursor는 커서에 인코딩하는 값입니다
+type pageCursor struct {
+	Kind  string `json:"k"`
+	After string `json:"a"`
+}
+
+// encodeCursor는 목록 종류와 마지막 키로 커서를 만듭니다
+func encodeCursor(kind, after string) string {
+	data, _ := json.Marshal(pageCursor{Kind: kind, After: after})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor는 커서에서 마지막 키를 꺼냅니다
+// 다른 목록의 커서이거나 키가 정수가 아니면 오류를 반환합니다
+func decodeCursor(kind, cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("Invalid cursor")
+	}
+	var decoded pageCursor
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.After == "" {
+		return "", fmt.Errorf("Invalid cursor")
+	}
+	if decoded.Kind != kind {
+		return "", fmt.Errorf("Invalid cursor: cursor belongs to a different list")
+	}
+	if _, err := strconv.ParseInt(decoded.After, 10, 64); err != nil {
+		return "", fmt.Errorf("Invalid cursor")
+	}
+	return decoded.After, nil
+}
+
+// parsePageQuery는 limit, cursor 쿼리 파라미터를 해석합니다
+// defaultLimit이 0이면 limit과 cursor가 모두 없을 때 전체를 조회합니다 (페이지네이션 이전 응답과 호환)
+func parsePageQuery(r *http.Request, kind string, defaultLimit int) (PageQuery, error) {
+	query := r.URL.Query()
+	page := PageQuery{Limit: defaultLimit}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		if query.Get("offset") != "" {
+			return page, fmt.Errorf("cursor and offset cannot be used together")
+		}
+		after, err := decodeCursor(kind, cursor)
+		if err != nil {
+			return page, err
+		}
+		page.After = after
+		if page.Limit <= 0 {
+			page.Limit = defaultPageLimit
+		}
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return page, fmt.Errorf("Invalid limit parameter")
+		}
+		page.Limit = limit
+	}
+	if page.Limit > maxPageLimit {
+		page.Limit = maxPageLimit
+	}
+	return page, nil
+}
+
+// newPageInfo는 페이지 메타데이터를 만들고, 다음 페이지가 있으면 Link 헤더를 설정합니다
+// next는 다음 페이지가 시작할 마지막 키이며, 비어 있으면 마지막 페이지입니다
+func newPageInfo(w http.ResponseWriter, r *http.Request, kind string, page PageQuery, next string) PageInfo {
+	info := PageInfo{Limit: page.Limit}
+	if !page.Paged() {
+		info.Limit = 0
+		return info
+	}
+
+	links := []string{pageLink(r, page.Limit, "") + `; rel="first"`}
+	if next != "" {
+		cursor := encodeCursor(kind, next)
+		info.HasMore = true
+		info.NextCursor = &cursor
+		links = append(links, pageLink(r, page.Limit, cursor)+`; rel="next"`)
+	}
+	w.Header().Set("Link", strings.Join(links, ", "))
+	return info
+}
+
+// pageLink는 요청 URL에서 페이지 파라미터만 바꾼 Link 헤더 값을 만듭니다
+func pageLink(r *http.Request, limit int, cursor string) string {
+	query := requestQuery(r)
+	query.Del("offset")
+	query.Del("cursor")
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	return "<" + r.URL.Path + "?" + query.Encode() + ">"
+}
+
+// originalQueryKey는 라우터가 경로 와일드카드를 쿼리로 옮기기 전의 쿼리를 담는 컨텍스트 키입니다
+type originalQueryKey struct{}
+
+// withOriginalQuery는 요청의 원래 쿼리 문자열을 컨텍스트에 저장합니다
+func withOriginalQuery(r *http.Request) context.Context {
+	return context.WithValue(r.Context(), originalQueryKey{}, r.URL.RawQuery)
+}
+
+// requestQuery는 클라이언트가 보낸 원래 쿼리 파라미터를 반환합니다
+func requestQuery(r *http.Request) url.Values {
+	if raw, ok := r.Context().Value(originalQueryKey{}).(string); ok {
+		query, _ := url.ParseQuery(raw)
+		return query
+	}
+	return r.URL.Query()
+}
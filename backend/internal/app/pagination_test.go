@@ -0,0 +1,161 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	if after, err := decodeCursor(cursorTopicHeights, encodeCursor(cursorTopicHeights, "120")); err != nil || after != "120" {
+		t.Errorf("커서 왕복 = %q (%v), want 120", after, err)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"base64가 아님", "bogus!"},
+		{"JSON이 아님", "Ym9ndXM"},
+		{"다른 목록의 커서", encodeCursor(cursorCompetitions, "1")},
+		{"정수가 아닌 키", encodeCursor(cursorTopicHeights, "abc")},
+		{"빈 키", encodeCursor(cursorTopicHeights, "")},
+	}
+	for _, tt := range tests {
+		if after, err := decodeCursor(cursorTopicHeights, tt.cursor); err == nil {
+			t.Errorf("%s: 커서 %q를 %q로 해석했습니다", tt.name, tt.cursor, after)
+		}
+	}
+}
+
+func TestParsePageQuery(t *testing.T) {
+	cursor := encodeCursor(cursorTopicHeights, "100")
+	tests := []struct {
+		query        string
+		defaultLimit int
+		want         PageQuery
+		wantErr      bool
+	}{
+		{"", 0, PageQuery{}, false},
+		{"", defaultPageLimit, PageQuery{Limit: defaultPageLimit}, false},
+		{"limit=5", 0, PageQuery{Limit: 5}, false},
+		{"limit=5000", 0, PageQuery{Limit: maxPageLimit}, false},
+		{"cursor=" + cursor, 0, PageQuery{After: "100", Limit: defaultPageLimit}, false},
+		{"cursor=" + cursor + "&limit=2", 0, PageQuery{After: "100", Limit: 2}, false},
+		{"limit=0", 0, PageQuery{}, true},
+		{"limit=abc", 0, PageQuery{}, true},
+		{"cursor=" + cursor + "&offset=10", 0, PageQuery{}, true},
+	}
+	for _, tt := range tests {
+		page, err := parsePageQuery(httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil), cursorTopicHeights, tt.defaultLimit)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: 오류가 없습니다 (%+v)", tt.query, page)
+			}
+			continue
+		}
+		if err != nil || page != tt.want {
+			t.Errorf("%q (기본 %d) = %+v (%v), want %+v", tt.query, tt.defaultLimit, page, err, tt.want)
+		}
+	}
+}
+
+func TestPageCompetitions(t *testing.T) {
+	competitions := []CompetitionV2{{ID: 1}, {ID: 3}, {ID: 4}, {ID: 7}}
+	ids := func(list []CompetitionV2) []int {
+		var result []int
+		for _, comp := range list {
+			result = append(result, comp.ID)
+		}
+		return result
+	}
+
+	if all, next := pageCompetitions(competitions, PageQuery{}); len(all) != 4 || next != "" {
+		t.Errorf("페이지 없이 조회 = %v, %q; want 전체, 다음 없음", ids(all), next)
+	}
+	first, next := pageCompetitions(competitions, PageQuery{Limit: 2})
+	if !reflect.DeepEqual(ids(first), []int{1, 3}) || next != "3" {
+		t.Errorf("첫 페이지 = %v, 다음 %q; want [1 3], 3", ids(first), next)
+	}
+	last, next := pageCompetitions(competitions, PageQuery{After: "3", Limit: 2})
+	if !reflect.DeepEqual(ids(last), []int{4, 7}) || next != "" {
+		t.Errorf("마지막 페이지 = %v, 다음 %q; want [4 7], 없음", ids(last), next)
+	}
+}
+
+func TestTopicHeightsCursorPagination(t *testing.T) {
+	db := newTestDatabase(t)
+	_, mux := newTestService(t, db)
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(3)
+	for i := 0; i < 5; i++ {
+		if err := db.SaveTopicInference(testSnapshot("1", 100+i*10, base.Add(time.Duration(i)*time.Minute), workers, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Link 헤더의 next를 따라가며 모든 페이지를 조회 (다른 쿼리 파라미터는 유지)
+	var heights []string
+	target := "/api/v1/topics/1/heights?limit=2&extra=kept"
+	for pages := 0; target != ""; pages++ {
+		if pages > 5 {
+			t.Fatal("다음 페이지가 끝나지 않습니다")
+		}
+		var response TopicBlockHeightsResponse
+		recorder := getJSON(t, mux, target, http.StatusOK, &response)
+		heights = append(heights, response.Data.Heights...)
+
+		link := recorder.Header().Get("Link")
+		if !strings.Contains(link, `rel="first"`) {
+			t.Errorf("Link 헤더에 first가 없습니다: %q", link)
+		}
+		target = nextPageLink(link)
+		if response.Page.Limit != 2 || response.Page.HasMore != (target != "") || (response.Page.NextCursor != nil) != response.Page.HasMore {
+			t.Errorf("페이지 정보 = %+v, 다음 링크 %q", response.Page, target)
+		}
+		if target == "" {
+			continue
+		}
+		next, err := url.Parse(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query := next.Query(); query.Get("extra") != "kept" || query.Get("cursor") != *response.Page.NextCursor || query.Get("limit") != "2" {
+			t.Errorf("다음 링크 쿼리 = %v", query)
+		}
+
+		// 페이지 사이에 저장된 더 높은 블록은 다음 페이지에 끼어들지 않음
+		if pages == 0 {
+			if err := db.SaveTopicInference(testSnapshot("1", 500, time.Now(), workers, 9)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if want := []string{"140", "130", "120", "110", "100"}; !reflect.DeepEqual(heights, want) {
+		t.Errorf("페이지를 이어 붙인 블록 높이 = %v, want %v", heights, want)
+	}
+
+	// 잘못된 커서와 다른 목록의 커서는 400
+	for _, cursor := range []string{"bogus", encodeCursor(cursorCompetitions, "1")} {
+		recorder := serveTest(mux, http.MethodGet, "/api/v1/topics/1/heights?cursor="+url.QueryEscape(cursor), "")
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("커서 %q 상태 코드 = %d, want 400", cursor, recorder.Code)
+			continue
+		}
+		if response := decodeErrorResponse(t, recorder); response.Error.Code != ErrCodeInvalidParameter {
+			t.Errorf("커서 %q 오류 코드 = %s, want %s", cursor, response.Error.Code, ErrCodeInvalidParameter)
+		}
+	}
+
+	// 마지막 키 다음부터 조회
+	var response TopicBlockHeightsResponse
+	getJSON(t, mux, "/api/v1/topics/1/heights?limit=10&cursor="+encodeCursor(cursorTopicHeights, strconv.Itoa(130)), http.StatusOK, &response)
+	if want := []string{"120", "110", "100"}; !reflect.DeepEqual(response.Data.Heights, want) || response.Page.HasMore {
+		t.Errorf("커서 130 다음 = %v (더 있음 %v), want %v", response.Data.Heights, response.Page.HasMore, want)
+	}
+}
@@ -0,0 +1,365 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// REST API 라우터
+// /api/v1 아래의 리소스 경로(예: /api/v1/topics/{id}/inferences/{height})를 Go 1.22 패턴 라우팅으로 등록하고,
+// 프론트엔드가 사용하는 쿼리 파라미터 방식의 이전 경로(/api/topics/inference?topic_id=&height=)는 같은 핸들러의 별칭으로 유지합니다
+// 메서드 검사는 패턴의 메서드로, 파라미터 검사는 routeParam 선언으로 라우터에서 한 번만 처리합니다
+
+// apiVersionPrefix는 리소스 경로의 접두사입니다
+const apiVersionPrefix = "/api/v1"
+
+// 파라미터 형식
+const (
+	paramInteger  = "integer"  // 0 이상의 정수 (토픽 ID, 블록 높이, 경쟁 ID)
+	paramAddress  = "address"  // 워커/참가자 주소 (영문 소문자와 숫자)
+	paramString   = "string"   // 형식 제한 없음
+	paramBoolean  = "boolean"  // true 또는 false
+	paramTime     = "time"     // RFC3339 시각 (핸들러는 queryTime으로 해석)
+	paramDuration = "duration" // 0보다 큰 Go duration (핸들러는 queryDuration으로 해석)
+)
+
+// paramPatterns는 형식별 허용 값입니다
+var paramPatterns = map[string]*regexp.Regexp{
+	paramInteger: regexp.MustCompile(`^[0-9]+$`),
+	paramAddress: regexp.MustCompile(`^[a-z0-9]{1,128}$`),
+	paramBoolean: regexp.MustCompile(`^(true|false)$`),
+}
+
+// paramValidators는 정규식으로 표현할 수 없는 형식의 검사 함수입니다
+var paramValidators = map[string]func(string) bool{
+	paramTime: func(value string) bool {
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	},
+	paramDuration: func(value string) bool {
+		duration, err := time.ParseDuration(value)
+		return err == nil && duration > 0
+	},
+}
+
+// routeParam은 라우트가 받는 파라미터 하나입니다
+// 핸들러는 항상 name 쿼리 파라미터로 값을 읽으며, path가 있으면 /api/v1 경로에서는 해당 경로 와일드카드 값을 name으로 옮깁니다
+type routeParam struct {
+	name     string   // 핸들러가 읽는 쿼리 파라미터 이름
+	path     string   // /api/v1 경로의 와일드카드 이름 (비어 있으면 두 경로 모두 쿼리 파라미터)
+	kind     string   // 값 형식
+	required bool     // 이전 경로에서 필수 여부 (경로 와일드카드는 항상 필수)
+	enum     []string // 허용 값 (문서화용, 검사는 핸들러가 함)
+}
+
+// apiRoute는 API 엔드포인트 하나입니다
+type apiRoute struct {
+	methods     []string         // 허용 메서드
+	path        string           // /api/v1 이후의 리소스 경로 (비어 있으면 이전 경로만 등록)
+	legacy      string           // 이전 경로 (비어 있으면 리소스 경로만 등록)
+	params      []routeParam     // 검사할 파라미터
+	handler     http.HandlerFunc // 처리 핸들러
+	summary     string           // 문서 요약
+	request     interface{}      // JSON 요청 본문의 타입 (OpenAPI 스키마 생성용 영값)
+	response    interface{}      // JSON 응답 본문의 타입 (OpenAPI 스키마 생성용 영값)
+	produces    []string         // JSON이 아닌 응답의 콘텐츠 타입
+	status      int              // 성공 상태 코드 (0이면 200)
+	conditional bool             // ETag/If-None-Match로 304 응답 가능
+}
+
+// apiRoutes는 서비스가 제공하는 API 엔드포인트 목록입니다
+func (s *Service) apiRoutes() []apiRoute {
+	topicID := routeParam{name: "topic_id", path: "id", kind: paramInteger, required: true}
+	competitionID := routeParam{name: "competition_id", path: "id", kind: paramInteger, required: true}
+	start := routeParam{name: "start", kind: paramTime}
+	end := routeParam{name: "end", kind: paramTime}
+	limit := routeParam{name: "limit", kind: paramInteger}
+	cursor := routeParam{name: "cursor", kind: paramString} // 이전 응답의 next_cursor
+	tier := routeParam{name: "tier", kind: paramString, enum: []string{SeriesTierAuto, SeriesTierRaw, SeriesTierHourly, SeriesTierDaily}}
+
+	return []apiRoute{
+		{methods: []string{http.MethodGet}, path: "/health", legacy: "/api/health",
+			summary: "서버와 수집기 상태", response: HealthResponse{},
+			handler: s.HandleHealth},
+
+		// 경쟁
+		{methods: []string{http.MethodGet}, path: "/competitions", legacy: "/api/competitions/v2",
+			params: []routeParam{
+				{name: "active", kind: paramBoolean},
+				{name: "lifecycle", kind: paramString},
+				limit, cursor,
+			},
+			summary: "경쟁 목록 (active, lifecycle 필터)", response: []CompetitionV2{},
+			handler: s.HandleGetCompetitionsV2},
+		{methods: []string{http.MethodGet}, path: "/competitions/latest", legacy: "/api/competitions",
+			summary: "최신 경쟁 원본 데이터", response: CompetitionsResponse{}, conditional: true,
+			handler: s.HandleGetCompetitions},
+		{methods: []string{http.MethodGet}, path: "/competitions/history", legacy: "/api/competitions/range",
+			params:  []routeParam{start, end, tier, limit, cursor},
+			summary: "기간 내 경쟁 원본 데이터 이력 (hourly/daily 계층은 구간별 마지막 이력, 선택된 계층은 X-Series-Tier 헤더)", response: []CompetitionsResponse{},
+			handler: s.HandleGetCompetitionsByTimeRange},
+		{methods: []string{http.MethodGet}, path: "/competitions/{id}",
+			params:  []routeParam{competitionID},
+			summary: "경쟁 정보", response: CompetitionResponse{},
+			handler: s.HandleGetCompetition},
+		{methods: []string{http.MethodGet}, path: "/competitions/{id}/changes", legacy: "/api/competitions/changes",
+			params:  []routeParam{{name: "competition_id", path: "id", kind: paramInteger}, start, end},
+			summary: "경쟁 필드 변경 이력", response: CompetitionChangesResponse{},
+			handler: s.HandleGetCompetitionChanges},
+		{methods: []string{http.MethodGet}, path: "/competitions/{id}/lifecycle", legacy: "/api/competitions/lifecycle",
+			params:  []routeParam{{name: "competition_id", path: "id", kind: paramInteger}},
+			summary: "경쟁 라이프사이클 전이 기록", response: CompetitionLifecycleResponse{},
+			handler: s.HandleGetCompetitionLifecycle},
+		{methods: []string{http.MethodGet}, path: "/competitions/{id}/leaderboard/movers", legacy: "/api/leaderboard/movers",
+			params:  []routeParam{competitionID, {name: "window", kind: paramDuration}, limit},
+			summary: "기간 동안 순위 변동이 큰 참가자", response: LeaderboardMoversResponse{},
+			handler: s.HandleGetLeaderboardMovers},
+		{methods: []string{http.MethodGet}, path: "/competitions/{id}/leaderboard/{address}", legacy: "/api/leaderboard/history",
+			params:  []routeParam{competitionID, {name: "address", path: "address", kind: paramAddress, required: true}, start, end, limit, cursor},
+			summary: "참가자 리더보드 시계열", response: LeaderboardHistoryResponse{},
+			handler: s.HandleGetLeaderboardHistory},
+
+		// 토픽
+		{methods: []string{http.MethodGet}, path: "/topics", legacy: "/api/topics/active",
+			summary: "활성 토픽 목록", response: ActiveTopicsResponse{},
+			handler: s.HandleGetActiveTopics},
+		{methods: []string{http.MethodGet}, path: "/topics/inferences", legacy: "/api/topics/inferences",
+			summary: "활성 토픽별 최신 스냅샷", response: AllTopicInferencesResponse{},
+			handler: s.HandleGetAllTopicInferences},
+		{methods: []string{http.MethodGet}, path: "/topics/{id}/inferences/latest", legacy: "/api/topics/inference",
+			params:  []routeParam{topicID, {name: "height", kind: paramInteger}},
+			summary: "토픽 최신 스냅샷 (이전 경로는 height로 특정 높이 조회)", response: TopicInferenceResponse{}, conditional: true,
+			handler: s.HandleGetTopicInference},
+		{methods: []string{http.MethodGet}, path: "/topics/{id}/inferences/{height}",
+			params:  []routeParam{topicID, {name: "height", path: "height", kind: paramInteger, required: true}},
+			summary: "블록 높이의 토픽 스냅샷", response: TopicInferenceResponse{}, conditional: true,
+			handler: s.HandleGetTopicInference},
+		{methods: []string{http.MethodGet}, path: "/topics/{id}/inferences", legacy: "/api/topics/range",
+			params: []routeParam{topicID,
+				{name: "start", kind: paramTime, required: true},
+				{name: "end", kind: paramTime, required: true},
+				tier, limit, cursor,
+			},
+			summary: "기간 내 토픽 스냅샷 (원본이 정리된 구간이나 긴 범위는 롤업 계층, 아카이브가 있으면 짧은 범위는 아카이브에서 조회)", response: TopicInferencesResponse{},
+			handler: s.HandleGetTopicInferencesByTimeRange},
+		{methods: []string{http.MethodGet}, path: "/topics/{id}/heights", legacy: "/api/topics/heights",
+			params:  []routeParam{topicID, limit, {name: "offset", kind: paramInteger}, cursor},
+			summary: "토픽 블록 높이 목록", response: TopicBlockHeightsResponse{},
+			handler: s.HandleGetTopicBlockHeights},
+		{methods: []string{http.MethodGet}, path: "/topics/{id}/series", legacy: "/api/topics/series",
+			params: []routeParam{topicID, start, end, tier,
+				{name: "workers", kind: paramBoolean},
+			},
+			summary: "토픽 지표 시계열", response: TopicSeriesResponse{},
+			handler: s.HandleGetTopicSeries},
+		{methods: []string{http.MethodGet}, path: "/topics/{id}/stats", legacy: "/api/topics/stats",
+			params:  []routeParam{topicID},
+			summary: "토픽 저장 통계", response: TopicStats{},
+			handler: s.HandleGetTopicStats},
+		{methods: []string{http.MethodGet}, path: "/topics/{id}/competition",
+			params:  []routeParam{topicID},
+			summary: "토픽의 경쟁 정보", response: CompetitionResponse{},
+			handler: s.HandleGetCompetitionByTopicID},
+
+		// 워커
+		{methods: []string{http.MethodGet}, path: "/workers/{address}",
+			params:  []routeParam{{name: "address", path: "address", kind: paramAddress, required: true}},
+			summary: "워커의 토픽별 최신 추론 값과 리더보드 기록", response: WorkerResponse{},
+			handler: s.HandleGetWorker},
+
+		// 조회/내보내기
+		{methods: []string{http.MethodGet}, path: "/search", legacy: "/api/search",
+			params: []routeParam{
+				{name: "q", kind: paramString, required: true},
+				{name: "type", kind: paramString},
+				limit,
+			},
+			summary: "경쟁, 토픽, 워커 전문 검색", response: SearchResponse{},
+			handler: s.HandleSearch},
+		{methods: []string{http.MethodGet}, path: "/export", legacy: "/api/export",
+			params: []routeParam{
+				{name: "dataset", kind: paramString, enum: []string{ExportDatasetSnapshots, ExportDatasetWorkers, ExportDatasetLeaderboard, ExportDatasetCompetitions}},
+				{name: "format", kind: paramString, enum: []string{ExportFormatNDJSON, ExportFormatCSV, ExportFormatParquet}},
+				{name: "topic_id", kind: paramInteger},
+				start, end,
+				{name: "min_height", kind: paramInteger},
+				{name: "max_height", kind: paramInteger},
+				{name: "columns", kind: paramString},
+				{name: "gzip", kind: paramBoolean},
+			},
+			summary:  "데이터 내보내기 (스트리밍)",
+			produces: []string{"application/x-ndjson", "text/csv", "application/vnd.apache.parquet", "application/gzip"},
+			handler:  s.HandleExport},
+		{methods: []string{http.MethodGet}, path: "/stream", legacy: "/api/stream",
+			params: []routeParam{
+				{name: "topic_id", kind: paramString},
+				{name: "types", kind: paramString},
+				{name: "last_event_id", kind: paramInteger},
+			},
+			summary:  "실시간 수집 이벤트 스트림 (SSE, topic_id와 types는 쉼표 구분)",
+			produces: []string{"text/event-stream"},
+			handler:  s.HandleStream},
+		{methods: []string{http.MethodGet}, path: "/ws", legacy: "/api/ws",
+			summary: "WebSocket 채널 구독 (topic:<id>, worker:<address>, competition:<id>)", status: http.StatusSwitchingProtocols,
+			handler: s.HandleWebSocket},
+		{methods: []string{http.MethodGet}, path: "/graphql", legacy: "/api/graphql",
+			params: []routeParam{
+				{name: "query", kind: paramString, required: true},
+				{name: "variables", kind: paramString},
+				{name: "operationName", kind: paramString},
+			},
+			summary: "GraphQL 조회 (경쟁, 토픽, 스냅샷, 워커 추론, 리더보드)", response: GraphQLResponse{},
+			handler: s.HandleGraphQL},
+		{methods: []string{http.MethodPost}, path: "/graphql", legacy: "/api/graphql",
+			summary: "GraphQL 조회 (JSON 본문)", request: GraphQLRequest{}, response: GraphQLResponse{},
+			handler: s.HandleGraphQL},
+		{methods: []string{http.MethodGet}, path: "/stats", legacy: "/api/stats",
+			summary: "데이터베이스 통계", response: DatabaseStats{},
+			handler: s.HandleGetDatabaseStats},
+
+		// 관리자
+		{methods: []string{http.MethodGet}, path: "/admin/backups", legacy: "/api/admin/backups",
+			summary: "백업 목록", response: BackupListResponse{},
+			handler: s.HandleBackups},
+		{methods: []string{http.MethodPost}, path: "/admin/backups", legacy: "/api/admin/backups",
+			summary: "백업 생성", response: BackupCreateResponse{}, status: http.StatusCreated,
+			handler: s.HandleBackups},
+		{methods: []string{http.MethodGet}, path: "/admin/archive", legacy: "/api/admin/archive",
+			summary: "Parquet 아카이브 매니페스트", response: ArchiveManifestResponse{},
+			handler: s.HandleGetArchiveManifest},
+		{methods: []string{http.MethodGet}, path: "/admin/integrity", legacy: "/api/admin/integrity",
+			summary: "마지막 무결성 검사 보고서", response: IntegrityResponse{},
+			handler: s.HandleIntegrity},
+		{methods: []string{http.MethodPost}, path: "/admin/integrity", legacy: "/api/admin/integrity",
+			summary: "무결성 검사 실행 (보고만 하며 격리와 정리는 check 명령으로 실행)", response: IntegrityResponse{},
+			handler: s.HandleIntegrity},
+	}
+}
+
+// RegisterRoutes는 API 엔드포인트를 /api/v1 리소스 경로와 이전 경로에 등록하고, API 문서 경로를 등록합니다
+// 허용되지 않은 메서드는 Allow 헤더와 함께 405로, 등록되지 않은 /api/ 경로는 404로 오류 응답을 보냅니다
+func (s *Service) RegisterRoutes(mux *http.ServeMux) {
+	allowed := make(map[string][]string) // 패턴 경로별 허용 메서드
+	var paths []string
+	handle := func(method, path string, handler http.Handler) {
+		if _, ok := allowed[path]; !ok {
+			paths = append(paths, path)
+		}
+		allowed[path] = append(allowed[path], method)
+		mux.Handle(method+" "+path, s.metrics.instrumentHandler(path, handler))
+	}
+
+	for _, route := range s.apiRoutes() {
+		for _, method := range route.methods {
+			if route.path != "" {
+				handle(method, apiVersionPrefix+route.path, withRouteParams(route.params, true, route.handler))
+			}
+			if route.legacy != "" {
+				handle(method, route.legacy, withRouteParams(route.params, false, route.handler))
+			}
+		}
+	}
+
+	// API 문서
+	handle(http.MethodGet, "/api/openapi.json", http.HandlerFunc(s.HandleOpenAPI))
+	handle(http.MethodGet, "/api/docs", http.HandlerFunc(s.HandleAPIDocs))
+
+	// Prometheus 지표
+	handle(http.MethodGet, "/metrics", http.HandlerFunc(s.HandleMetrics))
+
+	// 메서드 없는 패턴은 메서드가 있는 다른 경로 패턴과 충돌할 수 있으므로 나머지 메서드를 각각 등록
+	for _, path := range paths {
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			if !containsString(allowed[path], method) {
+				mux.Handle(method+" "+path, s.metrics.instrumentHandler(path, methodNotAllowed(allowed[path])))
+			}
+		}
+	}
+	mux.Handle("/api/", s.metrics.instrumentHandler("/api/", http.HandlerFunc(notFound)))
+}
+
+// withRouteParams는 파라미터를 검사한 뒤 핸들러를 호출하는 미들웨어입니다
+// versioned가 true이면 경로 와일드카드 값을 쿼리 파라미터로 옮겨 이전 경로와 같은 핸들러가 처리하도록 합니다
+func withRouteParams(params []routeParam, versioned bool, next http.HandlerFunc) http.Handler {
+	if len(params) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		for _, param := range params {
+			if versioned && param.path != "" {
+				query.Set(param.name, r.PathValue(param.path))
+			}
+
+			value := query.Get(param.name)
+			if value == "" {
+				if param.required || (versioned && param.path != "") {
+					writeError(w, http.StatusBadRequest, ErrCodeMissingParameter, fmt.Sprintf("Missing %s parameter", param.name))
+					return
+				}
+				continue
+			}
+			if !validParamValue(param.kind, value) {
+				writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, fmt.Sprintf("Invalid %s parameter: must be %s", param.name, paramKindDescription(param.kind)))
+				return
+			}
+		}
+
+		if versioned {
+			// 원본 요청을 바꾸지 않도록 복사본에 쿼리를 설정 (페이지 링크는 원래 쿼리로 만듦)
+			r = r.Clone(withOriginalQuery(r))
+			r.URL.RawQuery = query.Encode()
+		}
+		next(w, r)
+	})
+}
+
+// validParamValue는 값이 파라미터 형식에 맞는지 확인합니다 (검사가 없는 형식은 항상 허용)
+func validParamValue(kind, value string) bool {
+	if pattern, ok := paramPatterns[kind]; ok {
+		return pattern.MatchString(value)
+	}
+	if validate, ok := paramValidators[kind]; ok {
+		return validate(value)
+	}
+	return true
+}
+
+// paramKindDescription은 오류 메시지에 쓰는 형식 설명입니다
+func paramKindDescription(kind string) string {
+	switch kind {
+	case paramInteger:
+		return "a non-negative integer"
+	case paramAddress:
+		return "a lowercase alphanumeric address"
+	case paramBoolean:
+		return "true or false"
+	case paramTime:
+		return "an RFC3339 time"
+	case paramDuration:
+		return "a positive duration such as 24h"
+	default:
+		return strings.ToLower(kind)
+	}
+}
+
+// queryTime은 라우터가 검사한 paramTime 쿼리 파라미터를 해석합니다 (값이 없으면 fallback)
+func queryTime(r *http.Request, name string, fallback time.Time) time.Time {
+	parsed, err := time.Parse(time.RFC3339, r.URL.Query().Get(name))
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+// queryDuration은 라우터가 검사한 paramDuration 쿼리 파라미터를 해석합니다 (값이 없으면 fallback)
+func queryDuration(r *http.Request, name string, fallback time.Duration) time.Duration {
+	parsed, err := time.ParseDuration(r.URL.Query().Get(name))
+	if err != nil || parsed <= 0 {
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouteParamsAliasPathToQuery(t *testing.T) {
+	params := []routeParam{
+		{name: "topic_id", path: "id", kind: paramInteger, required: true},
+		{name: "start", kind: paramTime},
+	}
+	var got []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Query().Get("topic_id")+" "+r.URL.Query().Get("start"))
+		w.WriteHeader(http.StatusNoContent)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/topics/{id}/stats", withRouteParams(params, true, handler))
+	mux.Handle("GET /api/topics/stats", withRouteParams(params, false, handler))
+
+	// 경로 와일드카드 값이 같은 이름의 쿼리 파라미터보다 우선
+	for _, target := range []string{
+		"/api/v1/topics/7/stats?start=2024-01-01T00:00:00Z",
+		"/api/v1/topics/7/stats?topic_id=9&start=2024-01-01T00:00:00Z",
+		"/api/topics/stats?topic_id=7&start=2024-01-01T00:00:00Z",
+	} {
+		if recorder := serveTest(mux, http.MethodGet, target, ""); recorder.Code != http.StatusNoContent {
+			t.Errorf("%s 상태 코드 = %d, want 204: %s", target, recorder.Code, recorder.Body.String())
+		}
+	}
+	for i, value := range got {
+		if value != "7 2024-01-01T00:00:00Z" {
+			t.Errorf("요청 %d의 핸들러 쿼리 = %q, want topic_id 7과 start", i, value)
+		}
+	}
+	if len(got) != 3 {
+		t.Errorf("핸들러 호출 %d번, want 3", len(got))
+	}
+}
+
+func TestRouteParamsRejectInvalidValues(t *testing.T) {
+	env := newConformanceEnv(t)
+
+	tests := []struct {
+		target string
+		param  string
+		code   ErrorCode
+	}{
+		{"/api/v1/topics/abc/stats", "topic_id", ErrCodeInvalidParameter},
+		{"/api/topics/stats", "topic_id", ErrCodeMissingParameter},
+		{"/api/v1/workers/Allo1Upper", "address", ErrCodeInvalidParameter},
+		{"/api/v1/competitions/history?start=yesterday", "start", ErrCodeInvalidParameter},
+		{"/api/v1/competitions/1/changes?end=2024-01-01", "end", ErrCodeInvalidParameter},
+		{"/api/v1/competitions/1/leaderboard/allo1testworker1?start=1700000000", "start", ErrCodeInvalidParameter},
+		{"/api/v1/topics/1/inferences?start=2024-01-01T00:00:00Z", "end", ErrCodeMissingParameter},
+		{"/api/v1/topics/1/series?end=now", "end", ErrCodeInvalidParameter},
+		{"/api/v1/export?start=2024-13-01T00:00:00Z", "start", ErrCodeInvalidParameter},
+		{"/api/v1/competitions/1/leaderboard/movers?window=1d", "window", ErrCodeInvalidParameter},
+		{"/api/v1/competitions/1/leaderboard/movers?window=-1h", "window", ErrCodeInvalidParameter},
+		{"/api/v1/competitions?active=yes", "active", ErrCodeInvalidParameter},
+		{"/api/v1/topics/1/series?workers=1", "workers", ErrCodeInvalidParameter},
+		{"/api/v1/export?gzip=TRUE", "gzip", ErrCodeInvalidParameter},
+	}
+	for _, tt := range tests {
+		recorder := serveTest(env.mux, http.MethodGet, tt.target, "")
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s 상태 코드 = %d, want 400", tt.target, recorder.Code)
+			continue
+		}
+		response := decodeErrorResponse(t, recorder)
+		prefix := "Invalid " + tt.param + " parameter: must be "
+		if tt.code == ErrCodeMissingParameter {
+			prefix = "Missing " + tt.param + " parameter"
+		}
+		if response.Error.Code != tt.code || !strings.HasPrefix(response.Error.Message, prefix) {
+			t.Errorf("%s 오류 = %+v, want %s %q", tt.target, response.Error, tt.code, prefix)
+		}
+	}
+
+	// 형식에 맞는 값은 핸들러까지 전달
+	for _, target := range []string{
+		"/api/v1/competitions/history?start=2024-01-01T00:00:00Z&end=2024-01-02T00:00:00%2B09:00",
+		"/api/v1/competitions/1/leaderboard/movers?window=90m",
+		"/api/v1/competitions?active=false",
+	} {
+		if recorder := serveTest(env.mux, http.MethodGet, target, ""); recorder.Code != http.StatusOK {
+			t.Errorf("%s 상태 코드 = %d, want 200: %s", target, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestRoutesRejectWrongMethod(t *testing.T) {
+	env := newConformanceEnv(t)
+
+	tests := []struct {
+		method string
+		target string
+		allow  string
+	}{
+		{http.MethodDelete, "/api/v1/topics/1/stats", "GET, HEAD"},
+		{http.MethodPost, "/api/v1/competitions/1/changes", "GET, HEAD"},
+		{http.MethodPut, "/api/topics/range", "GET, HEAD"},
+		{http.MethodPatch, "/api/v1/graphql", "GET, HEAD, POST"},
+		{http.MethodDelete, "/api/v1/admin/backups", "GET, HEAD, POST"},
+		{http.MethodPost, "/metrics", "GET, HEAD"},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+		env.mux.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s %s 상태 코드 = %d, want 405", tt.method, tt.target, recorder.Code)
+			continue
+		}
+		if allow := recorder.Header().Get("Allow"); allow != tt.allow {
+			t.Errorf("%s %s Allow = %q, want %q", tt.method, tt.target, allow, tt.allow)
+		}
+		if response := decodeErrorResponse(t, recorder); response.Error.Code != ErrCodeMethodNotAllowed {
+			t.Errorf("%s %s 오류 코드 = %s, want %s", tt.method, tt.target, response.Error.Code, ErrCodeMethodNotAllowed)
+		}
+	}
+}
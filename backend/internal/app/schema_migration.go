@@ -0,0 +1,260 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// topicInferenceIndexes는 topic_inferences 테이블의 일반 인덱스입니다
+// 테이블을 다시 만드는 마이그레이션에서도 같은 인덱스를 재생성합니다
+var topicInferenceIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_topic_inferences_topic_id ON topic_inferences(topic_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_topic_inferences_timestamp ON topic_inferences(timestamp)`,
+	`CREATE INDEX IF NOT EXISTS idx_topic_inferences_keyframe_id ON topic_inferences(keyframe_id)`,
+}
+
+// topicInferenceContentHashIndexSQL은 본문 해시 인덱스입니다 (본문 저장소 마이그레이션에서 생성)
+const topicInferenceContentHashIndexSQL = `CREATE INDEX IF NOT EXISTS idx_topic_inferences_content_hash ON topic_inferences(content_hash)`
+
+// topicInferenceUniqueIndexSQL은 토픽/블록 높이 유일 인덱스입니다
+const topicInferenceUniqueIndexSQL = `CREATE UNIQUE INDEX IF NOT EXISTS idx_topic_inferences_topic_height ON topic_inferences(topic_id, inference_block_height)`
+
+// schemaMigrations는 PRAGMA user_version 순서대로 적용되는 스키마 마이그레이션 목록입니다
+// i번째 마이그레이션을 적용하면 user_version이 i+1이 됩니다
+var schemaMigrations = []func(d *Database, tx *sql.Tx) error{
+	(*Database).migrateIntegerBlockHeights,
+	(*Database).migrateSearchIndex,
+	(*Database).migrateContentHash,
+	(*Database).migrateCompetitionChangesUTC,
+}
+
+// migrateSchema는 user_version보다 새로운 마이그레이션을 각각 하나의 트랜잭션으로 적용합니다
+func (d *Database) migrateSchema() error {
+	var version int
+	if err := d.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("스키마 버전 조회 실패: %w", err)
+	}
+
+	for i := version; i < len(schemaMigrations); i++ {
+		startTime := time.Now()
+
+		tx, err := d.db.Begin()
+		if err != nil {
+			return fmt.Errorf("트랜잭션 시작 실패: %w", err)
+		}
+		if err := schemaMigrations[i](d, tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("스키마 버전 %d 마이그레이션 실패: %w", i+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("스키마 버전 기록 실패: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+		}
+
+		log.Printf("스키마 버전 %d 마이그레이션 완료: 소요 시간=%v", i+1, time.Since(startTime))
+	}
+
+	return nil
+}
+
+// migrateIntegerBlockHeights는 topic_inferences의 블록 높이 컬럼을 INTEGER로 변환하고
+// (topic_id, inference_block_height) 유일 인덱스를 생성합니다
+// 정수가 아닌 블록 높이를 가진 행과 중복 행(loss_block_height가 가장 높은 행만 유지)은 quarantined_rows로 옮깁니다
+func (d *Database) migrateIntegerBlockHeights(tx *sql.Tx) error {
+	columnType, err := columnDeclaredType(tx, "topic_inferences", "inference_block_height")
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(columnType, "INTEGER") {
+		if err := d.quarantineInvalidBlockHeights(tx); err != nil {
+			return err
+		}
+
+		statements := []string{
+			`CREATE TABLE topic_inferences_migrated (
+				id INTEGER PRIMARY KEY,
+				topic_id TEXT NOT NULL,
+				timestamp TEXT NOT NULL,
+				inference_block_height INTEGER NOT NULL,
+				loss_block_height INTEGER NOT NULL,
+				data BLOB NOT NULL,
+				encoding TEXT NOT NULL DEFAULT 'full',
+				keyframe_id INTEGER
+			)`,
+			`INSERT INTO topic_inferences_migrated (id, topic_id, timestamp, inference_block_height, loss_block_height, data, encoding, keyframe_id)
+				SELECT id, topic_id, timestamp, CAST(inference_block_height AS INTEGER), CAST(loss_block_height AS INTEGER), data, encoding, keyframe_id
+				FROM topic_inferences`,
+			`DROP TABLE topic_inferences`,
+			`ALTER TABLE topic_inferences_migrated RENAME TO topic_inferences`,
+		}
+		statements = append(statements, topicInferenceIndexes...)
+		for _, statement := range statements {
+			if _, err := tx.Exec(statement); err != nil {
+				return fmt.Errorf("topic_inferences 테이블 변환 실패: %w", err)
+			}
+		}
+	}
+
+	if _, err := tx.Exec(topicInferenceUniqueIndexSQL); err != nil {
+		return fmt.Errorf("토픽/블록 높이 유일 인덱스 생성 실패: %w", err)
+	}
+
+	return nil
+}
+
+// migrateContentHash는 topic_inferences와 competitions에 본문 저장소(snapshot_blobs)를 참조하는
+// content_hash 컬럼과 인덱스를 추가합니다
+// 기존 행은 본문을 그대로 두며, 조회할 때 해시를 계산하고 다시 저장될 때 본문 저장소로 옮겨집니다
+func (d *Database) migrateContentHash(tx *sql.Tx) error {
+	for _, table := range []string{"topic_inferences", "competitions"} {
+		if _, err := columnDeclaredType(tx, table, "content_hash"); err == nil {
+			continue
+		}
+		if _, err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN content_hash TEXT"); err != nil {
+			return fmt.Errorf("%s.content_hash 컬럼 추가 실패: %w", table, err)
+		}
+	}
+
+	statements := []string{
+		topicInferenceContentHashIndexSQL,
+		`CREATE INDEX IF NOT EXISTS idx_competitions_content_hash ON competitions(content_hash)`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("본문 해시 인덱스 생성 실패: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// migrateCompetitionChangesUTC는 로컬 시간대 오프셋으로 기록된 competition_changes.changed_at을 UTC로 바꿉니다
+// 변경 이력은 문자열 범위로 조회하므로 시간대가 섞여 있으면 범위 경계의 이력이 빠지거나 순서가 바뀝니다
+func (d *Database) migrateCompetitionChangesUTC(tx *sql.Tx) error {
+	result, err := tx.Exec(`
+		UPDATE competition_changes
+		SET changed_at = strftime('%Y-%m-%dT%H:%M:%SZ', changed_at)
+		WHERE changed_at NOT LIKE '%Z' AND strftime('%Y-%m-%dT%H:%M:%SZ', changed_at) IS NOT NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("경쟁 변경 이력 시각 변환 실패: %w", err)
+	}
+	if converted, _ := result.RowsAffected(); converted > 0 {
+		log.Printf("경쟁 변경 이력 %d개의 시각을 UTC로 변환했습니다", converted)
+	}
+	return nil
+}
+
+// quarantineInvalidBlockHeights는 정수로 변환할 수 없는 블록 높이와 중복 토픽/블록 높이 행을 격리합니다
+// 체인 재구성에 실패하면(복원할 수 없는 체인) 재구성 없이 격리하고, 남은 깨진 행은 무결성 검사에서 처리합니다
+func (d *Database) quarantineInvalidBlockHeights(tx *sql.Tx) error {
+	reasons := make(map[int64]string)
+
+	rows, err := tx.Query(`
+		SELECT id FROM topic_inferences
+		WHERE inference_block_height = '' OR inference_block_height GLOB '*[^0-9]*'
+			OR loss_block_height = '' OR loss_block_height GLOB '*[^0-9]*'
+	`)
+	if err != nil {
+		return fmt.Errorf("잘못된 블록 높이 조회 실패: %w", err)
+	}
+	if err := collectQuarantineIDs(rows, reasons, IntegrityIssueHeight); err != nil {
+		return err
+	}
+
+	rows, err = tx.Query(`
+		SELECT id FROM (
+			SELECT id, ROW_NUMBER() OVER (
+				PARTITION BY topic_id, CAST(inference_block_height AS INTEGER)
+				ORDER BY CAST(loss_block_height AS INTEGER) DESC, id DESC
+			) AS position
+			FROM topic_inferences
+			WHERE inference_block_height <> '' AND inference_block_height NOT GLOB '*[^0-9]*'
+		)
+		WHERE position > 1
+	`)
+	if err != nil {
+		return fmt.Errorf("중복 블록 높이 조회 실패: %w", err)
+	}
+	if err := collectQuarantineIDs(rows, reasons, IntegrityIssueDuplicate); err != nil {
+		return err
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(reasons))
+	for id := range reasons {
+		ids = append(ids, id)
+	}
+	quarantinedAt := time.Now().UTC().Format(time.RFC3339)
+
+	if _, err := tx.Exec("SAVEPOINT quarantine_heights"); err != nil {
+		return fmt.Errorf("세이브포인트 생성 실패: %w", err)
+	}
+	if err := d.quarantineRows(tx, tx, "topic_inferences", ids, reasons, quarantinedAt, true); err != nil {
+		log.Printf("블록 높이 마이그레이션: 체인 재구성 실패, 재구성 없이 격리합니다: %v", err)
+		if _, err := tx.Exec("ROLLBACK TO quarantine_heights"); err != nil {
+			return fmt.Errorf("세이브포인트 롤백 실패: %w", err)
+		}
+		if err := d.quarantineRows(tx, tx, "topic_inferences", ids, reasons, quarantinedAt, false); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("RELEASE quarantine_heights"); err != nil {
+		return fmt.Errorf("세이브포인트 해제 실패: %w", err)
+	}
+
+	log.Printf("블록 높이 마이그레이션: 잘못되었거나 중복된 행 %d개를 격리했습니다", len(ids))
+	return nil
+}
+
+// collectQuarantineIDs는 조회된 행 ID를 격리 사유와 함께 기록합니다
+func collectQuarantineIDs(rows *sql.Rows, reasons map[int64]string, reason string) error {
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("격리 대상 스캔 실패: %w", err)
+		}
+		if existing, ok := reasons[id]; ok {
+			reasons[id] = existing + "," + reason
+		} else {
+			reasons[id] = reason
+		}
+	}
+	return rows.Err()
+}
+
+// columnDeclaredType은 테이블 컬럼의 선언된 타입을 반환합니다
+func columnDeclaredType(q sqlQueryer, table, column string) (string, error) {
+	rows, err := q.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return "", fmt.Errorf("%s 테이블 정보 조회 실패: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			return "", fmt.Errorf("%s 테이블 정보 스캔 실패: %w", table, err)
+		}
+		if name == column {
+			return columnType, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s 테이블에 %s 컬럼이 없습니다", table, column)
+}
@@ -0,0 +1,203 @@
+package app
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+// legacySchema는 블록 높이 마이그레이션 이전 스키마입니다 (TEXT 블록 높이, 델타 컬럼만 추가됨, 본문 저장소 없음)
+var legacySchema = []string{
+	`CREATE TABLE topic_inferences (
+		id INTEGER PRIMARY KEY,
+		topic_id TEXT NOT NULL,
+		timestamp TEXT NOT NULL,
+		inference_block_height TEXT NOT NULL,
+		loss_block_height TEXT NOT NULL,
+		data BLOB NOT NULL,
+		encoding TEXT NOT NULL DEFAULT 'full',
+		keyframe_id INTEGER
+	)`,
+	`CREATE TABLE competitions (
+		id INTEGER PRIMARY KEY,
+		timestamp TEXT NOT NULL,
+		data BLOB NOT NULL
+	)`,
+}
+
+// legacyRow는 이전 스키마에 직접 저장할 스냅샷 행입니다
+type legacyRow struct {
+	id       int64
+	height   string
+	loss     string
+	keyframe int64 // 0이면 전체 스냅샷
+	record   map[string]interface{}
+}
+
+// createLegacyDatabase는 이전 스키마로 데이터베이스 파일을 만들고 행을 저장합니다
+// 델타 행은 같은 체인의 직전 행 대비 델타로 인코딩합니다
+func createLegacyDatabase(t *testing.T, path string, rows []legacyRow) {
+	t.Helper()
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, statement := range legacySchema {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("이전 스키마 생성 실패: %v", err)
+		}
+	}
+
+	prev := make(map[int64]map[string]interface{}) // 체인 ID -> 직전 스냅샷
+	for _, row := range rows {
+		state, _, err := normalizeSnapshot(row.record)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encoding, chainID, keyframe := snapshotEncodingFull, row.id, interface{}(nil)
+		var data []byte
+		if row.keyframe != 0 {
+			encoding, chainID, keyframe = snapshotEncodingDelta, row.keyframe, row.keyframe
+			if data, err = encodeDeltaBlob(diffSnapshots(prev[chainID], state)); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			jsonData, err := json.Marshal(state)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = snappy.Encode(nil, jsonData)
+		}
+		prev[chainID] = state
+
+		if _, err := db.Exec(
+			"INSERT INTO topic_inferences (id, topic_id, timestamp, inference_block_height, loss_block_height, data, encoding, keyframe_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			row.id, row.record["topic_id"], row.record["timestamp"], row.height, row.loss, data, encoding, keyframe,
+		); err != nil {
+			t.Fatalf("이전 스키마 행(ID=%d) 저장 실패: %v", row.id, err)
+		}
+	}
+}
+
+func TestMigrateIntegerBlockHeights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(4)
+	snapshot := func(topicID string, height, cycle int) map[string]interface{} {
+		return testSnapshot(topicID, height, base.Add(time.Duration(cycle)*time.Minute), workers, cycle)
+	}
+
+	// 토픽 1: 키프레임(1)과 델타(2, 3) 체인, 키프레임과 같은 높이이면서 loss가 더 높은 행(4), 정수가 아닌 높이(5)
+	// 토픽 2: 문자열 정렬과 정수 정렬이 다른 높이 (9 < 10)
+	rows := []legacyRow{
+		{id: 1, height: "100", loss: "90", record: snapshot("1", 100, 0)},
+		{id: 2, height: "110", loss: "100", keyframe: 1, record: snapshot("1", 110, 1)},
+		{id: 3, height: "120", loss: "110", keyframe: 1, record: snapshot("1", 120, 2)},
+		{id: 4, height: "100", loss: "95", record: snapshot("1", 100, 3)},
+		{id: 5, height: "abc", loss: "1", record: snapshot("1", 0, 4)},
+		{id: 6, height: "9", loss: "1", record: snapshot("2", 9, 5)},
+		{id: 7, height: "10", loss: "2", record: snapshot("2", 10, 6)},
+	}
+	createLegacyDatabase(t, path, rows)
+
+	db := openTestDatabase(t, path)
+
+	var version int
+	if err := db.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil || version != len(schemaMigrations) {
+		t.Fatalf("user_version = %d (%v), want %d", version, err, len(schemaMigrations))
+	}
+	for _, column := range []string{"inference_block_height", "loss_block_height"} {
+		if columnType, err := columnDeclaredType(db.db, "topic_inferences", column); err != nil || columnType != "INTEGER" {
+			t.Errorf("%s 타입 = %q (%v), want INTEGER", column, columnType, err)
+		}
+	}
+
+	// 중복 행 중 loss가 낮은 키프레임과 정수가 아닌 높이는 격리
+	quarantined := make(map[int64]string)
+	result, err := db.db.Query("SELECT source_id, reason FROM quarantined_rows WHERE source_table = 'topic_inferences'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for result.Next() {
+		var id int64
+		var reason string
+		if err := result.Scan(&id, &reason); err != nil {
+			t.Fatal(err)
+		}
+		quarantined[id] = reason
+	}
+	result.Close()
+	if want := map[int64]string{1: IntegrityIssueDuplicate, 5: IntegrityIssueHeight}; !reflect.DeepEqual(quarantined, want) {
+		t.Errorf("격리된 행 = %v, want %v", quarantined, want)
+	}
+
+	// 격리된 키프레임 뒤의 델타는 새 키프레임으로 재구성되어 그대로 복원
+	for _, row := range []legacyRow{rows[1], rows[2], rows[3], rows[5], rows[6]} {
+		topicID := row.record["topic_id"].(string)
+		state, encoding, _ := storedSnapshot(t, db, topicID, row.height)
+		if want := expectedSnapshot(t, row.record); !reflect.DeepEqual(state, want.state) {
+			t.Errorf("토픽 %s 높이 %s (%s) 복원 결과가 원본과 다릅니다", topicID, row.height, encoding)
+		}
+		if row.id == 2 && encoding != snapshotEncodingFull {
+			t.Errorf("재구성된 첫 행의 저장 방식 = %s, want full", encoding)
+		}
+	}
+
+	// 토픽/블록 높이 유일 인덱스
+	if _, err := db.db.Exec(
+		"INSERT INTO topic_inferences (topic_id, timestamp, inference_block_height, loss_block_height, data) VALUES ('1', ?, 110, 1, x'')",
+		base.Format(time.RFC3339),
+	); err == nil {
+		t.Error("같은 토픽/블록 높이 행을 추가했는데 오류가 없습니다")
+	}
+
+	// 블록 높이 탐색은 정수 순서
+	latest, err := db.GetLatestTopicInference("2")
+	if err != nil || latest == nil {
+		t.Fatalf("토픽 2 최신 스냅샷 조회 실패: %v", err)
+	}
+	if latest["inference_block_height"] != "10" || latest["prev_height"] != "9" {
+		t.Errorf("최신 스냅샷 높이 = %v, 이전 높이 = %v; want 10, 9", latest["inference_block_height"], latest["prev_height"])
+	}
+	heights, _, err := db.GetTopicBlockHeights("1", 10, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"120", "110", "100"}; !reflect.DeepEqual(heights.Heights, want) {
+		t.Errorf("토픽 1 블록 높이 = %v, want %v", heights.Heights, want)
+	}
+	byHeight, err := db.GetTopicInferenceByHeight("1", "110")
+	if err != nil || byHeight == nil {
+		t.Fatalf("높이 110 조회 실패: %v", err)
+	}
+	if byHeight["prev_height"] != "100" || byHeight["next_height"] != "120" {
+		t.Errorf("높이 110의 이전/다음 높이 = %v/%v, want 100/120", byHeight["prev_height"], byHeight["next_height"])
+	}
+
+	// 다시 열어도 마이그레이션을 반복하지 않음
+	db.Close()
+	reopened := openTestDatabase(t, path)
+	if count := countRows(t, reopened, "SELECT COUNT(*) FROM topic_inferences"); count != 5 {
+		t.Errorf("다시 연 뒤 스냅샷 수 = %d, want 5", count)
+	}
+	if count := countRows(t, reopened, "SELECT COUNT(*) FROM quarantined_rows"); count != len(quarantined) {
+		t.Errorf("다시 연 뒤 격리된 행 수 = %d, want %d", count, len(quarantined))
+	}
+}
+
+// countRows는 COUNT 쿼리 결과를 반환합니다
+func countRows(t testing.TB, db *Database, query string, args ...interface{}) int {
+	t.Helper()
+	var count int
+	if err := db.db.QueryRow(query, args...).Scan(&count); err != nil {
+		t.Fatalf("%s 실패: %v", query, err)
+	}
+	return count
+}
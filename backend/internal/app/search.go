@@ -0,0 +1,425 @@
+package app
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 검색 결과 유형
+const (
+	SearchKindCompetition = "competition"
+	SearchKindTopic       = "topic"
+	SearchKindWorker      = "worker"
+)
+
+// 검색 결과 기본/최대 개수
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchResult는 검색 결과 하나입니다
+// Score는 bm25 점수의 부호를 바꾼 값으로 클수록 관련도가 높습니다
+type SearchResult struct {
+	Kind    string  `json:"kind"`
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"`
+}
+
+// searchDocument는 검색 인덱스에 들어가는 문서 하나입니다
+type searchDocument struct {
+	kind  string
+	refID string
+	title string
+	body  string
+	tags  string
+}
+
+// migrateSearchIndex는 FTS5 검색 인덱스 테이블을 만들고 현재 데이터로 채웁니다
+func (d *Database) migrateSearchIndex(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+			kind UNINDEXED,
+			ref_id UNINDEXED,
+			title,
+			body,
+			tags,
+			tokenize = 'unicode61 remove_diacritics 2'
+		)
+	`); err != nil {
+		return fmt.Errorf("검색 인덱스 테이블 생성 실패: %w", err)
+	}
+
+	_, err := rebuildSearchIndex(tx, []sqlQueryer{tx})
+	return err
+}
+
+// RebuildSearchIndex는 경쟁, 토픽, 워커 검색 인덱스를 현재 데이터로 다시 만듭니다
+func (d *Database) RebuildSearchIndex() (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+
+	// 토픽 스냅샷은 메인 데이터베이스(같은 트랜잭션)와 각 토픽 샤드에서 읽음
+	sources := []sqlQueryer{tx}
+	stores, err := d.topicStores()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defer releaseTopicStores(stores)
+	for _, store := range stores {
+		if store.isShard() {
+			sources = append(sources, store.reader)
+		}
+	}
+
+	count, err := rebuildSearchIndex(tx, sources)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+
+	if d.debug {
+		log.Printf("검색 인덱스 갱신 완료: 문서 %d개", count)
+	}
+	return count, nil
+}
+
+// rebuildSearchIndex는 검색 인덱스를 비우고 모든 문서를 다시 삽입합니다
+// snapshotSources는 topic_inferences 테이블을 가진 저장소 목록입니다
+func rebuildSearchIndex(tx *sql.Tx, snapshotSources []sqlQueryer) (int, error) {
+	documents, err := collectSearchDocuments(tx, snapshotSources)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM search_index"); err != nil {
+		return 0, fmt.Errorf("검색 인덱스 초기화 실패: %w", err)
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO search_index (kind, ref_id, title, body, tags) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, fmt.Errorf("SQL 준비 실패: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, doc := range documents {
+		if _, err := stmt.Exec(doc.kind, doc.refID, doc.title, doc.body, doc.tags); err != nil {
+			return 0, fmt.Errorf("검색 문서 삽입 실패 (%s %s): %w", doc.kind, doc.refID, err)
+		}
+	}
+
+	return len(documents), nil
+}
+
+// collectSearchDocuments는 경쟁, 토픽, 워커 문서를 만듭니다
+// 워커는 리더보드의 사용자 이름/주소와 각 토픽 최신 스냅샷의 워커 주소에서 가져옵니다
+func collectSearchDocuments(q sqlQueryer, snapshotSources []sqlQueryer) ([]searchDocument, error) {
+	var documents []searchDocument
+
+	rows, err := q.Query(`
+		SELECT id, name, COALESCE(description, ''), COALESCE(detailed_description, ''), topic_id, COALESCE(tags, '')
+		FROM competitions_v2 ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("경쟁 데이터 조회 실패: %w", err)
+	}
+
+	topicCompetitions := make(map[string][]string) // 토픽 ID -> 경쟁 이름 목록
+	topicTags := make(map[string][]string)
+	for rows.Next() {
+		var id int64
+		var name, description, detailed, tagsJSON string
+		var topicID sql.NullInt64
+		if err := rows.Scan(&id, &name, &description, &detailed, &topicID, &tagsJSON); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("경쟁 데이터 스캔 실패: %w", err)
+		}
+
+		tags := searchTags(tagsJSON)
+		documents = append(documents, searchDocument{
+			kind:  SearchKindCompetition,
+			refID: strconv.FormatInt(id, 10),
+			title: name,
+			body:  strings.TrimSpace(description + "\n" + detailed),
+			tags:  strings.Join(tags, " "),
+		})
+
+		if topicID.Valid {
+			key := strconv.FormatInt(topicID.Int64, 10)
+			topicCompetitions[key] = append(topicCompetitions[key], name)
+			topicTags[key] = append(topicTags[key], tags...)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("경쟁 데이터 처리 중 오류: %w", err)
+	}
+
+	// 토픽 문서 (경쟁에 연결된 토픽과 수집된 적이 있는 토픽)
+	collectedTopics := make(map[string]sqlQueryer) // 토픽 ID -> 스냅샷 저장소
+	for _, source := range snapshotSources {
+		topicRows, err := source.Query("SELECT DISTINCT topic_id FROM topic_inferences")
+		if err != nil {
+			return nil, fmt.Errorf("토픽 목록 조회 실패: %w", err)
+		}
+		for topicRows.Next() {
+			var topicID string
+			if err := topicRows.Scan(&topicID); err != nil {
+				topicRows.Close()
+				return nil, fmt.Errorf("토픽 ID 스캔 실패: %w", err)
+			}
+			collectedTopics[topicID] = source
+			if _, ok := topicCompetitions[topicID]; !ok {
+				topicCompetitions[topicID] = nil
+			}
+		}
+		topicRows.Close()
+		if err := topicRows.Err(); err != nil {
+			return nil, fmt.Errorf("토픽 목록 처리 중 오류: %w", err)
+		}
+	}
+
+	topicIDs := make([]string, 0, len(topicCompetitions))
+	for topicID := range topicCompetitions {
+		topicIDs = append(topicIDs, topicID)
+	}
+	sort.Strings(topicIDs)
+	for _, topicID := range topicIDs {
+		documents = append(documents, searchDocument{
+			kind:  SearchKindTopic,
+			refID: topicID,
+			title: "Topic " + topicID,
+			body:  strings.Join(topicCompetitions[topicID], "\n"),
+			tags:  strings.Join(topicTags[topicID], " "),
+		})
+	}
+
+	workers, err := collectSearchWorkers(q, collectedTopics)
+	if err != nil {
+		return nil, err
+	}
+	return append(documents, workers...), nil
+}
+
+// searchWorker는 워커 문서를 만들기 위해 모은 정보입니다
+type searchWorker struct {
+	names  map[string]bool
+	topics map[string]bool
+}
+
+// collectSearchWorkers는 리더보드와 최신 스냅샷에서 워커 문서를 만듭니다
+// topics는 토픽 ID별 스냅샷 저장소입니다
+func collectSearchWorkers(q sqlQueryer, topics map[string]sqlQueryer) ([]searchDocument, error) {
+	workers := make(map[string]*searchWorker)
+	addWorker := func(address, topicID string, names ...string) {
+		if address == "" {
+			return
+		}
+		worker, ok := workers[address]
+		if !ok {
+			worker = &searchWorker{names: make(map[string]bool), topics: make(map[string]bool)}
+			workers[address] = worker
+		}
+		worker.topics[topicID] = true
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name != "" {
+				worker.names[name] = true
+			}
+		}
+	}
+
+	rows, err := q.Query(`
+		SELECT DISTINCT cosmos_address, topic_id, COALESCE(username, ''), COALESCE(first_name, ''), COALESCE(last_name, '')
+		FROM leaderboard_entries
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("리더보드 참가자 조회 실패: %w", err)
+	}
+	for rows.Next() {
+		var address, topicID, username, firstName, lastName string
+		if err := rows.Scan(&address, &topicID, &username, &firstName, &lastName); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("리더보드 참가자 스캔 실패: %w", err)
+		}
+		addWorker(address, topicID, username, strings.TrimSpace(firstName+" "+lastName))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("리더보드 참가자 처리 중 오류: %w", err)
+	}
+
+	// 각 토픽 최신 스냅샷의 워커 주소
+	topicIDs := make([]string, 0, len(topics))
+	for topicID := range topics {
+		topicIDs = append(topicIDs, topicID)
+	}
+	sort.Strings(topicIDs)
+	for _, topicID := range topicIDs {
+		// 검색 인덱스 마이그레이션은 본문 저장소 마이그레이션 이전 스키마에서도 실행됨
+		source := topics[topicID]
+		columns := snapshotRowColumnsFor(source)
+		decoder := newSnapshotDecoder(source)
+		decoder.columns = columns
+		row, err := findSnapshotRow(source,
+			"SELECT "+columns+" FROM topic_inferences WHERE topic_id = ? ORDER BY inference_block_height DESC LIMIT 1",
+			topicID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("토픽 %s 최신 스냅샷 조회 실패: %w", topicID, err)
+		}
+		if row == nil {
+			continue
+		}
+		state, err := decoder.decode(*row)
+		if err != nil {
+			// 복원할 수 없는 스냅샷은 무결성 검사에서 처리하므로 검색 인덱스에서만 제외
+			log.Printf("검색 인덱스: 토픽 %s 최신 스냅샷 복원 실패: %v", topicID, err)
+			continue
+		}
+		networkInferences, _ := state["network_inferences"].(map[string]interface{})
+		synthesisValue, _ := networkInferences["synthesis_value"].([]interface{})
+		for _, item := range synthesisValue {
+			if workerData, ok := item.(map[string]interface{}); ok {
+				addWorker(getStringValue(workerData, "worker", ""), topicID)
+			}
+		}
+	}
+
+	addresses := make([]string, 0, len(workers))
+	for address := range workers {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	documents := make([]searchDocument, 0, len(addresses))
+	for _, address := range addresses {
+		worker := workers[address]
+		title := address
+		names := sortedKeys(worker.names)
+		if len(names) > 0 {
+			title = names[0]
+		}
+		topics := sortedKeys(worker.topics)
+		documents = append(documents, searchDocument{
+			kind:  SearchKindWorker,
+			refID: address,
+			title: title,
+			body:  strings.Join(append([]string{address}, names...), "\n") + "\ntopics " + strings.Join(topics, " "),
+		})
+	}
+	return documents, nil
+}
+
+// sortedKeys는 집합의 키를 정렬하여 반환합니다
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// searchTags는 JSON 배열로 저장된 태그를 문자열 목록으로 변환합니다
+func searchTags(tagsJSON string) []string {
+	var raw []interface{}
+	if err := json.Unmarshal([]byte(tagsJSON), &raw); err != nil {
+		return nil
+	}
+	tags := make([]string, 0, len(raw))
+	for _, tag := range raw {
+		switch value := tag.(type) {
+		case string:
+			tags = append(tags, value)
+		case map[string]interface{}:
+			// 태그가 객체인 경우 이름 필드 사용
+			if name := getStringValue(value, "name", ""); name != "" {
+				tags = append(tags, name)
+			}
+		}
+	}
+	return tags
+}
+
+// buildSearchQuery는 사용자 입력을 FTS5 쿼리로 변환합니다
+// 각 단어를 따옴표로 감싸 FTS5 문법 문자를 무력화하고, 접두어 검색(*)으로 부분 입력도 찾습니다
+func buildSearchQuery(input string) string {
+	terms := strings.FieldsFunc(input, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_'
+	})
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, `"`+term+`"*`)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Search는 검색 인덱스에서 관련도 순으로 결과를 찾습니다
+// kinds가 비어 있으면 모든 유형을 검색합니다
+func (d *Database) Search(input string, kinds []string, limit int) ([]SearchResult, error) {
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	match := buildSearchQuery(input)
+	if match == "" {
+		return []SearchResult{}, nil
+	}
+
+	// 제목 > 태그 > 본문 순으로 가중치 부여 (kind, ref_id는 인덱싱하지 않음)
+	query := `
+		SELECT kind, ref_id, title,
+			snippet(search_index, -1, '<mark>', '</mark>', '…', 12),
+			bm25(search_index, 0.0, 0.0, 10.0, 2.0, 5.0) AS rank
+		FROM search_index
+		WHERE search_index MATCH ?`
+	args := []interface{}{match}
+	if len(kinds) > 0 {
+		query += " AND kind IN (" + strings.TrimSuffix(strings.Repeat("?,", len(kinds)), ",") + ")"
+		for _, kind := range kinds {
+			args = append(args, kind)
+		}
+	}
+	query += " ORDER BY rank LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := d.reader.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("검색 실패: %w", err)
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		var result SearchResult
+		var rank float64
+		if err := rows.Scan(&result.Kind, &result.ID, &result.Title, &result.Snippet, &rank); err != nil {
+			return nil, fmt.Errorf("검색 결과 스캔 실패: %w", err)
+		}
+		result.Score = -rank
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("검색 결과 처리 중 오류: %w", err)
+	}
+
+	return results, nil
+}
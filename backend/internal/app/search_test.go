@@ -0,0 +1,111 @@
+package app
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"bitcoin price", `"bitcoin"* "price"*`},
+		{"  ", ""},
+		{`"`, ""},
+		{"(((", ""},
+		// FTS5 연산자와 문법 문자는 따옴표 안의 일반 단어가 되거나 제거됨
+		{"bitcoin OR weather", `"bitcoin"* "OR"* "weather"*`},
+		{"NEAR(a b, 2)", `"NEAR"* "a"* "b"* "2"*`},
+		{"title:weather", `"title"* "weather"*`},
+		{`-btc ^eth "usd`, `"btc"* "eth"* "usd"*`},
+		{"btc*", `"btc"*`},
+		{"worker_1 allo1abc", `"worker_1"* "allo1abc"*`},
+		{"비트코인 예측", `"비트코인"* "예측"*`},
+	}
+	for _, tt := range tests {
+		if got := buildSearchQuery(tt.input); got != tt.want {
+			t.Errorf("buildSearchQuery(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSearchRankingAndEscaping(t *testing.T) {
+	db := newTestDatabase(t)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	description := "Compare volatility with the bitcoin price"
+	competitions := []Competition{
+		{ID: 1, Name: "Bitcoin Price Prediction", TopicID: 1, StartDate: start, EndDate: start.AddDate(0, 1, 0), Tags: []string{"btc"}},
+		{ID: 2, Name: "Ethereum Volatility", TopicID: 2, StartDate: start, EndDate: start.AddDate(0, 1, 0), Description: &description, Tags: []string{"eth"}},
+		{ID: 3, Name: "Weather Forecast", TopicID: 3, StartDate: start, EndDate: start.AddDate(0, 1, 0), Tags: []string{"bitcoin"}},
+	}
+	if err := db.SaveCompetitions(testCompetitions(competitions...)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.RebuildSearchIndex(); err != nil {
+		t.Fatal(err)
+	}
+	_, mux := newTestService(t, db)
+
+	search := func(query string) SearchResponse {
+		t.Helper()
+		var response SearchResponse
+		getJSON(t, mux, "/api/v1/search?"+url.Values{"q": {query}, "type": {SearchKindCompetition}}.Encode(), http.StatusOK, &response)
+		return response
+	}
+	ids := func(response SearchResponse) []string {
+		var ids []string
+		for _, result := range response.Results {
+			ids = append(ids, result.ID)
+		}
+		return ids
+	}
+
+	// 제목 > 태그 > 본문 순으로 관련도가 높음
+	response := search("bitcoin")
+	if got, want := ids(response), []string{"1", "3", "2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("bitcoin 검색 순서 = %v, want %v", got, want)
+	}
+	for i := 1; i < len(response.Results); i++ {
+		if response.Results[i].Score >= response.Results[i-1].Score {
+			t.Errorf("점수가 관련도 순이 아닙니다: %+v", response.Results)
+		}
+	}
+	if snippet := response.Results[2].Snippet; !strings.Contains(snippet, "<mark>bitcoin</mark>") {
+		t.Errorf("본문 일치 스니펫 = %q, want 강조된 bitcoin", snippet)
+	}
+
+	// 접두어와 여러 단어(모두 포함)
+	if got := ids(search("bitc pred")); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("bitc pred 검색 = %v, want [1]", got)
+	}
+
+	// FTS5 문법은 일반 단어로 검색되어 오류나 연산자 동작이 없음 (OR, NEAR, title도 포함해야 할 단어)
+	for query, want := range map[string]int{
+		"bitcoin OR weather":    0,
+		"title:weather":         0,
+		"NEAR(bitcoin weather)": 0,
+		"(((":                   0,
+		`"`:                     0,
+		"weather*)":             1, // 경쟁 3 (weather 접두어)
+	} {
+		if response := search(query); response.Count != want {
+			t.Errorf("%q 검색 결과 = %v, want %d개", query, ids(response), want)
+		}
+	}
+
+	// 유형 필터와 전체 검색
+	var all SearchResponse
+	getJSON(t, mux, "/api/v1/search?q=weather", http.StatusOK, &all)
+	kinds := make(map[string]bool)
+	for _, result := range all.Results {
+		kinds[result.Kind] = true
+	}
+	if !kinds[SearchKindCompetition] || !kinds[SearchKindTopic] {
+		t.Errorf("weather 전체 검색 유형 = %v, want 경쟁과 토픽", kinds)
+	}
+}
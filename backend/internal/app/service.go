@@ -1,19 +1,31 @@
 package app
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // Service는 모니터링 서비스의 HTTP API를 제공합니다
 type Service struct {
-	monitor *Monitor
-	db      *Database
+	monitor    *Monitor
+	db         *Database
+	backups    *BackupManager    // 백업 관리자 (설정되지 않으면 백업 API 비활성)
+	archiver   *Archiver         // Parquet 아카이브 (설정되지 않으면 아카이브 API 비활성)
+	integrity  *IntegrityChecker // 무결성 검사기 (설정되지 않으면 무결성 API 비활성)
+	elector    *LeaderElector    // 리더 선출기 (설정되지 않으면 항상 리더로 보고하고 이벤트 연결을 받음)
+	events     *EventBroker      // 이벤트 브로커 (설정되지 않으면 스트림 API 비활성)
+	hub        *WebSocketHub     // WebSocket 허브 (설정되지 않으면 WebSocket API 비활성)
+	metrics    *Metrics          // Prometheus 지표 (설정되지 않으면 지표 수집과 /metrics 비활성)
+	adminToken string            // 관리자 API 토큰 (비어 있으면 관리자 API 비활성화)
 }
 
 // NewService는 새로운 서비스를 생성합니다
@@ -24,120 +36,464 @@ func NewService(monitor *Monitor, db *Database) *Service {
 	}
 }
 
-// HandleGetCompetitions는 경쟁 데이터를 반환하는 핸들러입니다
-func (s *Service) HandleGetCompetitions(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// SetBackupManager는 백업 API에서 사용할 백업 관리자를 설정합니다
+func (s *Service) SetBackupManager(backups *BackupManager) {
+	s.backups = backups
+}
+
+// SetIntegrityChecker는 무결성 API에서 사용할 무결성 검사기를 설정합니다
+func (s *Service) SetIntegrityChecker(checker *IntegrityChecker) {
+	s.integrity = checker
+}
+
+// SetArchiver는 아카이브 API에서 사용할 아카이브 저장소를 설정합니다
+func (s *Service) SetArchiver(archiver *Archiver) {
+	s.archiver = archiver
+}
+
+// SetLeaderElector는 상태 API에서 보고하고 이벤트 연결을 받을지 판단할 리더 선출기를 설정합니다
+func (s *Service) SetLeaderElector(elector *LeaderElector) {
+	s.elector = elector
+}
+
+// SetEventBroker는 스트림 API에서 구독할 이벤트 브로커를 설정합니다
+func (s *Service) SetEventBroker(events *EventBroker) {
+	s.events = events
+}
+
+// SetWebSocketHub는 WebSocket API에서 사용할 허브를 설정합니다
+func (s *Service) SetWebSocketHub(hub *WebSocketHub) {
+	s.hub = hub
+}
+
+// SetMetrics는 API 요청을 기록하고 /metrics로 노출할 지표를 설정합니다
+func (s *Service) SetMetrics(metrics *Metrics) {
+	s.metrics = metrics
+}
+
+// SetAdminToken은 관리자 API 인증 토큰을 설정합니다
+func (s *Service) SetAdminToken(token string) {
+	s.adminToken = token
+}
+
+// authorizeAdmin은 관리자 API 요청의 Bearer 토큰을 확인합니다
+// 토큰이 설정되지 않았으면 관리자 API를 사용할 수 없으며(403), 토큰은 상수 시간으로 비교합니다
+// 인증에 실패하면 응답을 작성하고 false를 반환합니다
+func (s *Service) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if s.adminToken == "" {
+		writeError(w, http.StatusForbidden, ErrCodeAdminDisabled, "Admin API is disabled: set admin_token to enable it")
+		return false
+	}
+	token, ok := bearerToken(r)
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+		writeError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
+		return false
+	}
+	return true
+}
+
+// bearerToken은 Authorization 헤더의 Bearer 토큰을 반환합니다 (스킴은 대소문자 구분 없음)
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// HandleHealth는 서버와 수집기 상태를 반환하는 핸들러입니다
+func (s *Service) HandleHealth(w http.ResponseWriter, r *http.Request) {
+	monitorStatus := "stopped"
+	if s.monitor.IsRunning() {
+		monitorStatus = "running"
+	}
+	leader := LeaderStatus{IsLeader: true}
+	if s.elector != nil {
+		leader = s.elector.Status()
+	}
+
+	writeJSON(w, http.StatusOK, HealthResponse{
+		Status:        "healthy",
+		MonitorStatus: monitorStatus,
+		Leader:        leader,
+		Timestamp:     time.Now().Format(time.RFC3339),
+	})
+}
+
+// HandleBackups는 백업 목록을 조회(GET)하거나 새 백업을 생성(POST)하는 관리자 핸들러입니다
+func (s *Service) HandleBackups(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+	if s.backups == nil {
+		writeError(w, http.StatusServiceUnavailable, ErrCodeNotConfigured, "Backups are not configured")
 		return
 	}
 
+	if r.Method == http.MethodPost {
+		backup, err := s.backups.CreateBackup()
+		if err != nil {
+			log.Printf("백업 생성 실패: %v", err)
+			writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to create backup: %v", err))
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, BackupCreateResponse{Status: "success", Backup: backup})
+		return
+	}
+
+	backups, err := s.backups.ListBackups()
+	if err != nil {
+		log.Printf("백업 목록 조회 실패: %v", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to list backups")
+		return
+	}
+
+	var totalSize int64
+	for _, backup := range backups {
+		totalSize += backup.SizeBytes
+	}
+
+	writeJSON(w, http.StatusOK, BackupListResponse{
+		Status:         "success",
+		Count:          len(backups),
+		TotalSizeBytes: totalSize,
+		Backups:        backups,
+	})
+}
+
+// HandleIntegrity는 마지막 무결성 검사 보고서를 조회(GET)하거나 검사를 즉시 실행(POST)하는 관리자 핸들러입니다
+// HTTP로 실행한 검사는 보고만 하며, 행을 옮기거나 지우는 격리와 정리는 check 명령으로만 실행합니다
+func (s *Service) HandleIntegrity(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+	if s.integrity == nil {
+		writeError(w, http.StatusServiceUnavailable, ErrCodeNotConfigured, "Integrity checks are not configured")
+		return
+	}
+
+	var report *IntegrityReport
+	if r.Method == http.MethodPost {
+		var err error
+		report, err = s.integrity.Run(IntegrityOptions{})
+		if err != nil {
+			log.Printf("무결성 검사 실패: %v", err)
+			writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to check integrity: %v", err))
+			return
+		}
+	} else {
+		report = s.integrity.LastReport()
+		if report == nil {
+			writeError(w, http.StatusNotFound, ErrCodeNotFound, "No integrity check has been run yet")
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, IntegrityResponse{
+		Status:     "success",
+		OK:         report.OK(),
+		Unresolved: report.Unresolved(),
+		Report:     report,
+	})
+}
+
+// HandleGetArchiveManifest는 Parquet 아카이브 매니페스트를 반환하는 관리자 핸들러입니다
+func (s *Service) HandleGetArchiveManifest(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+	if s.archiver == nil {
+		writeError(w, http.StatusServiceUnavailable, ErrCodeNotConfigured, "Archive is not configured")
+		return
+	}
+
+	manifest, err := s.archiver.LoadManifest()
+	if err != nil {
+		log.Printf("아카이브 매니페스트 조회 실패: %v", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to load archive manifest")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ArchiveManifestResponse{Status: "success", Manifest: manifest})
+}
+
+// writeSnapshotETag는 스냅샷 본문 해시를 ETag로 설정하고, If-None-Match와 일치하면 304 응답을 보냅니다
+// 304 응답을 보냈으면 true를 반환합니다
+func writeSnapshotETag(w http.ResponseWriter, r *http.Request, hash string) bool {
+	if hash == "" {
+		return false
+	}
+	etag := `"` + hash + `"`
+	w.Header().Set("ETag", etag)
+
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// HandleGetCompetitions는 경쟁 데이터를 반환하는 핸들러입니다
+func (s *Service) HandleGetCompetitions(w http.ResponseWriter, r *http.Request) {
 	// 최신 데이터 조회
-	data, err := s.db.GetLatestCompetitions()
+	data, hash, err := s.db.GetLatestCompetitionsWithHash()
 	if err != nil {
 		log.Printf("데이터 조회 실패: %v", err)
-		http.Error(w, "Failed to retrieve data", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve data")
 		return
 	}
 
 	if data == nil {
-		// 데이터가 없는 경우
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"error": "No data available"}`))
+		// 아직 수집된 데이터가 없는 경우
+		writeError(w, http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "No competition data has been collected yet")
+		return
+	}
+
+	// 본문이 바뀌지 않았으면 304 응답
+	if writeSnapshotETag(w, r, hash) {
 		return
 	}
 
 	// JSON 응답 반환
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	writeJSON(w, http.StatusOK, data)
 }
 
 // HandleGetCompetitionsByTimeRange는 지정된 시간 범위의 경쟁 데이터를 반환하는 핸들러입니다
 func (s *Service) HandleGetCompetitionsByTimeRange(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// 시작/종료 시간 (기본값: 최근 24시간, 형식은 라우터에서 검사)
+	start := queryTime(r, "start", time.Now().Add(-24*time.Hour))
+	end := queryTime(r, "end", time.Now())
+
+	// 페이지 조건 (limit 또는 cursor가 없으면 전체 조회)
+	page, err := parsePageQuery(r, cursorCompetitionHistory, 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
 		return
 	}
 
-	// 쿼리 파라미터에서 시작/종료 시간 추출
-	startStr := r.URL.Query().Get("start")
-	endStr := r.URL.Query().Get("end")
+	// 데이터 조회 (계층은 조회 범위와 보존 정책에 맞게 자동 선택)
+	data, next, tier, err := s.db.GetCompetitionsByTimeRange(start, end, r.URL.Query().Get("tier"), page)
+	if err != nil {
+		log.Printf("데이터 조회 실패: %v", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve data")
+		return
+	}
+	w.Header().Set(seriesTierHeader, tier)
 
-	var start, end time.Time
-	var err error
+	// 데이터가 없으면 빈 배열 반환
+	if data == nil {
+		data = []interface{}{}
+	}
 
-	if startStr != "" {
-		start, err = time.Parse(time.RFC3339, startStr)
-		if err != nil {
-			http.Error(w, "Invalid start time format. Use RFC3339 format.", http.StatusBadRequest)
+	// 응답 본문은 배열이므로 페이지 정보는 Link 헤더로만 전달
+	newPageInfo(w, r, cursorCompetitionHistory, page, next)
+
+	// JSON 응답 반환
+	writeJSON(w, http.StatusOK, data)
+}
+
+// HandleGetCompetitionChanges는 경쟁별 필드 변경 이력(타임라인)을 반환하는 핸들러입니다
+func (s *Service) HandleGetCompetitionChanges(w http.ResponseWriter, r *http.Request) {
+	// 쿼리 파라미터에서 경쟁 ID 추출 (선택적)
+	competitionID := 0
+	if idStr := r.URL.Query().Get("competition_id"); idStr != "" {
+		parsedID, err := strconv.Atoi(idStr)
+		if err != nil || parsedID <= 0 {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid competition_id parameter")
 			return
 		}
-	} else {
-		// 기본값: 24시간 전
-		start = time.Now().Add(-24 * time.Hour)
+		competitionID = parsedID
 	}
 
-	if endStr != "" {
-		end, err = time.Parse(time.RFC3339, endStr)
-		if err != nil {
-			http.Error(w, "Invalid end time format. Use RFC3339 format.", http.StatusBadRequest)
+	// 시작/종료 시간 (기본값: 전체 기간, 형식은 라우터에서 검사)
+	start := queryTime(r, "start", time.Unix(0, 0).UTC())
+	end := queryTime(r, "end", time.Now())
+
+	changes, err := s.db.GetCompetitionChanges(competitionID, start, end)
+	if err != nil {
+		log.Printf("경쟁 변경 이력 조회 실패: %v", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve competition changes")
+		return
+	}
+
+	// 경쟁별 타임라인으로 묶기
+	timelines := make(map[string][]CompetitionChange)
+	for _, change := range changes {
+		key := strconv.Itoa(change.CompetitionID)
+		timelines[key] = append(timelines[key], change)
+	}
+
+	writeJSON(w, http.StatusOK, CompetitionChangesResponse{
+		Status:    "success",
+		Count:     len(changes),
+		Timelines: timelines,
+	})
+}
+
+// HandleGetCompetitionLifecycle은 경쟁 라이프사이클 전이 기록을 반환하는 핸들러입니다
+func (s *Service) HandleGetCompetitionLifecycle(w http.ResponseWriter, r *http.Request) {
+	// 쿼리 파라미터에서 경쟁 ID 추출 (선택적)
+	competitionID := 0
+	if idStr := r.URL.Query().Get("competition_id"); idStr != "" {
+		parsedID, err := strconv.Atoi(idStr)
+		if err != nil || parsedID <= 0 {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid competition_id parameter")
 			return
 		}
-	} else {
-		// 기본값: 현재 시간
-		end = time.Now()
+		competitionID = parsedID
 	}
 
-	// 데이터 조회
-	data, err := s.db.GetCompetitionsByTimeRange(start, end)
+	transitions, err := s.db.GetLifecycleTransitions(competitionID)
 	if err != nil {
-		log.Printf("데이터 조회 실패: %v", err)
-		http.Error(w, "Failed to retrieve data", http.StatusInternalServerError)
+		log.Printf("라이프사이클 전이 기록 조회 실패: %v", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve lifecycle transitions")
 		return
 	}
 
-	if len(data) == 0 {
-		// 데이터가 없는 경우
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"error": "No data available for the specified time range"}`))
+	// 경쟁별로 묶기
+	timelines := make(map[string][]LifecycleTransition)
+	for _, transition := range transitions {
+		key := strconv.Itoa(transition.CompetitionID)
+		timelines[key] = append(timelines[key], transition)
+	}
+
+	writeJSON(w, http.StatusOK, CompetitionLifecycleResponse{
+		Status:    "success",
+		Count:     len(transitions),
+		Timelines: timelines,
+	})
+}
+
+// parseCompetitionIDParam은 필수 competition_id 쿼리 파라미터를 파싱합니다
+func parseCompetitionIDParam(r *http.Request) (int, error) {
+	idStr := r.URL.Query().Get("competition_id")
+	if idStr == "" {
+		return 0, fmt.Errorf("Missing competition_id parameter")
+	}
+	competitionID, err := strconv.Atoi(idStr)
+	if err != nil || competitionID <= 0 {
+		return 0, fmt.Errorf("Invalid competition_id parameter")
+	}
+	return competitionID, nil
+}
+
+// HandleGetLeaderboardHistory는 경쟁 내 참가자의 리더보드 시계열을 반환하는 핸들러입니다
+func (s *Service) HandleGetLeaderboardHistory(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := parseCompetitionIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
 		return
 	}
 
-	// JSON 응답 반환
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	address := r.URL.Query().Get("address")
+	if address == "" {
+		writeError(w, http.StatusBadRequest, ErrCodeMissingParameter, "Missing address parameter")
+		return
+	}
+
+	// 시작/종료 시간 (기본값: 전체 기간, 형식은 라우터에서 검사)
+	start := queryTime(r, "start", time.Unix(0, 0).UTC())
+	end := queryTime(r, "end", time.Now())
+
+	page, err := parsePageQuery(r, cursorLeaderboard, 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
+		return
+	}
+
+	history, next, err := s.db.GetLeaderboardHistory(competitionID, address, start, end, page)
+	if err != nil {
+		log.Printf("리더보드 기록 조회 실패: %v", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve leaderboard history")
+		return
+	}
+
+	if history == nil {
+		writeError(w, http.StatusNotFound, ErrCodeCompetitionNotFound, fmt.Sprintf("Competition %d not found or has no topic", competitionID))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, LeaderboardHistoryResponse{
+		Status:  "success",
+		Count:   len(history.History),
+		History: history,
+		Page:    newPageInfo(w, r, cursorLeaderboard, page, next),
+	})
 }
 
-// HandleGetDatabaseStats는 데이터베이스 통계를 반환하는 핸들러입니다
-func (s *Service) HandleGetDatabaseStats(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// HandleGetLeaderboardMovers는 기간 동안 순위 변동이 큰 참가자를 반환하는 핸들러입니다
+func (s *Service) HandleGetLeaderboardMovers(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := parseCompetitionIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
 		return
 	}
 
+	// 비교 기간 (기본값: 24시간, 형식은 라우터에서 검사)
+	window := queryDuration(r, "window", 24*time.Hour)
+
+	// 반환할 참가자 수 (기본값: 10)
+	limit := 10
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid limit parameter")
+			return
+		}
+		limit = parsed
+	}
+
+	movers, err := s.db.GetLeaderboardMovers(competitionID, window, time.Now(), limit)
+	if err != nil {
+		log.Printf("리더보드 순위 변동 조회 실패: %v", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve leaderboard movers")
+		return
+	}
+
+	if movers == nil {
+		writeError(w, http.StatusNotFound, ErrCodeCompetitionNotFound, fmt.Sprintf("Competition %d not found or has no topic", competitionID))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, LeaderboardMoversResponse{
+		Status: "success",
+		Window: window.String(),
+		Movers: movers,
+	})
+}
+
+// HandleGetDatabaseStats는 데이터베이스 통계를 반환하는 핸들러입니다
+func (s *Service) HandleGetDatabaseStats(w http.ResponseWriter, r *http.Request) {
 	// 데이터베이스 통계 조회
 	stats, err := s.db.GetDatabaseStats()
 	if err != nil {
 		log.Printf("통계 조회 실패: %v", err)
-		http.Error(w, "Failed to retrieve statistics", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve statistics")
 		return
 	}
+	stats.SnapshotCache = s.db.SnapshotCacheStats()
 
 	// JSON 응답 반환
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(stats)
+	writeJSON(w, http.StatusOK, stats)
 }
 
 // HandleSetDirectURL은 직접 URL을 설정하는 핸들러입니다
 func (s *Service) HandleSetDirectURL(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// 요청 본문 읽기
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Failed to read request body")
 		return
 	}
 
@@ -146,7 +502,7 @@ func (s *Service) HandleSetDirectURL(w http.ResponseWriter, r *http.Request) {
 		URL string `json:"url"`
 	}
 	if err := json.Unmarshal(body, &requestData); err != nil {
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Invalid JSON format")
 		return
 	}
 
@@ -154,24 +510,22 @@ func (s *Service) HandleSetDirectURL(w http.ResponseWriter, r *http.Request) {
 	s.monitor.SetDirectURL(requestData.URL)
 
 	// 응답 반환
-	response := map[string]string{
-		"status":  "success",
-		"message": fmt.Sprintf("Direct URL set to: %s", requestData.URL),
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, http.StatusOK, MessageResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("Direct URL set to: %s", requestData.URL),
+	})
 }
 
 // HandleFetchNow는 즉시 데이터 수집을 요청하는 핸들러입니다
 func (s *Service) HandleFetchNow(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// 모니터링 서비스 실행 상태 확인
 	if !s.monitor.IsRunning() {
-		http.Error(w, "Monitoring service is not running", http.StatusServiceUnavailable)
+		writeError(w, http.StatusServiceUnavailable, ErrCodeMonitorNotRunning, "Monitoring service is not running")
 		return
 	}
 
@@ -179,47 +533,26 @@ func (s *Service) HandleFetchNow(w http.ResponseWriter, r *http.Request) {
 	go s.monitor.collectData()
 
 	// 응답 반환
-	response := map[string]string{
-		"status":  "success",
-		"message": "Data collection requested",
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, http.StatusOK, MessageResponse{Status: "success", Message: "Data collection requested"})
 }
 
 // HandleGetActiveTopics는 활성 토픽 목록을 반환하는 핸들러입니다
 func (s *Service) HandleGetActiveTopics(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 활성 토픽 목록 조회
 	activeTopics := s.monitor.GetTopicInferenceStore().GetActiveTopics()
 
 	// 응답 반환
-	response := map[string]interface{}{
-		"status":        "success",
-		"active_topics": activeTopics,
-		"count":         len(activeTopics),
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, http.StatusOK, ActiveTopicsResponse{
+		Status:       "success",
+		ActiveTopics: activeTopics,
+		Count:        len(activeTopics),
+	})
 }
 
 // HandleGetTopicInference는 토픽 추론 데이터를 반환하는 핸들러입니다
 func (s *Service) HandleGetTopicInference(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 쿼리 파라미터에서 토픽 ID 추출
-	topicID := r.URL.Query().Get("topic_id")
-	if topicID == "" {
-		http.Error(w, "Missing topic_id parameter", http.StatusBadRequest)
-		return
-	}
+	topicID := r.URL.Query().Get("topic_id") // 필수 여부와 형식은 라우터에서 검사
 
 	// 쿼리 파라미터에서 블록 높이 추출 (선택적)
 	height := r.URL.Query().Get("height")
@@ -233,7 +566,7 @@ func (s *Service) HandleGetTopicInference(w http.ResponseWriter, r *http.Request
 		inference, err = s.db.GetTopicInferenceByHeight(topicID, height)
 		if err != nil {
 			log.Printf("토픽 %s의 블록 높이 %s 데이터 조회 실패: %v", topicID, height, err)
-			http.Error(w, fmt.Sprintf("Failed to retrieve data for topic %s at height %s", topicID, height), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to retrieve data for topic %s at height %s", topicID, height))
 			return
 		}
 		currentHeight = height
@@ -242,7 +575,7 @@ func (s *Service) HandleGetTopicInference(w http.ResponseWriter, r *http.Request
 		inference, err = s.db.GetLatestTopicInference(topicID)
 		if err != nil {
 			log.Printf("토픽 %s 데이터 조회 실패: %v", topicID, err)
-			http.Error(w, fmt.Sprintf("Failed to retrieve data for topic %s", topicID), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to retrieve data for topic %s", topicID))
 			return
 		}
 		// 최신 데이터의 블록 높이 가져오기
@@ -252,12 +585,25 @@ func (s *Service) HandleGetTopicInference(w http.ResponseWriter, r *http.Request
 	}
 
 	if inference == nil {
-		// 데이터가 없는 경우
+		// 데이터가 없는 경우: 토픽 자체가 없는지, 해당 높이만 없는지 구분
 		if height != "" {
-			http.Error(w, fmt.Sprintf("No data available for topic %s at height %s", topicID, height), http.StatusNotFound)
-		} else {
-			http.Error(w, fmt.Sprintf("No data available for topic %s", topicID), http.StatusNotFound)
+			exists, err := s.db.TopicExists(topicID)
+			if err != nil {
+				log.Printf("토픽 %s 존재 여부 확인 실패: %v", topicID, err)
+				writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to retrieve data for topic %s", topicID))
+				return
+			}
+			if exists {
+				writeError(w, http.StatusNotFound, ErrCodeHeightNotFound, fmt.Sprintf("No data available for topic %s at height %s", topicID, height))
+				return
+			}
 		}
+		writeError(w, http.StatusNotFound, ErrCodeTopicNotFound, fmt.Sprintf("No data available for topic %s", topicID))
+		return
+	}
+
+	// 스냅샷 본문이 바뀌지 않았으면 304 응답
+	if hash, ok := inference["snapshot_hash"].(string); ok && writeSnapshotETag(w, r, hash) {
 		return
 	}
 
@@ -286,7 +632,7 @@ func (s *Service) HandleGetTopicInference(w http.ResponseWriter, r *http.Request
 	}
 
 	// 응답 데이터 구성
-	responseData := make(map[string]interface{})
+	responseData := make(InferenceSnapshot)
 	for k, v := range inference {
 		// prev_height와 next_height는 최상위 레벨로 이동
 		if k == "prev_height" || k == "next_height" {
@@ -296,31 +642,33 @@ func (s *Service) HandleGetTopicInference(w http.ResponseWriter, r *http.Request
 	}
 
 	// 응답 반환
-	response := map[string]interface{}{
-		"status": "success",
-		"data":   responseData,
-		"pagination": map[string]interface{}{
-			"prev_height":    inference["prev_height"],
-			"next_height":    inference["next_height"],
-			"current_height": currentHeight,
+	writeJSON(w, http.StatusOK, TopicInferenceResponse{
+		Status: "success",
+		Data:   responseData,
+		Pagination: HeightPagination{
+			PrevHeight:    heightPointer(inference["prev_height"]),
+			NextHeight:    heightPointer(inference["next_height"]),
+			CurrentHeight: currentHeight,
 		},
+	})
+}
+
+// heightPointer는 스냅샷의 prev_height/next_height 값을 응답용 포인터로 변환합니다 (없으면 nil)
+func heightPointer(value interface{}) *string {
+	height, ok := value.(string)
+	if !ok || height == "" {
+		return nil
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	return &height
 }
 
 // HandleGetAllTopicInferences는 모든 토픽의 추론 데이터를 반환하는 핸들러입니다
 func (s *Service) HandleGetAllTopicInferences(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 활성 토픽 목록 가져오기
 	activeTopics := s.monitor.GetTopicInferenceStore().GetActiveTopics()
 
 	// 각 토픽에 대해 가공된 데이터 조회
-	inferences := make(map[string]interface{})
+	inferences := make(map[string]InferenceSnapshot)
 	for _, topicID := range activeTopics {
 		inference, err := s.db.GetLatestTopicInference(topicID)
 		if err != nil {
@@ -334,26 +682,24 @@ func (s *Service) HandleGetAllTopicInferences(w http.ResponseWriter, r *http.Req
 	}
 
 	// 응답 반환
-	response := map[string]interface{}{
-		"status":     "success",
-		"count":      len(inferences),
-		"inferences": inferences,
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, http.StatusOK, AllTopicInferencesResponse{
+		Status:     "success",
+		Count:      len(inferences),
+		Inferences: inferences,
+	})
 }
 
 // HandleForceCollectTopicData는 특정 토픽의 추론 데이터를 강제로 수집하는 핸들러입니다
 func (s *Service) HandleForceCollectTopicData(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// 요청 본문 읽기
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Failed to read request body")
 		return
 	}
 
@@ -362,42 +708,40 @@ func (s *Service) HandleForceCollectTopicData(w http.ResponseWriter, r *http.Req
 		TopicID string `json:"topic_id"`
 	}
 	if err := json.Unmarshal(body, &requestData); err != nil {
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Invalid JSON format")
 		return
 	}
 
 	if requestData.TopicID == "" {
-		http.Error(w, "Missing topic_id parameter", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeMissingParameter, "Missing topic_id parameter")
 		return
 	}
 
 	// 토픽 추론 데이터 강제 수집
 	if err := s.monitor.ForceCollectTopicData(requestData.TopicID); err != nil {
 		log.Printf("토픽 %s 데이터 강제 수집 실패: %v", requestData.TopicID, err)
-		http.Error(w, fmt.Sprintf("Failed to collect data for topic %s: %v", requestData.TopicID, err), http.StatusInternalServerError)
+		writeError(w, http.StatusBadGateway, ErrCodeUpstreamUnavailable, fmt.Sprintf("Failed to collect data for topic %s: %v", requestData.TopicID, err))
 		return
 	}
 
 	// 응답 반환
-	response := map[string]string{
-		"status":  "success",
-		"message": fmt.Sprintf("Data collection for topic %s requested", requestData.TopicID),
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, http.StatusOK, MessageResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("Data collection for topic %s requested", requestData.TopicID),
+	})
 }
 
 // HandleAddActiveTopic은 활성 토픽을 추가하는 핸들러입니다
 func (s *Service) HandleAddActiveTopic(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// 요청 본문 읽기
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Failed to read request body")
 		return
 	}
 
@@ -406,12 +750,12 @@ func (s *Service) HandleAddActiveTopic(w http.ResponseWriter, r *http.Request) {
 		TopicID string `json:"topic_id"`
 	}
 	if err := json.Unmarshal(body, &requestData); err != nil {
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Invalid JSON format")
 		return
 	}
 
 	if requestData.TopicID == "" {
-		http.Error(w, "Missing topic_id parameter", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeMissingParameter, "Missing topic_id parameter")
 		return
 	}
 
@@ -419,25 +763,23 @@ func (s *Service) HandleAddActiveTopic(w http.ResponseWriter, r *http.Request) {
 	s.monitor.GetTopicInferenceStore().AddActiveTopic(requestData.TopicID)
 
 	// 응답 반환
-	response := map[string]string{
-		"status":  "success",
-		"message": fmt.Sprintf("Topic %s added to active topics", requestData.TopicID),
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, http.StatusOK, MessageResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("Topic %s added to active topics", requestData.TopicID),
+	})
 }
 
 // HandleRemoveActiveTopic은 활성 토픽을 제거하는 핸들러입니다
 func (s *Service) HandleRemoveActiveTopic(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// 요청 본문 읽기
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Failed to read request body")
 		return
 	}
 
@@ -446,12 +788,12 @@ func (s *Service) HandleRemoveActiveTopic(w http.ResponseWriter, r *http.Request
 		TopicID string `json:"topic_id"`
 	}
 	if err := json.Unmarshal(body, &requestData); err != nil {
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Invalid JSON format")
 		return
 	}
 
 	if requestData.TopicID == "" {
-		http.Error(w, "Missing topic_id parameter", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, ErrCodeMissingParameter, "Missing topic_id parameter")
 		return
 	}
 
@@ -459,69 +801,47 @@ func (s *Service) HandleRemoveActiveTopic(w http.ResponseWriter, r *http.Request
 	s.monitor.GetTopicInferenceStore().RemoveActiveTopic(requestData.TopicID)
 
 	// 응답 반환
-	response := map[string]string{
-		"status":  "success",
-		"message": fmt.Sprintf("Topic %s removed from active topics", requestData.TopicID),
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, http.StatusOK, MessageResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("Topic %s removed from active topics", requestData.TopicID),
+	})
 }
 
 // HandleGetTopicStats는 특정 토픽의 통계를 반환하는 핸들러입니다
 func (s *Service) HandleGetTopicStats(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 쿼리 파라미터에서 토픽 ID 추출
-	topicID := r.URL.Query().Get("topic_id")
-	if topicID == "" {
-		http.Error(w, "Missing topic_id parameter", http.StatusBadRequest)
-		return
-	}
+	topicID := r.URL.Query().Get("topic_id") // 필수 여부와 형식은 라우터에서 검사
 
 	// 토픽 통계 조회
 	stats, err := s.db.GetTopicStats(topicID)
 	if err != nil {
 		log.Printf("토픽 %s 통계 조회 실패: %v", topicID, err)
-		http.Error(w, fmt.Sprintf("Failed to retrieve statistics for topic %s", topicID), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to retrieve statistics for topic %s", topicID))
+		return
+	}
+	if stats == nil {
+		writeError(w, http.StatusNotFound, ErrCodeTopicNotFound, fmt.Sprintf("No data available for topic %s", topicID))
 		return
 	}
 
 	// 응답 반환
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(stats)
+	writeJSON(w, http.StatusOK, stats)
 }
 
 // HandleGetTopicBlockHeights는 특정 토픽의 블록 높이 리스트를 반환하는 핸들러입니다
 func (s *Service) HandleGetTopicBlockHeights(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 쿼리 파라미터에서 토픽 ID 추출
-	topicID := r.URL.Query().Get("topic_id")
-	if topicID == "" {
-		http.Error(w, "Missing topic_id parameter", http.StatusBadRequest)
-		return
-	}
+	topicID := r.URL.Query().Get("topic_id") // 필수 여부와 형식은 라우터에서 검사
 
-	// 페이지네이션 파라미터 추출
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 100 // 기본값
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+	// 페이지네이션 파라미터 추출 (cursor가 없으면 이전처럼 offset 사용)
+	page, err := parsePageQuery(r, cursorTopicHeights, defaultPageLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
+		return
 	}
 
 	offset := 0 // 기본값
-	if offsetStr != "" {
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
 		parsedOffset, err := strconv.Atoi(offsetStr)
 		if err == nil && parsedOffset >= 0 {
 			offset = parsedOffset
@@ -529,37 +849,368 @@ func (s *Service) HandleGetTopicBlockHeights(w http.ResponseWriter, r *http.Requ
 	}
 
 	// 블록 높이 리스트 조회
-	result, err := s.db.GetTopicBlockHeights(topicID, limit, offset)
+	result, next, err := s.db.GetTopicBlockHeights(topicID, page.Limit, offset, page.After)
 	if err != nil {
 		log.Printf("토픽 %s 블록 높이 조회 실패: %v", topicID, err)
-		http.Error(w, fmt.Sprintf("Failed to retrieve block heights for topic %s", topicID), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to retrieve block heights for topic %s", topicID))
 		return
 	}
 
-	// 응답 형식 변경
-	response := map[string]interface{}{
-		"status": "success",
-		"data": map[string]interface{}{
-			"topic_id":      result["topic_id"],
-			"total_count":   result["total_count"],
-			"limit":         result["limit"],
-			"offset":        result["offset"],
-			"heights":       result["heights"],
-			"heights_count": result["heights_count"],
-		},
+	if result.TotalCount == 0 {
+		writeError(w, http.StatusNotFound, ErrCodeTopicNotFound, fmt.Sprintf("No data available for topic %s", topicID))
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	// 응답 반환
+	writeJSON(w, http.StatusOK, TopicBlockHeightsResponse{
+		Status: "success",
+		Data:   *result,
+		Page:   newPageInfo(w, r, cursorTopicHeights, page, next),
+	})
 }
 
-// HandleGetCompetitionsV2는 새로운 형식의 경쟁 데이터를 반환하는 핸들러입니다
-func (s *Service) HandleGetCompetitionsV2(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// HandleExport는 토픽 스냅샷, 워커별 값, 리더보드, 경쟁 데이터를 CSV/NDJSON/Parquet으로 스트리밍하는 핸들러입니다
+// 파라미터: dataset, format, topic_id, start, end(RFC3339), min_height, max_height, columns(쉼표 구분), gzip
+func (s *Service) HandleExport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := ExportOptions{
+		Dataset: query.Get("dataset"),
+		Format:  query.Get("format"),
+		TopicID: query.Get("topic_id"),
+		Start:   queryTime(r, "start", time.Time{}),
+		End:     queryTime(r, "end", time.Time{}),
+		Gzip:    query.Get("gzip") == "true",
+	}
+	if opts.Dataset == "" {
+		opts.Dataset = ExportDatasetSnapshots
+	}
+	if opts.Format == "" {
+		opts.Format = ExportFormatNDJSON
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *int64
+	}{{"min_height", &opts.MinHeight}, {"max_height", &opts.MaxHeight}} {
+		if value := query.Get(param.name); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed <= 0 {
+				writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, fmt.Sprintf("Invalid %s parameter", param.name))
+				return
+			}
+			*param.target = parsed
+		}
+	}
+
+	if columns := query.Get("columns"); columns != "" {
+		for _, column := range strings.Split(columns, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				opts.Columns = append(opts.Columns, column)
+			}
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
+		return
+	}
+
+	// 첫 바이트를 쓰기 전에 실패하면 오류 응답을 보낼 수 있도록 기록 여부를 추적
+	output := &exportResponseWriter{ResponseWriter: w, opts: opts}
+	count, err := s.db.Export(output, opts)
+	if err != nil {
+		log.Printf("내보내기 실패 (dataset=%s, format=%s, topic=%s, %d행 기록): %v", opts.Dataset, opts.Format, opts.TopicID, count, err)
+		if !output.started {
+			writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to export data")
+		}
 		return
 	}
+	if !output.started {
+		// 빈 결과도 헤더만 있는 파일로 응답
+		output.Write(nil)
+	}
+}
 
+// exportResponseWriter는 첫 쓰기 시점에 내보내기 응답 헤더를 설정합니다
+type exportResponseWriter struct {
+	http.ResponseWriter
+	opts    ExportOptions
+	started bool
+}
+
+func (e *exportResponseWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.Header().Set("Content-Type", e.opts.ContentType())
+		e.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.opts.FileName()))
+		e.WriteHeader(http.StatusOK)
+	}
+	return e.ResponseWriter.Write(p)
+}
+
+// HandleStream은 수집 이벤트를 SSE(text/event-stream)로 보내는 핸들러입니다
+// topic_id와 types로 받을 이벤트를 거르고, Last-Event-ID 헤더(또는 last_event_id 파라미터)로 놓친 이벤트부터 다시 받습니다
+// 클라이언트가 연결을 끊거나, 대기열이 가득 차거나, 서버가 종료되면 스트림을 끝냅니다
+func (s *Service) HandleStream(w http.ResponseWriter, r *http.Request) {
+	if s.events == nil {
+		writeError(w, http.StatusServiceUnavailable, ErrCodeNotConfigured, "Event stream is not configured")
+		return
+	}
+
+	filter, err := parseEventFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
+		return
+	}
+	lastID, err := parseLastEventID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
+		return
+	}
+	revoked, ok := s.acceptEventConnection(w)
+	if !ok {
+		return
+	}
+
+	// 미들웨어가 ResponseWriter를 감싸도 Flush가 전달되도록 ResponseController 사용
+	controller := http.NewResponseController(w)
+
+	sub, backlog := s.events.subscribe(lastID, filter)
+	defer s.events.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx 프록시 버퍼링 비활성화
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+	for _, event := range backlog {
+		if err := writeSSEEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		log.Printf("이벤트 스트림 전송 실패: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-revoked:
+			// 리더 자격을 잃으면 스트림을 끝냄 (다시 연결하면 503과 새 리더 ID를 받음)
+			return
+		case event, ok := <-sub.events:
+			if !ok {
+				return
+			}
+			if err := writeSSEEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// HandleWebSocket은 WebSocket 구독 연결을 처리하는 핸들러입니다
+// 업그레이드 요청이 아니면 426으로 응답하며, 메시지 형식은 README의 "WebSocket 구독" 절을 따릅니다
+func (s *Service) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if s.hub == nil {
+		writeError(w, http.StatusServiceUnavailable, ErrCodeNotConfigured, "WebSocket hub is not configured")
+		return
+	}
+	if !websocket.IsWebSocketUpgrade(r) {
+		w.Header().Set("Upgrade", "websocket")
+		writeError(w, http.StatusUpgradeRequired, ErrCodeUpgradeRequired, "WebSocket upgrade required")
+		return
+	}
+	revoked, ok := s.acceptEventConnection(w)
+	if !ok {
+		return
+	}
+	s.hub.ServeWebSocket(w, r, revoked)
+}
+
+// acceptEventConnection은 이 인스턴스가 이벤트 스트림/WebSocket 연결을 받을 수 있는지 확인합니다
+// 이벤트는 수집기를 실행하는 리더에서만 발생하므로, 리더가 아닌 레플리카는 현재 리더 ID를 X-Leader-ID 헤더로 알리고 503으로 거부합니다
+// 반환한 채널은 리더 자격을 잃으면 닫힙니다 (리더 선출을 사용하지 않으면 nil)
+func (s *Service) acceptEventConnection(w http.ResponseWriter) (<-chan struct{}, bool) {
+	if s.elector == nil {
+		return nil, true
+	}
+	revoked := s.elector.Revoked()
+	select {
+	case <-revoked:
+	default:
+		return revoked, true
+	}
+
+	message := "This replica is not the collector leader and publishes no events"
+	if leaderID := s.elector.Status().LeaderID; leaderID != "" && leaderID != s.elector.InstanceID() {
+		w.Header().Set(leaderIDHeader, leaderID)
+		message += "; connect to leader " + leaderID
+	}
+	writeError(w, http.StatusServiceUnavailable, ErrCodeNotLeader, message)
+	return nil, false
+}
+
+// HandleSearch는 경쟁, 토픽, 워커를 전문 검색하여 관련도 순으로 반환하는 핸들러입니다
+// type 파라미터(쉼표 구분)로 competition, topic, worker 중 일부만 검색할 수 있습니다
+func (s *Service) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, ErrCodeMissingParameter, "Missing q parameter")
+		return
+	}
+
+	var kinds []string
+	if typeParam := r.URL.Query().Get("type"); typeParam != "" {
+		for _, kind := range strings.Split(typeParam, ",") {
+			kind = strings.TrimSpace(kind)
+			switch kind {
+			case SearchKindCompetition, SearchKindTopic, SearchKindWorker:
+				kinds = append(kinds, kind)
+			default:
+				writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, fmt.Sprintf("Invalid type: %s", kind))
+				return
+			}
+		}
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid limit parameter")
+			return
+		}
+		limit = parsed
+	}
+
+	results, err := s.db.Search(query, kinds, limit)
+	if err != nil {
+		log.Printf("검색 실패 (q=%s): %v", query, err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to search")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, SearchResponse{
+		Status:  "success",
+		Query:   query,
+		Count:   len(results),
+		Results: results,
+	})
+}
+
+// HandleGetTopicInferencesByTimeRange는 기간 내 토픽 추론 데이터를 반환하는 핸들러입니다
+// 라이브 데이터베이스에서 정리된 구간은 아카이브에서 읽어 함께 반환합니다
+func (s *Service) HandleGetTopicInferencesByTimeRange(w http.ResponseWriter, r *http.Request) {
+	// 필수 여부와 형식은 라우터에서 검사
+	topicID := r.URL.Query().Get("topic_id")
+	start := queryTime(r, "start", time.Time{})
+	end := queryTime(r, "end", time.Time{})
+
+	page, err := parsePageQuery(r, cursorTopicSnapshots, 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
+		return
+	}
+
+	// 원본이 정리된 구간이나 긴 범위는 롤업 계층의 구간별 지표로 응답
+	tier := r.URL.Query().Get("tier")
+	if tier == "" || tier == SeriesTierAuto {
+		tier = s.db.selectRangeTier(topicID, start, end, time.Now())
+	}
+	if tier != SeriesTierRaw {
+		series, err := s.db.GetTopicSeries(topicID, start, end, tier, true)
+		if err != nil {
+			log.Printf("토픽 %s 기간 롤업 조회 실패: %v", topicID, err)
+			writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve topic inferences")
+			return
+		}
+		writeJSON(w, http.StatusOK, TopicInferencesResponse{
+			Status:     "success",
+			TopicID:    topicID,
+			Tier:       series.Tier,
+			Count:      len(series.Points),
+			Inferences: []InferenceSnapshot{},
+			Series:     series.Points,
+			Page:       newPageInfo(w, r, cursorTopicSnapshots, page, ""),
+		})
+		return
+	}
+
+	inferences, next, err := s.db.GetTopicInferencesByTimeRange(topicID, start, end, page)
+	if err != nil {
+		log.Printf("토픽 %s 기간 데이터 조회 실패: %v", topicID, err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve topic inferences")
+		return
+	}
+
+	snapshots := make([]InferenceSnapshot, 0, len(inferences))
+	for _, inference := range inferences {
+		snapshots = append(snapshots, inference)
+	}
+
+	writeJSON(w, http.StatusOK, TopicInferencesResponse{
+		Status:     "success",
+		TopicID:    topicID,
+		Tier:       SeriesTierRaw,
+		Count:      len(snapshots),
+		Inferences: snapshots,
+		Page:       newPageInfo(w, r, cursorTopicSnapshots, page, next),
+	})
+}
+
+// HandleGetTopicSeries는 토픽 지표 시계열을 반환하는 핸들러입니다
+// tier를 지정하지 않으면 조회 범위와 보존 정책에 맞는 계층(raw/hourly/daily)을 자동으로 선택합니다
+func (s *Service) HandleGetTopicSeries(w http.ResponseWriter, r *http.Request) {
+	topicID := r.URL.Query().Get("topic_id") // 필수 여부와 형식은 라우터에서 검사
+
+	// 시작/종료 시간 (기본값: 최근 24시간, 형식은 라우터에서 검사)
+	end := queryTime(r, "end", time.Now())
+	start := queryTime(r, "start", end.Add(-24*time.Hour))
+	if !start.Before(end) {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, "start must be before end")
+		return
+	}
+
+	tier := r.URL.Query().Get("tier")
+	switch tier {
+	case "", SeriesTierAuto, SeriesTierRaw, SeriesTierHourly, SeriesTierDaily:
+	default:
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid tier parameter. Use auto, raw, hourly or daily.")
+		return
+	}
+
+	includeWorkers := r.URL.Query().Get("workers") == "true"
+
+	series, err := s.db.GetTopicSeries(topicID, start, end, tier, includeWorkers)
+	if err != nil {
+		log.Printf("토픽 %s 시계열 조회 실패: %v", topicID, err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve topic series")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TopicSeriesResponse{
+		Status: "success",
+		Count:  len(series.Points),
+		Series: series,
+	})
+}
+
+// HandleGetCompetitionsV2는 새로운 형식의 경쟁 데이터를 반환하는 핸들러입니다
+func (s *Service) HandleGetCompetitionsV2(w http.ResponseWriter, r *http.Request) {
 	// 쿼리 파라미터에서 active 필터 확인
 	activeParam := r.URL.Query().Get("active")
 
@@ -579,49 +1230,124 @@ func (s *Service) HandleGetCompetitionsV2(w http.ResponseWriter, r *http.Request
 
 	if err != nil {
 		log.Printf("경쟁 데이터 조회 실패: %v", err)
-		http.Error(w, "Failed to retrieve competition data", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve competition data")
 		return
 	}
 
-	if len(competitions) == 0 {
-		// 데이터가 없는 경우
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"error": "No competition data available"}`))
+	// 라이프사이클 필터 (쉼표로 여러 상태 지정 가능)
+	if lifecycleParam := r.URL.Query().Get("lifecycle"); lifecycleParam != "" {
+		states := make(map[string]bool)
+		for _, state := range strings.Split(lifecycleParam, ",") {
+			states[strings.TrimSpace(state)] = true
+		}
+
+		filtered := make([]CompetitionV2, 0, len(competitions))
+		for _, comp := range competitions {
+			if states[comp.Lifecycle] {
+				filtered = append(filtered, comp)
+			}
+		}
+		competitions = filtered
+	}
+
+	// 페이지 조건 (limit 또는 cursor가 없으면 전체 조회)
+	page, err := parsePageQuery(r, cursorCompetitions, 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
 		return
 	}
+	competitions, next := pageCompetitions(competitions, page)
+
+	// 데이터가 없으면 빈 배열 반환
+	if competitions == nil {
+		competitions = []CompetitionV2{}
+	}
+
+	// 응답 본문은 배열이므로 페이지 정보는 Link 헤더로만 전달
+	newPageInfo(w, r, cursorCompetitions, page, next)
 
 	// JSON 응답 반환
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(competitions)
+	writeJSON(w, http.StatusOK, competitions)
 }
 
-// HandleGetCompetitionByTopicID는 토픽 ID에 해당하는 경쟁 정보를 반환하는 핸들러입니다
-func (s *Service) HandleGetCompetitionByTopicID(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// pageCompetitions는 경쟁 ID 순 목록에서 page.After 다음 경쟁부터 최대 page.Limit개를 잘라냅니다
+// 경쟁 목록은 작고 필터를 적용한 뒤 잘라야 하므로 메모리에서 처리합니다
+func pageCompetitions(competitions []CompetitionV2, page PageQuery) ([]CompetitionV2, string) {
+	if !page.Paged() {
+		return competitions, ""
 	}
 
-	// 쿼리 파라미터에서 토픽 ID 추출
-	topicID := r.URL.Query().Get("topic_id")
-	if topicID == "" {
-		http.Error(w, "Missing topic_id parameter", http.StatusBadRequest)
-		return
+	after, _ := strconv.Atoi(page.After)
+	var paged []CompetitionV2
+	for _, comp := range competitions {
+		if page.After != "" && comp.ID <= after {
+			continue
+		}
+		if len(paged) == page.Limit {
+			return paged, strconv.Itoa(paged[len(paged)-1].ID)
+		}
+		paged = append(paged, comp)
 	}
+	return paged, ""
+}
+
+// HandleGetCompetitionByTopicID는 토픽 ID에 해당하는 경쟁 정보를 반환하는 핸들러입니다
+func (s *Service) HandleGetCompetitionByTopicID(w http.ResponseWriter, r *http.Request) {
+	// 쿼리 파라미터에서 토픽 ID 추출
+	topicID := r.URL.Query().Get("topic_id") // 필수 여부와 형식은 라우터에서 검사
 
 	// 토픽 ID에 해당하는 경쟁 정보 조회
 	competition, err := s.db.GetCompetitionByTopicID(topicID)
 	if err != nil {
 		log.Printf("토픽 ID %s의 경쟁 정보 조회 실패: %v", topicID, err)
-		http.Error(w, fmt.Sprintf("Failed to retrieve competition for topic %s: %v", topicID, err), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, fmt.Sprintf("Failed to retrieve competition for topic %s", topicID))
+		return
+	}
+	if competition == nil {
+		writeError(w, http.StatusNotFound, ErrCodeCompetitionNotFound, fmt.Sprintf("No competition found for topic %s", topicID))
 		return
 	}
 
 	// 응답 반환
-	response := map[string]interface{}{
-		"status":      "success",
-		"competition": competition,
+	writeJSON(w, http.StatusOK, CompetitionResponse{Status: "success", Competition: competition})
+}
+
+// HandleGetCompetition은 경쟁 ID에 해당하는 경쟁 정보를 반환하는 핸들러입니다
+func (s *Service) HandleGetCompetition(w http.ResponseWriter, r *http.Request) {
+	competitionID, err := parseCompetitionIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidParameter, err.Error())
+		return
+	}
+
+	competition, err := s.db.GetCompetitionV2ByID(competitionID)
+	if err != nil {
+		log.Printf("경쟁 %d 정보 조회 실패: %v", competitionID, err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve competition")
+		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	if competition == nil {
+		writeError(w, http.StatusNotFound, ErrCodeCompetitionNotFound, fmt.Sprintf("Competition %d not found", competitionID))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CompetitionResponse{Status: "success", Competition: competition})
+}
+
+// HandleGetWorker는 워커 주소의 토픽별 최신 추론 값과 리더보드 기록을 반환하는 핸들러입니다
+func (s *Service) HandleGetWorker(w http.ResponseWriter, r *http.Request) {
+	address := r.URL.Query().Get("address") // 필수 여부와 형식은 라우터에서 검사
+
+	worker, err := s.db.GetWorkerProfile(address)
+	if err != nil {
+		log.Printf("워커 %s 조회 실패: %v", address, err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve worker")
+		return
+	}
+	if worker == nil {
+		writeError(w, http.StatusNotFound, ErrCodeWorkerNotFound, fmt.Sprintf("No data available for worker %s", address))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, WorkerResponse{Status: "success", Worker: worker})
 }
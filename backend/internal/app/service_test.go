@@ -0,0 +1,105 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestService는 db를 조회하는 서비스와 라우트가 등록된 mux를 만듭니다
+func newTestService(t testing.TB, db *Database) (*Service, *http.ServeMux) {
+	t.Helper()
+	monitor := NewMonitor(nil, db, &Config{})
+	monitor.debug = false
+	monitor.GetTopicInferenceStore().SetDebug(false)
+
+	service := NewService(monitor, db)
+	mux := http.NewServeMux()
+	service.RegisterRoutes(mux)
+	return service, mux
+}
+
+// serveTest는 mux로 요청을 처리한 응답을 반환합니다 (body가 비어 있지 않으면 JSON 본문으로 전송)
+func serveTest(mux http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// testAdminToken은 관리자 API 테스트에서 사용하는 토큰입니다
+const testAdminToken = "test-admin-token"
+
+// adminHandler는 모든 요청에 관리자 토큰을 Bearer 헤더로 붙여 mux로 전달합니다
+func adminHandler(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("Authorization", "Bearer "+testAdminToken)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// getJSON은 GET 요청의 JSON 응답을 out에 디코딩하고 상태 코드가 want인지 확인합니다
+func getJSON(t testing.TB, mux http.Handler, target string, want int, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	recorder := serveTest(mux, http.MethodGet, target, "")
+	if recorder.Code != want {
+		t.Fatalf("GET %s 상태 코드 = %d, want %d (본문: %s)", target, recorder.Code, want, recorder.Body.String())
+	}
+	if out != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("GET %s 응답 디코딩 실패: %v (본문: %s)", target, err, recorder.Body.String())
+		}
+	}
+	return recorder
+}
+
+func TestAuthorizeAdmin(t *testing.T) {
+	db := newTestDatabase(t)
+	service, mux := newTestService(t, db)
+	service.SetBackupManager(NewBackupManager(db, t.TempDir(), 1, 0))
+
+	// 토큰이 설정되지 않으면 관리자 API를 사용할 수 없음
+	recorder := serveTest(adminHandler(mux), http.MethodGet, "/api/v1/admin/backups", "")
+	if recorder.Code != http.StatusForbidden || decodeErrorResponse(t, recorder).Error.Code != ErrCodeAdminDisabled {
+		t.Errorf("토큰 없이 관리자 API = %d %s, want 403 %s", recorder.Code, recorder.Body.String(), ErrCodeAdminDisabled)
+	}
+
+	service.SetAdminToken(testAdminToken)
+	tests := []struct {
+		authorization string
+		want          int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer", http.StatusUnauthorized},
+		{"Bearer wrong-token", http.StatusUnauthorized},
+		{"Bearer " + testAdminToken + "x", http.StatusUnauthorized},
+		{"Basic " + testAdminToken, http.StatusUnauthorized},
+		{"Bearer " + testAdminToken, http.StatusOK},
+		{"bearer  " + testAdminToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/backups", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		if recorder.Code != tt.want {
+			t.Errorf("Authorization %q 상태 코드 = %d, want %d", tt.authorization, recorder.Code, tt.want)
+			continue
+		}
+		if tt.want == http.StatusUnauthorized {
+			if response := decodeErrorResponse(t, recorder); response.Error.Code != ErrCodeUnauthorized || recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("Authorization %q 응답 = %s, want %s와 WWW-Authenticate", tt.authorization, recorder.Body.String(), ErrCodeUnauthorized)
+			}
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+// 스냅샷 본문 저장소 (내용 주소 방식)
+// 전체 스냅샷과 경쟁 데이터의 JSON 본문은 SHA-256 해시를 키로 snapshot_blobs에 한 번만 저장하고,
+// topic_inferences와 competitions 행은 content_hash로 본문을 참조합니다 (이 경우 행의 data는 비어 있음)
+// 이전 방식으로 저장된 행은 data에 본문을 그대로 가지고 있으며 해시는 조회할 때 계산합니다
+
+// snapshotBlobsTableSQL은 본문 저장소 테이블입니다 (메인 데이터베이스와 토픽 샤드 공통)
+const snapshotBlobsTableSQL = `
+	CREATE TABLE IF NOT EXISTS snapshot_blobs (
+		hash TEXT PRIMARY KEY,
+		data BLOB NOT NULL,
+		raw_size INTEGER NOT NULL,
+		created_at TEXT NOT NULL
+	)
+`
+
+// blobDataColumn은 data 컬럼 대신 조회하는 식입니다
+// data가 비어 있으면 content_hash로 snapshot_blobs의 본문을 가져오고, 그렇지 않으면 data를 그대로 사용합니다
+const blobDataColumn = "CASE WHEN length(data) = 0 THEN (SELECT b.data FROM snapshot_blobs b WHERE b.hash = content_hash) ELSE data END"
+
+// snapshotRowColumns는 snapshotRow로 스캔하는 topic_inferences 컬럼 목록입니다
+const snapshotRowColumns = "id, timestamp, encoding, keyframe_id, " + blobDataColumn
+
+// legacySnapshotRowColumns는 content_hash 컬럼이 없는 topic_inferences(본문 저장소 마이그레이션 이전)의 컬럼 목록입니다
+const legacySnapshotRowColumns = "id, timestamp, encoding, keyframe_id, data"
+
+// snapshotRowColumnsFor는 q의 topic_inferences 스키마에 맞는 스냅샷 행 컬럼 목록을 반환합니다
+// 본문 저장소 마이그레이션보다 앞선 스키마 마이그레이션은 content_hash 컬럼이 없는 테이블에서 실행됩니다
+func snapshotRowColumnsFor(q sqlQueryer) string {
+	if _, err := columnDeclaredType(q, "topic_inferences", "content_hash"); err != nil {
+		return legacySnapshotRowColumns
+	}
+	return snapshotRowColumns
+}
+
+// blobGCBatchSize는 참조되지 않는 본문을 정리할 때 한 번에 확인하는 해시 수입니다
+const blobGCBatchSize = 500
+
+// snapshotMetadataFields는 행 컬럼에 따로 저장되어 스냅샷 본문과 해시에서 제외되는 필드입니다
+// loss_block_height나 타임스탬프만 바뀐 경우에는 본문을 다시 쓰지 않습니다
+var snapshotMetadataFields = []string{"timestamp", "loss_block_height"}
+
+// blobContent는 해시로 식별되는 본문입니다
+type blobContent struct {
+	state map[string]interface{} // 메타데이터 필드를 제외한 스냅샷 (경쟁 데이터는 nil)
+	json  []byte                 // 해시 대상 JSON
+	blob  []byte                 // snappy로 압축한 json
+	hash  string                 // json의 SHA-256 (16진수)
+}
+
+// newBlobContent는 JSON 본문의 해시와 압축본을 만듭니다
+func newBlobContent(jsonData []byte) *blobContent {
+	return &blobContent{json: jsonData, blob: snappy.Encode(nil, jsonData), hash: contentHash(jsonData)}
+}
+
+// newSnapshotContent는 스냅샷에서 메타데이터 필드를 제외한 본문과 해시를 만듭니다
+// json.Marshal은 맵 키를 정렬하므로 같은 내용은 항상 같은 해시가 됩니다
+func newSnapshotContent(state map[string]interface{}) (*blobContent, error) {
+	stripped := stripSnapshotMetadata(state)
+	jsonData, err := json.Marshal(stripped)
+	if err != nil {
+		return nil, fmt.Errorf("JSON 마샬링 실패: %w", err)
+	}
+	content := newBlobContent(jsonData)
+	content.state = stripped
+	return content, nil
+}
+
+// snapshotContentHash는 복원한 스냅샷의 본문 해시를 계산합니다 (해시가 저장되지 않은 이전 행용)
+func snapshotContentHash(state map[string]interface{}) (string, error) {
+	content, err := newSnapshotContent(state)
+	if err != nil {
+		return "", err
+	}
+	return content.hash, nil
+}
+
+// contentHash는 본문의 SHA-256 해시를 16진수 문자열로 반환합니다
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// stripSnapshotMetadata는 메타데이터 필드를 제외한 얕은 복사본을 반환합니다
+func stripSnapshotMetadata(state map[string]interface{}) map[string]interface{} {
+	stripped := make(map[string]interface{}, len(state))
+	for key, value := range state {
+		stripped[key] = value
+	}
+	for _, field := range snapshotMetadataFields {
+		delete(stripped, field)
+	}
+	return stripped
+}
+
+// restoreSnapshotMetadata는 행 컬럼의 타임스탬프와 loss_block_height를 복원한 스냅샷에 채웁니다
+func restoreSnapshotMetadata(state map[string]interface{}, timestamp, lossBlockHeight string) {
+	state["timestamp"] = timestamp
+	state["loss_block_height"] = lossBlockHeight
+}
+
+// snapshotRowMetadataColumns는 API 응답에 필요한 메타데이터 컬럼 목록입니다 (snapshotRowColumns 뒤에 이어서 조회)
+const snapshotRowMetadataColumns = "CAST(loss_block_height AS TEXT), content_hash"
+
+// finishSnapshot은 복원한 스냅샷에 행의 메타데이터를 채우고 스냅샷 해시를 반환합니다
+// 해시가 저장되지 않은 이전 행은 복원한 본문으로 해시를 계산합니다
+func finishSnapshot(state map[string]interface{}, timestamp, lossBlockHeight string, storedHash sql.NullString) (string, error) {
+	hash := storedHash.String
+	if !storedHash.Valid {
+		var err error
+		if hash, err = snapshotContentHash(state); err != nil {
+			return "", err
+		}
+	}
+	restoreSnapshotMetadata(state, timestamp, lossBlockHeight)
+	return hash, nil
+}
+
+// putSnapshotBlob은 압축한 본문을 저장합니다 (같은 해시의 본문이 이미 있으면 그대로 둠)
+func putSnapshotBlob(q sqlQueryer, content *blobContent) error {
+	if _, err := q.Exec(
+		"INSERT OR IGNORE INTO snapshot_blobs (hash, data, raw_size, created_at) VALUES (?, ?, ?, ?)",
+		content.hash, content.blob, len(content.json), time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return fmt.Errorf("스냅샷 본문 저장 실패: %w", err)
+	}
+	return nil
+}
+
+// deleteBlobRows는 조건에 맞는 행을 삭제한 뒤 더 이상 참조되지 않는 본문을 snapshot_blobs에서 정리합니다
+// content_hash 컬럼이 없는 테이블(마이그레이션 이전)은 행만 삭제합니다
+func deleteBlobRows(q sqlQueryer, table, condition string, args ...interface{}) (int64, error) {
+	var hashes []string
+	if _, err := columnDeclaredType(q, table, "content_hash"); err == nil {
+		rows, err := q.Query(
+			"SELECT DISTINCT content_hash FROM "+table+" WHERE ("+condition+") AND content_hash IS NOT NULL AND length(data) = 0",
+			args...,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("%s 본문 해시 조회 실패: %w", table, err)
+		}
+		for rows.Next() {
+			var hash string
+			if err := rows.Scan(&hash); err != nil {
+				rows.Close()
+				return 0, fmt.Errorf("본문 해시 스캔 실패: %w", err)
+			}
+			hashes = append(hashes, hash)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("본문 해시 처리 중 오류: %w", err)
+		}
+	}
+
+	result, err := q.Exec("DELETE FROM "+table+" WHERE "+condition, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s 데이터 삭제 실패: %w", table, err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("영향받은 행 수 확인 실패: %w", err)
+	}
+
+	if _, err := gcSnapshotBlobs(q, hashes); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
+
+// gcSnapshotBlobs는 주어진 해시 중 어느 행도 참조하지 않는 본문을 삭제하고 삭제한 수를 반환합니다
+func gcSnapshotBlobs(q sqlQueryer, hashes []string) (int64, error) {
+	if len(hashes) == 0 {
+		return 0, nil
+	}
+
+	// 경쟁 데이터는 메인 데이터베이스에만 있음
+	conditions := []string{
+		"NOT EXISTS (SELECT 1 FROM topic_inferences t WHERE t.content_hash = snapshot_blobs.hash AND length(t.data) = 0)",
+	}
+	if _, err := columnDeclaredType(q, "competitions", "content_hash"); err == nil {
+		conditions = append(conditions,
+			"NOT EXISTS (SELECT 1 FROM competitions c WHERE c.content_hash = snapshot_blobs.hash AND length(c.data) = 0)")
+	}
+
+	var removed int64
+	for start := 0; start < len(hashes); start += blobGCBatchSize {
+		batch := hashes[start:min(start+blobGCBatchSize, len(hashes))]
+		args := make([]interface{}, len(batch))
+		for i, hash := range batch {
+			args[i] = hash
+		}
+
+		result, err := q.Exec(
+			"DELETE FROM snapshot_blobs WHERE hash IN ("+strings.TrimSuffix(strings.Repeat("?,", len(batch)), ",")+") AND "+
+				strings.Join(conditions, " AND "),
+			args...,
+		)
+		if err != nil {
+			return removed, fmt.Errorf("참조되지 않는 스냅샷 본문 정리 실패: %w", err)
+		}
+		count, _ := result.RowsAffected()
+		removed += count
+	}
+	return removed, nil
+}
+
+// SnapshotBlobStats는 본문 저장소 통계입니다
+type SnapshotBlobStats struct {
+	BlobCount       int64 `json:"blob_count"`
+	StoredSizeBytes int64 `json:"stored_size_bytes"` // 압축된 본문 크기
+	RawSizeBytes    int64 `json:"raw_size_bytes"`    // 압축 전 JSON 크기
+	ReferenceCount  int64 `json:"reference_count"`   // 본문을 참조하는 행 수 (같은 본문을 여러 행이 참조하면 중복 제거된 것)
+}
+
+// snapshotBlobStats는 저장소 하나의 본문 통계를 stats에 더합니다
+func snapshotBlobStats(q sqlQueryer, stats *SnapshotBlobStats) error {
+	var count, stored, raw int64
+	if err := q.QueryRow(
+		"SELECT COUNT(*), COALESCE(SUM(LENGTH(data)), 0), COALESCE(SUM(raw_size), 0) FROM snapshot_blobs",
+	).Scan(&count, &stored, &raw); err != nil {
+		return fmt.Errorf("스냅샷 본문 통계 조회 실패: %w", err)
+	}
+	stats.BlobCount += count
+	stats.StoredSizeBytes += stored
+	stats.RawSizeBytes += raw
+
+	tables := []string{"topic_inferences"}
+	if _, err := columnDeclaredType(q, "competitions", "content_hash"); err == nil {
+		tables = append(tables, "competitions")
+	}
+	for _, table := range tables {
+		var references int64
+		if err := q.QueryRow(
+			"SELECT COUNT(*) FROM " + table + " WHERE content_hash IS NOT NULL AND length(data) = 0",
+		).Scan(&references); err != nil {
+			return fmt.Errorf("%s 본문 참조 수 조회 실패: %w", table, err)
+		}
+		stats.ReferenceCount += references
+	}
+	return nil
+}
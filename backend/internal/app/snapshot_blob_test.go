@@ -0,0 +1,155 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// blobCount는 저장된 본문 수를 반환합니다
+func blobCount(t testing.TB, db *Database) int {
+	t.Helper()
+	return countRows(t, db, "SELECT COUNT(*) FROM snapshot_blobs")
+}
+
+func TestSnapshotBlobStorage(t *testing.T) {
+	db := newTestDatabase(t)
+	base := time.Now().Add(-2 * time.Hour)
+	workers := testWorkers(5)
+
+	first := testSnapshot("1", 100, base, workers, 0)
+	if err := db.SaveTopicInference(first); err != nil {
+		t.Fatal(err)
+	}
+	_, _, hash := storedSnapshot(t, db, "1", "100")
+	if want := expectedSnapshot(t, first).hash; hash != want {
+		t.Fatalf("저장된 해시 = %q, want %q", hash, want)
+	}
+	if count := countRows(t, db, "SELECT COUNT(*) FROM topic_inferences WHERE length(data) = 0 AND content_hash = ?", hash); count != 1 {
+		t.Fatalf("본문 저장소를 참조하는 행 = %d, want 1", count)
+	}
+	if count := countRows(t, db, "SELECT COUNT(*) FROM snapshot_blobs WHERE hash = ?", hash); count != 1 {
+		t.Fatalf("본문 저장소에 해시 %s가 없습니다", hash)
+	}
+	latest, err := db.GetLatestTopicInference("1")
+	if err != nil || latest["snapshot_hash"] != hash {
+		t.Fatalf("최신 스냅샷 해시 = %v (%v), want %s", latest["snapshot_hash"], err, hash)
+	}
+
+	// loss 높이와 타임스탬프만 바뀌면 본문은 그대로
+	relabeled := testSnapshot("1", 100, base.Add(time.Minute), workers, 0)
+	relabeled["loss_block_height"] = "95"
+	if err := db.SaveTopicInference(relabeled); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, got := storedSnapshot(t, db, "1", "100"); got != hash || blobCount(t, db) != 1 {
+		t.Errorf("메타데이터 변경 후 해시 = %s, 본문 수 = %d; want %s, 1", got, blobCount(t, db), hash)
+	}
+
+	// 본문이 바뀌면 새 본문을 저장하고 이전 본문은 정리
+	changed := testSnapshot("1", 100, base.Add(time.Minute), workers, 1)
+	if err := db.SaveTopicInference(changed); err != nil {
+		t.Fatal(err)
+	}
+	_, _, changedHash := storedSnapshot(t, db, "1", "100")
+	if changedHash == hash || changedHash != expectedSnapshot(t, changed).hash {
+		t.Errorf("본문 변경 후 해시 = %s, want %s", changedHash, expectedSnapshot(t, changed).hash)
+	}
+	if count := countRows(t, db, "SELECT COUNT(*) FROM snapshot_blobs WHERE hash = ?", hash); count != 0 || blobCount(t, db) != 1 {
+		t.Errorf("이전 본문 = %d개, 전체 본문 = %d개; want 0, 1", count, blobCount(t, db))
+	}
+
+	// 정리된 행의 본문은 함께 삭제
+	if err := db.SaveTopicInference(testSnapshot("1", 110, time.Now(), workers, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if blobCount(t, db) != 2 {
+		t.Fatalf("본문 수 = %d, want 2", blobCount(t, db))
+	}
+	if deleted, err := db.PruneOldTopicData("1", time.Hour); err != nil || deleted != 1 {
+		t.Fatalf("정리된 행 = %d (%v), want 1", deleted, err)
+	}
+	if count := countRows(t, db, "SELECT COUNT(*) FROM snapshot_blobs WHERE hash = ?", changedHash); count != 0 || blobCount(t, db) != 1 {
+		t.Errorf("정리 후 정리된 행의 본문 = %d개, 전체 본문 = %d개; want 0, 1", count, blobCount(t, db))
+	}
+
+	var stats SnapshotBlobStats
+	if err := snapshotBlobStats(db.reader, &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.BlobCount != 1 || stats.ReferenceCount != 1 {
+		t.Errorf("본문 통계 = %+v, want 본문 1개와 참조 1개", stats)
+	}
+}
+
+func TestCompetitionBlobHash(t *testing.T) {
+	db := newTestDatabase(t)
+
+	save := func(prizePool int) {
+		t.Helper()
+		competitions := &CompetitionsResponse{}
+		competitions.PageProps.CompetitionsPage.ActiveAndUpcomingCompetitions = []Competition{{ID: 1, Name: "competition", TopicID: 1, PrizePool: prizePool}}
+		if err := db.SaveCompetitions(competitions); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	save(100)
+	_, firstHash, err := db.GetLatestCompetitionsWithHash()
+	if err != nil || firstHash == "" {
+		t.Fatalf("경쟁 데이터 해시 조회 실패: %q (%v)", firstHash, err)
+	}
+	save(100)
+	save(200)
+	_, secondHash, err := db.GetLatestCompetitionsWithHash()
+	if err != nil || secondHash == firstHash {
+		t.Fatalf("변경 후 해시 = %q (%v), 이전 해시와 달라야 합니다", secondHash, err)
+	}
+
+	// 같은 본문은 다시 저장하지 않고, 이력 행은 본문 저장소를 참조
+	if count := countRows(t, db, "SELECT COUNT(*) FROM competitions WHERE length(data) = 0 AND content_hash IN (?, ?)", firstHash, secondHash); count != 2 {
+		t.Errorf("본문 저장소를 참조하는 경쟁 이력 = %d, want 2", count)
+	}
+	if count := countRows(t, db, "SELECT COUNT(*) FROM snapshot_blobs WHERE hash IN (?, ?)", firstHash, secondHash); count != 2 {
+		t.Errorf("저장된 경쟁 본문 = %d, want 2", count)
+	}
+}
+
+func TestMigrateContentHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	record := testSnapshot("1", 100, time.Now().Add(-time.Hour), testWorkers(3), 0)
+	createLegacyDatabase(t, path, []legacyRow{{id: 1, height: "100", loss: "90", record: record}})
+
+	db := openTestDatabase(t, path)
+	for _, table := range []string{"topic_inferences", "competitions"} {
+		if columnType, err := columnDeclaredType(db.db, table, "content_hash"); err != nil || columnType != "TEXT" {
+			t.Errorf("%s.content_hash 타입 = %q (%v), want TEXT", table, columnType, err)
+		}
+	}
+	for _, index := range []string{"idx_topic_inferences_content_hash", "idx_competitions_content_hash"} {
+		if count := countRows(t, db, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = ?", index); count != 1 {
+			t.Errorf("인덱스 %s가 없습니다", index)
+		}
+	}
+
+	// 이전 행은 본문을 그대로 두고 조회할 때 해시를 계산
+	want := expectedSnapshot(t, record).hash
+	if _, _, hash := storedSnapshot(t, db, "1", "100"); hash != "" {
+		t.Errorf("이전 행의 저장된 해시 = %q, want 없음", hash)
+	}
+	latest, err := db.GetLatestTopicInference("1")
+	if err != nil || latest["snapshot_hash"] != want {
+		t.Fatalf("이전 행의 스냅샷 해시 = %v (%v), want %s", latest["snapshot_hash"], err, want)
+	}
+
+	// 다시 저장하면 본문 저장소로 옮겨짐
+	if err := db.SaveTopicInference(record); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, hash := storedSnapshot(t, db, "1", "100"); hash != want {
+		t.Errorf("다시 저장한 행의 해시 = %q, want %s", hash, want)
+	}
+	if count := countRows(t, db, "SELECT COUNT(*) FROM topic_inferences WHERE length(data) = 0"); count != 1 {
+		t.Errorf("본문 저장소를 참조하는 행 = %d, want 1", count)
+	}
+}
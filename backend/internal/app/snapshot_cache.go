@@ -0,0 +1,236 @@
+package app
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// 스냅샷 캐시 기본값
+const (
+	defaultSnapshotCacheBytes = 64 << 20 // 64MB
+	defaultSnapshotCacheTTL   = 10 * time.Second
+)
+
+// SnapshotCacheStats는 스냅샷 캐시 지표입니다
+type SnapshotCacheStats struct {
+	Entries       int     `json:"entries"`
+	Bytes         int64   `json:"bytes"`
+	MaxBytes      int64   `json:"max_bytes"`
+	TTLSeconds    float64 `json:"ttl_seconds"`
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	Evictions     uint64  `json:"evictions"`
+	Invalidations uint64  `json:"invalidations"`
+	HitRatio      float64 `json:"hit_ratio"`
+}
+
+// snapshotCacheKey는 캐시 키입니다 (height가 비어 있으면 토픽의 최신 스냅샷)
+type snapshotCacheKey struct {
+	topicID string
+	height  string
+}
+
+// snapshotCacheEntry는 가공이 끝난 스냅샷 하나입니다
+type snapshotCacheEntry struct {
+	key     snapshotCacheKey
+	value   map[string]interface{}
+	size    int64 // JSON 인코딩 크기 (메모리 사용량 추정치)
+	expires time.Time
+}
+
+// snapshotCache는 가공된 스냅샷을 토픽/블록 높이별로 보관하는 크기 제한 LRU 캐시입니다
+// 같은 프로세스의 쓰기는 커밋 후 토픽 단위로 무효화하고, 다른 레플리카의 쓰기는 TTL로 반영합니다
+type snapshotCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	ttl      time.Duration
+	bytes    int64
+	entries  map[snapshotCacheKey]*list.Element
+	lru      *list.List // 앞쪽이 가장 최근에 사용한 항목
+
+	// 무효화 세대: 조회 시작 시점의 세대가 저장 시점과 다르면 저장하지 않음
+	// (커밋 전에 읽은 오래된 결과가 무효화 이후 캐시에 들어가는 것을 방지)
+	epoch       uint64
+	allEpoch    uint64
+	topicEpochs map[string]uint64
+
+	hits          uint64
+	misses        uint64
+	evictions     uint64
+	invalidations uint64
+}
+
+// newSnapshotCache는 새로운 스냅샷 캐시를 생성합니다
+func newSnapshotCache(maxBytes int64, ttl time.Duration) *snapshotCache {
+	return &snapshotCache{
+		maxBytes:    maxBytes,
+		ttl:         ttl,
+		entries:     make(map[snapshotCacheKey]*list.Element),
+		lru:         list.New(),
+		topicEpochs: make(map[string]uint64),
+	}
+}
+
+// generation은 토픽의 현재 무효화 세대를 반환합니다
+func (c *snapshotCache) generation(topicID string) uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return max(c.allEpoch, c.topicEpochs[topicID])
+}
+
+// get은 캐시된 스냅샷의 복사본을 반환합니다 (호출자가 결과를 수정해도 캐시에 영향 없음)
+func (c *snapshotCache) get(key snapshotCacheKey) (map[string]interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	element, ok := c.entries[key]
+	if ok && time.Now().After(element.Value.(*snapshotCacheEntry).expires) {
+		c.removeElement(element)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		c.mu.Unlock()
+		return nil, false
+	}
+	c.hits++
+	c.lru.MoveToFront(element)
+	value := element.Value.(*snapshotCacheEntry).value
+	c.mu.Unlock()
+
+	return cloneSnapshot(value), true
+}
+
+// put은 조회 시작 시점의 세대(gen)가 그대로일 때만 스냅샷을 저장합니다
+// 캐시 전체 크기보다 큰 스냅샷은 저장하지 않습니다
+func (c *snapshotCache) put(key snapshotCacheKey, value map[string]interface{}, gen uint64) {
+	if c == nil || value == nil {
+		return
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	size := int64(len(encoded))
+	if size > c.maxBytes {
+		return
+	}
+	entry := &snapshotCacheEntry{
+		key:     key,
+		value:   cloneSnapshot(value),
+		size:    size,
+		expires: time.Now().Add(c.ttl),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if max(c.allEpoch, c.topicEpochs[key.topicID]) != gen {
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		c.removeElement(element)
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	c.bytes += size
+	for c.bytes > c.maxBytes {
+		oldest := c.lru.Back()
+		if oldest == nil {
+			break
+		}
+		c.removeElement(oldest)
+		c.evictions++
+	}
+}
+
+// invalidateTopic은 토픽의 모든 캐시 항목을 제거합니다 (쓰기 커밋 후 호출)
+func (c *snapshotCache) invalidateTopic(topicID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	c.topicEpochs[topicID] = c.epoch
+	c.invalidations++
+	for key, element := range c.entries {
+		if key.topicID == topicID {
+			c.removeElement(element)
+		}
+	}
+}
+
+// invalidateAll은 모든 캐시 항목을 제거합니다 (여러 토픽에 걸친 정리/격리 후 호출)
+func (c *snapshotCache) invalidateAll() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	c.allEpoch = c.epoch
+	c.topicEpochs = make(map[string]uint64)
+	c.invalidations++
+	c.entries = make(map[snapshotCacheKey]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+}
+
+// removeElement는 항목 하나를 제거합니다 (mu를 보유한 상태에서 호출)
+func (c *snapshotCache) removeElement(element *list.Element) {
+	entry := element.Value.(*snapshotCacheEntry)
+	c.lru.Remove(element)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+}
+
+// stats는 캐시 지표를 반환합니다
+func (c *snapshotCache) stats() SnapshotCacheStats {
+	if c == nil {
+		return SnapshotCacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := SnapshotCacheStats{
+		Entries:       len(c.entries),
+		Bytes:         c.bytes,
+		MaxBytes:      c.maxBytes,
+		TTLSeconds:    c.ttl.Seconds(),
+		Hits:          c.hits,
+		Misses:        c.misses,
+		Evictions:     c.evictions,
+		Invalidations: c.invalidations,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRatio = float64(c.hits) / float64(total)
+	}
+	return stats
+}
+
+// SetSnapshotCache는 가공된 스냅샷 캐시의 최대 크기와 TTL을 설정합니다
+// 기존 캐시 항목은 비웁니다
+func (d *Database) SetSnapshotCache(maxBytes int64, ttl time.Duration) {
+	if maxBytes <= 0 {
+		maxBytes = defaultSnapshotCacheBytes
+	}
+	if ttl <= 0 {
+		ttl = defaultSnapshotCacheTTL
+	}
+	d.cache = newSnapshotCache(maxBytes, ttl)
+}
+
+// SnapshotCacheStats는 스냅샷 캐시 지표를 반환합니다
+func (d *Database) SnapshotCacheStats() SnapshotCacheStats {
+	return d.cache.stats()
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// latestHeight는 최신 스냅샷의 블록 높이를 반환합니다
+func latestHeight(t *testing.T, db *Database, topicID string) interface{} {
+	t.Helper()
+	latest, err := db.GetLatestTopicInference(topicID)
+	if err != nil || latest == nil {
+		t.Fatalf("토픽 %s 최신 스냅샷 조회 실패: %v", topicID, err)
+	}
+	return latest["inference_block_height"]
+}
+
+func TestSnapshotCacheInvalidatesOnWrite(t *testing.T) {
+	db := newTestDatabase(t)
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(4)
+	for topicID, height := range map[string]int{"1": 100, "2": 200} {
+		if err := db.SaveTopicInference(testSnapshot(topicID, height, base, workers, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 두 번째 조회는 캐시에서 응답하고, 반환된 결과를 바꿔도 캐시에는 영향 없음
+	latestHeight(t, db, "1")
+	latestHeight(t, db, "2")
+	cached, err := db.GetLatestTopicInference("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached["inference_block_height"] = "tampered"
+	if got := latestHeight(t, db, "1"); got != "100" {
+		t.Errorf("캐시된 최신 높이 = %v, want 100", got)
+	}
+	if stats := db.SnapshotCacheStats(); stats.Hits != 2 || stats.Misses != 2 || stats.Entries != 2 {
+		t.Errorf("캐시 통계 = %+v, want 적중 2, 실패 2, 항목 2", stats)
+	}
+
+	// 저장하면 해당 토픽만 무효화
+	invalidations := db.SnapshotCacheStats().Invalidations
+	if err := db.SaveTopicInference(testSnapshot("1", 110, base.Add(time.Minute), workers, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if stats := db.SnapshotCacheStats(); stats.Entries != 1 || stats.Invalidations != invalidations+1 {
+		t.Errorf("저장 후 캐시 통계 = %+v, want 항목 1 (토픽 2), 무효화 %d", stats, invalidations+1)
+	}
+	if got := latestHeight(t, db, "1"); got != "110" {
+		t.Errorf("저장 후 최신 높이 = %v, want 110", got)
+	}
+
+	// 블록 높이별 조회도 정리 후에는 캐시가 아닌 데이터베이스 결과
+	if snapshot, err := db.GetTopicInferenceByHeight("1", "100"); err != nil || snapshot == nil {
+		t.Fatalf("높이 100 조회 실패: %v", err)
+	}
+	if _, err := db.db.Exec("UPDATE topic_inferences SET timestamp = ? WHERE inference_block_height = 100", base.Add(-48*time.Hour).Format(time.RFC3339)); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, err := db.PruneOldTopicData("1", 24*time.Hour); err != nil || deleted != 1 {
+		t.Fatalf("정리된 행 = %d (%v), want 1", deleted, err)
+	}
+	if snapshot, err := db.GetTopicInferenceByHeight("1", "100"); err != nil || snapshot != nil {
+		t.Errorf("정리된 높이 100 조회 = %v (%v), want 없음", snapshot != nil, err)
+	}
+}
+
+func TestSnapshotCacheRejectsStaleGeneration(t *testing.T) {
+	cache := newSnapshotCache(defaultSnapshotCacheBytes, time.Minute)
+	key := snapshotCacheKey{topicID: "1"}
+	value := map[string]interface{}{"inference_block_height": "100"}
+
+	// 조회 도중 무효화되면 조회 시작 시점의 결과는 저장하지 않음
+	gen := cache.generation("1")
+	cache.invalidateTopic("1")
+	cache.put(key, value, gen)
+	if _, ok := cache.get(key); ok {
+		t.Error("무효화 이전 세대의 결과가 캐시에 저장되었습니다")
+	}
+
+	// 다른 토픽의 무효화는 영향 없음
+	gen = cache.generation("1")
+	otherGen := cache.generation("2")
+	cache.invalidateTopic("2")
+	cache.put(key, value, gen)
+	if _, ok := cache.get(key); !ok {
+		t.Error("다른 토픽 무효화 후 결과가 저장되지 않았습니다")
+	}
+
+	// 전체 무효화는 모든 토픽의 이전 세대를 거부
+	cache.invalidateAll()
+	cache.put(key, value, gen)
+	cache.put(snapshotCacheKey{topicID: "2"}, value, otherGen)
+	if stats := cache.stats(); stats.Entries != 0 {
+		t.Errorf("전체 무효화 후 항목 = %d, want 0", stats.Entries)
+	}
+	cache.put(key, value, cache.generation("1"))
+	if _, ok := cache.get(key); !ok {
+		t.Error("전체 무효화 후 새 세대의 결과가 저장되지 않았습니다")
+	}
+}
+
+func TestSnapshotCacheSizeAndTTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	db := openTestDatabase(t, path)
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(20)
+	for _, topicID := range []string{"1", "2", "3"} {
+		if err := db.SaveTopicInference(testSnapshot(topicID, 100, base, workers, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 스냅샷 두 개보다 작은 캐시는 오래된 항목부터 제거
+	latestHeight(t, db, "1")
+	size := db.SnapshotCacheStats().Bytes
+	db.SetSnapshotCache(size*2-1, time.Minute)
+	for _, topicID := range []string{"1", "2", "3"} {
+		latestHeight(t, db, topicID)
+	}
+	if stats := db.SnapshotCacheStats(); stats.Entries != 1 || stats.Evictions != 2 || stats.Bytes > stats.MaxBytes {
+		t.Errorf("크기 제한 캐시 통계 = %+v, want 항목 1, 제거 2", stats)
+	}
+
+	// 다른 레플리카(같은 파일의 다른 연결)의 쓰기는 TTL이 지난 뒤 반영
+	ttl := 300 * time.Millisecond
+	db.SetSnapshotCache(defaultSnapshotCacheBytes, ttl)
+	latestHeight(t, db, "1")
+	replica := openTestDatabase(t, path)
+	if err := replica.SaveTopicInference(testSnapshot("1", 110, base.Add(time.Minute), workers, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if got := latestHeight(t, db, "1"); got != "100" {
+		t.Errorf("TTL 이전 최신 높이 = %v, want 100 (캐시)", got)
+	}
+	time.Sleep(ttl + 50*time.Millisecond)
+	if got := latestHeight(t, db, "1"); got != "110" {
+		t.Errorf("TTL 이후 최신 높이 = %v, want 110", got)
+	}
+}
@@ -0,0 +1,818 @@
+package app
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/golang/snappy"
+)
+
+// 스냅샷 저장 방식
+const (
+	snapshotEncodingFull  = "full"  // 전체 스냅샷 (키프레임)
+	snapshotEncodingDelta = "delta" // 직전 스냅샷 대비 델타
+)
+
+// 기본 키프레임 간격 (키프레임 하나에 연결되는 최대 델타 수)
+const defaultSnapshotKeyframeInterval = 50
+
+// sqlQueryer는 *sql.DB와 *sql.Tx가 공통으로 제공하는 메서드 집합입니다
+type sqlQueryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// snapshotDelta는 두 스냅샷 객체 사이의 차이를 표현합니다
+// - Set: 새로 추가되었거나 값이 바뀐 키 (값 전체 교체)
+// - Delete: 삭제된 키
+// - Objects: 하위 객체에 대한 재귀 델타
+// - Workers: worker 주소를 키로 하는 객체 배열(synthesis_value 등)에 대한 델타
+type snapshotDelta struct {
+	Set     map[string]interface{}      `json:"s,omitempty"`
+	Delete  []string                    `json:"d,omitempty"`
+	Objects map[string]*snapshotDelta   `json:"o,omitempty"`
+	Workers map[string]*workerListDelta `json:"w,omitempty"`
+}
+
+// workerListDelta는 worker 목록 배열의 차이를 worker 주소 단위로 표현합니다
+// 적용 결과의 순서는 이전 목록 순서에서 삭제된 worker를 빼고 추가된 worker를 주소 순으로 덧붙인 순서이며,
+// 실제 순서가 이와 다르면 Order에 실제 순서의 각 worker가 기본 순서에서 몇 번째인지 기록합니다
+type workerListDelta struct {
+	Set     map[string]interface{}    `json:"s,omitempty"`
+	Delete  []string                  `json:"d,omitempty"`
+	Objects map[string]*snapshotDelta `json:"o,omitempty"`
+	Order   []int                     `json:"r,omitempty"`
+}
+
+// isEmpty는 델타에 변경 사항이 없는지 확인합니다
+func (d *snapshotDelta) isEmpty() bool {
+	return len(d.Set) == 0 && len(d.Delete) == 0 && len(d.Objects) == 0 && len(d.Workers) == 0
+}
+
+// isEmpty는 worker 목록 델타에 변경 사항이 없는지 확인합니다
+func (d *workerListDelta) isEmpty() bool {
+	return len(d.Set) == 0 && len(d.Delete) == 0 && len(d.Objects) == 0 && len(d.Order) == 0
+}
+
+// diffSnapshots는 prev에서 cur로 가는 델타를 계산합니다
+func diffSnapshots(prev, cur map[string]interface{}) *snapshotDelta {
+	delta := &snapshotDelta{}
+
+	for key := range prev {
+		if _, exists := cur[key]; !exists {
+			delta.Delete = append(delta.Delete, key)
+		}
+	}
+	sort.Strings(delta.Delete)
+
+	for key, curValue := range cur {
+		prevValue, exists := prev[key]
+		if !exists {
+			setDeltaValue(delta, key, curValue)
+			continue
+		}
+
+		// 하위 객체는 재귀적으로 비교
+		prevMap, prevIsMap := prevValue.(map[string]interface{})
+		curMap, curIsMap := curValue.(map[string]interface{})
+		if prevIsMap && curIsMap {
+			if sub := diffSnapshots(prevMap, curMap); !sub.isEmpty() {
+				if delta.Objects == nil {
+					delta.Objects = make(map[string]*snapshotDelta)
+				}
+				delta.Objects[key] = sub
+			}
+			continue
+		}
+
+		// worker 목록 배열은 worker 주소 단위로 비교
+		prevWorkers, prevOrder, prevIsWorkers := workerListIndex(prevValue)
+		curWorkers, curOrder, curIsWorkers := workerListIndex(curValue)
+		if prevIsWorkers && curIsWorkers {
+			if sub := diffWorkerLists(prevWorkers, curWorkers, prevOrder, curOrder); !sub.isEmpty() {
+				if delta.Workers == nil {
+					delta.Workers = make(map[string]*workerListDelta)
+				}
+				delta.Workers[key] = sub
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(prevValue, curValue) {
+			setDeltaValue(delta, key, curValue)
+		}
+	}
+
+	return delta
+}
+
+// setDeltaValue는 델타의 Set 맵에 값을 기록합니다
+func setDeltaValue(delta *snapshotDelta, key string, value interface{}) {
+	if delta.Set == nil {
+		delta.Set = make(map[string]interface{})
+	}
+	delta.Set[key] = value
+}
+
+// diffWorkerLists는 worker 주소별 객체 맵 사이의 델타를 계산합니다
+// prevOrder와 curOrder는 각 목록의 worker 주소 순서입니다
+func diffWorkerLists(prev, cur map[string]map[string]interface{}, prevOrder, curOrder []string) *workerListDelta {
+	delta := &workerListDelta{}
+
+	for worker := range prev {
+		if _, exists := cur[worker]; !exists {
+			delta.Delete = append(delta.Delete, worker)
+		}
+	}
+	sort.Strings(delta.Delete)
+
+	for worker, curEntry := range cur {
+		prevEntry, exists := prev[worker]
+		if !exists {
+			if delta.Set == nil {
+				delta.Set = make(map[string]interface{})
+			}
+			delta.Set[worker] = curEntry
+			continue
+		}
+
+		if sub := diffSnapshots(prevEntry, curEntry); !sub.isEmpty() {
+			if delta.Objects == nil {
+				delta.Objects = make(map[string]*snapshotDelta)
+			}
+			delta.Objects[worker] = sub
+		}
+	}
+
+	defaultOrder := defaultWorkerOrder(prevOrder, delta)
+	if !reflect.DeepEqual(defaultOrder, curOrder) {
+		position := make(map[string]int, len(defaultOrder))
+		for i, worker := range defaultOrder {
+			position[worker] = i
+		}
+		delta.Order = make([]int, len(curOrder))
+		for i, worker := range curOrder {
+			delta.Order[i] = position[worker]
+		}
+	}
+
+	return delta
+}
+
+// defaultWorkerOrder는 Order가 없는 델타를 적용했을 때의 worker 순서를 계산합니다
+// 이전 순서에서 삭제된 worker를 빼고, 추가된 worker를 주소 순으로 덧붙입니다
+func defaultWorkerOrder(prevOrder []string, delta *workerListDelta) []string {
+	deleted := make(map[string]bool, len(delta.Delete))
+	for _, worker := range delta.Delete {
+		deleted[worker] = true
+	}
+
+	order := make([]string, 0, len(prevOrder)+len(delta.Set))
+	existing := make(map[string]bool, len(prevOrder))
+	for _, worker := range prevOrder {
+		existing[worker] = true
+		if !deleted[worker] {
+			order = append(order, worker)
+		}
+	}
+
+	var added []string
+	for worker := range delta.Set {
+		if !existing[worker] {
+			added = append(added, worker)
+		}
+	}
+	sort.Strings(added)
+	return append(order, added...)
+}
+
+// applyWorkerOrder는 기본 순서에 델타의 위치 목록을 적용한 worker 순서를 반환합니다 (위치 목록이 없으면 기본 순서)
+func applyWorkerOrder(defaultOrder []string, positions []int) ([]string, error) {
+	if len(positions) == 0 {
+		return defaultOrder, nil
+	}
+	if len(positions) != len(defaultOrder) {
+		return nil, fmt.Errorf("worker 순서 길이(%d)가 항목 수(%d)와 다릅니다", len(positions), len(defaultOrder))
+	}
+
+	order := make([]string, len(positions))
+	used := make([]bool, len(defaultOrder))
+	for i, position := range positions {
+		if position < 0 || position >= len(defaultOrder) || used[position] {
+			return nil, fmt.Errorf("worker 순서에 잘못된 위치 %d가 있습니다", position)
+		}
+		used[position] = true
+		order[i] = defaultOrder[position]
+	}
+	return order, nil
+}
+
+// workerListIndex는 값이 worker 객체 배열이면 worker 주소를 키로 하는 맵과 배열 안의 worker 순서를 반환합니다
+func workerListIndex(value interface{}) (map[string]map[string]interface{}, []string, bool) {
+	list, ok := value.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, nil, false
+	}
+
+	index := make(map[string]map[string]interface{}, len(list))
+	order := make([]string, 0, len(list))
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, nil, false
+		}
+		worker, ok := entry["worker"].(string)
+		if !ok || worker == "" {
+			return nil, nil, false
+		}
+		if _, duplicated := index[worker]; duplicated {
+			return nil, nil, false
+		}
+		index[worker] = entry
+		order = append(order, worker)
+	}
+
+	return index, order, true
+}
+
+// applySnapshotDelta는 base 스냅샷에 델타를 적용합니다 (base는 직접 수정됩니다)
+func applySnapshotDelta(base map[string]interface{}, delta *snapshotDelta) error {
+	for _, key := range delta.Delete {
+		delete(base, key)
+	}
+
+	for key, value := range delta.Set {
+		base[key] = value
+	}
+
+	for key, sub := range delta.Objects {
+		target, ok := base[key].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("델타 적용 실패: %s 필드가 객체가 아닙니다", key)
+		}
+		if err := applySnapshotDelta(target, sub); err != nil {
+			return err
+		}
+	}
+
+	for key, sub := range delta.Workers {
+		index, prevOrder, ok := workerListIndex(base[key])
+		if !ok {
+			return fmt.Errorf("델타 적용 실패: %s 필드가 worker 목록이 아닙니다", key)
+		}
+
+		for _, worker := range sub.Delete {
+			delete(index, worker)
+		}
+		for worker, value := range sub.Set {
+			entry, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("델타 적용 실패: worker %s 항목이 객체가 아닙니다", worker)
+			}
+			index[worker] = entry
+		}
+		for worker, entryDelta := range sub.Objects {
+			entry, ok := index[worker]
+			if !ok {
+				return fmt.Errorf("델타 적용 실패: worker %s 항목이 없습니다", worker)
+			}
+			if err := applySnapshotDelta(entry, entryDelta); err != nil {
+				return err
+			}
+		}
+
+		order, err := applyWorkerOrder(defaultWorkerOrder(prevOrder, sub), sub.Order)
+		if err != nil {
+			return fmt.Errorf("델타 적용 실패: %s 필드의 %w", key, err)
+		}
+		if len(order) != len(index) {
+			return fmt.Errorf("델타 적용 실패: %s 필드의 worker 순서(%d개)와 항목 수(%d개)가 다릅니다", key, len(order), len(index))
+		}
+
+		list := make([]interface{}, 0, len(order))
+		for _, worker := range order {
+			entry, ok := index[worker]
+			if !ok {
+				return fmt.Errorf("델타 적용 실패: %s 필드의 worker 순서에 없는 worker %s가 있습니다", key, worker)
+			}
+			list = append(list, entry)
+		}
+		base[key] = list
+	}
+
+	return nil
+}
+
+// snapshotRow는 topic_inferences 테이블의 저장 형식 정보를 담는 행입니다
+type snapshotRow struct {
+	ID         int64
+	Timestamp  string
+	Encoding   string
+	KeyframeID sql.NullInt64
+	Data       []byte
+}
+
+// chainID는 행이 속한 키프레임 체인의 ID를 반환합니다
+func (r snapshotRow) chainID() int64 {
+	if r.Encoding == snapshotEncodingDelta && r.KeyframeID.Valid {
+		return r.KeyframeID.Int64
+	}
+	return r.ID
+}
+
+// loadSnapshotChain은 키프레임부터 uptoID까지의 체인 행을 ID 순으로 가져옵니다
+// fromID보다 큰 ID의 행만 가져오며, fromID가 0이면 키프레임부터 가져옵니다
+// columns는 snapshotRow로 스캔할 컬럼 목록입니다 (보통 snapshotRowColumns)
+func loadSnapshotChain(q sqlQueryer, columns string, keyframeID, fromID, uptoID int64) ([]snapshotRow, error) {
+	rows, err := q.Query(
+		`SELECT `+columns+` FROM topic_inferences
+		WHERE (id = ? OR keyframe_id = ?) AND id > ? AND id <= ?
+		ORDER BY id`,
+		keyframeID, keyframeID, fromID, uptoID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("스냅샷 체인 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var chain []snapshotRow
+	for rows.Next() {
+		var row snapshotRow
+		if err := rows.Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data); err != nil {
+			return nil, fmt.Errorf("스냅샷 체인 스캔 실패: %w", err)
+		}
+		chain = append(chain, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("스냅샷 체인 처리 중 오류: %w", err)
+	}
+
+	return chain, nil
+}
+
+// snapshotDecoder는 키프레임/델타로 저장된 스냅샷을 전체 스냅샷으로 복원합니다
+// 같은 체인의 행을 ID 순으로 연속해서 복원하는 경우 이전 결과를 재사용합니다
+type snapshotDecoder struct {
+	q         sqlQueryer
+	columns   string // 체인을 조회할 컬럼 목록
+	chainID   int64
+	lastID    int64
+	lastState map[string]interface{}
+}
+
+// newSnapshotDecoder는 새로운 스냅샷 디코더를 생성합니다
+func newSnapshotDecoder(q sqlQueryer) *snapshotDecoder {
+	return &snapshotDecoder{q: q, columns: snapshotRowColumns}
+}
+
+// decode는 행을 전체 스냅샷 객체로 복원합니다
+// 반환된 맵은 디코더 내부 상태와 공유되지 않습니다
+func (sd *snapshotDecoder) decode(row snapshotRow) (map[string]interface{}, error) {
+	if row.Encoding != snapshotEncodingDelta {
+		state, err := decodeSnapshotBlob(row.Data)
+		if err != nil {
+			return nil, err
+		}
+		sd.remember(row.ID, row.ID, state)
+		return cloneSnapshot(state), nil
+	}
+
+	if !row.KeyframeID.Valid {
+		return nil, fmt.Errorf("델타 스냅샷(ID=%d)에 키프레임 정보가 없습니다", row.ID)
+	}
+	keyframeID := row.KeyframeID.Int64
+
+	// 같은 체인의 앞선 상태가 있으면 그 이후의 델타만 적용
+	var state map[string]interface{}
+	fromID := int64(0)
+	if sd.lastState != nil && sd.chainID == keyframeID && sd.lastID < row.ID {
+		state = sd.lastState
+		fromID = sd.lastID
+	}
+
+	chain, err := loadSnapshotChain(sd.q, sd.columns, keyframeID, fromID, row.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 || chain[len(chain)-1].ID != row.ID {
+		return nil, fmt.Errorf("델타 스냅샷(ID=%d)의 체인을 찾을 수 없습니다", row.ID)
+	}
+
+	for _, link := range chain {
+		if link.Encoding != snapshotEncodingDelta {
+			if link.ID != keyframeID {
+				return nil, fmt.Errorf("키프레임 체인(ID=%d)에 예상치 못한 전체 스냅샷(ID=%d)이 있습니다", keyframeID, link.ID)
+			}
+			state, err = decodeSnapshotBlob(link.Data)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if state == nil {
+			return nil, fmt.Errorf("델타 스냅샷(ID=%d)의 키프레임(ID=%d)이 없습니다", row.ID, keyframeID)
+		}
+
+		delta, err := decodeDeltaBlob(link.Data)
+		if err != nil {
+			return nil, err
+		}
+		if err := applySnapshotDelta(state, delta); err != nil {
+			return nil, fmt.Errorf("델타 스냅샷(ID=%d) 적용 실패: %w", link.ID, err)
+		}
+	}
+
+	sd.remember(keyframeID, row.ID, state)
+	return cloneSnapshot(state), nil
+}
+
+// remember는 마지막으로 복원한 체인 상태를 기억합니다
+func (sd *snapshotDecoder) remember(chainID, id int64, state map[string]interface{}) {
+	sd.chainID = chainID
+	sd.lastID = id
+	sd.lastState = state
+}
+
+// decodeJSON은 행을 전체 스냅샷 JSON으로 복원합니다
+func (sd *snapshotDecoder) decodeJSON(row snapshotRow) ([]byte, error) {
+	if row.Encoding != snapshotEncodingDelta {
+		jsonData, err := snappy.Decode(nil, row.Data)
+		if err != nil {
+			return nil, fmt.Errorf("압축 해제 실패: %w", err)
+		}
+		return jsonData, nil
+	}
+
+	state, err := sd.decode(row)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("JSON 마샬링 실패: %w", err)
+	}
+	return jsonData, nil
+}
+
+// decodeSnapshotBlob은 압축된 전체 스냅샷을 객체로 변환합니다
+func decodeSnapshotBlob(data []byte) (map[string]interface{}, error) {
+	jsonData, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, fmt.Errorf("압축 해제 실패: %w", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(jsonData, &state); err != nil {
+		return nil, fmt.Errorf("JSON 언마샬링 실패: %w", err)
+	}
+	return state, nil
+}
+
+// decodeDeltaBlob은 압축된 델타를 변환합니다
+func decodeDeltaBlob(data []byte) (*snapshotDelta, error) {
+	jsonData, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, fmt.Errorf("델타 압축 해제 실패: %w", err)
+	}
+
+	var delta snapshotDelta
+	if err := json.Unmarshal(jsonData, &delta); err != nil {
+		return nil, fmt.Errorf("델타 JSON 언마샬링 실패: %w", err)
+	}
+	return &delta, nil
+}
+
+// encodeDeltaBlob은 델타를 JSON으로 마샬링하고 압축합니다
+func encodeDeltaBlob(delta *snapshotDelta) ([]byte, error) {
+	jsonData, err := json.Marshal(delta)
+	if err != nil {
+		return nil, fmt.Errorf("델타 JSON 마샬링 실패: %w", err)
+	}
+	return snappy.Encode(nil, jsonData), nil
+}
+
+// cloneSnapshot은 JSON 호환 객체를 깊은 복사합니다
+func cloneSnapshot(state map[string]interface{}) map[string]interface{} {
+	return cloneJSONValue(state).(map[string]interface{})
+}
+
+// cloneJSONValue는 JSON 호환 값을 깊은 복사합니다
+func cloneJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = cloneJSONValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = cloneJSONValue(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// normalizeSnapshot은 구조체가 섞인 스냅샷을 JSON 호환 객체로 변환합니다
+func normalizeSnapshot(data map[string]interface{}) (map[string]interface{}, []byte, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("JSON 마샬링 실패: %w", err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(jsonData, &state); err != nil {
+		return nil, nil, fmt.Errorf("JSON 언마샬링 실패: %w", err)
+	}
+	return state, jsonData, nil
+}
+
+// findSnapshotRow는 쿼리 결과의 첫 번째 스냅샷 행을 조회합니다 (없으면 nil)
+func findSnapshotRow(q sqlQueryer, query string, args ...interface{}) (*snapshotRow, error) {
+	var row snapshotRow
+	err := q.QueryRow(query, args...).Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// insertSnapshot은 새 스냅샷 행을 삽입합니다
+// 델타 저장이 활성화되어 있으면 같은 토픽의 직전 스냅샷 대비 델타로 저장하고,
+// 체인 길이가 키프레임 간격에 도달했거나 델타가 더 크면 전체 스냅샷(키프레임)으로 저장합니다
+// 전체 스냅샷의 본문은 snapshot_blobs에 저장하고 행은 해시로 참조합니다
+func (d *Database) insertSnapshot(tx *sql.Tx, topicID, timestamp, inferenceBlockHeight, lossBlockHeight string, content *blobContent) (int64, string, error) {
+	encoding := snapshotEncodingFull
+	blob := []byte{}
+	var keyframeID sql.NullInt64
+
+	if d.snapshotDelta {
+		latest, err := findSnapshotRow(tx,
+			"SELECT "+snapshotRowColumns+" FROM topic_inferences WHERE topic_id = ? ORDER BY id DESC LIMIT 1",
+			topicID,
+		)
+		if err != nil {
+			return 0, "", fmt.Errorf("직전 스냅샷 조회 실패: %w", err)
+		}
+
+		if latest != nil {
+			chainID := latest.chainID()
+
+			var chainLength int
+			if err := tx.QueryRow("SELECT COUNT(*) FROM topic_inferences WHERE keyframe_id = ?", chainID).Scan(&chainLength); err != nil {
+				return 0, "", fmt.Errorf("스냅샷 체인 길이 조회 실패: %w", err)
+			}
+
+			if chainLength+1 < d.keyframeInterval {
+				prevState, err := newSnapshotDecoder(tx).decode(*latest)
+				if err != nil {
+					return 0, "", fmt.Errorf("직전 스냅샷 복원 실패: %w", err)
+				}
+
+				deltaBlob, err := encodeDeltaBlob(diffSnapshots(stripSnapshotMetadata(prevState), content.state))
+				if err != nil {
+					return 0, "", err
+				}
+
+				if len(deltaBlob) < len(content.blob) {
+					encoding = snapshotEncodingDelta
+					blob = deltaBlob
+					keyframeID = sql.NullInt64{Int64: chainID, Valid: true}
+				}
+			}
+		}
+	}
+
+	if encoding == snapshotEncodingFull {
+		if err := putSnapshotBlob(tx, content); err != nil {
+			return 0, "", err
+		}
+	}
+
+	result, err := tx.Exec(
+		"INSERT INTO topic_inferences (topic_id, timestamp, inference_block_height, loss_block_height, data, encoding, keyframe_id, content_hash) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		topicID, timestamp, inferenceBlockHeight, lossBlockHeight, blob, encoding, keyframeID, content.hash,
+	)
+	if err != nil {
+		return 0, "", fmt.Errorf("토픽 데이터 저장 실패: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, "", fmt.Errorf("삽입 ID 확인 실패: %w", err)
+	}
+
+	return id, encoding, nil
+}
+
+// updateSnapshot은 기존 스냅샷 행의 내용을 교체합니다
+// 체인 중간의 행이 바뀌면 바로 다음 델타가 깨지므로 다음 행을 새 내용 기준으로 다시 인코딩합니다
+func (d *Database) updateSnapshot(tx *sql.Tx, id int64, timestamp, lossBlockHeight string, content *blobContent) (string, error) {
+	row, err := findSnapshotRow(tx, "SELECT "+snapshotRowColumns+" FROM topic_inferences WHERE id = ?", id)
+	if err != nil {
+		return "", fmt.Errorf("기존 스냅샷 조회 실패: %w", err)
+	}
+	if row == nil {
+		return "", fmt.Errorf("스냅샷(ID=%d)을 찾을 수 없습니다", id)
+	}
+
+	chainID := row.chainID()
+
+	// 바로 다음 체인 행은 변경 전에 복원해 둠
+	successor, err := findSnapshotRow(tx,
+		"SELECT "+snapshotRowColumns+" FROM topic_inferences WHERE keyframe_id = ? AND id > ? ORDER BY id LIMIT 1",
+		chainID, id,
+	)
+	if err != nil {
+		return "", fmt.Errorf("다음 스냅샷 조회 실패: %w", err)
+	}
+
+	var successorState map[string]interface{}
+	if successor != nil {
+		successorState, err = newSnapshotDecoder(tx).decode(*successor)
+		if err != nil {
+			return "", fmt.Errorf("다음 스냅샷 복원 실패: %w", err)
+		}
+		successorState = stripSnapshotMetadata(successorState)
+	}
+
+	// 델타 행은 체인 안의 이전 행 기준으로 다시 인코딩하고, 전체 스냅샷은 본문 저장소에 저장
+	blob := []byte{}
+	if row.Encoding == snapshotEncodingDelta {
+		predecessor, err := findSnapshotRow(tx,
+			"SELECT "+snapshotRowColumns+" FROM topic_inferences WHERE (id = ? OR keyframe_id = ?) AND id < ? ORDER BY id DESC LIMIT 1",
+			chainID, chainID, id,
+		)
+		if err != nil {
+			return "", fmt.Errorf("이전 스냅샷 조회 실패: %w", err)
+		}
+		if predecessor == nil {
+			return "", fmt.Errorf("델타 스냅샷(ID=%d)의 이전 행을 찾을 수 없습니다", id)
+		}
+
+		prevState, err := newSnapshotDecoder(tx).decode(*predecessor)
+		if err != nil {
+			return "", fmt.Errorf("이전 스냅샷 복원 실패: %w", err)
+		}
+
+		blob, err = encodeDeltaBlob(diffSnapshots(stripSnapshotMetadata(prevState), content.state))
+		if err != nil {
+			return "", err
+		}
+	} else if err := putSnapshotBlob(tx, content); err != nil {
+		return "", err
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE topic_inferences SET timestamp = ?, loss_block_height = ?, data = ?, content_hash = ? WHERE id = ?",
+		timestamp, lossBlockHeight, blob, content.hash, id,
+	); err != nil {
+		return "", fmt.Errorf("토픽 데이터 업데이트 실패: %w", err)
+	}
+
+	if successor != nil {
+		successorBlob, err := encodeDeltaBlob(diffSnapshots(content.state, successorState))
+		if err != nil {
+			return "", err
+		}
+		if _, err := tx.Exec("UPDATE topic_inferences SET data = ? WHERE id = ?", successorBlob, successor.ID); err != nil {
+			return "", fmt.Errorf("다음 스냅샷 재인코딩 실패: %w", err)
+		}
+	}
+
+	return row.Encoding, nil
+}
+
+// rebaseSnapshotChains는 삭제 예정 행이 포함된 체인의 남는 행들을 다시 인코딩합니다
+// 남는 첫 행을 키프레임으로 승격하고 나머지는 그 뒤의 델타로 다시 연결하므로
+// 키프레임이나 중간 델타가 삭제되어도 고아 델타가 생기지 않습니다
+func (d *Database) rebaseSnapshotChains(tx *sql.Tx, deleteCondition string, args ...interface{}) (int, error) {
+	// 삭제될 행과 남는 행이 함께 있는 체인 찾기
+	rows, err := tx.Query(
+		`SELECT DISTINCT CASE WHEN encoding = 'delta' THEN keyframe_id ELSE id END FROM topic_inferences WHERE `+deleteCondition+`
+		INTERSECT
+		SELECT DISTINCT CASE WHEN encoding = 'delta' THEN keyframe_id ELSE id END FROM topic_inferences WHERE NOT (`+deleteCondition+`)`,
+		append(append([]interface{}{}, args...), args...)...,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("재구성 대상 체인 조회 실패: %w", err)
+	}
+
+	var chainIDs []int64
+	for rows.Next() {
+		var chainID int64
+		if err := rows.Scan(&chainID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("체인 ID 스캔 실패: %w", err)
+		}
+		chainIDs = append(chainIDs, chainID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("체인 ID 처리 중 오류: %w", err)
+	}
+
+	for _, chainID := range chainIDs {
+		if err := d.rebaseSnapshotChain(tx, chainID, deleteCondition, args...); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(chainIDs), nil
+}
+
+// rebaseSnapshotChain은 하나의 체인에서 삭제되지 않을 행들을 새 체인으로 다시 인코딩합니다
+func (d *Database) rebaseSnapshotChain(tx *sql.Tx, chainID int64, deleteCondition string, args ...interface{}) error {
+	// 남는 행의 ID 목록
+	keepRows, err := tx.Query(
+		`SELECT id FROM topic_inferences WHERE (id = ? OR keyframe_id = ?) AND NOT (`+deleteCondition+`) ORDER BY id`,
+		append([]interface{}{chainID, chainID}, args...)...,
+	)
+	if err != nil {
+		return fmt.Errorf("체인(ID=%d) 유지 행 조회 실패: %w", chainID, err)
+	}
+	keep := make(map[int64]bool)
+	for keepRows.Next() {
+		var id int64
+		if err := keepRows.Scan(&id); err != nil {
+			keepRows.Close()
+			return fmt.Errorf("유지 행 스캔 실패: %w", err)
+		}
+		keep[id] = true
+	}
+	keepRows.Close()
+
+	// 체인 전체를 복원한 뒤 남는 행만 다시 인코딩
+	// content_hash 컬럼이 없는 테이블(마이그레이션 중)은 본문 저장소 없이 행에 본문을 저장
+	columns := snapshotRowColumnsFor(tx)
+	legacy := columns == legacySnapshotRowColumns
+	chain, err := loadSnapshotChain(tx, columns, chainID, 0, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+
+	decoder := newSnapshotDecoder(tx)
+	decoder.columns = columns
+	var newKeyframeID int64
+	var prevState map[string]interface{}
+
+	for _, link := range chain {
+		state, err := decoder.decode(link)
+		if err != nil {
+			return fmt.Errorf("체인(ID=%d) 복원 실패: %w", chainID, err)
+		}
+		if !keep[link.ID] {
+			continue
+		}
+
+		content, err := newSnapshotContent(state)
+		if err != nil {
+			return err
+		}
+
+		// 남는 첫 행은 키프레임으로 승격 (본문은 본문 저장소에 저장), 나머지는 델타로 다시 인코딩
+		encoding, keyframeID, data := snapshotEncodingFull, interface{}(nil), content.blob
+		if prevState == nil {
+			if !legacy {
+				if err := putSnapshotBlob(tx, content); err != nil {
+					return err
+				}
+				data = []byte{}
+			}
+			newKeyframeID = link.ID
+		} else {
+			encoding, keyframeID = snapshotEncodingDelta, newKeyframeID
+			if data, err = encodeDeltaBlob(diffSnapshots(prevState, content.state)); err != nil {
+				return err
+			}
+		}
+
+		query, args := "UPDATE topic_inferences SET encoding = ?, keyframe_id = ?, data = ?, content_hash = ? WHERE id = ?",
+			[]interface{}{encoding, keyframeID, data, content.hash, link.ID}
+		if legacy {
+			query, args = "UPDATE topic_inferences SET encoding = ?, keyframe_id = ?, data = ? WHERE id = ?",
+				[]interface{}{encoding, keyframeID, data, link.ID}
+		}
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("스냅샷 재인코딩 실패 (ID=%d): %w", link.ID, err)
+		}
+
+		prevState = content.state
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// workerList는 주소 순서대로 worker 객체 배열을 만듭니다
+func workerList(entries ...[2]string) []interface{} {
+	list := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, map[string]interface{}{"worker": entry[0], "value": entry[1]})
+	}
+	return list
+}
+
+func TestApplySnapshotDeltaPreservesWorkerOrder(t *testing.T) {
+	prev := workerList([2]string{"c", "1"}, [2]string{"a", "1"}, [2]string{"b", "1"})
+
+	tests := []struct {
+		name      string
+		cur       []interface{}
+		wantOrder bool // 델타에 Order가 기록되어야 하는지
+	}{
+		{"값만 변경", workerList([2]string{"c", "2"}, [2]string{"a", "1"}, [2]string{"b", "1"}), false},
+		{"순서 변경", workerList([2]string{"b", "1"}, [2]string{"c", "1"}, [2]string{"a", "1"}), true},
+		{"끝에 추가", workerList([2]string{"c", "1"}, [2]string{"a", "1"}, [2]string{"b", "1"}, [2]string{"d", "1"}), false},
+		{"중간에 추가", workerList([2]string{"c", "1"}, [2]string{"d", "1"}, [2]string{"a", "1"}, [2]string{"b", "1"}), true},
+		{"삭제", workerList([2]string{"c", "1"}, [2]string{"b", "3"}), false},
+		{"삭제 후 순서 변경", workerList([2]string{"b", "1"}, [2]string{"c", "1"}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prevState := map[string]interface{}{"synthesis_value": cloneJSONValue(prev)}
+			curState := map[string]interface{}{"synthesis_value": tt.cur}
+
+			delta := diffSnapshots(prevState, curState)
+			workers := delta.Workers["synthesis_value"]
+			if workers == nil {
+				t.Fatalf("worker 목록 델타가 없습니다: %+v", delta)
+			}
+			if got := len(workers.Order) > 0; got != tt.wantOrder {
+				t.Errorf("Order 기록 = %v, want %v (Order=%v)", got, tt.wantOrder, workers.Order)
+			}
+
+			// 저장 형식(JSON)을 거친 델타를 적용
+			blob, err := encodeDeltaBlob(delta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := decodeDeltaBlob(blob)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := applySnapshotDelta(prevState, decoded); err != nil {
+				t.Fatalf("델타 적용 실패: %v", err)
+			}
+			if !reflect.DeepEqual(prevState, curState) {
+				t.Errorf("복원 결과가 다릅니다\n got: %v\nwant: %v", prevState, curState)
+			}
+		})
+	}
+}
+
+func TestApplySnapshotDeltaRejectsInconsistentOrder(t *testing.T) {
+	base := map[string]interface{}{"synthesis_value": workerList([2]string{"a", "1"}, [2]string{"b", "1"})}
+	delta := &snapshotDelta{Workers: map[string]*workerListDelta{
+		"synthesis_value": {Order: []int{0, 0}},
+	}}
+	if err := applySnapshotDelta(base, delta); err == nil {
+		t.Fatal("중복된 위치가 있는 순서를 적용했는데 오류가 없습니다")
+	}
+}
+
+// rotateWorkers는 workers를 n칸 회전한 복사본을 반환합니다
+func rotateWorkers(workers []string, n int) []string {
+	rotated := make([]string, 0, len(workers))
+	rotated = append(rotated, workers[n%len(workers):]...)
+	return append(rotated, workers[:n%len(workers)]...)
+}
+
+// saveDeltaChain은 키프레임 간격 keyframeInterval로 count개의 스냅샷을 저장하고 저장한 레코드를 반환합니다
+// worker 배열은 주소 순이 아니며 몇 주기마다 순서가 바뀝니다
+func saveDeltaChain(t *testing.T, db *Database, count int, base time.Time) []map[string]interface{} {
+	t.Helper()
+	workers := rotateWorkers(testWorkers(20), 7)
+
+	records := make([]map[string]interface{}, 0, count)
+	for cycle := 0; cycle < count; cycle++ {
+		order := workers
+		if cycle%3 == 2 {
+			order = rotateWorkers(workers, cycle)
+		}
+		record := testSnapshot("1", 1000000+cycle*10, base.Add(time.Duration(cycle)*time.Minute), order, cycle)
+		if err := db.SaveTopicInference(record); err != nil {
+			t.Fatalf("스냅샷 %d 저장 실패: %v", cycle, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// assertSnapshotsRestored는 저장된 스냅샷이 원래 레코드와 배열 순서까지 같고 저장된 해시와 일치하는지 확인합니다
+// 저장 방식별 행 수를 반환합니다
+func assertSnapshotsRestored(t *testing.T, db *Database, records []map[string]interface{}) map[string]int {
+	t.Helper()
+	encodings := make(map[string]int)
+	for _, record := range records {
+		height := record["inference_block_height"].(string)
+		want := expectedSnapshot(t, record)
+
+		got, encoding, hash := storedSnapshot(t, db, "1", height)
+		encodings[encoding]++
+		if !reflect.DeepEqual(got, want.state) {
+			t.Errorf("높이 %s (%s) 복원 결과가 원본과 다릅니다\n got: %v\nwant: %v", height, encoding, got, want.state)
+			continue
+		}
+		content, err := newSnapshotContent(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if content.hash != hash || hash != want.hash {
+			t.Errorf("높이 %s (%s) 해시 불일치: 복원=%s, 저장=%s, 원본=%s", height, encoding, content.hash, hash, want.hash)
+		}
+	}
+	return encodings
+}
+
+func TestSnapshotDeltaRoundTrip(t *testing.T) {
+	db := newTestDatabase(t)
+	db.SetSnapshotDelta(true, 8)
+
+	records := saveDeltaChain(t, db, 20, time.Now().Add(-time.Hour))
+	encodings := assertSnapshotsRestored(t, db, records)
+
+	if encodings[snapshotEncodingDelta] == 0 || encodings[snapshotEncodingFull] < 3 {
+		t.Errorf("저장 방식 분포 = %v, 키프레임 3개 이상과 델타가 있어야 합니다", encodings)
+	}
+}
+
+func TestRebaseSnapshotChainsAfterKeyframePruned(t *testing.T) {
+	db := newTestDatabase(t)
+	db.SetSnapshotDelta(true, 50)
+
+	// 모두 한 체인: 첫 행이 키프레임, 나머지는 델타
+	base := time.Now().Add(-100 * time.Minute)
+	records := saveDeltaChain(t, db, 12, base)
+	if _, encoding, _ := storedSnapshot(t, db, "1", records[1]["inference_block_height"].(string)); encoding != snapshotEncodingDelta {
+		t.Fatalf("두 번째 스냅샷 저장 방식 = %s, want delta", encoding)
+	}
+
+	// 키프레임과 앞쪽 델타 4개(0~4분)를 정리
+	deleted, err := db.PruneOldTopicData("1", 100*time.Minute-4*time.Minute-30*time.Second)
+	if err != nil {
+		t.Fatalf("정리 실패: %v", err)
+	}
+	if deleted != 5 {
+		t.Fatalf("삭제된 행 = %d, want 5", deleted)
+	}
+
+	remaining := records[5:]
+	encodings := assertSnapshotsRestored(t, db, remaining)
+	if encodings[snapshotEncodingFull] != 1 || encodings[snapshotEncodingDelta] != len(remaining)-1 {
+		t.Errorf("재구성 후 저장 방식 분포 = %v, 키프레임 1개와 델타 %d개여야 합니다", encodings, len(remaining)-1)
+	}
+	if _, encoding, _ := storedSnapshot(t, db, "1", remaining[0]["inference_block_height"].(string)); encoding != snapshotEncodingFull {
+		t.Errorf("남은 첫 행의 저장 방식 = %s, want full", encoding)
+	}
+
+	// 재구성된 체인 뒤에도 델타로 이어서 저장
+	next := testSnapshot("1", 1000000+len(records)*10, base.Add(time.Duration(len(records))*time.Minute), rotateWorkers(testWorkers(20), 3), len(records))
+	if err := db.SaveTopicInference(next); err != nil {
+		t.Fatal(err)
+	}
+	assertSnapshotsRestored(t, db, append(remaining, next))
+}
+
+func TestSnapshotDeltaLegacyRowsWithoutOrder(t *testing.T) {
+	// Order가 없는 이전 델타는 기존 순서를 유지하고 추가된 worker를 주소 순으로 덧붙임
+	base := map[string]interface{}{"synthesis_value": workerList([2]string{"c", "1"}, [2]string{"a", "1"})}
+	delta := &snapshotDelta{Workers: map[string]*workerListDelta{
+		"synthesis_value": {
+			Set:    map[string]interface{}{"e": map[string]interface{}{"worker": "e", "value": "1"}, "d": map[string]interface{}{"worker": "d", "value": "1"}},
+			Delete: []string{"a"},
+		},
+	}}
+	if err := applySnapshotDelta(base, delta); err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	for _, item := range base["synthesis_value"].([]interface{}) {
+		order = append(order, item.(map[string]interface{})["worker"].(string))
+	}
+	if want := []string{"c", "d", "e"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("순서 = %v, want %v", order, want)
+	}
+}
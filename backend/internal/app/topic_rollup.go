@@ -0,0 +1,818 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 롤업 해상도 및 조회 계층
+const (
+	rollupResolutionHour = "hour" // 시간 단위 롤업
+	rollupResolutionDay  = "day"  // 일 단위 롤업
+
+	SeriesTierAuto   = "auto"   // 조회 범위에 맞게 자동 선택
+	SeriesTierRaw    = "raw"    // 원본 스냅샷
+	SeriesTierHourly = "hourly" // 시간 단위 롤업
+	SeriesTierDaily  = "daily"  // 일 단위 롤업
+)
+
+// seriesTierHeader는 배열로 응답하는 기간 조회에서 선택된 계층을 알려주는 응답 헤더입니다
+const seriesTierHeader = "X-Series-Tier"
+
+// 자동 계층 선택 시 계층별 최대 조회 범위 (이보다 긴 범위는 더 거친 계층 사용)
+const (
+	seriesRawMaxSpan    = 48 * time.Hour
+	seriesHourlyMaxSpan = 90 * 24 * time.Hour
+)
+
+// TopicSeriesPoint는 토픽 지표 시계열의 한 지점입니다
+// 원본 계층에서는 스냅샷 하나, 롤업 계층에서는 구간 하나를 나타냅니다
+type TopicSeriesPoint struct {
+	BucketStart string              `json:"bucket_start"`
+	SampleCount int                 `json:"sample_count"`
+	CombinedAvg *float64            `json:"combined_avg"`
+	CombinedMin *float64            `json:"combined_min"`
+	CombinedMax *float64            `json:"combined_max"`
+	NaiveAvg    *float64            `json:"naive_avg"`
+	WorkerCount float64             `json:"worker_count"` // 스냅샷당 평균 워커 수
+	FirstHeight int64               `json:"first_height"`
+	LastHeight  int64               `json:"last_height"`
+	Workers     []WorkerSeriesPoint `json:"workers,omitempty"`
+}
+
+// WorkerSeriesPoint는 시계열 지점 안의 워커별 지표입니다
+type WorkerSeriesPoint struct {
+	Worker      string   `json:"worker"`
+	SampleCount int      `json:"sample_count"`
+	InfererAvg  *float64 `json:"inferer_avg"`
+	InfererMin  *float64 `json:"inferer_min"`
+	InfererMax  *float64 `json:"inferer_max"`
+	OneOutAvg   *float64 `json:"one_out_avg"`
+	WeightAvg   *float64 `json:"weight_avg"`
+}
+
+// TopicSeries는 토픽 지표 시계열 조회 결과입니다
+type TopicSeries struct {
+	TopicID string             `json:"topic_id"`
+	Tier    string             `json:"tier"`
+	Start   string             `json:"start"`
+	End     string             `json:"end"`
+	Points  []TopicSeriesPoint `json:"points"`
+}
+
+// metricAccumulator는 평균/최소/최대 계산을 위한 누적값입니다
+type metricAccumulator struct {
+	sum   float64
+	min   float64
+	max   float64
+	count int
+}
+
+func (a *metricAccumulator) add(value float64) {
+	if a.count == 0 || value < a.min {
+		a.min = value
+	}
+	if a.count == 0 || value > a.max {
+		a.max = value
+	}
+	a.sum += value
+	a.count++
+}
+
+func (a *metricAccumulator) avg() *float64 {
+	if a.count == 0 {
+		return nil
+	}
+	value := a.sum / float64(a.count)
+	return &value
+}
+
+func (a *metricAccumulator) minValue() *float64 {
+	if a.count == 0 {
+		return nil
+	}
+	value := a.min
+	return &value
+}
+
+func (a *metricAccumulator) maxValue() *float64 {
+	if a.count == 0 {
+		return nil
+	}
+	value := a.max
+	return &value
+}
+
+// workerAccumulator는 구간 안의 워커별 누적값입니다
+type workerAccumulator struct {
+	samples int
+	inferer metricAccumulator
+	oneOut  metricAccumulator
+	weight  metricAccumulator
+}
+
+// rollupAccumulator는 구간 하나의 토픽/워커 지표 누적값입니다
+type rollupAccumulator struct {
+	samples     int
+	combined    metricAccumulator
+	naive       metricAccumulator
+	workerTotal int
+	firstHeight int64
+	lastHeight  int64
+	workers     map[string]*workerAccumulator
+}
+
+func newRollupAccumulator() *rollupAccumulator {
+	return &rollupAccumulator{workers: make(map[string]*workerAccumulator)}
+}
+
+// snapshotFloat는 스냅샷 맵에서 숫자 또는 숫자 문자열 값을 가져옵니다
+func snapshotFloat(data map[string]interface{}, key string) (float64, bool) {
+	switch value := data[key].(type) {
+	case float64:
+		return value, true
+	case string:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+			return 0, false
+		}
+		return parsed, true
+	}
+	return 0, false
+}
+
+// addSnapshot은 복원된 원본 스냅샷 하나를 누적합니다
+func (a *rollupAccumulator) addSnapshot(snapshot map[string]interface{}) {
+	a.samples++
+
+	if heightStr, ok := snapshot["inference_block_height"].(string); ok {
+		if height, err := strconv.ParseInt(heightStr, 10, 64); err == nil {
+			if a.firstHeight == 0 || height < a.firstHeight {
+				a.firstHeight = height
+			}
+			if height > a.lastHeight {
+				a.lastHeight = height
+			}
+		}
+	}
+
+	networkInferences, ok := snapshot["network_inferences"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if value, ok := snapshotFloat(networkInferences, "combined_value"); ok {
+		a.combined.add(value)
+	}
+	if value, ok := snapshotFloat(networkInferences, "naive_value"); ok {
+		a.naive.add(value)
+	}
+
+	synthesisValue, _ := networkInferences["synthesis_value"].([]interface{})
+	for _, item := range synthesisValue {
+		workerData, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		worker, _ := workerData["worker"].(string)
+		if worker == "" {
+			continue
+		}
+
+		acc, exists := a.workers[worker]
+		if !exists {
+			acc = &workerAccumulator{}
+			a.workers[worker] = acc
+		}
+		acc.samples++
+		a.workerTotal++
+
+		if value, ok := snapshotFloat(workerData, "inferer_values"); ok {
+			acc.inferer.add(value)
+		}
+		if value, ok := snapshotFloat(workerData, "one_out_inferer_values"); ok {
+			acc.oneOut.add(value)
+		}
+		if value, ok := snapshotFloat(workerData, "weight"); ok {
+			acc.weight.add(value)
+		}
+	}
+}
+
+// point는 누적값을 시계열 지점으로 변환합니다
+func (a *rollupAccumulator) point(bucketStart string) TopicSeriesPoint {
+	point := TopicSeriesPoint{
+		BucketStart: bucketStart,
+		SampleCount: a.samples,
+		CombinedAvg: a.combined.avg(),
+		CombinedMin: a.combined.minValue(),
+		CombinedMax: a.combined.maxValue(),
+		NaiveAvg:    a.naive.avg(),
+		FirstHeight: a.firstHeight,
+		LastHeight:  a.lastHeight,
+	}
+	if a.samples > 0 {
+		point.WorkerCount = float64(a.workerTotal) / float64(a.samples)
+	}
+
+	for worker, acc := range a.workers {
+		point.Workers = append(point.Workers, WorkerSeriesPoint{
+			Worker:      worker,
+			SampleCount: acc.samples,
+			InfererAvg:  acc.inferer.avg(),
+			InfererMin:  acc.inferer.minValue(),
+			InfererMax:  acc.inferer.maxValue(),
+			OneOutAvg:   acc.oneOut.avg(),
+			WeightAvg:   acc.weight.avg(),
+		})
+	}
+	sort.Slice(point.Workers, func(i, j int) bool {
+		return point.Workers[i].Worker < point.Workers[j].Worker
+	})
+
+	return point
+}
+
+// saveRollupPoint는 롤업 구간 하나를 topic_rollups/worker_rollups 테이블에 저장합니다
+func saveRollupPoint(tx *sql.Tx, topicID, resolution string, point TopicSeriesPoint) error {
+	_, err := tx.Exec(
+		`INSERT OR REPLACE INTO topic_rollups (
+			topic_id, resolution, bucket_start, sample_count,
+			combined_avg, combined_min, combined_max, naive_avg,
+			worker_count, first_height, last_height
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		topicID, resolution, point.BucketStart, point.SampleCount,
+		point.CombinedAvg, point.CombinedMin, point.CombinedMax, point.NaiveAvg,
+		point.WorkerCount, point.FirstHeight, point.LastHeight,
+	)
+	if err != nil {
+		return fmt.Errorf("토픽 롤업 저장 실패: %w", err)
+	}
+
+	// 다시 계산한 구간이면 이전 워커 롤업을 교체
+	_, err = tx.Exec(
+		"DELETE FROM worker_rollups WHERE topic_id = ? AND resolution = ? AND bucket_start = ?",
+		topicID, resolution, point.BucketStart,
+	)
+	if err != nil {
+		return fmt.Errorf("워커 롤업 삭제 실패: %w", err)
+	}
+
+	for _, worker := range point.Workers {
+		_, err = tx.Exec(
+			`INSERT INTO worker_rollups (
+				topic_id, resolution, bucket_start, worker, sample_count,
+				inferer_avg, inferer_min, inferer_max, one_out_avg, weight_avg
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			topicID, resolution, point.BucketStart, worker.Worker, worker.SampleCount,
+			worker.InfererAvg, worker.InfererMin, worker.InfererMax, worker.OneOutAvg, worker.WeightAvg,
+		)
+		if err != nil {
+			return fmt.Errorf("워커 롤업 저장 실패: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// rollupStart는 롤업을 다시 계산할 시작 구간을 결정합니다
+// 마지막으로 저장된 구간은 늦게 도착한 데이터를 반영하기 위해 다시 계산합니다
+// 롤업 테이블은 q에서, 원본 데이터의 시작 시각은 source에서 조회합니다 (토픽 샤드를 사용하면 서로 다른 파일)
+func rollupStart(q, source sqlQueryer, topicID, resolution, sourceQuery string, truncate func(time.Time) time.Time) (time.Time, bool, error) {
+	var lastBucket sql.NullString
+	err := q.QueryRow(
+		"SELECT MAX(bucket_start) FROM topic_rollups WHERE topic_id = ? AND resolution = ?",
+		topicID, resolution,
+	).Scan(&lastBucket)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("마지막 롤업 구간 조회 실패: %w", err)
+	}
+	if lastBucket.Valid {
+		start, err := time.Parse(time.RFC3339, lastBucket.String)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("롤업 구간 파싱 실패: %w", err)
+		}
+		return start, true, nil
+	}
+
+	var earliest sql.NullString
+	if err := source.QueryRow(sourceQuery, topicID).Scan(&earliest); err != nil {
+		return time.Time{}, false, fmt.Errorf("원본 데이터 시작 시각 조회 실패: %w", err)
+	}
+	if !earliest.Valid {
+		return time.Time{}, false, nil
+	}
+	start, err := time.Parse(time.RFC3339, earliest.String)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("원본 데이터 시각 파싱 실패: %w", err)
+	}
+	return truncate(start.UTC()), true, nil
+}
+
+// truncateToDay는 UTC 기준 자정으로 내림합니다
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// truncateToHour는 UTC 기준 정시로 내림합니다
+func truncateToHour(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
+
+// RollupTopicData는 완료된 시간/일 구간의 토픽 데이터를 롤업합니다
+// 시간 롤업은 원본 스냅샷에서, 일 롤업은 시간 롤업에서 계산합니다
+func (d *Database) RollupTopicData(topicID string, now time.Time) (int, error) {
+	hourly, err := d.rollupHourly(topicID, truncateToHour(now))
+	if err != nil {
+		return 0, err
+	}
+	daily, err := d.rollupDaily(topicID, truncateToDay(now))
+	if err != nil {
+		return hourly, err
+	}
+
+	if d.debug && hourly+daily > 0 {
+		log.Printf("RollupTopicData 완료: 토픽 ID=%s, 시간 롤업 %d개, 일 롤업 %d개", topicID, hourly, daily)
+	}
+
+	return hourly + daily, nil
+}
+
+// rollupHourly는 end 이전의 완료된 시간 구간을 원본 스냅샷에서 롤업합니다
+func (d *Database) rollupHourly(topicID string, end time.Time) (int, error) {
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return 0, err
+	}
+	defer store.release()
+
+	start, ok, err := rollupStart(d.reader, store.reader, topicID, rollupResolutionHour,
+		"SELECT MIN(timestamp) FROM topic_inferences WHERE topic_id = ?", truncateToHour)
+	if err != nil || !ok || !start.Before(end) {
+		return 0, err
+	}
+
+	// 소수점 초가 붙은 타임스탬프는 문자열 비교가 어긋날 수 있으므로 범위를 조금 넓혀 조회한 뒤 구간으로 걸러냄
+	rows, err := store.reader.Query(
+		"SELECT "+snapshotRowColumns+" FROM topic_inferences WHERE topic_id = ? AND timestamp >= ? AND timestamp < ? ORDER BY id",
+		topicID, start.Add(-time.Second).Format(time.RFC3339), end.Add(time.Second).Format(time.RFC3339),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("원본 스냅샷 조회 실패: %w", err)
+	}
+
+	decoder := newSnapshotDecoder(store.reader)
+	buckets := make(map[string]*rollupAccumulator)
+	for rows.Next() {
+		var row snapshotRow
+		if err := rows.Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("데이터 스캔 실패: %w", err)
+		}
+
+		timestamp, err := time.Parse(time.RFC3339, row.Timestamp)
+		if err != nil {
+			continue
+		}
+		bucketStart := truncateToHour(timestamp)
+		if bucketStart.Before(start) || !bucketStart.Before(end) {
+			continue
+		}
+
+		snapshot, err := decoder.decode(row)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("스냅샷 복원 실패 (ID=%d): %w", row.ID, err)
+		}
+
+		bucket := bucketStart.Format(time.RFC3339)
+		acc, exists := buckets[bucket]
+		if !exists {
+			acc = newRollupAccumulator()
+			buckets[bucket] = acc
+		}
+		acc.addSnapshot(snapshot)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for bucket, acc := range buckets {
+		if err = saveRollupPoint(tx, topicID, rollupResolutionHour, acc.point(bucket)); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+
+	return len(buckets), nil
+}
+
+// rollupDaily는 end 이전의 완료된 일 구간을 시간 롤업에서 계산합니다
+// 평균은 구간별 샘플 수로 가중하여 합칩니다
+func (d *Database) rollupDaily(topicID string, end time.Time) (int, error) {
+	start, ok, err := rollupStart(d.reader, d.reader, topicID, rollupResolutionDay,
+		"SELECT MIN(bucket_start) FROM topic_rollups WHERE topic_id = ? AND resolution = 'hour'", truncateToDay)
+	if err != nil || !ok || !start.Before(end) {
+		return 0, err
+	}
+
+	startStr, endStr := start.Format(time.RFC3339), end.Format(time.RFC3339)
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec(`
+		INSERT OR REPLACE INTO topic_rollups (
+			topic_id, resolution, bucket_start, sample_count,
+			combined_avg, combined_min, combined_max, naive_avg,
+			worker_count, first_height, last_height
+		)
+		SELECT
+			topic_id, 'day', substr(bucket_start, 1, 10) || 'T00:00:00Z', SUM(sample_count),
+			SUM(combined_avg * sample_count) / SUM(CASE WHEN combined_avg IS NULL THEN 0 ELSE sample_count END),
+			MIN(combined_min), MAX(combined_max),
+			SUM(naive_avg * sample_count) / SUM(CASE WHEN naive_avg IS NULL THEN 0 ELSE sample_count END),
+			SUM(worker_count * sample_count) / SUM(sample_count),
+			MIN(NULLIF(first_height, 0)), MAX(last_height)
+		FROM topic_rollups
+		WHERE topic_id = ? AND resolution = 'hour' AND bucket_start >= ? AND bucket_start < ?
+		GROUP BY substr(bucket_start, 1, 10)
+	`, topicID, startStr, endStr)
+	if err != nil {
+		return 0, fmt.Errorf("일 롤업 저장 실패: %w", err)
+	}
+
+	_, err = tx.Exec(
+		"DELETE FROM worker_rollups WHERE topic_id = ? AND resolution = 'day' AND bucket_start >= ? AND bucket_start < ?",
+		topicID, startStr, endStr,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("워커 일 롤업 삭제 실패: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO worker_rollups (
+			topic_id, resolution, bucket_start, worker, sample_count,
+			inferer_avg, inferer_min, inferer_max, one_out_avg, weight_avg
+		)
+		SELECT
+			topic_id, 'day', substr(bucket_start, 1, 10) || 'T00:00:00Z', worker, SUM(sample_count),
+			SUM(inferer_avg * sample_count) / SUM(CASE WHEN inferer_avg IS NULL THEN 0 ELSE sample_count END),
+			MIN(inferer_min), MAX(inferer_max),
+			SUM(one_out_avg * sample_count) / SUM(CASE WHEN one_out_avg IS NULL THEN 0 ELSE sample_count END),
+			SUM(weight_avg * sample_count) / SUM(CASE WHEN weight_avg IS NULL THEN 0 ELSE sample_count END)
+		FROM worker_rollups
+		WHERE topic_id = ? AND resolution = 'hour' AND bucket_start >= ? AND bucket_start < ?
+		GROUP BY substr(bucket_start, 1, 10), worker
+	`, topicID, startStr, endStr)
+	if err != nil {
+		return 0, fmt.Errorf("워커 일 롤업 저장 실패: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return int(rowsAffected), nil
+}
+
+// retentionPolicyFor는 토픽에 적용할 보존 정책을 반환합니다
+func (d *Database) retentionPolicyFor(topicID string) RetentionPolicy {
+	policy := d.retention
+	if override, ok := d.topicRetention[topicID]; ok {
+		if override.RawDays > 0 {
+			policy.RawDays = override.RawDays
+		}
+		if override.HourlyDays > 0 {
+			policy.HourlyDays = override.HourlyDays
+		}
+	}
+	return policy
+}
+
+// ApplyTopicRetention은 모든 토픽에 대해 롤업을 계산한 뒤 계층별 보존 기간이 지난 데이터를 삭제합니다
+// 원본 스냅샷은 RawDays, 시간 롤업은 HourlyDays 동안 보존하며 일 롤업은 삭제하지 않습니다
+func (d *Database) ApplyTopicRetention(now time.Time) (int64, error) {
+	// 스냅샷이 있는 토픽 (토픽 샤드 포함)과 롤업만 남은 토픽
+	snapshotTopics, err := d.snapshotTopicIDs()
+	if err != nil {
+		return 0, err
+	}
+	topics := make(map[string]bool, len(snapshotTopics))
+	for _, topicID := range snapshotTopics {
+		topics[topicID] = true
+	}
+
+	rows, err := d.reader.Query("SELECT DISTINCT topic_id FROM topic_rollups")
+	if err != nil {
+		return 0, fmt.Errorf("토픽 목록 조회 실패: %w", err)
+	}
+	for rows.Next() {
+		var topicID string
+		if err := rows.Scan(&topicID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("토픽 ID 스캔 실패: %w", err)
+		}
+		topics[topicID] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+	topicIDs := sortedKeys(topics)
+
+	var totalDeleted int64
+	for _, topicID := range topicIDs {
+		// 원본을 지우기 전에 롤업을 먼저 계산
+		if _, err := d.RollupTopicData(topicID, now); err != nil {
+			return totalDeleted, fmt.Errorf("토픽 %s 롤업 실패: %w", topicID, err)
+		}
+
+		policy := d.retentionPolicyFor(topicID)
+
+		rawDeleted, err := d.PruneOldTopicData(topicID, time.Duration(policy.RawDays)*24*time.Hour)
+		if err != nil {
+			return totalDeleted, err
+		}
+		totalDeleted += rawDeleted
+
+		hourlyCutoff := now.Add(-time.Duration(policy.HourlyDays) * 24 * time.Hour).UTC().Format(time.RFC3339)
+		for _, table := range []string{"topic_rollups", "worker_rollups"} {
+			result, err := d.db.Exec(
+				"DELETE FROM "+table+" WHERE topic_id = ? AND resolution = ? AND bucket_start < ?",
+				topicID, rollupResolutionHour, hourlyCutoff,
+			)
+			if err != nil {
+				return totalDeleted, fmt.Errorf("시간 롤업 정리 실패: %w", err)
+			}
+			if table == "topic_rollups" {
+				hourlyDeleted, _ := result.RowsAffected()
+				totalDeleted += hourlyDeleted
+			}
+		}
+	}
+
+	if d.debug {
+		log.Printf("ApplyTopicRetention 완료: 토픽 %d개, %d개 레코드 삭제됨", len(topicIDs), totalDeleted)
+	}
+
+	return totalDeleted, nil
+}
+
+// selectSeriesTier는 조회 범위와 토픽 보존 정책에 맞는 계층을 선택합니다
+// 범위 시작 시점의 데이터가 남아 있는 가장 세밀한 계층을 고르되, 범위가 길면 더 거친 계층을 사용합니다
+func (d *Database) selectSeriesTier(topicID string, start, end, now time.Time) string {
+	policy := d.retentionPolicyFor(topicID)
+	span := end.Sub(start)
+
+	rawCutoff := now.Add(-time.Duration(policy.RawDays) * 24 * time.Hour)
+	if !start.Before(rawCutoff) && span <= seriesRawMaxSpan {
+		return SeriesTierRaw
+	}
+
+	hourlyCutoff := now.Add(-time.Duration(policy.HourlyDays) * 24 * time.Hour)
+	if !start.Before(hourlyCutoff) && span <= seriesHourlyMaxSpan {
+		return SeriesTierHourly
+	}
+
+	return SeriesTierDaily
+}
+
+// selectRangeTier는 기간 스냅샷 조회(/topics/{id}/inferences)에 사용할 계층을 선택합니다
+// 아카이브가 설정되어 있으면 정리된 원본도 아카이브에서 읽을 수 있으므로 범위가 짧으면 원본 계층을 유지합니다
+func (d *Database) selectRangeTier(topicID string, start, end, now time.Time) string {
+	tier := d.selectSeriesTier(topicID, start, end, now)
+	if tier != SeriesTierRaw && d.archiver != nil && end.Sub(start) <= seriesRawMaxSpan {
+		return SeriesTierRaw
+	}
+	return tier
+}
+
+// competitionBucketColumn은 경쟁 이력을 시간/일 구간으로 묶을 때 사용할 타임스탬프 접두사 식입니다
+func competitionBucketColumn(tier string) string {
+	if tier == SeriesTierDaily {
+		return "substr(timestamp, 1, 10)" // YYYY-MM-DD
+	}
+	return "substr(timestamp, 1, 13)" // YYYY-MM-DDTHH
+}
+
+// GetTopicSeries는 토픽 지표 시계열을 지정한 계층에서 가져옵니다
+// tier가 auto면 조회 범위에 맞는 계층을 자동으로 선택하며, includeWorkers가 true면 워커별 지표를 포함합니다
+func (d *Database) GetTopicSeries(topicID string, start, end time.Time, tier string, includeWorkers bool) (*TopicSeries, error) {
+	if tier == "" || tier == SeriesTierAuto {
+		tier = d.selectSeriesTier(topicID, start, end, time.Now())
+	}
+
+	if d.debug {
+		log.Printf("GetTopicSeries 시작: 토픽 ID=%s, 계층=%s", topicID, tier)
+	}
+
+	series := &TopicSeries{
+		TopicID: topicID,
+		Tier:    tier,
+		Start:   start.Format(time.RFC3339),
+		End:     end.Format(time.RFC3339),
+		Points:  []TopicSeriesPoint{},
+	}
+
+	var err error
+	switch tier {
+	case SeriesTierRaw:
+		series.Points, err = d.getRawSeries(topicID, start, end, includeWorkers)
+	case SeriesTierHourly:
+		series.Points, err = d.getRollupSeries(topicID, rollupResolutionHour, start, end, includeWorkers)
+	case SeriesTierDaily:
+		series.Points, err = d.getRollupSeries(topicID, rollupResolutionDay, start, end, includeWorkers)
+	default:
+		return nil, fmt.Errorf("알 수 없는 계층: %s", tier)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if d.debug {
+		log.Printf("GetTopicSeries 완료: %d개 지점", len(series.Points))
+	}
+
+	return series, nil
+}
+
+// getRawSeries는 원본 스냅샷을 하나씩 시계열 지점으로 변환합니다
+func (d *Database) getRawSeries(topicID string, start, end time.Time, includeWorkers bool) ([]TopicSeriesPoint, error) {
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return nil, err
+	}
+	defer store.release()
+
+	rows, err := store.reader.Query(
+		"SELECT "+snapshotRowColumns+" FROM topic_inferences WHERE topic_id = ? AND timestamp BETWEEN ? AND ? ORDER BY timestamp",
+		topicID, start.Format(time.RFC3339), end.Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("토픽 데이터 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	decoder := newSnapshotDecoder(store.reader)
+	points := []TopicSeriesPoint{}
+	for rows.Next() {
+		var row snapshotRow
+		if err := rows.Scan(&row.ID, &row.Timestamp, &row.Encoding, &row.KeyframeID, &row.Data); err != nil {
+			return nil, fmt.Errorf("데이터 스캔 실패: %w", err)
+		}
+
+		snapshot, err := decoder.decode(row)
+		if err != nil {
+			return nil, fmt.Errorf("스냅샷 복원 실패: %w", err)
+		}
+
+		acc := newRollupAccumulator()
+		acc.addSnapshot(snapshot)
+		point := acc.point(row.Timestamp)
+		if !includeWorkers {
+			point.Workers = nil
+		}
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	return points, nil
+}
+
+// getRollupSeries는 롤업 테이블에서 시계열 지점을 가져옵니다
+func (d *Database) getRollupSeries(topicID, resolution string, start, end time.Time, includeWorkers bool) ([]TopicSeriesPoint, error) {
+	// 시작 시각이 포함된 구간부터 조회
+	truncate := truncateToHour
+	if resolution == rollupResolutionDay {
+		truncate = truncateToDay
+	}
+	startStr := truncate(start).Format(time.RFC3339)
+	endStr := end.UTC().Format(time.RFC3339)
+
+	rows, err := d.reader.Query(`
+		SELECT bucket_start, sample_count, combined_avg, combined_min, combined_max, naive_avg,
+			worker_count, first_height, last_height
+		FROM topic_rollups
+		WHERE topic_id = ? AND resolution = ? AND bucket_start BETWEEN ? AND ?
+		ORDER BY bucket_start
+	`, topicID, resolution, startStr, endStr)
+	if err != nil {
+		return nil, fmt.Errorf("롤업 데이터 조회 실패: %w", err)
+	}
+
+	points := []TopicSeriesPoint{}
+	index := make(map[string]int)
+	for rows.Next() {
+		var point TopicSeriesPoint
+		var combinedAvg, combinedMin, combinedMax, naiveAvg, workerCount sql.NullFloat64
+		var firstHeight, lastHeight sql.NullInt64
+
+		if err := rows.Scan(&point.BucketStart, &point.SampleCount, &combinedAvg, &combinedMin, &combinedMax, &naiveAvg,
+			&workerCount, &firstHeight, &lastHeight); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("롤업 데이터 스캔 실패: %w", err)
+		}
+
+		point.CombinedAvg = nullFloatPtr(combinedAvg)
+		point.CombinedMin = nullFloatPtr(combinedMin)
+		point.CombinedMax = nullFloatPtr(combinedMax)
+		point.NaiveAvg = nullFloatPtr(naiveAvg)
+		point.WorkerCount = workerCount.Float64
+		point.FirstHeight = firstHeight.Int64
+		point.LastHeight = lastHeight.Int64
+
+		index[point.BucketStart] = len(points)
+		points = append(points, point)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	if !includeWorkers || len(points) == 0 {
+		return points, nil
+	}
+
+	workerRows, err := d.reader.Query(`
+		SELECT bucket_start, worker, sample_count, inferer_avg, inferer_min, inferer_max, one_out_avg, weight_avg
+		FROM worker_rollups
+		WHERE topic_id = ? AND resolution = ? AND bucket_start BETWEEN ? AND ?
+		ORDER BY bucket_start, worker
+	`, topicID, resolution, startStr, endStr)
+	if err != nil {
+		return nil, fmt.Errorf("워커 롤업 조회 실패: %w", err)
+	}
+	defer workerRows.Close()
+
+	for workerRows.Next() {
+		var bucketStart string
+		var worker WorkerSeriesPoint
+		var infererAvg, infererMin, infererMax, oneOutAvg, weightAvg sql.NullFloat64
+
+		if err := workerRows.Scan(&bucketStart, &worker.Worker, &worker.SampleCount,
+			&infererAvg, &infererMin, &infererMax, &oneOutAvg, &weightAvg); err != nil {
+			return nil, fmt.Errorf("워커 롤업 스캔 실패: %w", err)
+		}
+
+		worker.InfererAvg = nullFloatPtr(infererAvg)
+		worker.InfererMin = nullFloatPtr(infererMin)
+		worker.InfererMax = nullFloatPtr(infererMax)
+		worker.OneOutAvg = nullFloatPtr(oneOutAvg)
+		worker.WeightAvg = nullFloatPtr(weightAvg)
+
+		if i, ok := index[bucketStart]; ok {
+			points[i].Workers = append(points[i].Workers, worker)
+		}
+	}
+
+	if err := workerRows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	return points, nil
+}
+
+// nullFloatPtr는 NULL이 아닌 경우에만 값의 포인터를 반환합니다
+func nullFloatPtr(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	v := value.Float64
+	return &v
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rangeURL은 기간 조회 URL을 만듭니다
+func rangeURL(path string, start, end time.Time, extra string) string {
+	target := path + "?start=" + url.QueryEscape(start.Format(time.RFC3339)) + "&end=" + url.QueryEscape(end.Format(time.RFC3339))
+	if extra != "" {
+		target += "&" + extra
+	}
+	return target
+}
+
+func TestTopicRangeUsesRollupsAfterRetention(t *testing.T) {
+	db := newTestDatabase(t)
+	db.SetRetentionPolicy(RetentionPolicy{RawDays: 1, HourlyDays: 30}, nil)
+	_, mux := newTestService(t, db)
+
+	now := time.Now()
+	oldHour := now.Add(-72 * time.Hour).Truncate(time.Hour)
+	workers := testWorkers(3)
+	for i := 0; i < 4; i++ {
+		if err := db.SaveTopicInference(testSnapshot("1", 1000+i*10, oldHour.Add(time.Duration(i)*10*time.Minute), workers, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.SaveTopicInference(testSnapshot("1", 2000, now.Add(-time.Hour), workers, 9)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.ApplyTopicRetention(now); err != nil {
+		t.Fatalf("보존 정책 적용 실패: %v", err)
+	}
+
+	// 원본이 정리된 구간은 시간 롤업으로 응답
+	var old TopicInferencesResponse
+	getJSON(t, mux, rangeURL("/api/v1/topics/1/inferences", oldHour.Add(-time.Hour), oldHour.Add(2*time.Hour), ""), http.StatusOK, &old)
+	if old.Tier != SeriesTierHourly {
+		t.Fatalf("정리된 구간 계층 = %q, want hourly", old.Tier)
+	}
+	if len(old.Inferences) != 0 || len(old.Series) != 1 || old.Series[0].SampleCount != 4 {
+		t.Fatalf("정리된 구간 응답: 스냅샷 %d개, 롤업 %+v; want 스냅샷 0개와 4개 표본의 롤업 1개", len(old.Inferences), old.Series)
+	}
+	if len(old.Series[0].Workers) != len(workers) {
+		t.Errorf("롤업 워커 수 = %d, want %d", len(old.Series[0].Workers), len(workers))
+	}
+
+	// 최근 구간은 원본 스냅샷
+	var recent TopicInferencesResponse
+	getJSON(t, mux, rangeURL("/api/v1/topics/1/inferences", now.Add(-2*time.Hour), now, ""), http.StatusOK, &recent)
+	if recent.Tier != SeriesTierRaw || len(recent.Inferences) != 1 || len(recent.Series) != 0 {
+		t.Fatalf("최근 구간 응답: 계층 %q, 스냅샷 %d개, 롤업 %d개; want raw, 1, 0", recent.Tier, len(recent.Inferences), len(recent.Series))
+	}
+
+	// tier=raw는 기존처럼 원본만 조회
+	var forced TopicInferencesResponse
+	getJSON(t, mux, rangeURL("/api/v1/topics/1/inferences", oldHour.Add(-time.Hour), oldHour.Add(2*time.Hour), "tier=raw"), http.StatusOK, &forced)
+	if forced.Tier != SeriesTierRaw || len(forced.Inferences) != 0 {
+		t.Fatalf("tier=raw 응답: 계층 %q, 스냅샷 %d개; want raw, 0", forced.Tier, len(forced.Inferences))
+	}
+}
+
+func TestCompetitionHistoryKeepsTierBuckets(t *testing.T) {
+	db := newTestDatabase(t)
+	db.SetRetentionPolicy(RetentionPolicy{RawDays: 1, HourlyDays: 10}, nil)
+	_, mux := newTestService(t, db)
+
+	// 내용이 다른 경쟁 이력 저장 후 타임스탬프를 과거로 옮김
+	// 구간은 저장된 타임스탬프의 날짜/시간 접두사로 나뉘므로, 같은 구간의 이력이 자정을 넘지 않도록 UTC 기준으로 배치
+	now := time.Now().UTC()
+	hourly := now.Add(-5 * 24 * time.Hour).Truncate(time.Hour)      // 시간 단위로 남는 구간
+	daily := now.Add(-20 * 24 * time.Hour).Truncate(24 * time.Hour) // 일 단위로 남는 구간
+	recent := now.Add(-time.Minute)                                 // 원본으로 남는 구간 (같은 시각 두 번)
+	timestamps := []time.Time{
+		daily, daily.Add(time.Hour), daily.Add(2 * time.Hour),
+		hourly, hourly.Add(10 * time.Minute), hourly.Add(time.Hour),
+		recent, recent,
+	}
+	for i, timestamp := range timestamps {
+		competitions := &CompetitionsResponse{}
+		competitions.PageProps.CompetitionsPage.ActiveAndUpcomingCompetitions = []Competition{{ID: 1, Name: "competition", TopicID: 1, PrizePool: i}}
+		if err := db.SaveCompetitions(competitions); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.db.Exec("UPDATE competitions SET timestamp = ? WHERE id = (SELECT MAX(id) FROM competitions)", timestamp.Format(time.RFC3339)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleted, err := db.PruneOldData(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("경쟁 이력 정리 실패: %v", err)
+	}
+	// 일 구간: 3개 중 마지막 1개, 시간 구간: 같은 시간의 2개 중 마지막 1개
+	if deleted != 3 {
+		t.Errorf("삭제된 이력 = %d, want 3", deleted)
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		extra      string
+		wantTier   string
+		wantCount  int
+	}{
+		{"일 계층", daily.Add(-time.Hour), daily.Add(3 * time.Hour), "", SeriesTierDaily, 1},
+		{"시간 계층", hourly.Add(-time.Hour), hourly.Add(2 * time.Hour), "", SeriesTierHourly, 2},
+		{"원본", now.Add(-2 * time.Hour), now, "", SeriesTierRaw, 2},
+		{"원본 구간의 일 계층 지정", now.Add(-2 * time.Hour), now, "tier=daily", SeriesTierDaily, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var history []CompetitionsResponse
+			recorder := getJSON(t, mux, rangeURL("/api/v1/competitions/history", tt.start, tt.end, tt.extra), http.StatusOK, &history)
+			if tier := recorder.Header().Get(seriesTierHeader); tier != tt.wantTier {
+				t.Errorf("계층 = %q, want %q", tier, tt.wantTier)
+			}
+			if len(history) != tt.wantCount {
+				t.Errorf("이력 수 = %d, want %d", len(history), tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,815 @@
+package app
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 토픽 샤드 파일 이름 규칙: topic-<토픽 ID>.db (보관 파일은 archived/topic-<토픽 ID>-20060102T150405Z.db)
+const (
+	topicShardFilePrefix     = "topic-"
+	topicShardFileSuffix     = ".db"
+	topicShardArchiveDirName = "archived"
+	topicShardTimeLayout     = "20060102T150405Z"
+
+	topicShardIdleReadConnections = 2                // 샤드 읽기 연결 풀에 유지할 유휴 연결 수 (토픽 수만큼 풀이 생기므로 작게 유지)
+	topicShardRecheckInterval     = 30 * time.Second // 다른 인스턴스의 보관 작업을 반영하기 위해 카탈로그를 다시 확인하는 주기
+)
+
+// topicShardIDPattern은 파일 이름에 사용할 수 있는 토픽 ID 형식입니다
+var topicShardIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// topicStore는 topic_inferences 테이블을 담은 SQLite 파일의 쓰기/읽기 연결입니다
+// 샤딩을 사용하지 않으면 메인 데이터베이스 하나만 사용합니다
+// topicStoreFor 등으로 얻은 샤드는 사용이 끝나면 release해야 하며, 목록에서 제거된 샤드는 마지막 사용자가 release할 때 닫습니다
+type topicStore struct {
+	topicID   string  // 샤드의 토픽 ID (메인 데이터베이스는 빈 문자열)
+	path      string  // SQLite 파일 경로
+	db        *sql.DB // 쓰기 전용 단일 연결
+	reader    *sql.DB // 읽기 전용 연결 풀
+	checkedAt time.Time
+
+	// 참조 수와 닫기 상태 (shards.mu로 보호, 메인 데이터베이스는 shards가 nil이며 참조를 세지 않음)
+	shards  *topicShards
+	refs    int
+	retired bool          // 목록에서 제거되어 마지막 release에서 닫음
+	closed  chan struct{} // 연결을 닫으면 닫힘
+}
+
+// isShard는 토픽 샤드 파일인지 확인합니다
+func (s *topicStore) isShard() bool {
+	return s.topicID != ""
+}
+
+// close는 샤드 파일의 연결을 닫습니다
+func (s *topicStore) close() error {
+	readErr := s.reader.Close()
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	return readErr
+}
+
+// acquireLocked는 샤드 참조 수를 늘립니다 (shards.mu를 보유한 상태에서 호출)
+func (s *topicStore) acquireLocked() *topicStore {
+	s.refs++
+	return s
+}
+
+// release는 topicStoreFor, topicStoreForWrite, topicStores로 얻은 저장소의 사용을 마칩니다
+func (s *topicStore) release() {
+	if s == nil || s.shards == nil {
+		return
+	}
+	s.shards.mu.Lock()
+	defer s.shards.mu.Unlock()
+
+	s.refs--
+	if s.retired && s.refs == 0 {
+		s.closeLocked()
+	}
+}
+
+// retireLocked는 샤드를 닫도록 표시하고, 사용 중인 고루틴이 없으면 바로 닫습니다 (shards.mu를 보유한 상태에서 호출)
+func (s *topicStore) retireLocked() {
+	if s.retired {
+		return
+	}
+	s.retired = true
+	if s.refs == 0 {
+		s.closeLocked()
+	}
+}
+
+// closeLocked는 샤드 연결을 닫고 closed를 닫습니다 (shards.mu를 보유한 상태에서 호출)
+func (s *topicStore) closeLocked() {
+	if err := s.close(); err != nil {
+		log.Printf("토픽 %s 샤드 닫기 실패: %v", s.topicID, err)
+	}
+	close(s.closed)
+}
+
+// releaseTopicStores는 topicStores로 얻은 저장소를 모두 release합니다
+func releaseTopicStores(stores []*topicStore) {
+	for _, store := range stores {
+		store.release()
+	}
+}
+
+// topicShards는 열려 있는 토픽 샤드 파일을 관리합니다
+type topicShards struct {
+	dir      string
+	mu       sync.Mutex // stores 보호
+	stores   map[string]*topicStore
+	createMu sync.Mutex // 샤드 생성과 보관이 동시에 실행되지 않도록 보호
+}
+
+// TopicShardInfo는 토픽 샤드 파일 하나의 정보입니다
+type TopicShardInfo struct {
+	TopicID   string `json:"topic_id"`
+	FileName  string `json:"file_name"`
+	CreatedAt string `json:"created_at"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// TopicShardArchive는 보관된 토픽 샤드 파일 정보입니다
+type TopicShardArchive struct {
+	TopicID    string `json:"topic_id"`
+	Path       string `json:"path"`
+	RowCount   int64  `json:"row_count"`
+	SizeBytes  int64  `json:"size_bytes"`
+	ArchivedAt string `json:"archived_at"`
+}
+
+// SetTopicSharding은 토픽 스냅샷을 토픽마다 별도의 SQLite 파일(dir/topic-<토픽 ID>.db)에 저장하도록 설정합니다
+// 메인 데이터베이스는 경쟁, 리더보드, 롤업과 샤드 카탈로그(topic_shards)를 보관합니다
+// 샤드가 아직 없는 토픽은 메인 데이터베이스에서 조회하며, 처음 저장할 때 샤드를 만들고 기존 데이터를 옮깁니다
+func (d *Database) SetTopicSharding(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("토픽 샤드 디렉토리 생성 실패: %w", err)
+	}
+	d.shards = &topicShards{
+		dir:    dir,
+		stores: make(map[string]*topicStore),
+	}
+	return nil
+}
+
+// TopicShardingEnabled는 토픽별 샤드 파일을 사용하는지 확인합니다
+func (d *Database) TopicShardingEnabled() bool {
+	return d.shards != nil
+}
+
+// topicShardFileName은 토픽 샤드 파일 이름을 반환합니다
+func topicShardFileName(topicID string) (string, error) {
+	if !topicShardIDPattern.MatchString(topicID) {
+		return "", fmt.Errorf("샤드 파일 이름으로 사용할 수 없는 토픽 ID: %q", topicID)
+	}
+	return topicShardFilePrefix + topicID + topicShardFileSuffix, nil
+}
+
+// openTopicStore는 토픽 샤드 파일을 메인 데이터베이스와 같은 방식(WAL, 단일 쓰기 연결, 읽기 전용 풀)으로 엽니다
+func openTopicStore(path, topicID string) (*topicStore, error) {
+	db, err := sql.Open("sqlite", sqliteDSN(path,
+		"journal_mode(WAL)",
+		"synchronous(NORMAL)",
+	)+"&_txlock=immediate")
+	if err != nil {
+		return nil, fmt.Errorf("토픽 %s 샤드 연결 실패: %w", topicID, err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
+	// 샤드는 새로 만들어지므로 마이그레이션 없이 현재 스키마로 생성
+	statements := append([]string{topicInferencesTableSQL, topicInferenceUniqueIndexSQL, topicInferenceContentHashIndexSQL, snapshotBlobsTableSQL}, topicInferenceIndexes...)
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("토픽 %s 샤드 초기화 실패: %w", topicID, err)
+		}
+	}
+
+	reader, err := sql.Open("sqlite", sqliteDSN(path, "query_only(1)"))
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("토픽 %s 샤드 읽기 전용 연결 실패: %w", topicID, err)
+	}
+	reader.SetMaxOpenConns(max(runtime.NumCPU(), minReadConnections))
+	reader.SetMaxIdleConns(topicShardIdleReadConnections)
+
+	return &topicStore{
+		topicID:   topicID,
+		path:      path,
+		db:        db,
+		reader:    reader,
+		checkedAt: time.Now(),
+		closed:    make(chan struct{}),
+	}, nil
+}
+
+// topicStoreFor는 토픽 스냅샷 조회에 사용할 저장소를 반환합니다
+// 샤딩을 사용하지 않거나 샤드가 없는 토픽은 메인 데이터베이스를 반환합니다
+// 사용이 끝나면 release해야 합니다
+func (d *Database) topicStoreFor(topicID string) (*topicStore, error) {
+	if d.shards == nil || topicID == "" {
+		return d.main, nil
+	}
+	return d.lookupTopicShard(topicID, false)
+}
+
+// topicStoreForWrite는 토픽 스냅샷을 저장할 저장소를 반환합니다
+// 샤드가 없으면 새로 만들고 메인 데이터베이스에 남아 있던 토픽 데이터를 옮깁니다
+// 사용이 끝나면 release해야 합니다
+func (d *Database) topicStoreForWrite(topicID string) (*topicStore, error) {
+	if d.shards == nil || topicID == "" {
+		return d.main, nil
+	}
+	return d.lookupTopicShard(topicID, true)
+}
+
+// topicStoresFor는 토픽의 저장소를 반환합니다 (topicID가 비어 있으면 모든 저장소)
+func (d *Database) topicStoresFor(topicID string) ([]*topicStore, error) {
+	if topicID == "" {
+		return d.topicStores()
+	}
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return nil, err
+	}
+	return []*topicStore{store}, nil
+}
+
+// topicStores는 메인 데이터베이스와 카탈로그에 등록된 모든 샤드를 토픽 ID 순으로 반환합니다
+// 사용이 끝나면 releaseTopicStores로 release해야 합니다
+func (d *Database) topicStores() ([]*topicStore, error) {
+	stores := []*topicStore{d.main}
+	if d.shards == nil {
+		return stores, nil
+	}
+
+	shards, err := d.ListTopicShards()
+	if err != nil {
+		return nil, err
+	}
+	for _, shard := range shards {
+		store, err := d.openTopicShard(shard.TopicID, shard.FileName)
+		if err != nil {
+			releaseTopicStores(stores)
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+	return stores, nil
+}
+
+// snapshotTopicIDs는 모든 저장소에 스냅샷이 있는 토픽 ID를 정렬하여 반환합니다
+func (d *Database) snapshotTopicIDs() ([]string, error) {
+	stores, err := d.topicStores()
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTopicStores(stores)
+
+	seen := make(map[string]bool)
+	for _, store := range stores {
+		rows, err := store.reader.Query("SELECT DISTINCT topic_id FROM topic_inferences")
+		if err != nil {
+			return nil, fmt.Errorf("토픽 목록 조회 실패: %w", err)
+		}
+		for rows.Next() {
+			var topicID string
+			if err := rows.Scan(&topicID); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("토픽 ID 스캔 실패: %w", err)
+			}
+			seen[topicID] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("토픽 목록 처리 중 오류: %w", err)
+		}
+	}
+	return sortedKeys(seen), nil
+}
+
+// lookupTopicShard는 카탈로그에서 토픽 샤드를 찾아 엽니다
+// 샤드가 없으면 create가 true일 때 새로 만들고, 아니면 메인 데이터베이스를 반환합니다
+func (d *Database) lookupTopicShard(topicID string, create bool) (*topicStore, error) {
+	d.shards.mu.Lock()
+	store, cached := d.shards.stores[topicID]
+	if cached && time.Since(store.checkedAt) < topicShardRecheckInterval {
+		store.acquireLocked()
+		d.shards.mu.Unlock()
+		return store, nil
+	}
+	d.shards.mu.Unlock()
+
+	fileName, err := d.topicShardFile(topicID)
+	if err != nil {
+		return nil, err
+	}
+	if fileName != "" {
+		return d.openTopicShard(topicID, fileName)
+	}
+
+	// 다른 인스턴스가 보관한 샤드는 닫음
+	if cached {
+		d.closeTopicShard(topicID)
+	}
+	if !create {
+		return d.main, nil
+	}
+	return d.createTopicShard(topicID)
+}
+
+// topicShardFile은 카탈로그에 등록된 토픽 샤드 파일 이름을 반환합니다 (없으면 빈 문자열)
+func (d *Database) topicShardFile(topicID string) (string, error) {
+	var fileName string
+	err := d.reader.QueryRow("SELECT file_name FROM topic_shards WHERE topic_id = ?", topicID).Scan(&fileName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("토픽 샤드 카탈로그 조회 실패: %w", err)
+	}
+	return fileName, nil
+}
+
+// openTopicShard는 이미 열린 샤드를 재사용하거나 샤드 파일을 엽니다 (사용이 끝나면 release)
+func (d *Database) openTopicShard(topicID, fileName string) (*topicStore, error) {
+	path := filepath.Join(d.shards.dir, fileName)
+
+	d.shards.mu.Lock()
+	defer d.shards.mu.Unlock()
+
+	if store, ok := d.shards.stores[topicID]; ok {
+		if store.path == path {
+			store.checkedAt = time.Now()
+			return store.acquireLocked(), nil
+		}
+		delete(d.shards.stores, topicID)
+		store.retireLocked()
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("토픽 %s 샤드 파일을 찾을 수 없습니다: %w", topicID, err)
+	}
+	store, err := openTopicStore(path, topicID)
+	if err != nil {
+		return nil, err
+	}
+	store.shards = d.shards
+	d.shards.stores[topicID] = store
+	return store.acquireLocked(), nil
+}
+
+// closeTopicShard는 열린 샤드를 목록에서 제거합니다
+// 샤드는 사용 중인 고루틴이 모두 release한 뒤에 닫힙니다
+func (d *Database) closeTopicShard(topicID string) {
+	d.shards.mu.Lock()
+	defer d.shards.mu.Unlock()
+
+	if store, ok := d.shards.stores[topicID]; ok {
+		delete(d.shards.stores, topicID)
+		store.retireLocked()
+	}
+}
+
+// closeTopicShards는 열린 모든 샤드를 목록에서 제거하고, 사용 중이 아닌 샤드는 바로 닫습니다
+func (d *Database) closeTopicShards() {
+	if d.shards == nil {
+		return
+	}
+	d.shards.mu.Lock()
+	defer d.shards.mu.Unlock()
+
+	for _, store := range d.shards.stores {
+		store.retireLocked()
+	}
+	d.shards.stores = make(map[string]*topicStore)
+}
+
+// createTopicShard는 토픽 샤드 파일을 만들고 메인 데이터베이스의 토픽 데이터를 옮긴 뒤 카탈로그에 등록합니다
+// 데이터는 샤드에 복사한 다음 카탈로그 등록과 같은 트랜잭션에서 메인 데이터베이스에서 삭제하므로,
+// 중간에 실패하면 메인 데이터베이스의 데이터가 그대로 남고 다음 저장 때 다시 시도합니다
+func (d *Database) createTopicShard(topicID string) (*topicStore, error) {
+	d.shards.createMu.Lock()
+	defer d.shards.createMu.Unlock()
+
+	// 잠금을 기다리는 동안 다른 고루틴이 만들었을 수 있음
+	fileName, err := d.topicShardFile(topicID)
+	if err != nil {
+		return nil, err
+	}
+	if fileName != "" {
+		return d.openTopicShard(topicID, fileName)
+	}
+
+	fileName, err = topicShardFileName(topicID)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(d.shards.dir, fileName)
+
+	// 카탈로그에 없는 파일은 이전에 중단된 생성이나 복원 전 데이터이므로 덮어쓰지 않고 옆으로 옮김
+	if _, err := os.Stat(path); err == nil {
+		orphanPath := path + ".orphaned." + time.Now().UTC().Format(topicShardTimeLayout)
+		if err := moveSQLiteFile(path, orphanPath); err != nil {
+			return nil, fmt.Errorf("카탈로그에 없는 토픽 %s 샤드 파일 이동 실패: %w", topicID, err)
+		}
+		log.Printf("카탈로그에 없는 토픽 샤드 파일을 옮겼습니다: %s", orphanPath)
+	}
+
+	store, err := openTopicStore(path, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := d.copyTopicRows(topicID, store)
+	if err != nil {
+		store.close()
+		return nil, err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		store.close()
+		return nil, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	if _, err := tx.Exec(
+		"INSERT INTO topic_shards (topic_id, file_name, created_at) VALUES (?, ?, ?)",
+		topicID, fileName, time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		tx.Rollback()
+		store.close()
+		return nil, fmt.Errorf("토픽 샤드 카탈로그 등록 실패: %w", err)
+	}
+	if _, err := deleteBlobRows(tx, "topic_inferences", "topic_id = ?", topicID); err != nil {
+		tx.Rollback()
+		store.close()
+		return nil, fmt.Errorf("메인 데이터베이스의 토픽 데이터 삭제 실패: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		store.close()
+		return nil, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+
+	d.shards.mu.Lock()
+	store.shards = d.shards
+	d.shards.stores[topicID] = store.acquireLocked()
+	d.shards.mu.Unlock()
+	d.cache.invalidateTopic(topicID)
+
+	log.Printf("토픽 %s 샤드를 만들었습니다: %s (옮긴 스냅샷 %d개)", topicID, path, moved)
+	return store, nil
+}
+
+// copyTopicRows는 메인 데이터베이스의 토픽 스냅샷을 ID와 델타 체인을 그대로 유지한 채 샤드에 복사합니다
+func (d *Database) copyTopicRows(topicID string, store *topicStore) (int64, error) {
+	rows, err := d.reader.Query(
+		"SELECT id, topic_id, timestamp, inference_block_height, loss_block_height, data, encoding, keyframe_id, content_hash FROM topic_inferences WHERE topic_id = ? ORDER BY id",
+		topicID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("옮길 토픽 데이터 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	tx, err := store.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		"INSERT INTO topic_inferences (id, topic_id, timestamp, inference_block_height, loss_block_height, data, encoding, keyframe_id, content_hash) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("SQL 준비 실패: %w", err)
+	}
+	defer stmt.Close()
+
+	var copied int64
+	for rows.Next() {
+		var id, inferenceBlockHeight, lossBlockHeight int64
+		var rowTopicID, timestamp, encoding string
+		var data []byte
+		var keyframeID sql.NullInt64
+		var hash sql.NullString
+		if err := rows.Scan(&id, &rowTopicID, &timestamp, &inferenceBlockHeight, &lossBlockHeight, &data, &encoding, &keyframeID, &hash); err != nil {
+			return 0, fmt.Errorf("데이터 스캔 실패: %w", err)
+		}
+		if data == nil {
+			data = []byte{}
+		}
+		if _, err := stmt.Exec(id, rowTopicID, timestamp, inferenceBlockHeight, lossBlockHeight, data, encoding, keyframeID, hash); err != nil {
+			return 0, fmt.Errorf("샤드에 스냅샷 복사 실패 (ID=%d): %w", id, err)
+		}
+		copied++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	// 토픽 행이 참조하는 본문도 샤드의 본문 저장소로 복사
+	blobs, err := d.reader.Query(
+		"SELECT hash, data, raw_size, created_at FROM snapshot_blobs WHERE hash IN (SELECT content_hash FROM topic_inferences WHERE topic_id = ?)",
+		topicID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("토픽 본문 조회 실패: %w", err)
+	}
+	defer blobs.Close()
+	for blobs.Next() {
+		var hash, createdAt string
+		var data []byte
+		var rawSize int64
+		if err := blobs.Scan(&hash, &data, &rawSize, &createdAt); err != nil {
+			return 0, fmt.Errorf("본문 스캔 실패: %w", err)
+		}
+		if _, err := tx.Exec(
+			"INSERT OR IGNORE INTO snapshot_blobs (hash, data, raw_size, created_at) VALUES (?, ?, ?, ?)",
+			hash, data, rawSize, createdAt,
+		); err != nil {
+			return 0, fmt.Errorf("샤드에 본문 복사 실패 (해시=%s): %w", hash, err)
+		}
+	}
+	if err := blobs.Err(); err != nil {
+		return 0, fmt.Errorf("본문 처리 중 오류: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+	return copied, nil
+}
+
+// MigrateTopicShards는 메인 데이터베이스에 남아 있는 모든 토픽 데이터를 토픽별 샤드로 옮깁니다
+// 옮긴 토픽 ID 목록을 반환합니다
+func (d *Database) MigrateTopicShards() ([]string, error) {
+	if d.shards == nil {
+		return nil, fmt.Errorf("토픽 샤딩이 활성화되어 있지 않습니다")
+	}
+
+	rows, err := d.reader.Query("SELECT DISTINCT topic_id FROM topic_inferences ORDER BY topic_id")
+	if err != nil {
+		return nil, fmt.Errorf("토픽 목록 조회 실패: %w", err)
+	}
+	var topicIDs []string
+	for rows.Next() {
+		var topicID string
+		if err := rows.Scan(&topicID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("토픽 ID 스캔 실패: %w", err)
+		}
+		topicIDs = append(topicIDs, topicID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("토픽 목록 처리 중 오류: %w", err)
+	}
+
+	for i, topicID := range topicIDs {
+		store, err := d.topicStoreForWrite(topicID)
+		if err != nil {
+			return topicIDs[:i], err
+		}
+		store.release()
+	}
+	return topicIDs, nil
+}
+
+// ArchiveTopicShard는 토픽 샤드 파일을 보관 디렉토리로 옮기고 카탈로그에서 제거합니다
+// 큰 DELETE 없이 파일 이동만으로 토픽 데이터를 라이브 데이터베이스에서 분리합니다
+// 보관 이후 같은 토픽을 다시 저장하면 빈 샤드가 새로 만들어집니다
+func (d *Database) ArchiveTopicShard(topicID string) (*TopicShardArchive, error) {
+	if d.shards == nil {
+		return nil, fmt.Errorf("토픽 샤딩이 활성화되어 있지 않습니다")
+	}
+
+	// 메인 데이터베이스에만 있는 토픽이면 먼저 샤드로 옮김
+	store, err := d.topicStoreForWrite(topicID)
+	if err != nil {
+		return nil, err
+	}
+	released := false
+	defer func() {
+		if !released {
+			store.release()
+		}
+	}()
+
+	d.shards.createMu.Lock()
+	defer d.shards.createMu.Unlock()
+
+	var rowCount int64
+	if err := store.reader.QueryRow("SELECT COUNT(*) FROM topic_inferences").Scan(&rowCount); err != nil {
+		return nil, fmt.Errorf("토픽 %s 레코드 수 조회 실패: %w", topicID, err)
+	}
+
+	fileName, err := d.topicShardFile(topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 카탈로그에서 먼저 제거하여 이후 조회가 보관 중인 파일을 다시 열지 않도록 함
+	if _, err := d.db.Exec("DELETE FROM topic_shards WHERE topic_id = ?", topicID); err != nil {
+		return nil, fmt.Errorf("토픽 샤드 카탈로그 삭제 실패: %w", err)
+	}
+	restoreCatalog := func() {
+		if _, err := d.db.Exec(
+			"INSERT OR IGNORE INTO topic_shards (topic_id, file_name, created_at) VALUES (?, ?, ?)",
+			topicID, fileName, time.Now().UTC().Format(time.RFC3339),
+		); err != nil {
+			log.Printf("토픽 %s 샤드 카탈로그 복구 실패: %v", topicID, err)
+		}
+	}
+
+	// 목록에서 제거한 뒤 진행 중인 조회/저장이 끝나 연결이 닫힐 때까지 기다림
+	d.shards.mu.Lock()
+	if d.shards.stores[topicID] == store {
+		delete(d.shards.stores, topicID)
+	}
+	store.retireLocked()
+	d.shards.mu.Unlock()
+
+	// WAL 내용을 파일에 반영한 뒤 연결을 닫아야 파일 하나만 옮기면 됨
+	if _, err := store.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		log.Printf("토픽 %s 샤드 체크포인트 실패: %v", topicID, err)
+	}
+	released = true
+	store.release()
+	<-store.closed
+	d.cache.invalidateTopic(topicID)
+
+	archiveDir := filepath.Join(d.shards.dir, topicShardArchiveDirName)
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		restoreCatalog()
+		return nil, fmt.Errorf("샤드 보관 디렉토리 생성 실패: %w", err)
+	}
+
+	archivedAt := time.Now().UTC()
+	archivePath := filepath.Join(archiveDir,
+		topicShardFilePrefix+topicID+"-"+archivedAt.Format(topicShardTimeLayout)+topicShardFileSuffix)
+	if err := moveSQLiteFile(store.path, archivePath); err != nil {
+		restoreCatalog()
+		return nil, fmt.Errorf("토픽 %s 샤드 파일 이동 실패: %w", topicID, err)
+	}
+
+	var sizeBytes int64
+	if info, err := os.Stat(archivePath); err == nil {
+		sizeBytes = info.Size()
+	}
+
+	archive := &TopicShardArchive{
+		TopicID:    topicID,
+		Path:       archivePath,
+		RowCount:   rowCount,
+		SizeBytes:  sizeBytes,
+		ArchivedAt: archivedAt.Format(time.RFC3339),
+	}
+	if _, err := d.db.Exec(
+		"INSERT INTO topic_shard_archives (topic_id, path, row_count, size_bytes, archived_at) VALUES (?, ?, ?, ?, ?)",
+		archive.TopicID, archive.Path, archive.RowCount, archive.SizeBytes, archive.ArchivedAt,
+	); err != nil {
+		return archive, fmt.Errorf("샤드 보관 기록 저장 실패: %w", err)
+	}
+
+	log.Printf("토픽 %s 샤드를 보관했습니다: %s (스냅샷 %d개, %.2fMB)",
+		topicID, archivePath, rowCount, float64(sizeBytes)/(1024*1024))
+	return archive, nil
+}
+
+// VacuumTopicShard는 토픽 샤드 파일 하나만 VACUUM하여 다른 토픽의 읽기/쓰기를 막지 않고 공간을 회수합니다
+func (d *Database) VacuumTopicShard(topicID string) error {
+	if d.shards == nil {
+		return fmt.Errorf("토픽 샤딩이 활성화되어 있지 않습니다")
+	}
+	store, err := d.topicStoreFor(topicID)
+	if err != nil {
+		return err
+	}
+	defer store.release()
+	if !store.isShard() {
+		return fmt.Errorf("토픽 %s의 샤드가 없습니다", topicID)
+	}
+	if _, err := store.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("토픽 %s 샤드 VACUUM 실패: %w", topicID, err)
+	}
+	return nil
+}
+
+// ListTopicShards는 카탈로그에 등록된 토픽 샤드 목록을 토픽 ID 순으로 반환합니다
+func (d *Database) ListTopicShards() ([]TopicShardInfo, error) {
+	rows, err := d.reader.Query("SELECT topic_id, file_name, created_at FROM topic_shards")
+	if err != nil {
+		return nil, fmt.Errorf("토픽 샤드 카탈로그 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	shards := []TopicShardInfo{}
+	for rows.Next() {
+		var shard TopicShardInfo
+		if err := rows.Scan(&shard.TopicID, &shard.FileName, &shard.CreatedAt); err != nil {
+			return nil, fmt.Errorf("토픽 샤드 카탈로그 스캔 실패: %w", err)
+		}
+		if d.shards != nil {
+			path := filepath.Join(d.shards.dir, shard.FileName)
+			for _, suffix := range []string{"", "-wal"} {
+				if info, err := os.Stat(path + suffix); err == nil {
+					shard.SizeBytes += info.Size()
+				}
+			}
+		}
+		shards = append(shards, shard)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+
+	sort.Slice(shards, func(i, j int) bool { return shards[i].TopicID < shards[j].TopicID })
+	return shards, nil
+}
+
+// ListTopicShardArchives는 보관된 토픽 샤드 파일 목록을 최신순으로 반환합니다
+func (d *Database) ListTopicShardArchives() ([]TopicShardArchive, error) {
+	rows, err := d.reader.Query(
+		"SELECT topic_id, path, row_count, size_bytes, archived_at FROM topic_shard_archives ORDER BY id DESC",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("샤드 보관 기록 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	archives := []TopicShardArchive{}
+	for rows.Next() {
+		var archive TopicShardArchive
+		if err := rows.Scan(&archive.TopicID, &archive.Path, &archive.RowCount, &archive.SizeBytes, &archive.ArchivedAt); err != nil {
+			return nil, fmt.Errorf("샤드 보관 기록 스캔 실패: %w", err)
+		}
+		archives = append(archives, archive)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("결과 처리 중 오류: %w", err)
+	}
+	return archives, nil
+}
+
+// mergeTopicShardsInto는 백업 파일에 모든 샤드의 스냅샷을 합쳐 단일 파일 백업으로 만듭니다
+// 샤드마다 ID가 겹칠 수 있으므로 백업 파일의 최대 ID만큼 ID와 keyframe_id를 함께 이동하여 델타 체인을 유지합니다
+// 합친 뒤 카탈로그를 비우므로, 복원한 데이터베이스는 샤드 없이 동작하거나 다음 저장 때 샤드를 다시 만듭니다
+func (d *Database) mergeTopicShardsInto(path string) error {
+	stores, err := d.topicStores()
+	if err != nil {
+		return err
+	}
+	defer releaseTopicStores(stores)
+
+	backup, err := sql.Open("sqlite", sqliteDSN(path))
+	if err != nil {
+		return fmt.Errorf("백업 파일 열기 실패: %w", err)
+	}
+	defer backup.Close()
+	backup.SetMaxOpenConns(1)
+
+	for _, store := range stores {
+		if !store.isShard() {
+			continue
+		}
+		if _, err := backup.Exec("ATTACH DATABASE ? AS shard", store.path); err != nil {
+			return fmt.Errorf("토픽 %s 샤드 연결 실패: %w", store.topicID, err)
+		}
+
+		var offset int64
+		err := backup.QueryRow("SELECT COALESCE(MAX(id), 0) FROM main.topic_inferences").Scan(&offset)
+		if err == nil {
+			_, err = backup.Exec(`
+				INSERT INTO main.topic_inferences (id, topic_id, timestamp, inference_block_height, loss_block_height, data, encoding, keyframe_id, content_hash)
+				SELECT id + ?, topic_id, timestamp, inference_block_height, loss_block_height, data, encoding, keyframe_id + ?, content_hash
+				FROM shard.topic_inferences ORDER BY id
+			`, offset, offset)
+		}
+		if err == nil {
+			_, err = backup.Exec(`
+				INSERT OR IGNORE INTO main.snapshot_blobs (hash, data, raw_size, created_at)
+				SELECT hash, data, raw_size, created_at FROM shard.snapshot_blobs
+			`)
+		}
+		if _, detachErr := backup.Exec("DETACH DATABASE shard"); detachErr != nil && err == nil {
+			err = detachErr
+		}
+		if err != nil {
+			return fmt.Errorf("토픽 %s 샤드를 백업에 합치기 실패: %w", store.topicID, err)
+		}
+	}
+
+	if _, err := backup.Exec("DELETE FROM topic_shards"); err != nil {
+		return fmt.Errorf("백업 파일의 샤드 카탈로그 초기화 실패: %w", err)
+	}
+	return nil
+}
+
+// moveSQLiteFile은 SQLite 파일과 남아 있는 WAL/공유 메모리/롤백 저널 파일을 함께 옮깁니다
+func moveSQLiteFile(src, dst string) error {
+	if err := os.Rename(src, dst); err != nil {
+		return err
+	}
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if _, err := os.Stat(src + suffix); err == nil {
+			if err := os.Rename(src+suffix, dst+suffix); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newShardedTestDatabase는 토픽 샤드를 사용하는 테스트 데이터베이스를 만듭니다
+func newShardedTestDatabase(t *testing.T) (*Database, string) {
+	t.Helper()
+	db := newTestDatabase(t)
+	dir := filepath.Join(t.TempDir(), "shards")
+	if err := db.SetTopicSharding(dir); err != nil {
+		t.Fatal(err)
+	}
+	return db, dir
+}
+
+// shardRefs는 열린 토픽 샤드의 참조 수를 반환합니다 (열려 있지 않으면 -1)
+func shardRefs(db *Database, topicID string) int {
+	db.shards.mu.Lock()
+	defer db.shards.mu.Unlock()
+	if store, ok := db.shards.stores[topicID]; ok {
+		return store.refs
+	}
+	return -1
+}
+
+func TestTopicShardMovesMainData(t *testing.T) {
+	db := newTestDatabase(t)
+	db.SetSnapshotDelta(true, 4)
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(5)
+	var records []map[string]interface{}
+	for cycle := 0; cycle < 5; cycle++ {
+		record := testSnapshot("1", 100+cycle*10, base.Add(time.Duration(cycle)*time.Minute), workers, cycle)
+		records = append(records, record)
+		if err := db.SaveTopicInference(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 샤딩을 켠 뒤 처음 저장할 때 메인 데이터베이스의 토픽 데이터를 샤드로 옮김
+	dir := filepath.Join(t.TempDir(), "shards")
+	if err := db.SetTopicSharding(dir); err != nil {
+		t.Fatal(err)
+	}
+	next := testSnapshot("1", 150, base.Add(5*time.Minute), workers, 5)
+	records = append(records, next)
+	if err := db.SaveTopicInference(next); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := countRows(t, db, "SELECT COUNT(*) FROM topic_inferences"); count != 0 {
+		t.Errorf("메인 데이터베이스에 남은 스냅샷 = %d, want 0", count)
+	}
+	shards, err := db.ListTopicShards()
+	if err != nil || len(shards) != 1 || shards[0].TopicID != "1" {
+		t.Fatalf("샤드 목록 = %+v (%v), want 토픽 1", shards, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, shards[0].FileName)); err != nil {
+		t.Fatalf("샤드 파일이 없습니다: %v", err)
+	}
+
+	// 델타 체인을 유지한 채 옮겨져 모두 그대로 복원
+	for _, record := range records {
+		height := record["inference_block_height"].(string)
+		state, _, _ := storedSnapshot(t, db, "1", height)
+		if !reflect.DeepEqual(state, expectedSnapshot(t, record).state) {
+			t.Errorf("샤드의 높이 %s 본문이 원본과 다릅니다", height)
+		}
+	}
+	if got := latestHeight(t, db, "1"); got != "150" {
+		t.Errorf("샤드 최신 높이 = %v, want 150", got)
+	}
+	if refs := shardRefs(db, "1"); refs != 0 {
+		t.Errorf("조회가 끝난 뒤 샤드 참조 수 = %d, want 0", refs)
+	}
+}
+
+func TestArchiveTopicShardWaitsForInFlightUsers(t *testing.T) {
+	db, dir := newShardedTestDatabase(t)
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(3)
+	for i := 0; i < 3; i++ {
+		if err := db.SaveTopicInference(testSnapshot("1", 100+i*10, base.Add(time.Duration(i)*time.Minute), workers, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 조회 중인 고루틴이 샤드를 잡고 있는 동안 보관을 시작
+	store, err := db.topicStoreFor("1")
+	if err != nil || !store.isShard() {
+		t.Fatalf("토픽 1 샤드 조회 실패: %v", err)
+	}
+	archived := make(chan *TopicShardArchive, 1)
+	archiveErr := make(chan error, 1)
+	go func() {
+		archive, err := db.ArchiveTopicShard("1")
+		archived <- archive
+		archiveErr <- err
+	}()
+
+	select {
+	case <-archived:
+		t.Fatal("사용 중인 샤드를 기다리지 않고 보관했습니다")
+	case <-time.After(100 * time.Millisecond):
+	}
+	var count int
+	if err := store.reader.QueryRow("SELECT COUNT(*) FROM topic_inferences").Scan(&count); err != nil || count != 3 {
+		t.Fatalf("보관 대기 중 샤드 조회 = %d (%v), want 3", count, err)
+	}
+
+	store.release()
+	var archive *TopicShardArchive
+	select {
+	case archive = <-archived:
+		if err := <-archiveErr; err != nil {
+			t.Fatalf("샤드 보관 실패: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("release 후에도 샤드 보관이 끝나지 않았습니다")
+	}
+
+	select {
+	case <-store.closed:
+	default:
+		t.Error("보관한 샤드 연결이 닫히지 않았습니다")
+	}
+	if archive.RowCount != 3 {
+		t.Errorf("보관된 스냅샷 수 = %d, want 3", archive.RowCount)
+	}
+	if _, err := os.Stat(archive.Path); err != nil {
+		t.Errorf("보관 파일이 없습니다: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "topic-1.db")); !os.IsNotExist(err) {
+		t.Errorf("보관 후에도 샤드 파일이 남아 있습니다: %v", err)
+	}
+
+	// 보관 후 조회는 비어 있고, 다시 저장하면 빈 샤드를 새로 만듦
+	if latest, err := db.GetLatestTopicInference("1"); err != nil || latest != nil {
+		t.Errorf("보관 후 최신 스냅샷 = %v (%v), want 없음", latest, err)
+	}
+	if err := db.SaveTopicInference(testSnapshot("1", 200, time.Now(), workers, 9)); err != nil {
+		t.Fatal(err)
+	}
+	if heights, _, err := db.GetTopicBlockHeights("1", 10, 0, ""); err != nil || !reflect.DeepEqual(heights.Heights, []string{"200"}) {
+		t.Errorf("새 샤드 블록 높이 = %v (%v), want [200]", heights, err)
+	}
+}
+
+func TestArchiveTopicShardDuringConcurrentReads(t *testing.T) {
+	db, _ := newShardedTestDatabase(t)
+	db.cache = nil // 모든 조회가 샤드 연결을 거치도록 캐시 없이
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(3)
+	for topic := 1; topic <= 2; topic++ {
+		if err := db.SaveTopicInference(testSnapshot(strconv.Itoa(topic), 100, base, workers, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var readErrs []error
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(topicID string) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if _, err := db.GetLatestTopicInference(topicID); err != nil {
+					mu.Lock()
+					readErrs = append(readErrs, err)
+					mu.Unlock()
+				}
+			}
+		}(strconv.Itoa(i%2 + 1))
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, err := db.ArchiveTopicShard("1"); err != nil {
+		t.Errorf("샤드 보관 실패: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	// 보관 중 닫힌 연결을 사용한 조회가 없어야 함
+	if len(readErrs) > 0 {
+		t.Errorf("보관 중 조회 오류 %d건 (첫 오류: %v)", len(readErrs), readErrs[0])
+	}
+	if refs := shardRefs(db, "2"); refs != 0 {
+		t.Errorf("조회가 끝난 뒤 토픽 2 샤드 참조 수 = %d, want 0", refs)
+	}
+}
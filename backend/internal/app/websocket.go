@@ -0,0 +1,719 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket 구독 API
+// 클라이언트는 연결한 뒤 토픽, 워커, 경쟁 채널을 실행 중에 구독/해제하고, 허브는 EventBroker의 수집 이벤트를 채널별로 나누어 보냅니다
+// 토픽 채널은 구독 시 전체 스냅샷을 한 번 보내고, 이후에는 직전에 보낸 스냅샷 대비 델타(diffSnapshots 형식)만 보냅니다
+// 메시지를 제때 읽지 못하는 클라이언트는 대기열이 가득 차면 연결을 끊어 다른 클라이언트와 수집기에 영향을 주지 않습니다
+
+// 채널 종류 (채널 이름은 "종류:ID" 형식, 예: topic:1, worker:allo1abc, competition:5)
+const (
+	WSChannelTopic       = "topic"
+	WSChannelWorker      = "worker"
+	WSChannelCompetition = "competition"
+)
+
+// 클라이언트 요청 종류
+const (
+	WSOpSubscribe   = "subscribe"
+	WSOpUnsubscribe = "unsubscribe"
+	WSOpPing        = "ping"
+)
+
+// 서버 메시지 종류
+const (
+	WSMessageWelcome         = "welcome"          // 연결 직후 연결 설정 안내
+	WSMessageSubscribed      = "subscribed"       // subscribe 응답 (현재 구독 중인 채널 목록)
+	WSMessageUnsubscribed    = "unsubscribed"     // unsubscribe 응답 (현재 구독 중인 채널 목록)
+	WSMessageSnapshot        = "snapshot"         // 토픽 전체 스냅샷 (구독 직후 또는 재동기화)
+	WSMessageDelta           = "delta"            // 직전 스냅샷(base_height) 대비 토픽 스냅샷 델타
+	WSMessageLossHeight      = "loss_height"      // 스냅샷의 loss_block_height 갱신
+	WSMessageWorker          = "worker"           // 새 스냅샷에 포함된 워커의 추론 값
+	WSMessageCompetition     = "competition"      // 경쟁 필드 변경 또는 라이프사이클 전이
+	WSMessageCollectionError = "collection_error" // 데이터 수집 실패
+	WSMessagePong            = "pong"             // ping 응답
+	WSMessageError           = "error"            // 요청 오류
+)
+
+// 연결 설정
+const (
+	wsWriteTimeout   = 10 * time.Second // 메시지 하나를 쓰는 최대 시간
+	wsPongTimeout    = 60 * time.Second // 이 시간 동안 pong이나 메시지가 없으면 연결을 끊음
+	wsPingInterval   = 25 * time.Second // ping 프레임 간격 (wsPongTimeout보다 짧아야 함)
+	wsMaxMessageSize = 4096             // 클라이언트 메시지 최대 크기
+	wsSendBuffer     = 64               // 클라이언트별 대기 메시지 수 (가득 차면 연결을 끊음)
+	wsMaxChannels    = 100              // 클라이언트별 최대 구독 채널 수
+)
+
+// WSClientMessage는 클라이언트가 보내는 요청입니다
+// ID를 지정하면 응답과 오류 메시지에 같은 ID가 포함됩니다
+type WSClientMessage struct {
+	Op       string   `json:"op"`
+	ID       string   `json:"id,omitempty"`
+	Channels []string `json:"channels,omitempty"`
+}
+
+// WSServerMessage는 서버가 보내는 메시지입니다
+type WSServerMessage struct {
+	Type       string      `json:"type"`
+	ID         string      `json:"id,omitempty"`          // 요청 ID (요청에 대한 응답일 때)
+	Channel    string      `json:"channel,omitempty"`     // 메시지가 속한 채널
+	Height     string      `json:"height,omitempty"`      // 스냅샷 블록 높이
+	BaseHeight string      `json:"base_height,omitempty"` // 델타를 적용할 스냅샷의 블록 높이
+	Data       interface{} `json:"data,omitempty"`
+	Error      *APIError   `json:"error,omitempty"`
+}
+
+// WSWelcome은 welcome 메시지의 데이터입니다
+type WSWelcome struct {
+	PingIntervalSeconds int `json:"ping_interval_seconds"`
+	PongTimeoutSeconds  int `json:"pong_timeout_seconds"`
+	MaxChannels         int `json:"max_channels"`
+	MaxMessageBytes     int `json:"max_message_bytes"`
+}
+
+// WSSubscriptions는 subscribed, unsubscribed 메시지의 데이터입니다
+type WSSubscriptions struct {
+	Channels []string `json:"channels"` // 요청 처리 후 구독 중인 채널 전체
+}
+
+// parseWSChannel은 채널 이름을 확인하고 종류와 ID를 반환합니다
+func parseWSChannel(name string) (string, string, error) {
+	kind, id, _ := strings.Cut(name, ":")
+	switch kind {
+	case WSChannelTopic, WSChannelCompetition:
+		if !paramPatterns[paramInteger].MatchString(id) {
+			return "", "", fmt.Errorf("Invalid channel %q: %s ID must be a non-negative integer", name, kind)
+		}
+	case WSChannelWorker:
+		if !paramPatterns[paramAddress].MatchString(id) {
+			return "", "", fmt.Errorf("Invalid channel %q: worker address must be lowercase alphanumeric", name)
+		}
+	default:
+		return "", "", fmt.Errorf("Invalid channel %q: must be topic:<id>, worker:<address> or competition:<id>", name)
+	}
+	return kind, id, nil
+}
+
+// wsChannel은 채널 이름을 만듭니다
+func wsChannel(kind, id string) string {
+	return kind + ":" + id
+}
+
+// wsClient는 WebSocket 연결 하나입니다
+// send 채널은 허브 고루틴만 닫으며, 닫기 전에 closeCode/closeReason을 설정합니다
+type wsClient struct {
+	conn        *websocket.Conn
+	send        chan []byte
+	channels    map[string]struct{} // 구독 중인 채널 (허브 고루틴에서만 사용)
+	revoked     <-chan struct{}     // 인스턴스가 리더 자격을 잃으면 닫힘 (nil이면 무시)
+	closeCode   int
+	closeReason string
+}
+
+// wsRequest는 클라이언트 요청을 허브 고루틴으로 전달합니다
+type wsRequest struct {
+	client  *wsClient
+	message WSClientMessage
+	err     error // 요청을 파싱하지 못한 경우
+}
+
+// wsTopicState는 토픽 구독자에게 마지막으로 보낸 스냅샷입니다 (다음 델타의 기준)
+type wsTopicState struct {
+	height string
+	state  map[string]interface{}
+}
+
+// WebSocketHub는 WebSocket 클라이언트의 채널 구독을 관리하고 수집 이벤트를 전달합니다
+// 구독 상태는 허브 고루틴 하나에서만 변경하므로 잠금 없이 처리합니다
+type WebSocketHub struct {
+	db       *Database
+	events   *EventBroker
+	upgrader websocket.Upgrader
+	debug    bool
+
+	register   chan *wsClient
+	unregister chan *wsClient
+	requests   chan wsRequest
+	stopChan   chan struct{}
+	done       chan struct{}
+
+	isRunning    bool
+	runningMutex sync.Mutex
+
+	// 아래 필드는 허브 고루틴에서만 사용
+	clients  map[*wsClient]struct{}
+	channels map[string]map[*wsClient]struct{} // 채널 -> 구독자
+	topics   map[string]*wsTopicState          // 토픽 ID -> 마지막으로 보낸 스냅샷
+	lastID   int64                             // 마지막으로 처리한 이벤트 ID (브로커 재구독용)
+}
+
+// NewWebSocketHub는 새로운 WebSocket 허브를 생성합니다
+func NewWebSocketHub(db *Database, events *EventBroker) *WebSocketHub {
+	return &WebSocketHub{
+		db:     db,
+		events: events,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 4096,
+			// REST API와 같이 모든 출처를 허용 (CORS Access-Control-Allow-Origin: *)
+			CheckOrigin: func(r *http.Request) bool { return true },
+			Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+				writeError(w, status, ErrCodeUpgradeRequired, reason.Error())
+			},
+		},
+		register:   make(chan *wsClient),
+		unregister: make(chan *wsClient),
+		requests:   make(chan wsRequest),
+		clients:    make(map[*wsClient]struct{}),
+		channels:   make(map[string]map[*wsClient]struct{}),
+		topics:     make(map[string]*wsTopicState),
+	}
+}
+
+// SetDebug는 디버깅 모드를 설정합니다
+func (h *WebSocketHub) SetDebug(debug bool) {
+	h.debug = debug
+}
+
+// Start는 이벤트를 구독하고 허브 고루틴을 시작합니다
+func (h *WebSocketHub) Start() error {
+	h.runningMutex.Lock()
+	defer h.runningMutex.Unlock()
+
+	if h.isRunning {
+		return fmt.Errorf("WebSocket 허브가 이미 실행 중입니다")
+	}
+
+	h.stopChan = make(chan struct{})
+	h.done = make(chan struct{})
+	h.isRunning = true
+	go h.run(h.stopChan, h.done)
+	return nil
+}
+
+// Stop은 모든 연결을 닫고 허브 고루틴을 중지합니다
+// 업그레이드된 연결은 http.Server.Shutdown이 기다리지 않으므로 서버 종료 시 함께 호출합니다
+func (h *WebSocketHub) Stop() {
+	h.runningMutex.Lock()
+	defer h.runningMutex.Unlock()
+
+	if !h.isRunning {
+		return
+	}
+	close(h.stopChan)
+	<-h.done
+	h.isRunning = false
+}
+
+// running은 허브 고루틴의 종료 채널을 반환합니다 (실행 중이 아니면 nil)
+func (h *WebSocketHub) running() chan struct{} {
+	h.runningMutex.Lock()
+	defer h.runningMutex.Unlock()
+	if !h.isRunning {
+		return nil
+	}
+	return h.done
+}
+
+// ServeWebSocket은 연결을 WebSocket으로 업그레이드하고 허브에 등록합니다
+// revoked가 닫히면(리더 자격을 잃으면) 1013(Try Again Later) "not leader"로 연결을 끊습니다
+func (h *WebSocketHub) ServeWebSocket(w http.ResponseWriter, r *http.Request, revoked <-chan struct{}) {
+	done := h.running()
+	if done == nil {
+		writeError(w, http.StatusServiceUnavailable, ErrCodeNotConfigured, "WebSocket hub is not running")
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// 업그레이더가 오류 응답을 보냄
+		return
+	}
+
+	client := &wsClient{
+		conn:     conn,
+		send:     make(chan []byte, wsSendBuffer),
+		channels: make(map[string]struct{}),
+		revoked:  revoked,
+	}
+	select {
+	case h.register <- client:
+	case <-done:
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), time.Now().Add(wsWriteTimeout))
+		conn.Close()
+		return
+	}
+
+	go h.writePump(client)
+	h.readPump(client, done)
+}
+
+// run은 클라이언트 등록/해제, 구독 요청, 수집 이벤트를 순서대로 처리합니다
+func (h *WebSocketHub) run(stop, done chan struct{}) {
+	defer close(done)
+
+	var sub *eventSubscription
+	var events chan Event // 브로커가 없거나 닫히면 nil (수신하지 않음)
+	if h.events != nil {
+		sub, _ = h.events.subscribe(0, EventFilter{})
+		events = sub.events
+	}
+
+	for {
+		select {
+		case <-stop:
+			if sub != nil {
+				h.events.unsubscribe(sub)
+			}
+			for client := range h.clients {
+				h.closeClient(client, websocket.CloseGoingAway, "server shutting down")
+			}
+			return
+
+		case client := <-h.register:
+			h.clients[client] = struct{}{}
+			h.deliver(client, WSServerMessage{Type: WSMessageWelcome, Data: WSWelcome{
+				PingIntervalSeconds: int(wsPingInterval / time.Second),
+				PongTimeoutSeconds:  int(wsPongTimeout / time.Second),
+				MaxChannels:         wsMaxChannels,
+				MaxMessageBytes:     wsMaxMessageSize,
+			}})
+
+		case client := <-h.unregister:
+			h.closeClient(client, websocket.CloseNormalClosure, "")
+
+		case request := <-h.requests:
+			h.handleRequest(request)
+
+		case event, ok := <-events:
+			if !ok {
+				// 허브가 이벤트를 제때 처리하지 못해 구독이 끊긴 경우 마지막 이벤트부터 다시 구독 (브로커가 닫혔으면 중단)
+				if h.events.isClosed() {
+					events = nil
+					continue
+				}
+				var backlog []Event
+				sub, backlog = h.events.subscribe(h.lastID, EventFilter{})
+				events = sub.events
+				log.Printf("WebSocket 허브 이벤트 재구독: 마지막 ID=%d, 재전송 %d개", h.lastID, len(backlog))
+				for _, event := range backlog {
+					h.handleEvent(event)
+				}
+				continue
+			}
+			h.handleEvent(event)
+		}
+	}
+}
+
+// handleRequest는 클라이언트의 subscribe, unsubscribe, ping 요청을 처리합니다
+func (h *WebSocketHub) handleRequest(request wsRequest) {
+	client := request.client
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	message := request.message
+
+	if request.err != nil {
+		h.deliverError(client, message.ID, ErrCodeInvalidRequestBody, "Invalid message: must be a JSON object with op and channels")
+		return
+	}
+
+	switch message.Op {
+	case WSOpPing:
+		h.deliver(client, WSServerMessage{Type: WSMessagePong, ID: message.ID})
+
+	case WSOpSubscribe, WSOpUnsubscribe:
+		if len(message.Channels) == 0 {
+			h.deliverError(client, message.ID, ErrCodeMissingParameter, "Missing channels")
+			return
+		}
+		for _, channel := range message.Channels {
+			if _, _, err := parseWSChannel(channel); err != nil {
+				h.deliverError(client, message.ID, ErrCodeInvalidParameter, err.Error())
+				return
+			}
+		}
+
+		if message.Op == WSOpUnsubscribe {
+			for _, channel := range message.Channels {
+				h.unsubscribe(client, channel)
+			}
+			h.deliver(client, WSServerMessage{Type: WSMessageUnsubscribed, ID: message.ID, Data: WSSubscriptions{Channels: client.channelList()}})
+			return
+		}
+
+		var added []string
+		for _, channel := range message.Channels {
+			if _, ok := client.channels[channel]; !ok && !containsString(added, channel) {
+				added = append(added, channel)
+			}
+		}
+		if len(client.channels)+len(added) > wsMaxChannels {
+			h.deliverError(client, message.ID, ErrCodeInvalidParameter, fmt.Sprintf("Too many channels: at most %d per connection", wsMaxChannels))
+			return
+		}
+		for _, channel := range added {
+			h.subscribe(client, channel)
+		}
+		h.deliver(client, WSServerMessage{Type: WSMessageSubscribed, ID: message.ID, Data: WSSubscriptions{Channels: client.channelList()}})
+
+		// 새로 구독한 토픽은 델타의 기준이 되는 전체 스냅샷을 먼저 보냄
+		for _, channel := range added {
+			if kind, topicID, _ := parseWSChannel(channel); kind == WSChannelTopic {
+				h.sendTopicSnapshot(client, topicID)
+			}
+		}
+
+	default:
+		h.deliverError(client, message.ID, ErrCodeInvalidParameter, fmt.Sprintf("Unknown op %q: must be subscribe, unsubscribe or ping", message.Op))
+	}
+}
+
+// subscribe는 클라이언트를 채널 구독자에 추가합니다
+func (h *WebSocketHub) subscribe(client *wsClient, channel string) {
+	subscribers, ok := h.channels[channel]
+	if !ok {
+		subscribers = make(map[*wsClient]struct{})
+		h.channels[channel] = subscribers
+	}
+	subscribers[client] = struct{}{}
+	client.channels[channel] = struct{}{}
+}
+
+// unsubscribe는 클라이언트를 채널 구독자에서 제거하고, 구독자가 없는 토픽의 스냅샷 상태를 정리합니다
+func (h *WebSocketHub) unsubscribe(client *wsClient, channel string) {
+	delete(client.channels, channel)
+	subscribers, ok := h.channels[channel]
+	if !ok {
+		return
+	}
+	delete(subscribers, client)
+	if len(subscribers) == 0 {
+		delete(h.channels, channel)
+		if kind, topicID, _ := parseWSChannel(channel); kind == WSChannelTopic {
+			delete(h.topics, topicID)
+		}
+	}
+}
+
+// closeClient는 클라이언트의 구독을 모두 해제하고 send 채널을 닫아 연결 종료를 요청합니다
+func (h *WebSocketHub) closeClient(client *wsClient, code int, reason string) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	delete(h.clients, client)
+	for channel := range client.channels {
+		h.unsubscribe(client, channel)
+	}
+	client.closeCode = code
+	client.closeReason = reason
+	close(client.send)
+}
+
+// handleEvent는 수집 이벤트를 해당 채널의 구독자에게 전달합니다
+func (h *WebSocketHub) handleEvent(event Event) {
+	h.lastID = event.ID
+
+	switch data := event.Data.(type) {
+	case SnapshotEvent:
+		h.publishSnapshot(data)
+
+	case LossHeightEvent:
+		channel := wsChannel(WSChannelTopic, data.TopicID)
+		if topic, ok := h.topics[data.TopicID]; ok && topic.height == data.InferenceBlockHeight {
+			// 다음 델타에 같은 변경이 다시 포함되지 않도록 기준 스냅샷에 반영
+			topic.state["loss_block_height"] = data.LossBlockHeight
+		}
+		h.broadcast(channel, WSServerMessage{Type: WSMessageLossHeight, Channel: channel, Height: data.InferenceBlockHeight, Data: data})
+
+	case CompetitionEvent:
+		channel := wsChannel(WSChannelCompetition, strconv.Itoa(data.CompetitionID))
+		h.broadcast(channel, WSServerMessage{Type: WSMessageCompetition, Channel: channel, Data: data})
+
+	case CollectionErrorEvent:
+		if data.TopicID != "" {
+			channel := wsChannel(WSChannelTopic, data.TopicID)
+			h.broadcast(channel, WSServerMessage{Type: WSMessageCollectionError, Channel: channel, Data: data})
+			return
+		}
+		// 토픽과 관련 없는 수집 실패는 모든 클라이언트에게 전달
+		message, err := json.Marshal(WSServerMessage{Type: WSMessageCollectionError, Data: data})
+		if err != nil {
+			log.Printf("WebSocket 메시지 JSON 변환 실패: %v", err)
+			return
+		}
+		for client := range h.clients {
+			h.send(client, message)
+		}
+
+	case ResetEvent:
+		// 놓친 이벤트가 있으므로 토픽 구독자에게 최신 전체 스냅샷을 다시 보냄
+		h.topics = make(map[string]*wsTopicState)
+		for channel, subscribers := range h.channels {
+			kind, topicID, _ := parseWSChannel(channel)
+			if kind != WSChannelTopic {
+				continue
+			}
+			for client := range subscribers {
+				h.sendTopicSnapshot(client, topicID)
+			}
+		}
+	}
+}
+
+// publishSnapshot은 새 스냅샷을 토픽 구독자에게 델타로, 워커 구독자에게 워커 추론 값으로 보냅니다
+func (h *WebSocketHub) publishSnapshot(event SnapshotEvent) {
+	channel := wsChannel(WSChannelTopic, event.TopicID)
+	_, topicSubscribed := h.channels[channel]
+	if !topicSubscribed && !h.hasWorkerSubscribers() {
+		return
+	}
+
+	prev := h.topics[event.TopicID]
+	if prev != nil && compareHeights(event.InferenceBlockHeight, prev.height) < 0 {
+		// 이미 보낸 스냅샷보다 이전 높이의 본문 변경은 최신 스냅샷 기준 델타에 포함하지 않음
+		return
+	}
+
+	state, err := h.db.GetTopicInferenceByHeight(event.TopicID, event.InferenceBlockHeight)
+	if err != nil {
+		log.Printf("WebSocket 허브 토픽 %s 스냅샷 조회 실패 (높이=%s): %v", event.TopicID, event.InferenceBlockHeight, err)
+		return
+	}
+	if state == nil {
+		return
+	}
+
+	if topicSubscribed {
+		if prev != nil {
+			delta := diffSnapshots(prev.state, state)
+			if !delta.isEmpty() {
+				h.broadcast(channel, WSServerMessage{
+					Type:       WSMessageDelta,
+					Channel:    channel,
+					BaseHeight: prev.height,
+					Height:     event.InferenceBlockHeight,
+					Data:       delta,
+				})
+			}
+		} else {
+			h.broadcast(channel, WSServerMessage{Type: WSMessageSnapshot, Channel: channel, Height: event.InferenceBlockHeight, Data: state})
+		}
+		h.topics[event.TopicID] = &wsTopicState{height: event.InferenceBlockHeight, state: state}
+	}
+
+	// 워커 구독자에게는 새 스냅샷의 synthesis_value에서 해당 워커 항목만 보냄
+	networkInferences, _ := state["network_inferences"].(map[string]interface{})
+	synthesisValue, _ := networkInferences["synthesis_value"].([]interface{})
+	for _, item := range synthesisValue {
+		workerData, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		workerChannel := wsChannel(WSChannelWorker, getStringValue(workerData, "worker", ""))
+		if _, ok := h.channels[workerChannel]; !ok {
+			continue
+		}
+		h.broadcast(workerChannel, WSServerMessage{
+			Type:    WSMessageWorker,
+			Channel: workerChannel,
+			Height:  event.InferenceBlockHeight,
+			Data: WorkerTopicInference{
+				TopicID:              event.TopicID,
+				InferenceBlockHeight: event.InferenceBlockHeight,
+				Timestamp:            event.Timestamp,
+				Inference:            workerData,
+			},
+		})
+	}
+}
+
+// sendTopicSnapshot은 클라이언트에게 토픽의 기준 스냅샷을 보냅니다
+// 허브가 보관한 스냅샷이 없으면 최신 스냅샷을 조회하여 기준으로 삼습니다 (스냅샷이 없는 토픽은 보내지 않음)
+func (h *WebSocketHub) sendTopicSnapshot(client *wsClient, topicID string) {
+	topic, ok := h.topics[topicID]
+	if !ok {
+		state, err := h.db.GetLatestTopicInference(topicID)
+		if err != nil {
+			log.Printf("WebSocket 허브 토픽 %s 최신 스냅샷 조회 실패: %v", topicID, err)
+			h.deliverError(client, "", ErrCodeInternal, fmt.Sprintf("Failed to load snapshot for topic %s", topicID))
+			return
+		}
+		if state == nil {
+			return
+		}
+		topic = &wsTopicState{height: getStringValue(state, "inference_block_height", ""), state: state}
+		h.topics[topicID] = topic
+	}
+
+	channel := wsChannel(WSChannelTopic, topicID)
+	h.deliver(client, WSServerMessage{Type: WSMessageSnapshot, Channel: channel, Height: topic.height, Data: topic.state})
+}
+
+// hasWorkerSubscribers는 워커 채널 구독자가 있는지 확인합니다
+func (h *WebSocketHub) hasWorkerSubscribers() bool {
+	for channel := range h.channels {
+		if strings.HasPrefix(channel, WSChannelWorker+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// broadcast는 메시지를 한 번만 JSON으로 변환하여 채널 구독자 모두에게 보냅니다
+func (h *WebSocketHub) broadcast(channel string, message WSServerMessage) {
+	subscribers := h.channels[channel]
+	if len(subscribers) == 0 {
+		return
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("WebSocket 메시지 JSON 변환 실패: %v", err)
+		return
+	}
+	for client := range subscribers {
+		h.send(client, data)
+	}
+}
+
+// deliver는 클라이언트 한 명에게 메시지를 보냅니다
+func (h *WebSocketHub) deliver(client *wsClient, message WSServerMessage) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("WebSocket 메시지 JSON 변환 실패: %v", err)
+		return
+	}
+	h.send(client, data)
+}
+
+// deliverError는 클라이언트에게 오류 메시지를 보냅니다
+func (h *WebSocketHub) deliverError(client *wsClient, id string, code ErrorCode, message string) {
+	h.deliver(client, WSServerMessage{Type: WSMessageError, ID: id, Error: &APIError{Code: code, Message: message}})
+}
+
+// send는 메시지를 클라이언트 대기열에 넣습니다
+// 대기열이 가득 찬 느린 클라이언트는 기다리지 않고 연결을 끊습니다 (클라이언트는 다시 연결하여 구독)
+func (h *WebSocketHub) send(client *wsClient, data []byte) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	select {
+	case client.send <- data:
+	default:
+		if h.debug {
+			log.Printf("느린 WebSocket 클라이언트 연결 종료: %s", client.conn.RemoteAddr())
+		}
+		h.closeClient(client, websocket.CloseTryAgainLater, "slow consumer")
+	}
+}
+
+// readPump는 클라이언트 요청을 읽어 허브로 전달합니다
+// pong 프레임이나 메시지가 wsPongTimeout 동안 없으면 연결을 끊습니다
+func (h *WebSocketHub) readPump(client *wsClient, done chan struct{}) {
+	defer func() {
+		select {
+		case h.unregister <- client:
+		case <-done:
+		}
+		client.conn.Close()
+	}()
+
+	client.conn.SetReadLimit(wsMaxMessageSize)
+	client.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	for {
+		_, data, err := client.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) && h.debug {
+				log.Printf("WebSocket 읽기 실패: %v", err)
+			}
+			return
+		}
+		client.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+
+		request := wsRequest{client: client}
+		request.err = json.Unmarshal(data, &request.message)
+		select {
+		case h.requests <- request:
+		case <-done:
+			return
+		}
+	}
+}
+
+// writePump는 대기열의 메시지와 ping 프레임을 보냅니다
+// send 채널이 닫히면 종료 코드와 사유를 담은 close 프레임을 보내고 연결을 닫습니다
+// 리더 자격을 잃으면 이후 이벤트가 없으므로 close 프레임을 보내고 연결을 닫습니다 (readPump가 허브에서 해제)
+func (h *WebSocketHub) writePump(client *wsClient) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer func() {
+		ticker.Stop()
+		client.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-client.send:
+			client.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if !ok {
+				client.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(client.closeCode, client.closeReason))
+				return
+			}
+			if err := client.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			client.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-client.revoked:
+			client.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			client.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "not leader"))
+			return
+		}
+	}
+}
+
+// channelList는 구독 중인 채널을 이름 순으로 반환합니다 (허브 고루틴에서만 호출)
+func (c *wsClient) channelList() []string {
+	channels := make([]string, 0, len(c.channels))
+	for channel := range c.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// compareHeights는 두 블록 높이를 정수로 비교합니다
+func compareHeights(a, b string) int {
+	x, _ := strconv.ParseInt(a, 10, 64)
+	y, _ := strconv.ParseInt(b, 10, 64)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,283 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsTestEnv는 WebSocket 허브를 연결한 테스트 서버입니다
+type wsTestEnv struct {
+	db      *Database
+	service *Service
+	events  *EventBroker
+	hub     *WebSocketHub
+	mux     *http.ServeMux
+	server  *httptest.Server
+}
+
+// newWSTestEnv는 이벤트 브로커와 WebSocket 허브를 연결한 테스트 서버를 시작합니다
+func newWSTestEnv(t *testing.T) *wsTestEnv {
+	t.Helper()
+	db := newTestDatabase(t)
+	service, mux := newTestService(t, db)
+	events := NewEventBroker(0)
+	db.SetEventBroker(events)
+	service.SetEventBroker(events)
+	hub := NewWebSocketHub(db, events)
+	if err := hub.Start(); err != nil {
+		t.Fatalf("WebSocket 허브 시작 실패: %v", err)
+	}
+	t.Cleanup(hub.Stop)
+	service.SetWebSocketHub(hub)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return &wsTestEnv{db: db, service: service, events: events, hub: hub, mux: mux, server: server}
+}
+
+// dial은 WebSocket으로 연결하고 welcome 메시지를 확인합니다
+func (env *wsTestEnv) dial(t *testing.T) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(env.url(), nil)
+	if err != nil {
+		t.Fatalf("WebSocket 연결 실패: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if welcome := readWSMessage(t, conn); welcome.Type != WSMessageWelcome {
+		t.Fatalf("첫 메시지 = %+v, want welcome", welcome)
+	}
+	return conn
+}
+
+// url은 WebSocket 경로의 ws:// 주소입니다
+func (env *wsTestEnv) url() string {
+	return "ws" + strings.TrimPrefix(env.server.URL, "http") + "/api/v1/ws"
+}
+
+// readWSMessage는 제한 시간 안에 서버 메시지 하나를 읽습니다
+func readWSMessage(t *testing.T, conn *websocket.Conn) WSServerMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var message WSServerMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("WebSocket 메시지 읽기 실패: %v", err)
+	}
+	return message
+}
+
+// sendWS는 클라이언트 요청을 보냅니다
+func sendWS(t *testing.T, conn *websocket.Conn, message WSClientMessage) {
+	t.Helper()
+	if err := conn.WriteJSON(message); err != nil {
+		t.Fatalf("WebSocket 요청 전송 실패: %v", err)
+	}
+}
+
+// subscriptionChannels는 subscribed/unsubscribed 메시지의 채널 목록을 반환합니다
+func subscriptionChannels(message WSServerMessage) []string {
+	data, _ := message.Data.(map[string]interface{})
+	list, _ := data["channels"].([]interface{})
+	channels := []string{}
+	for _, channel := range list {
+		channels = append(channels, channel.(string))
+	}
+	return channels
+}
+
+func TestWebSocketSubscriptions(t *testing.T) {
+	env := newWSTestEnv(t)
+	base := time.Now().Add(-time.Hour)
+	workers := testWorkers(3)
+	save := func(height int, cycle int) {
+		t.Helper()
+		if err := env.db.SaveTopicInference(testSnapshot("1", height, base.Add(time.Duration(cycle)*time.Minute), workers, cycle)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	publishCompetition := func(id int) {
+		value := "active"
+		env.events.Publish(EventTypeCompetition, "1", CompetitionEvent{CompetitionID: id, TopicID: "1", Field: "status", NewValue: &value})
+	}
+	save(100, 0)
+
+	topicChannel := wsChannel(WSChannelTopic, "1")
+	workerChannel := wsChannel(WSChannelWorker, workers[1])
+	competitionChannel := wsChannel(WSChannelCompetition, "1")
+	conn := env.dial(t)
+
+	// 구독하면 현재 채널 목록을 응답하고 토픽의 최신 스냅샷을 보냄
+	sendWS(t, conn, WSClientMessage{Op: WSOpSubscribe, ID: "s1", Channels: []string{topicChannel, workerChannel, competitionChannel}})
+	subscribed := readWSMessage(t, conn)
+	if want := []string{competitionChannel, topicChannel, workerChannel}; subscribed.Type != WSMessageSubscribed || subscribed.ID != "s1" || !reflect.DeepEqual(subscriptionChannels(subscribed), want) {
+		t.Fatalf("구독 응답 = %+v, want subscribed %v", subscribed, want)
+	}
+	if snapshot := readWSMessage(t, conn); snapshot.Type != WSMessageSnapshot || snapshot.Channel != topicChannel || snapshot.Height != "100" {
+		t.Fatalf("구독 직후 메시지 = %+v, want 토픽 1 높이 100 snapshot", snapshot)
+	}
+
+	// 새 스냅샷은 토픽 채널에 델타로, 워커 채널에 해당 워커의 추론 값으로 전달
+	save(110, 1)
+	delta := readWSMessage(t, conn)
+	if delta.Type != WSMessageDelta || delta.Channel != topicChannel || delta.BaseHeight != "100" || delta.Height != "110" {
+		t.Errorf("새 스냅샷 메시지 = %+v, want 100 -> 110 delta", delta)
+	}
+	worker := readWSMessage(t, conn)
+	workerData, _ := worker.Data.(map[string]interface{})
+	inference, _ := workerData["inference"].(map[string]interface{})
+	if worker.Type != WSMessageWorker || worker.Channel != workerChannel || worker.Height != "110" || inference["worker"] != workers[1] {
+		t.Errorf("워커 메시지 = %+v, want %s 높이 110", worker, workers[1])
+	}
+
+	// 구독한 경쟁의 이벤트만 전달
+	publishCompetition(2)
+	publishCompetition(1)
+	if competition := readWSMessage(t, conn); competition.Type != WSMessageCompetition || competition.Channel != competitionChannel {
+		t.Errorf("경쟁 메시지 = %+v, want %s", competition, competitionChannel)
+	}
+
+	// 구독을 해제한 채널에는 더 이상 보내지 않음
+	sendWS(t, conn, WSClientMessage{Op: WSOpUnsubscribe, ID: "u1", Channels: []string{topicChannel, workerChannel}})
+	unsubscribed := readWSMessage(t, conn)
+	if want := []string{competitionChannel}; unsubscribed.Type != WSMessageUnsubscribed || unsubscribed.ID != "u1" || !reflect.DeepEqual(subscriptionChannels(unsubscribed), want) {
+		t.Fatalf("구독 해제 응답 = %+v, want unsubscribed %v", unsubscribed, want)
+	}
+	save(120, 2)
+	publishCompetition(1) // 같은 구독 순서로 처리되므로 스냅샷 이벤트 다음 메시지
+	if next := readWSMessage(t, conn); next.Type != WSMessageCompetition {
+		t.Errorf("구독 해제 후 메시지 = %+v, want competition (토픽, 워커 메시지 없음)", next)
+	}
+
+	sendWS(t, conn, WSClientMessage{Op: WSOpUnsubscribe, Channels: []string{competitionChannel}})
+	if channels := subscriptionChannels(readWSMessage(t, conn)); len(channels) != 0 {
+		t.Errorf("모두 해제한 뒤 채널 = %v, want 없음", channels)
+	}
+	sendWS(t, conn, WSClientMessage{Op: WSOpPing, ID: "p1"})
+	if pong := readWSMessage(t, conn); pong.Type != WSMessagePong || pong.ID != "p1" {
+		t.Errorf("ping 응답 = %+v, want pong p1", pong)
+	}
+}
+
+func TestWebSocketRequestErrors(t *testing.T) {
+	env := newWSTestEnv(t)
+	conn := env.dial(t)
+
+	tests := []struct {
+		name    string
+		message WSClientMessage
+		code    ErrorCode
+	}{
+		{"잘못된 채널", WSClientMessage{Op: WSOpSubscribe, ID: "e1", Channels: []string{"bogus:1"}}, ErrCodeInvalidParameter},
+		{"정수가 아닌 토픽 ID", WSClientMessage{Op: WSOpSubscribe, ID: "e2", Channels: []string{"topic:abc"}}, ErrCodeInvalidParameter},
+		{"채널 누락", WSClientMessage{Op: WSOpSubscribe, ID: "e3"}, ErrCodeMissingParameter},
+		{"알 수 없는 op", WSClientMessage{Op: "publish", ID: "e4", Channels: []string{"topic:1"}}, ErrCodeInvalidParameter},
+	}
+	for _, tt := range tests {
+		sendWS(t, conn, tt.message)
+		response := readWSMessage(t, conn)
+		if response.Type != WSMessageError || response.ID != tt.message.ID || response.Error == nil || response.Error.Code != tt.code {
+			t.Errorf("%s: 응답 = %+v, want error %s", tt.name, response, tt.code)
+		}
+	}
+
+	// JSON이 아닌 요청
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("bogus")); err != nil {
+		t.Fatal(err)
+	}
+	if response := readWSMessage(t, conn); response.Type != WSMessageError || response.Error == nil || response.Error.Code != ErrCodeInvalidRequestBody {
+		t.Errorf("JSON이 아닌 요청 응답 = %+v, want error %s", response, ErrCodeInvalidRequestBody)
+	}
+
+	// 오류 뒤에도 연결은 유지되고 잘못된 채널은 구독되지 않음
+	sendWS(t, conn, WSClientMessage{Op: WSOpSubscribe, Channels: []string{"topic:1"}})
+	if channels := subscriptionChannels(readWSMessage(t, conn)); !reflect.DeepEqual(channels, []string{"topic:1"}) {
+		t.Errorf("오류 뒤 구독 채널 = %v, want [topic:1]", channels)
+	}
+}
+
+func TestWebSocketHubStopClosesClients(t *testing.T) {
+	env := newWSTestEnv(t)
+	conns := []*websocket.Conn{env.dial(t), env.dial(t)}
+	sendWS(t, conns[0], WSClientMessage{Op: WSOpSubscribe, Channels: []string{"topic:1"}})
+	readWSMessage(t, conns[0])
+
+	stopped := make(chan struct{})
+	go func() {
+		env.hub.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("연결된 클라이언트가 있는 허브가 중지되지 않았습니다")
+	}
+
+	// 모든 클라이언트는 going away 종료 프레임을 받음
+	for i, conn := range conns {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _, err := conn.ReadMessage()
+		closeErr, ok := err.(*websocket.CloseError)
+		if !ok || closeErr.Code != websocket.CloseGoingAway || closeErr.Text != "server shutting down" {
+			t.Errorf("클라이언트 %d 종료 = %v, want going away", i, err)
+		}
+	}
+
+	// 중지된 허브는 새 연결을 받지 않음
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	recorder := httptest.NewRecorder()
+	env.mux.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusServiceUnavailable || decodeErrorResponse(t, recorder).Error.Code != ErrCodeNotConfigured {
+		t.Errorf("중지된 허브 연결 = %d %s, want 503 %s", recorder.Code, recorder.Body.String(), ErrCodeNotConfigured)
+	}
+}
+
+func TestWebSocketRequiresUpgrade(t *testing.T) {
+	env := newWSTestEnv(t)
+	recorder := serveTest(env.mux, http.MethodGet, "/api/v1/ws", "")
+	if recorder.Code != http.StatusUpgradeRequired {
+		t.Fatalf("업그레이드 없는 요청 상태 코드 = %d, want 426", recorder.Code)
+	}
+	if upgrade := recorder.Header().Get("Upgrade"); upgrade != "websocket" {
+		t.Errorf("Upgrade 헤더 = %q, want websocket", upgrade)
+	}
+	if response := decodeErrorResponse(t, recorder); response.Error.Code != ErrCodeUpgradeRequired {
+		t.Errorf("오류 코드 = %s, want %s", response.Error.Code, ErrCodeUpgradeRequired)
+	}
+
+	// 허브가 없으면 503
+	_, mux := newTestService(t, newTestDatabase(t))
+	recorder = serveTest(mux, http.MethodGet, "/api/v1/ws", "")
+	if recorder.Code != http.StatusServiceUnavailable || decodeErrorResponse(t, recorder).Error.Code != ErrCodeNotConfigured {
+		t.Errorf("허브 없이 연결 = %d %s, want 503 %s", recorder.Code, recorder.Body.String(), ErrCodeNotConfigured)
+	}
+}
+
+func TestWebSocketRequiresLeader(t *testing.T) {
+	env := newWSTestEnv(t)
+	leader, follower := newElectorPair(t, env.db)
+
+	// 팔로워는 업그레이드하지 않고 리더 ID와 함께 503으로 거부
+	env.service.SetLeaderElector(follower)
+	_, resp, err := websocket.DefaultDialer.Dial(env.url(), nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get(leaderIDHeader) != "instance-a" {
+		t.Fatalf("팔로워 연결 = %v (%v), want 503과 리더 instance-a", resp, err)
+	}
+	resp.Body.Close()
+
+	// 리더 자격을 잃으면 연결된 클라이언트는 try again later 종료 프레임을 받음
+	env.service.SetLeaderElector(leader)
+	conn := env.dial(t)
+	handOverLeadership(t, env.db, leader, follower)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	if closeErr, ok := err.(*websocket.CloseError); !ok || closeErr.Code != websocket.CloseTryAgainLater || closeErr.Text != "not leader" {
+		t.Errorf("리더 자격 상실 후 종료 = %v, want try again later (not leader)", err)
+	}
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// WorkerTopicInference는 토픽 최신 스냅샷에 포함된 워커의 추론 값입니다
+type WorkerTopicInference struct {
+	TopicID              string                 `json:"topic_id"`
+	InferenceBlockHeight string                 `json:"inference_block_height"`
+	Timestamp            string                 `json:"timestamp"`
+	Inference            map[string]interface{} `json:"inference"` // synthesis_value 항목
+}
+
+// WorkerLeaderboardEntry는 토픽별 가장 최근 리더보드 기록입니다
+type WorkerLeaderboardEntry struct {
+	TopicID              string   `json:"topic_id"`
+	InferenceBlockHeight string   `json:"inference_block_height"`
+	Timestamp            string   `json:"timestamp"`
+	Username             string   `json:"username"`
+	Rank                 string   `json:"rank"`
+	Points               *float64 `json:"points"`
+	Score                *float64 `json:"score"`
+	Loss                 *float64 `json:"loss"`
+}
+
+// WorkerProfile은 워커 주소 하나의 토픽별 최신 추론 값과 리더보드 기록입니다
+type WorkerProfile struct {
+	Address     string                   `json:"address"`
+	Names       []string                 `json:"names"`
+	Topics      []string                 `json:"topics"`
+	Inferences  []WorkerTopicInference   `json:"inferences"`
+	Leaderboard []WorkerLeaderboardEntry `json:"leaderboard"`
+}
+
+// GetWorkerProfile은 워커 주소의 토픽별 최신 추론 값과 리더보드 기록을 가져옵니다
+// 최신 스냅샷과 리더보드 어디에도 없는 주소이면 nil을 반환합니다
+func (d *Database) GetWorkerProfile(address string) (*WorkerProfile, error) {
+	if d.debug {
+		log.Printf("GetWorkerProfile 시작: 주소=%s", address)
+	}
+
+	profile := &WorkerProfile{
+		Address:     address,
+		Names:       []string{},
+		Inferences:  []WorkerTopicInference{},
+		Leaderboard: []WorkerLeaderboardEntry{},
+	}
+	names := make(map[string]bool)
+	topics := make(map[string]bool)
+
+	// 토픽별 가장 최근 리더보드 기록
+	rows, err := d.reader.Query(`
+		SELECT topic_id, inference_block_height, timestamp, COALESCE(username, ''),
+			COALESCE(first_name, ''), COALESCE(last_name, ''), COALESCE(rank, ''), points, score, loss
+		FROM leaderboard_entries e
+		WHERE cosmos_address = ? AND id = (
+			SELECT MAX(id) FROM leaderboard_entries
+			WHERE cosmos_address = e.cosmos_address AND topic_id = e.topic_id
+		)
+		ORDER BY topic_id
+	`, address)
+	if err != nil {
+		return nil, fmt.Errorf("워커 리더보드 조회 실패: %w", err)
+	}
+	for rows.Next() {
+		var entry WorkerLeaderboardEntry
+		var firstName, lastName string
+		var points, score, loss sql.NullFloat64
+		if err := rows.Scan(&entry.TopicID, &entry.InferenceBlockHeight, &entry.Timestamp, &entry.Username,
+			&firstName, &lastName, &entry.Rank, &points, &score, &loss); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("워커 리더보드 스캔 실패: %w", err)
+		}
+		entry.Points = nullFloatPtr(points)
+		entry.Score = nullFloatPtr(score)
+		entry.Loss = nullFloatPtr(loss)
+		for _, name := range []string{entry.Username, strings.TrimSpace(firstName + " " + lastName)} {
+			if name = strings.TrimSpace(name); name != "" {
+				names[name] = true
+			}
+		}
+		topics[entry.TopicID] = true
+		profile.Leaderboard = append(profile.Leaderboard, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("워커 리더보드 처리 중 오류: %w", err)
+	}
+
+	// 각 토픽 최신 스냅샷의 synthesis_value에서 워커 항목 찾기 (스냅샷 캐시 사용)
+	topicIDs, err := d.snapshotTopicIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, topicID := range topicIDs {
+		latest, err := d.GetLatestTopicInference(topicID)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil {
+			continue
+		}
+		networkInferences, _ := latest["network_inferences"].(map[string]interface{})
+		synthesisValue, _ := networkInferences["synthesis_value"].([]interface{})
+		for _, item := range synthesisValue {
+			workerData, ok := item.(map[string]interface{})
+			if !ok || getStringValue(workerData, "worker", "") != address {
+				continue
+			}
+			profile.Inferences = append(profile.Inferences, WorkerTopicInference{
+				TopicID:              topicID,
+				InferenceBlockHeight: getStringValue(latest, "inference_block_height", ""),
+				Timestamp:            getStringValue(latest, "timestamp", ""),
+				Inference:            workerData,
+			})
+			topics[topicID] = true
+			break
+		}
+	}
+
+	if len(topics) == 0 {
+		return nil, nil
+	}
+	profile.Names = sortedKeys(names)
+	profile.Topics = sortedKeys(topics)
+
+	if d.debug {
+		log.Printf("GetWorkerProfile 완료: 주소=%s, 토픽 %d개", address, len(profile.Topics))
+	}
+	return profile, nil
+}
@@ -53,7 +53,10 @@ async function apiRequest<T>(
                     'Network error - cannot connect to the backend server. Make sure the server is running at ' +
                     API_BASE_URL;
             } else if (error.response) {
-                errorMessage = `Server error: ${error.response.status} ${error.response.statusText}`;
+                const apiError = getApiError(error);
+                errorMessage = apiError
+                    ? `Server error: ${error.response.status} ${apiError.code} - ${apiError.message}`
+                    : `Server error: ${error.response.status} ${error.response.statusText}`;
             }
         }
 
@@ -62,6 +65,23 @@ async function apiRequest<T>(
     }
 }
 
+// Error envelope returned by the backend: {"status": "error", "error": {"code", "message"}}
+export interface ApiError {
+    code: string;
+    message: string;
+}
+
+// Extract the error code and message from a failed request, if the backend sent one
+export function getApiError(error: unknown): ApiError | null {
+    if (error instanceof AxiosError) {
+        const body = error.response?.data;
+        if (body && body.status === 'error' && body.error?.code) {
+            return body.error as ApiError;
+        }
+    }
+    return null;
+}
+
 // API endpoints
 export const endpoints = {
     health: '/api/health',